@@ -3,8 +3,9 @@
 // This is the ONLY place that imports from "@wailsjs/go/..."
 // Everywhere else in the app should import from this file.
 
-import { Version } from "@wailsjs/go/wailsapp/App"
+import { Version, BuildInfo } from "@wailsjs/go/wailsapp/App"
 
 export const appBindings = {
   getVersion: Version,
+  getBuildInfo: BuildInfo,
 }
@@ -0,0 +1,10 @@
+// src/shared/bindings/diagnosticsBindings.js
+// Thin wrapper around the Go Diagnostics bindings generated by Wails.
+// Keeps frontend decoupled from internal Go package paths.
+
+import { Export } from "@wailsjs/go/wailsapp/Diagnostics"
+
+export const diagnosticsBindings = {
+  /** Export a diagnostics zip; an empty path uses the default folder. */
+  export: Export,
+}
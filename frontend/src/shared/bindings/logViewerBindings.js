@@ -0,0 +1,10 @@
+// src/shared/bindings/logViewerBindings.js
+// Thin wrapper around the Go LogViewer bindings generated by Wails.
+// Keeps frontend decoupled from internal Go package paths.
+
+import { Recent, LogFilePath } from "@wailsjs/go/wailsapp/LogViewer"
+
+export const logViewerBindings = {
+  recent: Recent,
+  logFilePath: LogFilePath,
+}
@@ -0,0 +1,20 @@
+// src/shared/bindings/specWatcherBindings.js
+// Thin wrapper around the Go SpecWatcher bindings generated by Wails.
+// Keeps frontend decoupled from internal Go package paths and event names.
+
+import { Start, Stop, Watching } from "@wailsjs/go/wailsapp/SpecWatcher"
+import { EventsOn } from "@wailsjs/runtime/runtime"
+
+// Must match the event names in internal/adapter/ui/wailsapp/spec_watcher.go.
+const EVENT_RESULT = "spec:watch:result"
+const EVENT_ERROR = "spec:watch:error"
+
+export const specWatcherBindings = {
+  start: Start,
+  stop: Stop,
+  watching: Watching,
+  /** Subscribe to incremental results ({ run, delta: { added, removed, total }, ... }); returns an unsubscribe function. */
+  onResult: (callback) => EventsOn(EVENT_RESULT, callback),
+  /** Subscribe to session failures (error message); returns an unsubscribe function. */
+  onError: (callback) => EventsOn(EVENT_ERROR, callback),
+}
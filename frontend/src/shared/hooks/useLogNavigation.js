@@ -8,6 +8,7 @@ import { loggerService } from "@/shared/services/loggerService"
  *
  * Hook that automatically logs every route change.
  * - Captures pathname from react-router
+ * - Keeps loggerService's current route in sync for log correlation
  * - Sends structured log to loggerService
  *
  * Usage:
@@ -17,6 +18,7 @@ export function useLogNavigation() {
   const location = useLocation()
 
   useEffect(() => {
+    loggerService.setRoute(location.pathname)
     loggerService.debug("Navigation", { path: location.pathname })
   }, [location])
 }
@@ -34,6 +34,11 @@ export const appService = {
     return version
   },
 
+  /** Get structured build info (version, commit, dirty, build time) */
+  async getBuildInfo() {
+    return safe(appBindings.getBuildInfo(), null)
+  },
+
   /** Project-related domain calls (placeholders for now) */
   projects: {
     async getAll() {
@@ -4,9 +4,24 @@
 // - Always logs to browser console (for dev visibility).
 // - Proxies structured logs to the Go Zap logger via Wails bindings.
 // - Uses centralized formatting for consistency.
+// - Sends metadata as a JSON object; the Go bridge turns keys into zap fields,
+//   redacts sensitive keys and tags entries with source/session.
 
 import { loggerBindings } from "@/shared/bindings/loggerBindings"
 
+// Current route, kept in sync by useLogNavigation (the app uses a MemoryRouter,
+// so window.location does not reflect navigation).
+let currentRoute = "/"
+
+/**
+ * payload
+ *
+ * Serialize metadata for the Go bridge, tagging the current route.
+ */
+function payload(meta) {
+  return JSON.stringify({ ...(meta || {}), route: currentRoute })
+}
+
 /**
  * format
  *
@@ -24,24 +39,28 @@ function format(level, msg, meta) {
 }
 
 export const loggerService = {
+  /** Record the active route so every entry can be correlated with it. */
+  setRoute(route) {
+    currentRoute = route || "/"
+  },
   info(msg, meta) {
     const entry = format("info", msg, meta)
     console.info("[frontend]", entry)
-    loggerBindings.info(entry.msg, JSON.stringify(entry.meta || {}))
+    loggerBindings.info(entry.msg, payload(entry.meta))
   },
   warn(msg, meta) {
     const entry = format("warn", msg, meta)
     console.warn("[frontend]", entry)
-    loggerBindings.warn(entry.msg, JSON.stringify(entry.meta || {}))
+    loggerBindings.warn(entry.msg, payload(entry.meta))
   },
   error(msg, meta) {
     const entry = format("error", msg, meta)
     console.error("[frontend]", entry)
-    loggerBindings.error(entry.msg, JSON.stringify(entry.meta || {}))
+    loggerBindings.error(entry.msg, payload(entry.meta))
   },
   debug(msg, meta) {
     const entry = format("debug", msg, meta)
     console.debug("[frontend]", entry)
-    loggerBindings.debug(entry.msg, JSON.stringify(entry.meta || {}))
+    loggerBindings.debug(entry.msg, payload(entry.meta))
   },
 }
@@ -0,0 +1,93 @@
+export namespace output {
+	
+	export class LogEntry {
+	    // Go type: time
+	    time: any;
+	    level: string;
+	    logger?: string;
+	    message: string;
+	    caller?: string;
+	    fields?: Record<string, any>;
+	
+	    static createFrom(source: any = {}) {
+	        return new LogEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = this.convertValues(source["time"], null);
+	        this.level = source["level"];
+	        this.logger = source["logger"];
+	        this.message = source["message"];
+	        this.caller = source["caller"];
+	        this.fields = source["fields"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LogQuery {
+	    level: string;
+	    logger: string;
+	    contains: string;
+	    limit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LogQuery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.level = source["level"];
+	        this.logger = source["logger"];
+	        this.contains = source["contains"];
+	        this.limit = source["limit"];
+	    }
+	}
+
+}
+
+export namespace version {
+	
+	export class BuildInfo {
+	    version: string;
+	    commit: string;
+	    dirty: boolean;
+	    buildTime: string;
+	    commitTime: string;
+	    goVersion: string;
+	    platform: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BuildInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.version = source["version"];
+	        this.commit = source["commit"];
+	        this.dirty = source["dirty"];
+	        this.buildTime = source["buildTime"];
+	        this.commitTime = source["commitTime"];
+	        this.goVersion = source["goVersion"];
+	        this.platform = source["platform"];
+	    }
+	}
+
+}
+
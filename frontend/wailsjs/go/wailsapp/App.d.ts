@@ -1,4 +1,7 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
+import {version} from '../models';
+
+export function BuildInfo():Promise<version.BuildInfo>;
 
 export function Version():Promise<string>;
@@ -2,6 +2,10 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function BuildInfo() {
+  return window['go']['wailsapp']['App']['BuildInfo']();
+}
+
 export function Version() {
   return window['go']['wailsapp']['App']['Version']();
 }
@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+)
+
+// diskFormat versions the on-disk entry layout; bump it to invalidate old entries.
+const diskFormat = 1
+
+// diskStore persists documents as "<dir>/<key>.json".
+type diskStore struct {
+	dir string
+}
+
+type diskEntry struct {
+	Format  int             `json:"format"`
+	Version string          `json:"version"`
+	Doc     json.RawMessage `json:"doc"`
+}
+
+func (d *diskStore) path(key string) string {
+	return filepath.Join(d.dir, key+".json")
+}
+
+func (d *diskStore) get(key string) (openapi.OpenAPIDoc, bool) {
+	raw, err := os.ReadFile(d.path(key))
+	if err != nil {
+		return openapi.OpenAPIDoc{}, false
+	}
+	var e diskEntry
+	if err := json.Unmarshal(raw, &e); err != nil || e.Format != diskFormat || len(e.Doc) == 0 {
+		d.remove(key)
+		return openapi.OpenAPIDoc{}, false
+	}
+	return openapi.OpenAPIDoc{JSON: e.Doc, Version: openapi.OpenAPIVersion(e.Version)}, true
+}
+
+// put writes the entry atomically so concurrent readers never see partial files.
+func (d *diskStore) put(key string, doc openapi.OpenAPIDoc) error {
+	raw, err := json.Marshal(diskEntry{Format: diskFormat, Version: doc.Version.String(), Doc: doc.JSON})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(d.dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(d.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), d.path(key))
+}
+
+func (d *diskStore) remove(key string) {
+	_ = os.Remove(d.path(key))
+}
@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/betoth/contractcheck/internal/adapter/refscan"
+)
+
+// contentKey hashes the root file and every local file reachable through
+// external `$ref`s, together with fingerprint, and returns the absolute root
+// path alongside. It reports false when the document cannot be keyed safely:
+// unreadable files (the wrapped loader owns that error) or remote refs, whose
+// content may change without notice.
+//
+// The key is location independent so disk entries survive a moved spec tree;
+// callers that hand out location-bound data (Sources) must add the root.
+func contentKey(rootPath, fingerprint string) (string, string, bool) {
+	root, err := filepath.Abs(rootPath)
+	if err != nil {
+		return "", "", false
+	}
+
+	hashes := map[string]string{}
+	queue := []string{root}
+	for len(queue) > 0 {
+		file := queue[0]
+		queue = queue[1:]
+		if _, seen := hashes[file]; seen {
+			continue
+		}
+
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			return "", "", false
+		}
+		sum := sha256.Sum256(raw)
+		hashes[file] = hex.EncodeToString(sum[:])
+
+		refs, remote := refscan.Refs(raw)
+		if remote {
+			return "", "", false
+		}
+		for _, target := range refs {
+			queue = append(queue, filepath.Join(filepath.Dir(file), target))
+		}
+	}
+
+	files := make([]string, 0, len(hashes))
+	for f := range hashes {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+
+	h := sha256.New()
+	h.Write([]byte(fingerprint))
+	for _, f := range files {
+		// Paths relative to the root keep keys stable when a spec tree moves.
+		rel, err := filepath.Rel(filepath.Dir(root), f)
+		if err != nil {
+			rel = f
+		}
+		h.Write([]byte(filepath.ToSlash(rel)))
+		h.Write([]byte{0})
+		h.Write([]byte(hashes[f]))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)), root, true
+}
@@ -0,0 +1,180 @@
+// Package cache provides a content-addressed cache for parsed OpenAPI specs,
+// exposed as a decorator around openapi.Loader.
+package cache
+
+import (
+	"bytes"
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+)
+
+// DefaultCapacity is the number of documents kept in memory when no capacity is given.
+const DefaultCapacity = 32
+
+// ModelBuilder rebuilds the typed model of a document restored from disk,
+// where only the JSON form is persisted.
+type ModelBuilder func(doc openapi.OpenAPIDoc, filePath string) (*openapi.Document, error)
+
+// Stats reports cache effectiveness since construction.
+type Stats struct {
+	MemoryHits int
+	DiskHits   int
+	Misses     int
+	Bypassed   int // loads that could not be keyed (e.g. remote refs)
+}
+
+// CachedLoader decorates an openapi.Loader with an in-memory LRU and an
+// optional on-disk layer. Entries are keyed by a hash over the root file,
+// every file reachable through external `$ref`s, and a fingerprint of the
+// loader options and version policy, so edits to any of them miss the cache.
+//
+// Memory entries are also keyed by the absolute root path, since the model
+// records the files it was built from; disk entries are location independent
+// and get their model rebuilt for the requested path. Every hit returns a
+// copy of the cached document, so callers may modify the top level freely.
+// Failed loads are never cached.
+type CachedLoader struct {
+	next        openapi.Loader
+	fingerprint string
+	mem         *lru
+	disk        *diskStore
+	build       ModelBuilder
+
+	mu    sync.Mutex
+	stats Stats
+}
+
+// Option configures a CachedLoader.
+type Option func(*CachedLoader)
+
+// WithCapacity bounds the in-memory layer to n documents.
+func WithCapacity(n int) Option {
+	return func(c *CachedLoader) {
+		if n > 0 {
+			c.mem = newLRU(n)
+		}
+	}
+}
+
+// WithDiskDir enables the on-disk layer under dir ("" keeps it disabled).
+// build restores the typed model for disk hits; when nil, Model stays nil.
+func WithDiskDir(dir string, build ModelBuilder) Option {
+	return func(c *CachedLoader) {
+		if dir != "" {
+			c.disk = &diskStore{dir: dir}
+			c.build = build
+		}
+	}
+}
+
+// WithFingerprint adds the parts (loader options, policy, …) to every cache key.
+func WithFingerprint(parts ...string) Option {
+	return func(c *CachedLoader) {
+		for _, p := range parts {
+			c.fingerprint += p + "\x00"
+		}
+	}
+}
+
+// NewCachedLoader wraps next. Without options it only caches in memory.
+func NewCachedLoader(next openapi.Loader, opts ...Option) *CachedLoader {
+	c := &CachedLoader{next: next, mem: newLRU(DefaultCapacity)}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Load returns the cached document for filePath when its content key is
+// known, otherwise delegates to the wrapped loader and stores the result.
+func (c *CachedLoader) Load(ctx context.Context, filePath string) (openapi.OpenAPIDoc, error) {
+	key, root, ok := contentKey(filePath, c.fingerprint)
+	if !ok {
+		c.count(func(s *Stats) { s.Bypassed++ })
+		return c.next.Load(ctx, filePath)
+	}
+
+	memKey := key + "\x00" + root
+
+	if doc, hit := c.mem.get(memKey); hit {
+		c.count(func(s *Stats) { s.MemoryHits++ })
+		return copyDoc(doc), nil
+	}
+
+	if doc, hit := c.fromDisk(key, filePath); hit {
+		c.mem.put(memKey, doc)
+		c.count(func(s *Stats) { s.DiskHits++ })
+		return copyDoc(doc), nil
+	}
+
+	c.count(func(s *Stats) { s.Misses++ })
+	doc, err := c.next.Load(ctx, filePath)
+	if err != nil {
+		return openapi.OpenAPIDoc{}, err
+	}
+	c.mem.put(memKey, doc)
+	if c.disk != nil {
+		// Best effort: a read-only cache dir must not fail the import.
+		_ = c.disk.put(key, doc)
+	}
+	return copyDoc(doc), nil
+}
+
+// copyDoc returns doc with its own JSON bytes and model, so a caller editing
+// the result cannot corrupt the cached entry. Schemas and operations below
+// the top-level slices and maps are still shared and must be treated as
+// read-only.
+func copyDoc(doc openapi.OpenAPIDoc) openapi.OpenAPIDoc {
+	doc.JSON = bytes.Clone(doc.JSON)
+	if doc.Model != nil {
+		m := *doc.Model
+		m.Servers = slices.Clone(m.Servers)
+		m.Security = slices.Clone(m.Security)
+		m.Operations = slices.Clone(m.Operations)
+		m.Schemas = maps.Clone(m.Schemas)
+		m.SecuritySchemes = maps.Clone(m.SecuritySchemes)
+		m.Extensions = maps.Clone(m.Extensions)
+		m.Sources = slices.Clone(m.Sources)
+		doc.Model = &m
+	}
+	return doc
+}
+
+// fromDisk restores a persisted entry and its typed model. Entries whose
+// model cannot be rebuilt are treated as stale and dropped.
+func (c *CachedLoader) fromDisk(key, filePath string) (openapi.OpenAPIDoc, bool) {
+	if c.disk == nil {
+		return openapi.OpenAPIDoc{}, false
+	}
+	doc, hit := c.disk.get(key)
+	if !hit || c.build == nil {
+		return doc, hit
+	}
+	model, err := c.build(doc, filePath)
+	if err != nil {
+		c.disk.remove(key)
+		return openapi.OpenAPIDoc{}, false
+	}
+	doc.Model = model
+	return doc, true
+}
+
+// Stats returns a snapshot of the hit/miss counters.
+func (c *CachedLoader) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+func (c *CachedLoader) count(fn func(*Stats)) {
+	c.mu.Lock()
+	fn(&c.stats)
+	c.mu.Unlock()
+}
+
+// compile-time check
+var _ openapi.Loader = (*CachedLoader)(nil)
@@ -0,0 +1,202 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/betoth/contractcheck/internal/adapter/cache"
+	kinopenapi "github.com/betoth/contractcheck/internal/adapter/openapi"
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+)
+
+// countingLoader returns the root file verbatim and counts calls.
+type countingLoader struct{ calls int }
+
+func (l *countingLoader) Load(_ context.Context, filePath string) (openapi.OpenAPIDoc, error) {
+	l.calls++
+	raw, err := os.ReadFile(filePath)
+	if err != nil {
+		return openapi.OpenAPIDoc{}, err
+	}
+	return openapi.OpenAPIDoc{JSON: raw, Version: "3.0.3", Model: &openapi.Document{Sources: []string{filePath}}}, nil
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCachedLoader_KeysOnContentOfRootAndRefs(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "root.yaml")
+	writeFile(t, root, `{"paths": {"$ref": "schemas/pet.yaml#/Pet"}, "x": {"$ref": "#/paths"}}`)
+	os.Mkdir(filepath.Join(dir, "schemas"), 0o755)
+	writeFile(t, filepath.Join(dir, "schemas", "pet.yaml"), "Pet: {type: object}\n")
+
+	next := &countingLoader{}
+	c := cache.NewCachedLoader(next)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.Load(ctx, root); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if next.calls != 1 {
+		t.Fatalf("expected second load to hit memory, got %d calls", next.calls)
+	}
+
+	// Editing a referenced file must invalidate the entry.
+	writeFile(t, filepath.Join(dir, "schemas", "pet.yaml"), "Pet: {type: string}\n")
+	if _, err := c.Load(ctx, root); err != nil {
+		t.Fatal(err)
+	}
+	if next.calls != 2 {
+		t.Fatalf("expected a miss after editing a ref, got %d calls", next.calls)
+	}
+
+	// A different fingerprint (options/policy) never shares entries.
+	other := cache.NewCachedLoader(next, cache.WithFingerprint("majors=3.x, 4.x"))
+	if _, err := other.Load(ctx, root); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Stats(); got.MemoryHits != 1 || got.Misses != 2 {
+		t.Fatalf("unexpected stats: %+v", got)
+	}
+}
+
+func TestCachedLoader_SameContentElsewhereKeepsItsSources(t *testing.T) {
+	a := filepath.Join(t.TempDir(), "root.json")
+	b := filepath.Join(t.TempDir(), "root.json")
+	writeFile(t, a, `{"openapi":"3.0.3"}`)
+	writeFile(t, b, `{"openapi":"3.0.3"}`)
+
+	next := &countingLoader{}
+	c := cache.NewCachedLoader(next)
+	ctx := context.Background()
+
+	docA, err := c.Load(ctx, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	docB, err := c.Load(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.calls != 2 || docB.Model.Sources[0] != b {
+		t.Fatalf("expected %s to load on its own, calls=%d sources=%v", b, next.calls, docB.Model.Sources)
+	}
+
+	// Hits are copies: editing one must not leak into the cached entry.
+	docA.Model.Sources[0] = "changed"
+	docA.Model.Info.Title = "changed"
+	again, err := c.Load(ctx, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Model.Sources[0] != a || again.Model.Info.Title != "" {
+		t.Fatalf("cached entry was modified through a previous result: %+v", again.Model)
+	}
+}
+
+func TestCachedLoader_DiskLayerRebuildsModel(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "root.json")
+	writeFile(t, root, `{"openapi":"3.0.3"}`)
+	cacheDir := filepath.Join(dir, "cache")
+
+	builds := 0
+	build := func(doc openapi.OpenAPIDoc, _ string) (*openapi.Document, error) {
+		builds++
+		return &openapi.Document{Version: doc.Version}, nil
+	}
+
+	next := &countingLoader{}
+	ctx := context.Background()
+	if _, err := cache.NewCachedLoader(next, cache.WithDiskDir(cacheDir, build)).Load(ctx, root); err != nil {
+		t.Fatal(err)
+	}
+
+	// A fresh instance (new process) is served from disk.
+	fresh := cache.NewCachedLoader(next, cache.WithDiskDir(cacheDir, build))
+	doc, err := fresh.Load(ctx, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.calls != 1 || fresh.Stats().DiskHits != 1 {
+		t.Fatalf("expected a disk hit, calls=%d stats=%+v", next.calls, fresh.Stats())
+	}
+	if doc.Model == nil || doc.Model.Version != "3.0.3" || builds != 1 || string(doc.JSON) != `{"openapi":"3.0.3"}` {
+		t.Fatalf("unexpected restored doc: %+v (builds=%d)", doc, builds)
+	}
+}
+
+func TestCachedLoader_SandboxIsPartOfTheKey(t *testing.T) {
+	dir := t.TempDir()
+	os.Mkdir(filepath.Join(dir, "api"), 0o755)
+	os.Mkdir(filepath.Join(dir, "shared"), 0o755)
+	root := filepath.Join(dir, "api", "openapi.yaml")
+	writeFile(t, root, `openapi: 3.0.3
+info: {title: Pets, version: "1.0"}
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: "../shared/pet.yaml#/Pet"}
+`)
+	writeFile(t, filepath.Join(dir, "shared", "pet.yaml"), "Pet: {type: object}\n")
+	cacheDir := filepath.Join(dir, "cache")
+
+	// Both loaders share the disk layer, as separate runs of the CLI do.
+	sandboxed := func(sandbox string) *cache.CachedLoader {
+		kin := kinopenapi.NewKinLoader(kinopenapi.WithExternalRefsAllowed(), kinopenapi.WithSandbox(sandbox))
+		return cache.NewCachedLoader(kin, cache.WithFingerprint(kin.Fingerprint()), cache.WithDiskDir(cacheDir, kin.BuildModel))
+	}
+	ctx := context.Background()
+
+	if _, err := sandboxed(dir).Load(ctx, root); err != nil {
+		t.Fatalf("wide sandbox: %v", err)
+	}
+
+	var ae *customerrors.AppError
+	narrow := sandboxed(filepath.Join(dir, "api"))
+	_, err := narrow.Load(ctx, root)
+	if !errors.As(err, &ae) || ae.Details[customerrors.DetailKind] != openapi.REF_OUTSIDE_SANDBOX {
+		t.Fatalf("narrow sandbox: expected %s, got %v", openapi.REF_OUTSIDE_SANDBOX, err)
+	}
+	if got := narrow.Stats(); got.DiskHits != 0 || got.MemoryHits != 0 {
+		t.Fatalf("expected the narrow sandbox to miss the cache, got %+v", got)
+	}
+
+	// The rejection must not evict the wide sandbox's entry.
+	wide := sandboxed(dir)
+	if _, err := wide.Load(ctx, root); err != nil || wide.Stats().DiskHits != 1 {
+		t.Fatalf("wide sandbox again: err=%v stats=%+v", err, wide.Stats())
+	}
+}
+
+func TestCachedLoader_BypassesRemoteRefs(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "root.yaml")
+	writeFile(t, root, "components:\n  schemas:\n    Pet:\n      $ref: 'https://example.com/pet.yaml'\n")
+
+	next := &countingLoader{}
+	c := cache.NewCachedLoader(next)
+	for i := 0; i < 2; i++ {
+		if _, err := c.Load(context.Background(), root); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if next.calls != 2 || c.Stats().Bypassed != 2 {
+		t.Fatalf("expected remote refs to bypass the cache, calls=%d stats=%+v", next.calls, c.Stats())
+	}
+}
@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+)
+
+// lru is a fixed-size, least-recently-used map of content keys to documents.
+type lru struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // front = most recently used
+	items    map[string]*list.Element
+}
+
+type lruEntry struct {
+	key string
+	doc openapi.OpenAPIDoc
+}
+
+func newLRU(capacity int) *lru {
+	return &lru{capacity: capacity, order: list.New(), items: map[string]*list.Element{}}
+}
+
+func (l *lru) get(key string) (openapi.OpenAPIDoc, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	el, ok := l.items[key]
+	if !ok {
+		return openapi.OpenAPIDoc{}, false
+	}
+	l.order.MoveToFront(el)
+	return el.Value.(*lruEntry).doc, true
+}
+
+func (l *lru) put(key string, doc openapi.OpenAPIDoc) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if el, ok := l.items[key]; ok {
+		el.Value.(*lruEntry).doc = doc
+		l.order.MoveToFront(el)
+		return
+	}
+	l.items[key] = l.order.PushFront(&lruEntry{key: key, doc: doc})
+	for l.order.Len() > l.capacity {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.items, oldest.Value.(*lruEntry).key)
+	}
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+func runAudit(ctx context.Context, args []string, stdout, stderr io.Writer, deps Deps) int {
+	fs := flag.NewFlagSet("audit", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	out := fs.String("o", "", "output file (default: stdout)")
+	format := fs.String("format", "text", "output format: "+strings.Join(findingFormats(deps), ", "))
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: contractcheck audit [-format name] [-o file] <spec>")
+		fmt.Fprintln(stderr, "Audits security: operations without security, inconsistent scopes, API keys in query strings,")
+		fmt.Fprintln(stderr, "basic auth over plain HTTP and OAuth flows without URLs. Exits with 1 when a finding is an error.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return ExitUsage
+	}
+	if err := checkFormat(*format, findingFormats(deps)); err != nil {
+		fmt.Fprintf(stderr, "audit: %v\n", err)
+		return ExitUsage
+	}
+	if deps.Audit == nil {
+		fmt.Fprintln(stderr, "audit: service not configured")
+		return ExitError
+	}
+
+	rep, err := deps.Audit.Audit(ctx, fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "audit: %v\n", err)
+		return ExitError
+	}
+
+	err = writeOutput(*out, stdout, func(w io.Writer) error {
+		return writeReport(w, *format, rep, deps)
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "audit: %v\n", err)
+		return ExitError
+	}
+	if *format == "text" && len(rep.Findings) == 0 && *out == "" {
+		fmt.Fprintf(stdout, "%s: no security findings\n", fs.Arg(0))
+	}
+	if hasErrors(rep.Findings) {
+		return ExitError
+	}
+	return ExitOK
+}
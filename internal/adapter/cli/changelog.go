@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+func runChangelog(ctx context.Context, args []string, stdout, stderr io.Writer, deps Deps) int {
+	fs := flag.NewFlagSet("changelog", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	sel := addSpecSelection(fs)
+	out := fs.String("o", "", "output file (default: stdout)")
+	format := fs.String("format", "markdown", "output format: markdown or json")
+	detailFlag := fs.String("detail", string(report.CHANGELOG_FIELDS), "entries per field change (fields) or per endpoint (endpoints)")
+	release := fs.String("version", "", "release version (default: the head spec's info.version)")
+	date := fs.String("date", "", `release date, YYYY-MM-DD or "today" (default: unreleased)`)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: contractcheck changelog [flags] <base-spec> <head-spec>")
+		fmt.Fprintln(stderr, "       contractcheck changelog -target <branch> [-head rev] [-repo dir] [flags] <spec>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if !sel.validArgs(fs) {
+		fs.Usage()
+		return ExitUsage
+	}
+	if *format != "markdown" && *format != "json" {
+		fmt.Fprintf(stderr, "changelog: unsupported format %q (want markdown, json)\n", *format)
+		return ExitUsage
+	}
+	detail, err := report.ParseChangelogDetail(*detailFlag)
+	if err != nil {
+		fmt.Fprintf(stderr, "changelog: %v\n", err)
+		return ExitUsage
+	}
+	if *date == "today" {
+		*date = time.Now().Format(time.DateOnly)
+	} else if *date != "" {
+		if _, err := time.Parse(time.DateOnly, *date); err != nil {
+			fmt.Fprintf(stderr, "changelog: invalid -date %q (want YYYY-MM-DD)\n", *date)
+			return ExitUsage
+		}
+	}
+	if deps.Compare == nil || deps.Changelog == nil || (*format == "markdown" && deps.ChangelogWriter == nil) {
+		fmt.Fprintln(stderr, "changelog: service not configured")
+		return ExitError
+	}
+
+	cmp, err := sel.compare(ctx, fs, deps.Compare)
+	if err != nil {
+		fmt.Fprintf(stderr, "changelog: %v\n", err)
+		return ExitError
+	}
+	cl, err := deps.Changelog.Changelog(ctx, cmp, input.ChangelogOptions{Detail: detail, Version: *release, Date: *date})
+	if err != nil {
+		fmt.Fprintf(stderr, "changelog: %v\n", err)
+		return ExitError
+	}
+
+	err = writeOutput(*out, stdout, func(w io.Writer) error {
+		if *format == "json" {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(cl)
+		}
+		return deps.ChangelogWriter.WriteChangelog(w, cl)
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "changelog: %v\n", err)
+		return ExitError
+	}
+	return ExitOK
+}
@@ -0,0 +1,448 @@
+// Package cli is the command-line adapter. It handles non-interactive
+// commands before (and instead of) starting the desktop UI.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/betoth/contractcheck/internal/adapter/diagnostics"
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+	"github.com/betoth/contractcheck/internal/version"
+)
+
+// Exit codes returned by Run.
+const (
+	ExitOK    = 0
+	ExitError = 1
+	ExitUsage = 2
+)
+
+// Deps are the use cases reachable from the command line.
+type Deps struct {
+	Diagnostics input.ExportDiagnostics
+	Bundle      input.BundleOpenAPISpec
+	Import      input.ImportOpenAPISpec
+	Dereference input.DereferenceOpenAPISpec
+	Writer      openapi.Writer
+	Format      input.FormatOpenAPISpec
+	Watch       input.WatchOpenAPISpec
+	Compare     input.CompareOpenAPISpecs
+	// Reporters are the extra -format values for commands that report
+	// findings (validate, diff), keyed by name, e.g. "sarif".
+	Reporters map[string]report.Writer
+	// Renderers are the extra -format values of diff that render the whole
+	// comparison for people, e.g. "html".
+	Renderers       map[string]report.ComparisonWriter
+	Changelog       input.GenerateChangelog
+	ChangelogWriter report.ChangelogWriter
+	Semver          input.RecommendVersion
+	Deprecations    input.CheckDeprecations
+	Pacts           input.VerifyPacts
+	TestGen         input.GenerateContractTests
+	Run             input.RunContractTests
+	Fuzz            input.FuzzAPI
+	Coverage        input.MeasureCoverage
+	CoverageWriter  report.CoverageWriter
+	Infer           input.InferOpenAPISpec
+	Drift           input.DetectDrift
+	Audit           input.AuditSecurity
+}
+
+// command is a single CLI subcommand.
+type command struct {
+	summary string
+	run     func(ctx context.Context, args []string, stdout, stderr io.Writer, deps Deps) int
+}
+
+var commands = map[string]command{
+	"bundle": {
+		summary: "merge a multi-file spec into one self-contained JSON/YAML document",
+		run:     runBundle,
+	},
+	"format": {
+		summary: "rewrite specs in canonical order; -check reports non-canonical files",
+		run:     runFormat,
+	},
+	"version": {
+		summary: "print version and build info (also --version)",
+		run:     runVersion,
+	},
+	"changelog": {
+		summary: "write a Keep a Changelog entry for the changes between two specs or revisions",
+		run:     runChangelog,
+	},
+	"diff": {
+		summary: "report contract changes between two specs or two git revisions of one",
+		run:     runDiff,
+	},
+	"semver": {
+		summary: "recommend the next info.version and flag bumps that understate the changes",
+		run:     runSemver,
+	},
+	"deprecations": {
+		summary: "track deprecations across revisions and fail on removals before their sunset",
+		run:     runDeprecations,
+	},
+	"pact": {
+		summary: "verify consumer contracts (Pact files) against a provider spec",
+		run:     runPact,
+	},
+	"testgen": {
+		summary: "generate Go contract tests that check a running server against the spec",
+		run:     runTestGen,
+	},
+	"run": {
+		summary: "call every operation on a running service and check the responses against the spec",
+		run:     runRun,
+	},
+	"fuzz": {
+		summary: "send mutated requests to a running service and check that invalid input is rejected",
+		run:     runFuzz,
+	},
+	"coverage": {
+		summary: "report which operations, status codes, parameters and enum values recorded traffic exercised",
+		run:     runCoverage,
+	},
+	"audit": {
+		summary: "audit security schemes and requirements (unsecured operations, scopes, API keys, TLS, OAuth URLs)",
+		run:     runAudit,
+	},
+	"drift": {
+		summary: "report where recorded traffic disagrees with the spec, most frequent first",
+		run:     runDrift,
+	},
+	"infer": {
+		summary: "draft an OpenAPI spec from recorded traffic (HAR files)",
+		run:     runInfer,
+	},
+	"dereference": {
+		summary: "inline all local $ref (flatten) for consumers that cannot follow refs",
+		run:     runDereference,
+	},
+	"watch": {
+		summary: "re-validate a spec and its referenced files on every change",
+		run:     runWatch,
+	},
+	"validate": {
+		summary: "load and validate a spec, reporting problems as findings",
+		run:     runValidate,
+	},
+	"diagnostics": {
+		summary: "export a diagnostics bundle (zip) for bug reports",
+		run:     runDiagnostics,
+	},
+}
+
+// IsCommand reports whether args (without the program name) request a CLI
+// command; otherwise the caller should start the desktop UI.
+func IsCommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	if isHelp(args[0]) || args[0] == "--version" {
+		return true
+	}
+	_, ok := commands[args[0]]
+	return ok
+}
+
+// Run executes the command in args and returns the process exit code.
+func Run(ctx context.Context, args []string, stdout, stderr io.Writer, deps Deps) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return ExitUsage
+	}
+	if isHelp(args[0]) {
+		usage(stdout)
+		return ExitOK
+	}
+	if args[0] == "--version" {
+		args[0] = "version"
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n\n", args[0])
+		usage(stderr)
+		return ExitUsage
+	}
+	return cmd.run(ctx, args[1:], stdout, stderr, deps)
+}
+
+func isHelp(arg string) bool {
+	return arg == "help" || arg == "-h" || arg == "--help"
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: contractcheck [command] [flags]")
+	fmt.Fprintln(w, "Without a command the desktop application starts.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-14s %s\n", name, commands[name].summary)
+	}
+}
+
+func runBundle(ctx context.Context, args []string, stdout, stderr io.Writer, deps Deps) int {
+	fs := flag.NewFlagSet("bundle", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	out := fs.String("o", "", "output file (default: stdout)")
+	formatFlag := fs.String("format", "", "output format: json or yaml (default: from -o extension, else json)")
+	sandbox := fs.String("sandbox", "", "directory external refs must stay within (default: the root spec's directory)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: contractcheck bundle [-o file] [-format json|yaml] [-sandbox dir] <root-spec>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return ExitUsage
+	}
+	if deps.Bundle == nil {
+		fmt.Fprintln(stderr, "bundle: service not configured")
+		return ExitError
+	}
+
+	format, err := outputFormat(*formatFlag, *out)
+	if err != nil {
+		fmt.Fprintf(stderr, "bundle: %v\n", err)
+		return ExitUsage
+	}
+
+	err = writeOutput(*out, stdout, func(w io.Writer) error {
+		_, err := deps.Bundle.Bundle(ctx, fs.Arg(0), w, format, input.BundleOptions{Sandbox: *sandbox})
+		return err
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "bundle: %v\n", err)
+		return ExitError
+	}
+	return ExitOK
+}
+
+func runDereference(ctx context.Context, args []string, stdout, stderr io.Writer, deps Deps) int {
+	fs := flag.NewFlagSet("dereference", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	out := fs.String("o", "", "output file (default: stdout)")
+	formatFlag := fs.String("format", "", "output format: json or yaml (default: from -o extension, else json)")
+	cycles := fs.String("cycles", string(openapi.CYCLE_ERROR), "recursive refs: error, keep-ref or truncate")
+	maxNodes := fs.Int("max-nodes", openapi.DEFAULT_MAX_NODES, "fail when the output exceeds this many nodes (negative = unlimited)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: contractcheck dereference [flags] <spec>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return ExitUsage
+	}
+	if deps.Import == nil || deps.Dereference == nil || deps.Writer == nil {
+		fmt.Fprintln(stderr, "dereference: service not configured")
+		return ExitError
+	}
+
+	format, err := outputFormat(*formatFlag, *out)
+	if err != nil {
+		fmt.Fprintf(stderr, "dereference: %v\n", err)
+		return ExitUsage
+	}
+	strategy, err := openapi.ParseCycleStrategy(*cycles)
+	if err != nil {
+		fmt.Fprintf(stderr, "dereference: %v\n", err)
+		return ExitUsage
+	}
+
+	doc, err := deps.Import.Import(ctx, fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "dereference: %v\n", err)
+		return ExitError
+	}
+	flat, stats, err := deps.Dereference.Dereference(ctx, doc, openapi.DerefOptions{Cycles: strategy, MaxNodes: *maxNodes})
+	if err != nil {
+		fmt.Fprintf(stderr, "dereference: %v\n", err)
+		return ExitError
+	}
+	err = writeOutput(*out, stdout, func(w io.Writer) error {
+		return deps.Writer.Write(w, flat, format)
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "dereference: %v\n", err)
+		return ExitError
+	}
+
+	fmt.Fprintf(stderr, "nodes %d -> %d (x%.1f), depth %d -> %d, inlined refs %d, cycles %d\n",
+		stats.InputNodes, stats.OutputNodes, stats.Growth(),
+		stats.InputDepth, stats.OutputDepth, stats.InlinedRefs, stats.Cycles)
+	return ExitOK
+}
+
+func runFormat(ctx context.Context, args []string, stdout, stderr io.Writer, deps Deps) int {
+	fs := flag.NewFlagSet("format", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	check := fs.Bool("check", false, "only report files that are not canonical (exit 1 if any)")
+	write := fs.Bool("w", false, "rewrite files in place")
+	formatFlag := fs.String("format", "", "output format: json or yaml (default: keep source format)")
+	strip := fs.Bool("strip-comments", false, "drop YAML comments")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: contractcheck format [-check | -w] [flags] <spec>...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if fs.NArg() == 0 || (*check && *write) || (!*check && !*write && fs.NArg() > 1) {
+		fs.Usage()
+		return ExitUsage
+	}
+	if deps.Format == nil {
+		fmt.Fprintln(stderr, "format: service not configured")
+		return ExitError
+	}
+
+	opts := openapi.FormatOptions{StripComments: *strip}
+	if *formatFlag != "" {
+		f, err := openapi.ParseFormat(*formatFlag)
+		if err != nil {
+			fmt.Fprintf(stderr, "format: %v\n", err)
+			return ExitUsage
+		}
+		opts.Format = f
+		// Rewriting in place must not leave JSON in a .yaml file or the reverse.
+		if *write {
+			for _, path := range fs.Args() {
+				if ext := filepath.Ext(path); ext == "" || !sameFormat(ext[1:], f) {
+					fmt.Fprintf(stderr, "format: %s: -w cannot write %s into this file; drop -format or write to stdout and redirect\n", path, f)
+					return ExitUsage
+				}
+			}
+		}
+	}
+
+	code := ExitOK
+	for _, path := range fs.Args() {
+		res, err := deps.Format.Format(ctx, path, opts)
+		if err != nil {
+			fmt.Fprintf(stderr, "format: %s: %v\n", path, err)
+			code = ExitError
+			continue
+		}
+		switch {
+		case *check:
+			if !res.Canonical {
+				fmt.Fprintln(stdout, path)
+				code = ExitError
+			}
+		case *write:
+			if res.Canonical {
+				continue
+			}
+			if err := os.WriteFile(path, res.Formatted, 0o644); err != nil {
+				fmt.Fprintf(stderr, "format: %s: %v\n", path, err)
+				code = ExitError
+			}
+		default:
+			if _, err := stdout.Write(res.Formatted); err != nil {
+				fmt.Fprintf(stderr, "format: %v\n", err)
+				return ExitError
+			}
+		}
+	}
+	return code
+}
+
+func runVersion(ctx context.Context, args []string, stdout, stderr io.Writer, deps Deps) int {
+	fs := flag.NewFlagSet("version", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	asJSON := fs.Bool("json", false, "print build info as JSON")
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	info := version.Get()
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(info); err != nil {
+			fmt.Fprintf(stderr, "version: %v\n", err)
+			return ExitError
+		}
+		return ExitOK
+	}
+	fmt.Fprintf(stdout, "contractcheck %s\n", info)
+	return ExitOK
+}
+
+func runDiagnostics(ctx context.Context, args []string, stdout, stderr io.Writer, deps Deps) int {
+	fs := flag.NewFlagSet("diagnostics", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	out := fs.String("o", diagnostics.FileName(time.Now()), "output zip path")
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if deps.Diagnostics == nil {
+		fmt.Fprintln(stderr, "diagnostics: exporter not configured")
+		return ExitError
+	}
+	if err := diagnostics.WriteFile(ctx, deps.Diagnostics, *out); err != nil {
+		fmt.Fprintf(stderr, "diagnostics: %v\n", err)
+		return ExitError
+	}
+	fmt.Fprintln(stdout, *out)
+	return ExitOK
+}
+
+// outputFormat resolves the spec format from an explicit flag or the output file extension.
+func outputFormat(flagValue, outPath string) (openapi.Format, error) {
+	if flagValue != "" {
+		return openapi.ParseFormat(flagValue)
+	}
+	if ext := filepath.Ext(outPath); ext != "" {
+		if f, err := openapi.ParseFormat(ext[1:]); err == nil {
+			return f, nil
+		}
+	}
+	return openapi.FORMAT_JSON, nil
+}
+
+// sameFormat reports whether a file extension (without the dot) names f.
+func sameFormat(ext string, f openapi.Format) bool {
+	got, err := openapi.ParseFormat(ext)
+	return err == nil && got == f
+}
+
+// writeOutput runs write against the file at path (removed on failure) or stdout when path is empty.
+func writeOutput(path string, stdout io.Writer, write func(io.Writer) error) (err error) {
+	if path == "" {
+		return write(stdout)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			_ = os.Remove(path)
+		}
+	}()
+	return write(f)
+}
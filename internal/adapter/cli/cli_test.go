@@ -0,0 +1,78 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/betoth/contractcheck/internal/adapter/cli"
+	kinopenapi "github.com/betoth/contractcheck/internal/adapter/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/service"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) With(...any) output.Logger  { return nopLogger{} }
+func (nopLogger) Named(string) output.Logger { return nopLogger{} }
+func (nopLogger) Info(string, ...any)        {}
+func (nopLogger) Warn(string, ...any)        {}
+func (nopLogger) Error(string, ...any)       {}
+func (nopLogger) Debug(string, ...any)       {}
+func (nopLogger) Sync() error                { return nil }
+
+func TestRun_FormatCheckFollowsExternalRefs(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "openapi.yaml")
+	write := func(path, body string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(root, `info: {version: "1.0", title: Pets}
+openapi: 3.0.3
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: "schemas.yaml#/Pet"}
+`)
+	write(filepath.Join(dir, "schemas.yaml"), "Pet: {type: object}\n")
+
+	format, err := service.NewOpenAPIFormatService(service.OpenAPIFormatParams{
+		LoaderFor: func(sandbox string) openapi.Loader {
+			return kinopenapi.NewKinLoader(kinopenapi.WithExternalRefsAllowed(), kinopenapi.WithSandbox(sandbox))
+		},
+		Formatter:     kinopenapi.NewYAMLFormatter(),
+		Logger:        nopLogger{},
+		VersionPolicy: service.NewOpenAPIVersionPolicy([]int{3}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deps := cli.Deps{Format: format}
+
+	run := func(args ...string) (int, string, string) {
+		var stdout, stderr bytes.Buffer
+		code := cli.Run(context.Background(), append([]string{"format"}, args...), &stdout, &stderr, deps)
+		return code, stdout.String(), stderr.String()
+	}
+
+	if code, stdout, stderr := run("-check", root); code != cli.ExitError || stdout != root+"\n" {
+		t.Fatalf("-check on a non-canonical spec: code=%d stdout=%q stderr=%q", code, stdout, stderr)
+	}
+	if code, _, stderr := run("-w", root); code != cli.ExitOK {
+		t.Fatalf("-w: code=%d stderr=%q", code, stderr)
+	}
+	if code, stdout, stderr := run("-check", root); code != cli.ExitOK || stdout != "" {
+		t.Fatalf("-check on the rewritten spec: code=%d stdout=%q stderr=%q", code, stdout, stderr)
+	}
+}
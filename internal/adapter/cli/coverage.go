@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+)
+
+func runCoverage(ctx context.Context, args []string, stdout, stderr io.Writer, deps Deps) int {
+	fs := flag.NewFlagSet("coverage", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	out := fs.String("o", "", "output file (default: stdout)")
+	format := fs.String("format", "json", "output format: json or html")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: contractcheck coverage [flags] <spec> <traffic.har|dir>...")
+		fmt.Fprintln(stderr, "Reports which paths, methods, status codes, parameters and enum values recorded traffic exercised")
+		fmt.Fprintln(stderr, "(HAR files from a browser, a proxy or a test run; directories are read for *.har).")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if fs.NArg() < 2 {
+		fs.Usage()
+		return ExitUsage
+	}
+	if *format != "json" && *format != "html" {
+		fmt.Fprintf(stderr, "coverage: unsupported format %q (want json, html)\n", *format)
+		return ExitUsage
+	}
+	if deps.Coverage == nil || (*format == "html" && deps.CoverageWriter == nil) {
+		fmt.Fprintln(stderr, "coverage: service not configured")
+		return ExitError
+	}
+	files, err := filesIn(fs.Args()[1:], "*.har", "HAR files")
+	if err != nil {
+		fmt.Fprintf(stderr, "coverage: %v\n", err)
+		return ExitError
+	}
+
+	cov, err := deps.Coverage.Coverage(ctx, fs.Arg(0), files)
+	if err != nil {
+		fmt.Fprintf(stderr, "coverage: %v\n", err)
+		return ExitError
+	}
+
+	err = writeOutput(*out, stdout, func(w io.Writer) error {
+		if *format == "json" {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(cov)
+		}
+		return deps.CoverageWriter.WriteCoverage(w, cov)
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "coverage: %v\n", err)
+		return ExitError
+	}
+	if *out != "" {
+		fmt.Fprintf(stderr, "coverage: %d exchange(s), %.1f%% of operations, %.1f%% of status codes covered\n",
+			cov.Exchanges, cov.Totals.Operations.Percent, cov.Totals.Statuses.Percent)
+	}
+	return ExitOK
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+func runDeprecations(ctx context.Context, args []string, stdout, stderr io.Writer, deps Deps) int {
+	fs := flag.NewFlagSet("deprecations", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	sel := addSpecSelection(fs)
+	out := fs.String("o", "", "output file (default: stdout)")
+	format := fs.String("format", "text", "output format: "+strings.Join(findingFormats(deps), ", "))
+	ledger := fs.String("ledger", "", "JSON file recording when elements were first deprecated (created if missing)")
+	frozen := fs.Bool("frozen", false, "read the ledger without recording this run")
+	grace := fs.Int("grace-days", 90, "minimum days between deprecation and removal (0 disables the check)")
+	date := fs.String("date", "", "check as of this date, YYYY-MM-DD (default: today)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: contractcheck deprecations [flags] <base-spec> <head-spec>")
+		fmt.Fprintln(stderr, "       contractcheck deprecations -target <branch> [-head rev] [-repo dir] [flags] <spec>")
+		fmt.Fprintln(stderr, "Exits with 1 when an element is removed without deprecation, before its grace period or before its sunset.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if !sel.validArgs(fs) {
+		fs.Usage()
+		return ExitUsage
+	}
+	if err := checkFormat(*format, findingFormats(deps)); err != nil {
+		fmt.Fprintf(stderr, "deprecations: %v\n", err)
+		return ExitUsage
+	}
+	if *grace < 0 {
+		fmt.Fprintf(stderr, "deprecations: invalid -grace-days %d\n", *grace)
+		return ExitUsage
+	}
+	if *date != "" {
+		if _, err := time.Parse(time.DateOnly, *date); err != nil {
+			fmt.Fprintf(stderr, "deprecations: invalid -date %q (want YYYY-MM-DD)\n", *date)
+			return ExitUsage
+		}
+	}
+	if deps.Deprecations == nil {
+		fmt.Fprintln(stderr, "deprecations: service not configured")
+		return ExitError
+	}
+
+	opts := input.DeprecationOptions{Ledger: *ledger, Frozen: *frozen, GraceDays: *grace, Date: *date}
+	var (
+		rep report.DeprecationReport
+		err error
+	)
+	if sel.git() {
+		rep, err = deps.Deprecations.CheckGit(ctx, sel.gitRequest(fs), opts)
+	} else {
+		rep, err = deps.Deprecations.CheckFiles(ctx, fs.Arg(0), fs.Arg(1), opts)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "deprecations: %v\n", err)
+		return ExitError
+	}
+
+	headFile := fs.Arg(fs.NArg() - 1)
+	err = writeOutput(*out, stdout, func(w io.Writer) error {
+		if err := writeReport(w, *format, rep.Report(headFile), deps); err != nil {
+			return err
+		}
+		if *format == "text" {
+			_, err := fmt.Fprintf(w, "%s -> %s: %d finding(s), %d deprecated element(s) tracked\n",
+				rep.Base, rep.Head, len(rep.Findings), len(rep.Ledger))
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "deprecations: %v\n", err)
+		return ExitError
+	}
+	if hasErrors(rep.Findings) {
+		return ExitError
+	}
+	return ExitOK
+}
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+func runDiff(ctx context.Context, args []string, stdout, stderr io.Writer, deps Deps) int {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	sel := addSpecSelection(fs)
+	out := fs.String("o", "", "output file (default: stdout)")
+	format := fs.String("format", "text", "output format: "+strings.Join(comparisonFormats(deps), ", "))
+	failOn := fs.String("fail-on", "breaking", "exit with 1 on: breaking, any or none")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: contractcheck diff [flags] <base-spec> <head-spec>")
+		fmt.Fprintln(stderr, "       contractcheck diff -target <branch> [-head rev] [-repo dir] [flags] <spec>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if !sel.validArgs(fs) {
+		fs.Usage()
+		return ExitUsage
+	}
+	if *failOn != "breaking" && *failOn != "any" && *failOn != "none" {
+		fmt.Fprintf(stderr, "diff: invalid -fail-on %q\n", *failOn)
+		return ExitUsage
+	}
+	if err := checkFormat(*format, comparisonFormats(deps)); err != nil {
+		fmt.Fprintf(stderr, "diff: %v\n", err)
+		return ExitUsage
+	}
+	if deps.Compare == nil {
+		fmt.Fprintln(stderr, "diff: service not configured")
+		return ExitError
+	}
+
+	cmp, err := sel.compare(ctx, fs, deps.Compare)
+	if err != nil {
+		fmt.Fprintf(stderr, "diff: %v\n", err)
+		return ExitError
+	}
+
+	// Report writers annotate the head spec as the user named it; git
+	// labels ("<commit>:<path>") are not file paths.
+	headFile := fs.Arg(fs.NArg() - 1)
+	err = writeOutput(*out, stdout, func(w io.Writer) error {
+		switch *format {
+		case "text":
+			printComparison(w, cmp)
+			return nil
+		case "json":
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(cmp)
+		default:
+			if r, ok := deps.Renderers[*format]; ok {
+				return r.WriteComparison(w, cmp)
+			}
+			return writeReport(w, *format, cmp.Report(headFile), deps)
+		}
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "diff: %v\n", err)
+		return ExitError
+	}
+
+	switch {
+	case *failOn == "breaking" && len(cmp.Breaking()) > 0:
+		return ExitError
+	case *failOn == "any" && len(cmp.Changes) > 0:
+		return ExitError
+	}
+	return ExitOK
+}
+
+func printComparison(w io.Writer, cmp report.Comparison) {
+	fmt.Fprintf(w, "%s -> %s: %d change(s), %d breaking\n", cmp.Base, cmp.Head, len(cmp.Changes), len(cmp.Breaking()))
+	for _, ch := range cmp.Changes {
+		fmt.Fprintf(w, "  %s\n", ch)
+	}
+}
+
+// specSelection holds the flags shared by commands that compare two
+// versions of a spec: two files, or one file at two git revisions.
+type specSelection struct {
+	target *string
+	head   *string
+	repo   *string
+}
+
+func addSpecSelection(fs *flag.FlagSet) specSelection {
+	return specSelection{
+		target: fs.String("target", "", "git mode: branch the change merges into; the base is its merge-base with -head"),
+		head:   fs.String("head", "HEAD", "git mode: revision under review"),
+		repo:   fs.String("repo", ".", "git mode: directory inside the repository"),
+	}
+}
+
+// validArgs reports whether the positional args match the mode: two specs,
+// or one spec with -target.
+func (s specSelection) validArgs(fs *flag.FlagSet) bool {
+	if !s.git() {
+		return fs.NArg() == 2
+	}
+	return fs.NArg() == 1
+}
+
+// git reports whether the selection is git mode.
+func (s specSelection) git() bool { return *s.target != "" }
+
+// gitRequest builds the git mode request for the spec in fs's arguments.
+func (s specSelection) gitRequest(fs *flag.FlagSet) input.GitCompareRequest {
+	return input.GitCompareRequest{
+		Repo:   *s.repo,
+		Path:   fs.Arg(0),
+		Target: *s.target,
+		Head:   *s.head,
+	}
+}
+
+func (s specSelection) compare(ctx context.Context, fs *flag.FlagSet, svc input.CompareOpenAPISpecs) (report.Comparison, error) {
+	if !s.git() {
+		return svc.Compare(ctx, fs.Arg(0), fs.Arg(1))
+	}
+	return svc.CompareGit(ctx, s.gitRequest(fs))
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+func runDrift(ctx context.Context, args []string, stdout, stderr io.Writer, deps Deps) int {
+	fs := flag.NewFlagSet("drift", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	out := fs.String("o", "", "output file (default: stdout)")
+	format := fs.String("format", "text", "output format: "+strings.Join(findingFormats(deps), ", "))
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: contractcheck drift [flags] <spec> <traffic.har|dir>...")
+		fmt.Fprintln(stderr, "Reports where recorded traffic disagrees with the spec (undocumented operations, status codes,")
+		fmt.Fprintln(stderr, "query parameters and fields; documented fields never seen), most frequent first.")
+		fmt.Fprintln(stderr, "json writes the whole report with counts; the other formats write the findings.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if fs.NArg() < 2 {
+		fs.Usage()
+		return ExitUsage
+	}
+	if err := checkFormat(*format, findingFormats(deps)); err != nil {
+		fmt.Fprintf(stderr, "drift: %v\n", err)
+		return ExitUsage
+	}
+	if deps.Drift == nil {
+		fmt.Fprintln(stderr, "drift: service not configured")
+		return ExitError
+	}
+	files, err := filesIn(fs.Args()[1:], "*.har", "HAR files")
+	if err != nil {
+		fmt.Fprintf(stderr, "drift: %v\n", err)
+		return ExitError
+	}
+
+	drift, err := deps.Drift.Drift(ctx, fs.Arg(0), files)
+	if err != nil {
+		fmt.Fprintf(stderr, "drift: %v\n", err)
+		return ExitError
+	}
+
+	err = writeOutput(*out, stdout, func(w io.Writer) error {
+		switch *format {
+		case "json":
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(drift)
+		case "text":
+			if err := writeReport(w, *format, drift.Report(), deps); err != nil {
+				return err
+			}
+			_, err := fmt.Fprintf(w, "%s: %d exchange(s), %d drift(s)\n", drift.Spec, drift.Exchanges, len(drift.Drifts))
+			return err
+		}
+		return writeReport(w, *format, drift.Report(), deps)
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "drift: %v\n", err)
+		return ExitError
+	}
+	return ExitOK
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+// findingFormats lists the -format values accepted by commands that report
+// findings: the built-in text and json, plus every configured reporter.
+func findingFormats(deps Deps) []string {
+	names := []string{"text", "json"}
+	extra := make([]string, 0, len(deps.Reporters))
+	for name := range deps.Reporters {
+		extra = append(extra, name)
+	}
+	sort.Strings(extra)
+	return append(names, extra...)
+}
+
+// comparisonFormats lists the -format values of diff: the finding formats
+// plus every configured comparison renderer.
+func comparisonFormats(deps Deps) []string {
+	extra := make([]string, 0, len(deps.Renderers))
+	for name := range deps.Renderers {
+		extra = append(extra, name)
+	}
+	sort.Strings(extra)
+	return append(findingFormats(deps), extra...)
+}
+
+// checkFormat rejects a format that is not in formats.
+func checkFormat(format string, formats []string) error {
+	for _, name := range formats {
+		if name == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported format %q (want %s)", format, strings.Join(formats, ", "))
+}
+
+// writeReport renders r in format; callers validate it first. The built-in
+// formats print the findings only.
+func writeReport(w io.Writer, format string, r report.Report, deps Deps) error {
+	findings := r.Findings
+	switch format {
+	case "text":
+		for _, f := range findings {
+			if _, err := fmt.Fprintln(w, f); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if findings == nil {
+			findings = []report.Finding{}
+		}
+		return enc.Encode(findings)
+	default:
+		return deps.Reporters[format].Write(w, r)
+	}
+}
+
+// hasErrors reports whether any finding has error severity.
+func hasErrors(findings []report.Finding) bool {
+	for _, f := range findings {
+		if f.Severity == report.SEVERITY_ERROR {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+)
+
+func runFuzz(ctx context.Context, args []string, stdout, stderr io.Writer, deps Deps) int {
+	fs := flag.NewFlagSet("fuzz", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	out := fs.String("o", "", "output file (default: stdout)")
+	format := fs.String("format", "text", "output format: "+strings.Join(findingFormats(deps), ", "))
+	baseURL := fs.String("base-url", "", "service to call (default: the spec's first absolute server URL)")
+	concurrency := fs.Int("concurrency", 0, "operations fuzzed at once (default 4)")
+	seed := fs.Int64("seed", 0, "seed of the random choices, to replay a run (default: random)")
+	cases := fs.Int("cases", 0, "most mutated requests per operation (default 50)")
+	credentials := credentialFlags{}
+	fs.Var(credentials, "auth", "credentials for a security scheme, `scheme=value` or scheme=env:VAR (repeatable)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: contractcheck fuzz [flags] <spec>")
+		fmt.Fprintln(stderr, "Sends mutated requests (boundary values, wrong types, missing required fields, oversized strings,")
+		fmt.Fprintln(stderr, "invalid enums) to a running service. Exits with 1 when invalid input is accepted or a 5xx is undocumented.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return ExitUsage
+	}
+	if err := checkFormat(*format, findingFormats(deps)); err != nil {
+		fmt.Fprintf(stderr, "fuzz: %v\n", err)
+		return ExitUsage
+	}
+	if deps.Fuzz == nil {
+		fmt.Fprintln(stderr, "fuzz: service not configured")
+		return ExitError
+	}
+
+	run, err := deps.Fuzz.Fuzz(ctx, fs.Arg(0), input.FuzzOptions{
+		BaseURL:     *baseURL,
+		Credentials: credentials,
+		Concurrency: *concurrency,
+		Seed:        *seed,
+		Cases:       *cases,
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "fuzz: %v\n", err)
+		return ExitError
+	}
+
+	rep := run.Report()
+	err = writeOutput(*out, stdout, func(w io.Writer) error {
+		if err := writeReport(w, *format, rep, deps); err != nil {
+			return err
+		}
+		if *format != "text" {
+			return nil
+		}
+		for _, f := range run.Failures {
+			if _, err := fmt.Fprintf(w, "  %s %s -> %d\n", f.Method, shortenLine(f.URL), f.Status); err != nil {
+				return err
+			}
+		}
+		_, err := fmt.Fprintf(w, "%s: %d case(s) on %d operation(s), %d failure(s); replay with -seed %d\n",
+			run.BaseURL, run.Cases, run.Operations, len(run.Failures), run.Seed)
+		return err
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "fuzz: %v\n", err)
+		return ExitError
+	}
+	if hasErrors(rep.Findings) {
+		return ExitError
+	}
+	return ExitOK
+}
+
+// shortenLine keeps URLs with oversized values readable in text output.
+func shortenLine(s string) string {
+	const max = 120
+	if len(s) > max {
+		return s[:max] + "..."
+	}
+	return s
+}
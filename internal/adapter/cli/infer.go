@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+)
+
+func runInfer(ctx context.Context, args []string, stdout, stderr io.Writer, deps Deps) int {
+	fs := flag.NewFlagSet("infer", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	out := fs.String("o", "", "output file (default: stdout)")
+	formatFlag := fs.String("format", "", "output format: json or yaml (default: from -o extension, else json)")
+	baseURL := fs.String("base-url", "", "service whose traffic is used (default: the origin most requests went to)")
+	title := fs.String("title", "", "info.title of the draft (default: named after the host)")
+	version := fs.String("version", "", "info.version of the draft (default 0.1.0)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: contractcheck infer [flags] <traffic.har|dir>...")
+		fmt.Fprintln(stderr, "Drafts an OpenAPI 3 spec from recorded traffic (HAR files; directories are read for *.har):")
+		fmt.Fprintln(stderr, "IDs in URLs become path parameters and schemas are inferred from the bodies.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return ExitUsage
+	}
+	format, err := outputFormat(*formatFlag, *out)
+	if err != nil {
+		fmt.Fprintf(stderr, "infer: %v\n", err)
+		return ExitUsage
+	}
+	if deps.Infer == nil || deps.Writer == nil {
+		fmt.Fprintln(stderr, "infer: service not configured")
+		return ExitError
+	}
+	files, err := filesIn(fs.Args(), "*.har", "HAR files")
+	if err != nil {
+		fmt.Fprintf(stderr, "infer: %v\n", err)
+		return ExitError
+	}
+
+	doc, err := deps.Infer.Infer(ctx, files, input.InferOptions{BaseURL: *baseURL, Title: *title, Version: *version})
+	if err != nil {
+		fmt.Fprintf(stderr, "infer: %v\n", err)
+		return ExitError
+	}
+	err = writeOutput(*out, stdout, func(w io.Writer) error {
+		return deps.Writer.Write(w, doc, format)
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "infer: %v\n", err)
+		return ExitError
+	}
+	if doc.Model != nil {
+		fmt.Fprintf(stderr, "infer: drafted %d operation(s)\n", len(doc.Model.Operations))
+	}
+	return ExitOK
+}
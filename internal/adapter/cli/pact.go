@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func runPact(ctx context.Context, args []string, stdout, stderr io.Writer, deps Deps) int {
+	fs := flag.NewFlagSet("pact", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	out := fs.String("o", "", "output file (default: stdout)")
+	format := fs.String("format", "text", "output format: "+strings.Join(findingFormats(deps), ", "))
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: contractcheck pact [flags] <provider-spec> <pact.json|dir>...")
+		fmt.Fprintln(stderr, "Verifies consumer contracts (Pact v2-v4 files; directories are read for *.json) against the provider spec.")
+		fmt.Fprintln(stderr, "Exits with 1 when an interaction does not conform to the spec.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if fs.NArg() < 2 {
+		fs.Usage()
+		return ExitUsage
+	}
+	if err := checkFormat(*format, findingFormats(deps)); err != nil {
+		fmt.Fprintf(stderr, "pact: %v\n", err)
+		return ExitUsage
+	}
+	if deps.Pacts == nil {
+		fmt.Fprintln(stderr, "pact: service not configured")
+		return ExitError
+	}
+	pacts, err := filesIn(fs.Args()[1:], "*.json", "Pact files")
+	if err != nil {
+		fmt.Fprintf(stderr, "pact: %v\n", err)
+		return ExitError
+	}
+
+	ver, err := deps.Pacts.Verify(ctx, fs.Arg(0), pacts)
+	if err != nil {
+		fmt.Fprintf(stderr, "pact: %v\n", err)
+		return ExitError
+	}
+
+	rep := ver.Report()
+	err = writeOutput(*out, stdout, func(w io.Writer) error {
+		if err := writeReport(w, *format, rep, deps); err != nil {
+			return err
+		}
+		if *format != "text" {
+			return nil
+		}
+		for _, p := range ver.Pacts {
+			var err error
+			if p.Consumer == "" {
+				_, err = fmt.Fprintf(w, "%s: not verified\n", p.File)
+			} else {
+				_, err = fmt.Fprintf(w, "%s -> %s (%s): %d interaction(s), %d failing, %d skipped\n",
+					p.Consumer, p.Provider, p.File, p.Interactions, p.Failing, p.Skipped)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "pact: %v\n", err)
+		return ExitError
+	}
+	if hasErrors(rep.Findings) {
+		return ExitError
+	}
+	return ExitOK
+}
+
+// filesIn expands directory arguments to the files matching pattern they
+// contain, sorted; file arguments are kept as given. kind names the files
+// in errors, e.g. "Pact files".
+func filesIn(args []string, pattern, kind string) ([]string, error) {
+	var files []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil || !info.IsDir() {
+			files = append(files, arg)
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(arg, pattern))
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no %s (%s) in %s", kind, pattern, arg)
+		}
+		files = append(files, matches...)
+	}
+	return files, nil
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+// credentialFlags collects repeated -auth scheme=value flags. A value of
+// the form "env:NAME" is read from the environment, so secrets stay out of
+// shell history and process listings.
+type credentialFlags map[string]string
+
+// String lists the scheme names only, never the values.
+func (c credentialFlags) String() string {
+	names := make([]string, 0, len(c))
+	for name := range c {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+func (c credentialFlags) Set(s string) error {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("want scheme=value, got %q", s)
+	}
+	if env, fromEnv := strings.CutPrefix(value, "env:"); fromEnv {
+		v, set := os.LookupEnv(env)
+		if !set {
+			return fmt.Errorf("environment variable %s is not set", env)
+		}
+		value = v
+	}
+	c[name] = value
+	return nil
+}
+
+func runRun(ctx context.Context, args []string, stdout, stderr io.Writer, deps Deps) int {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	out := fs.String("o", "", "output file (default: stdout)")
+	format := fs.String("format", "text", "output format: "+strings.Join(findingFormats(deps), ", "))
+	baseURL := fs.String("base-url", "", "service to call (default: the spec's first absolute server URL)")
+	concurrency := fs.Int("concurrency", 0, "requests in flight at once (default 4)")
+	credentials := credentialFlags{}
+	fs.Var(credentials, "auth", "credentials for a security scheme, `scheme=value` or scheme=env:VAR (repeatable)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: contractcheck run [flags] <spec>")
+		fmt.Fprintln(stderr, "Calls every operation of the spec on a running service and checks the responses against it.")
+		fmt.Fprintln(stderr, "Operations are ordered by x-contractcheck-depends-on. Exits with 1 when a response does not conform.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return ExitUsage
+	}
+	if err := checkFormat(*format, findingFormats(deps)); err != nil {
+		fmt.Fprintf(stderr, "run: %v\n", err)
+		return ExitUsage
+	}
+	if deps.Run == nil {
+		fmt.Fprintln(stderr, "run: service not configured")
+		return ExitError
+	}
+
+	run, err := deps.Run.Run(ctx, fs.Arg(0), input.RunOptions{
+		BaseURL:     *baseURL,
+		Credentials: credentials,
+		Concurrency: *concurrency,
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "run: %v\n", err)
+		return ExitError
+	}
+
+	rep := run.Report()
+	err = writeOutput(*out, stdout, func(w io.Writer) error {
+		if err := writeReport(w, *format, rep, deps); err != nil {
+			return err
+		}
+		if *format != "text" {
+			return nil
+		}
+		_, err := fmt.Fprintf(w, "%s: %d operation(s), %d passed, %d failed, %d skipped\n",
+			run.BaseURL, len(run.Operations), run.Count(report.OUTCOME_PASSED), run.Count(report.OUTCOME_FAILED), run.Count(report.OUTCOME_SKIPPED))
+		return err
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "run: %v\n", err)
+		return ExitError
+	}
+	if hasErrors(rep.Findings) {
+		return ExitError
+	}
+	return ExitOK
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+func runSemver(ctx context.Context, args []string, stdout, stderr io.Writer, deps Deps) int {
+	fs := flag.NewFlagSet("semver", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	sel := addSpecSelection(fs)
+	out := fs.String("o", "", "output file (default: stdout)")
+	format := fs.String("format", "text", "output format: text or json")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: contractcheck semver [flags] <base-spec> <head-spec>")
+		fmt.Fprintln(stderr, "       contractcheck semver -target <branch> [-head rev] [-repo dir] [flags] <spec>")
+		fmt.Fprintln(stderr, "Exits with 1 when the head spec's info.version understates the changes.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if !sel.validArgs(fs) {
+		fs.Usage()
+		return ExitUsage
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(stderr, "semver: unsupported format %q (want text, json)\n", *format)
+		return ExitUsage
+	}
+	if deps.Compare == nil || deps.Semver == nil {
+		fmt.Fprintln(stderr, "semver: service not configured")
+		return ExitError
+	}
+
+	cmp, err := sel.compare(ctx, fs, deps.Compare)
+	if err != nil {
+		fmt.Fprintf(stderr, "semver: %v\n", err)
+		return ExitError
+	}
+	advice, err := deps.Semver.RecommendVersion(ctx, cmp)
+	if err != nil {
+		fmt.Fprintf(stderr, "semver: %v\n", err)
+		return ExitError
+	}
+
+	err = writeOutput(*out, stdout, func(w io.Writer) error {
+		if *format == "json" {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(advice)
+		}
+		printVersionAdvice(w, advice)
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "semver: %v\n", err)
+		return ExitError
+	}
+	if advice.Understated {
+		return ExitError
+	}
+	return ExitOK
+}
+
+func printVersionAdvice(w io.Writer, a report.VersionAdvice) {
+	fmt.Fprintf(w, "%s -> %s: %s -> %s\n", a.Base, a.Head, orNone(a.BaseVersion), orNone(a.HeadVersion))
+	fmt.Fprintf(w, "  required bump: %s", a.Required)
+	if len(a.Reasons) > 0 {
+		fmt.Fprintf(w, " (%s)", strings.Join(a.Reasons, ", "))
+	}
+	fmt.Fprintln(w)
+	if a.Declared != "" {
+		fmt.Fprintf(w, "  declared bump: %s\n", a.Declared)
+	}
+	if a.Recommended != "" {
+		fmt.Fprintf(w, "  recommended version: %s\n", a.Recommended)
+	}
+	for _, n := range a.Notes {
+		fmt.Fprintf(w, "  note: %s\n", n)
+	}
+	if a.Understated {
+		fmt.Fprintf(w, "  UNDERSTATED: a %s bump does not cover changes that need a %s bump\n", a.Declared, a.Required)
+	}
+}
+
+func orNone(v string) string {
+	if v == "" {
+		return "(none)"
+	}
+	return v
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+)
+
+func runTestGen(ctx context.Context, args []string, stdout, stderr io.Writer, deps Deps) int {
+	fs := flag.NewFlagSet("testgen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	dir := fs.String("o", "contract", "output directory")
+	pkg := fs.String("package", "", "package name of the generated files (default: contract)")
+	check := fs.Bool("check", false, "only report generated files that are missing or out of date (exit 1 if any)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: contractcheck testgen [flags] <spec>")
+		fmt.Fprintln(stderr, "Generates Go contract tests: one per operation and declared response.")
+		fmt.Fprintln(stderr, "Run them with CONTRACTCHECK_BASE_URL set to the server under test.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return ExitUsage
+	}
+	if deps.TestGen == nil {
+		fmt.Fprintln(stderr, "testgen: service not configured")
+		return ExitError
+	}
+
+	gen, err := deps.TestGen.Generate(ctx, fs.Arg(0), input.TestGenOptions{Package: *pkg})
+	if err != nil {
+		fmt.Fprintf(stderr, "testgen: %v\n", err)
+		return ExitError
+	}
+
+	if *check {
+		code := ExitOK
+		for _, f := range gen.Files {
+			path := filepath.Join(*dir, f.Name)
+			if current, err := os.ReadFile(path); err != nil || !bytes.Equal(current, f.Content) {
+				fmt.Fprintln(stdout, path)
+				code = ExitError
+			}
+		}
+		return code
+	}
+
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		fmt.Fprintf(stderr, "testgen: %v\n", err)
+		return ExitError
+	}
+	for _, f := range gen.Files {
+		if err := os.WriteFile(filepath.Join(*dir, f.Name), f.Content, 0o644); err != nil {
+			fmt.Fprintf(stderr, "testgen: %v\n", err)
+			return ExitError
+		}
+	}
+	fmt.Fprintf(stdout, "%s: %d test(s) in %d file(s), %d skipped\n", *dir, gen.Tests, len(gen.Files), gen.Skipped)
+	return ExitOK
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+func runValidate(ctx context.Context, args []string, stdout, stderr io.Writer, deps Deps) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	out := fs.String("o", "", "output file (default: stdout)")
+	format := fs.String("format", "text", "output format: "+strings.Join(findingFormats(deps), ", "))
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: contractcheck validate [-format name] [-o file] <spec>")
+		fmt.Fprintln(stderr, "Loads and validates the spec; exits with 1 when it has errors.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return ExitUsage
+	}
+	if err := checkFormat(*format, findingFormats(deps)); err != nil {
+		fmt.Fprintf(stderr, "validate: %v\n", err)
+		return ExitUsage
+	}
+	if deps.Import == nil {
+		fmt.Fprintln(stderr, "validate: service not configured")
+		return ExitError
+	}
+
+	// A spec that fails to load is a finding, not a command failure: report
+	// writers still get a well-formed document to publish.
+	subject := report.Subject{File: fs.Arg(0), Checks: []string{report.CHECK_LOAD}}
+	var findings []report.Finding
+	doc, err := deps.Import.Import(ctx, fs.Arg(0))
+	switch {
+	case err != nil && ctx.Err() != nil:
+		fmt.Fprintf(stderr, "validate: %v\n", err)
+		return ExitError
+	case err != nil:
+		findings = append(findings, report.FromError(err))
+	case doc.Model != nil:
+		for _, op := range doc.Model.Operations {
+			subject.Operations = append(subject.Operations, op.Key())
+		}
+	}
+
+	rep := report.Report{Subjects: []report.Subject{subject}, Findings: findings}
+	err = writeOutput(*out, stdout, func(w io.Writer) error {
+		return writeReport(w, *format, rep, deps)
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "validate: %v\n", err)
+		return ExitError
+	}
+	if *format == "text" && len(findings) == 0 && *out == "" {
+		fmt.Fprintf(stdout, "%s: valid\n", fs.Arg(0))
+	}
+	if hasErrors(findings) {
+		return ExitError
+	}
+	return ExitOK
+}
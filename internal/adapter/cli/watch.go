@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+)
+
+func runWatch(ctx context.Context, args []string, stdout, stderr io.Writer, deps Deps) int {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	asJSON := fs.Bool("json", false, "print one JSON object per run instead of text")
+	base := fs.String("base", "", "also diff every run against this spec file, or the spec at this git revision")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: contractcheck watch [-json] [-base <spec|rev>] <spec>")
+		fmt.Fprintln(stderr, "Re-validates, lints, audits and (with -base) diffs the spec whenever it or a referenced file changes; stop with Ctrl+C.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return ExitUsage
+	}
+	if deps.Watch == nil {
+		fmt.Fprintln(stderr, "watch: service not configured")
+		return ExitError
+	}
+
+	emit := func(r input.WatchResult) { printWatchResult(stdout, r) }
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		emit = func(r input.WatchResult) { _ = enc.Encode(r) }
+	}
+	opts := input.WatchOptions{Base: baseline(*base)}
+	if err := deps.Watch.Watch(ctx, fs.Arg(0), opts, emit); err != nil {
+		fmt.Fprintf(stderr, "watch: %v\n", err)
+		return ExitError
+	}
+	return ExitOK
+}
+
+// baseline reads -base: an existing file is a spec, anything else a git revision.
+func baseline(base string) input.Baseline {
+	if base == "" {
+		return input.Baseline{}
+	}
+	if info, err := os.Stat(base); err == nil && !info.IsDir() {
+		return input.Baseline{File: base}
+	}
+	return input.Baseline{Rev: base}
+}
+
+// printWatchResult prints only what changed: "+" for new findings and "-"
+// for resolved ones.
+func printWatchResult(w io.Writer, r input.WatchResult) {
+	trigger := r.File
+	if len(r.Changed) > 0 {
+		names := make([]string, len(r.Changed))
+		for i, c := range r.Changed {
+			names[i] = filepath.Base(c)
+		}
+		trigger = "changed " + strings.Join(names, ", ")
+	}
+	fmt.Fprintf(w, "[%s] run %d (%s): %d finding(s), +%d -%d in %s\n",
+		time.Now().Format("15:04:05"), r.Run, trigger, r.Delta.Total,
+		len(r.Delta.Added), len(r.Delta.Removed), r.Duration.Round(time.Millisecond))
+	for _, f := range r.Delta.Added {
+		fmt.Fprintf(w, "  + %s\n", f)
+	}
+	for _, f := range r.Delta.Removed {
+		fmt.Fprintf(w, "  - %s\n", f)
+	}
+}
@@ -0,0 +1,29 @@
+package compatreport
+
+import (
+	"io"
+	texttemplate "text/template"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+var changelogMarkdown = texttemplate.Must(texttemplate.New("changelog.md.tmpl").Funcs(texttemplate.FuncMap{
+	"code": mdCode,
+	"text": mdText,
+}).ParseFS(templates, "templates/changelog.md.tmpl"))
+
+// ChangelogWriter renders a changelog as a Keep a Changelog Markdown document.
+type ChangelogWriter struct{}
+
+// NewChangelogWriter builds a ChangelogWriter.
+func NewChangelogWriter() *ChangelogWriter {
+	return &ChangelogWriter{}
+}
+
+// WriteChangelog renders c; spec content is escaped for Markdown.
+func (w *ChangelogWriter) WriteChangelog(out io.Writer, c report.Changelog) error {
+	return changelogMarkdown.Execute(out, c)
+}
+
+// compile-time check
+var _ report.ChangelogWriter = (*ChangelogWriter)(nil)
@@ -0,0 +1,42 @@
+package compatreport_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/betoth/contractcheck/internal/adapter/compatreport"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+func TestChangelogWriter_Golden(t *testing.T) {
+	cases := []struct {
+		name string
+		cl   report.Changelog
+	}{
+		{"changelog_release", report.Changelog{
+			Title: "Petstore", Version: "1.5.0", Date: "2026-10-18", Detail: report.CHANGELOG_FIELDS, Breaking: 1,
+			Sections: []report.ChangelogSection{
+				{Category: report.CHANGELOG_ADDED, Entries: []report.ChangelogEntry{
+					{Operation: "GET /owners", Changes: []string{"operation-added"}},
+					{Operation: "GET /pets/{id}", Text: `optional query parameter "fields" was added`, Changes: []string{"parameter-added"}},
+				}},
+				{Category: report.CHANGELOG_DEPRECATED, Entries: []report.ChangelogEntry{
+					{Operation: "GET /pets/{id}", Text: "property status was deprecated (response 200 application/json)", Changes: []string{"property-deprecated"}},
+				}},
+				{Category: report.CHANGELOG_REMOVED, Entries: []report.ChangelogEntry{
+					{Operation: "DELETE /pets/{id}", Breaking: true, Changes: []string{"operation-removed"}},
+				}},
+			},
+		}},
+		{"changelog_unreleased", report.Changelog{Detail: report.CHANGELOG_ENDPOINTS, Sections: []report.ChangelogSection{}}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := compatreport.NewChangelogWriter().WriteChangelog(&buf, tc.cl); err != nil {
+				t.Fatal(err)
+			}
+			golden(t, tc.name+".md", buf.Bytes())
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package compatreport
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+var coverageReport = htmltemplate.Must(htmltemplate.New("coverage.html.tmpl").Funcs(htmltemplate.FuncMap{
+	"percent": coveragePercent,
+	"level":   coverageLevel,
+}).ParseFS(templates, "templates/coverage.html.tmpl"))
+
+// CoverageHTMLWriter renders a coverage report as a single HTML page.
+type CoverageHTMLWriter struct {
+	cfg config
+}
+
+// NewCoverageHTMLWriter builds a CoverageHTMLWriter with safe defaults.
+func NewCoverageHTMLWriter(opts ...Option) *CoverageHTMLWriter {
+	return &CoverageHTMLWriter{cfg: newConfig(DefaultCoverageTitle, opts)}
+}
+
+// coverageView is the template model.
+type coverageView struct {
+	Title string
+	report.Coverage
+}
+
+// WriteCoverage renders c; spec content is escaped by html/template.
+func (w *CoverageHTMLWriter) WriteCoverage(out io.Writer, c report.Coverage) error {
+	return coverageReport.Execute(out, coverageView{Title: w.cfg.title, Coverage: c})
+}
+
+// coveragePercent shows a count as "75.0%", or a dash when there was
+// nothing to cover.
+func coveragePercent(c report.CoverageCount) string {
+	if c.Total == 0 {
+		return "–"
+	}
+	return fmt.Sprintf("%.1f%%", c.Percent)
+}
+
+// coverageLevel colours a count: "good" from 80%, "fair" from 50%.
+func coverageLevel(c report.CoverageCount) string {
+	switch {
+	case c.Total == 0:
+		return "none"
+	case c.Percent >= 80:
+		return "good"
+	case c.Percent >= 50:
+		return "fair"
+	}
+	return "poor"
+}
+
+// compile-time check
+var _ report.CoverageWriter = (*CoverageHTMLWriter)(nil)
@@ -0,0 +1,70 @@
+package compatreport_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/betoth/contractcheck/internal/adapter/compatreport"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+func petCoverage() report.Coverage {
+	totals := func(ops, statuses, params, values [2]int) report.CoverageTotals {
+		return report.CoverageTotals{
+			Operations: report.NewCoverageCount(ops[0], ops[1]),
+			Statuses:   report.NewCoverageCount(statuses[0], statuses[1]),
+			Parameters: report.NewCoverageCount(params[0], params[1]),
+			EnumValues: report.NewCoverageCount(values[0], values[1]),
+		}
+	}
+	return report.Coverage{
+		Spec:      "api/openapi.yaml",
+		Sources:   []string{"staging.har", "<script>.har"},
+		Exchanges: 7,
+		Paths:     report.NewCoverageCount(1, 2),
+		Totals:    totals([2]int{1, 2}, [2]int{1, 3}, [2]int{1, 2}, [2]int{1, 2}),
+		ByPath: []report.PathCoverage{
+			{Path: "/pets", CoverageTotals: totals([2]int{1, 1}, [2]int{1, 2}, [2]int{1, 1}, [2]int{1, 2})},
+			{Path: "/pets/{id}", CoverageTotals: totals([2]int{0, 1}, [2]int{0, 1}, [2]int{0, 1}, [2]int{})},
+		},
+		ByMethod: []report.MethodCoverage{
+			{Method: "DELETE", CoverageTotals: totals([2]int{0, 1}, [2]int{0, 1}, [2]int{0, 1}, [2]int{})},
+			{Method: "GET", CoverageTotals: totals([2]int{1, 1}, [2]int{1, 2}, [2]int{1, 1}, [2]int{1, 2})},
+		},
+		Operations: []report.OperationCoverage{
+			{
+				Operation: "GET /pets", Path: "/pets", Method: "GET", Calls: 5, Invalid: 1,
+				Issues:     []string{"status 500 is not declared"},
+				Statuses:   []report.StatusCoverage{{Status: "200", Calls: 5}, {Status: "400"}},
+				Parameters: []report.ParameterCoverage{{Name: "status", In: "query", Calls: 3}},
+				Enums: []report.EnumCoverage{{Location: `query parameter "status"`, Values: []report.EnumValueCoverage{
+					{Value: "available", Calls: 3}, {Value: "sold"},
+				}}},
+			},
+			{
+				Operation: "DELETE /pets/{id}", Path: "/pets/{id}", Method: "DELETE",
+				Statuses:   []report.StatusCoverage{{Status: "204"}},
+				Parameters: []report.ParameterCoverage{{Name: "id", In: "path"}},
+				Enums:      []report.EnumCoverage{},
+			},
+		},
+		Uncovered: []report.UncoveredItem{
+			{Operation: "GET /pets", Kind: report.COVERAGE_STATUS, Item: "400"},
+			{Operation: "GET /pets", Kind: report.COVERAGE_ENUM_VALUE, Item: `query parameter "status": sold`},
+			{Operation: "DELETE /pets/{id}", Kind: report.COVERAGE_OPERATION},
+		},
+		Unmatched: []report.UnmatchedRequest{{Method: "GET", Path: "/health", Calls: 1}},
+	}
+}
+
+func TestCoverageHTMLWriter_Golden(t *testing.T) {
+	var buf bytes.Buffer
+	if err := compatreport.NewCoverageHTMLWriter().WriteCoverage(&buf, petCoverage()); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "<script>") {
+		t.Error("source names must be escaped")
+	}
+	golden(t, "coverage.html", buf.Bytes())
+}
@@ -0,0 +1,174 @@
+// Package compatreport renders reports for people: spec comparisons as
+// compatibility reports (self-contained HTML, Markdown) for release reviews,
+// changelogs, and traffic coverage reports.
+package compatreport
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+// DefaultTitle heads every compatibility report unless overridden with
+// WithTitle; DefaultCoverageTitle heads coverage reports.
+const (
+	DefaultTitle         = "API compatibility report"
+	DefaultCoverageTitle = "API coverage report"
+)
+
+// documentWide groups changes that are not scoped to an operation.
+const documentWide = "Document"
+
+// config holds the options shared by the renderers.
+type config struct {
+	title string
+}
+
+// Option configures a renderer.
+type Option func(*config)
+
+// WithTitle overrides the report title.
+func WithTitle(title string) Option {
+	return func(c *config) {
+		if title != "" {
+			c.title = title
+		}
+	}
+}
+
+func newConfig(title string, opts []Option) config {
+	c := config{title: title}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// view is the template model: the comparison grouped by path, then
+// operation, with breaking changes first.
+type view struct {
+	Title       string
+	Base        side
+	Head        side
+	Total       int
+	Breaking    int
+	NonBreaking int
+	Operations  int
+	Paths       []pathGroup
+}
+
+type side struct {
+	Label string
+	Spec  report.SpecInfo
+}
+
+type pathGroup struct {
+	Path       string
+	Breaking   int
+	Operations []operationGroup
+}
+
+type operationGroup struct {
+	Method   string
+	Key      string
+	Breaking int
+	Changes  []report.Change
+}
+
+// Verdict summarizes the comparison in a few words.
+func (v view) Verdict() string {
+	switch {
+	case v.Breaking > 0:
+		return "Breaking changes"
+	case v.Total > 0:
+		return "Backwards compatible"
+	default:
+		return "No changes"
+	}
+}
+
+func newView(cfg config, c report.Comparison) view {
+	v := view{
+		Title:      cfg.title,
+		Base:       side{Label: c.Base, Spec: c.BaseSpec},
+		Head:       side{Label: c.Head, Spec: c.HeadSpec},
+		Total:      len(c.Changes),
+		Breaking:   len(c.Breaking()),
+		Operations: len(c.Operations),
+	}
+	v.NonBreaking = v.Total - v.Breaking
+
+	paths := map[string]*pathGroup{}
+	ops := map[string]*operationGroup{}
+	var order []string
+	for _, ch := range c.Changes {
+		method, path := splitOperation(ch.Operation)
+		pg, ok := paths[path]
+		if !ok {
+			pg = &pathGroup{Path: path}
+			paths[path] = pg
+			order = append(order, path)
+		}
+		og, ok := ops[ch.Operation]
+		if !ok {
+			og = &operationGroup{Method: method, Key: ch.Operation}
+			ops[ch.Operation] = og
+		}
+		og.Changes = append(og.Changes, ch)
+		if ch.Breaking() {
+			og.Breaking++
+			pg.Breaking++
+		}
+	}
+
+	// Paths sorted, document-wide changes first; operations in the usual
+	// method order; breaking changes first within an operation.
+	sort.Slice(order, func(i, j int) bool {
+		if (order[i] == documentWide) != (order[j] == documentWide) {
+			return order[i] == documentWide
+		}
+		return order[i] < order[j]
+	})
+	for _, path := range order {
+		pg := paths[path]
+		for key, og := range ops {
+			if _, p := splitOperation(key); p == path {
+				sort.SliceStable(og.Changes, func(i, j int) bool {
+					return og.Changes[i].Breaking() && !og.Changes[j].Breaking()
+				})
+				pg.Operations = append(pg.Operations, *og)
+			}
+		}
+		sort.Slice(pg.Operations, func(i, j int) bool {
+			a, b := pg.Operations[i], pg.Operations[j]
+			if methodRank(a.Method) != methodRank(b.Method) {
+				return methodRank(a.Method) < methodRank(b.Method)
+			}
+			return a.Key < b.Key
+		})
+		v.Paths = append(v.Paths, *pg)
+	}
+	return v
+}
+
+// splitOperation splits "METHOD /path"; changes without an operation belong
+// to the document-wide group.
+func splitOperation(op string) (method, path string) {
+	method, path, ok := strings.Cut(op, " ")
+	if !ok || op == "" {
+		return "", documentWide
+	}
+	return method, path
+}
+
+var methodOrder = []string{"GET", "PUT", "POST", "DELETE", "OPTIONS", "HEAD", "PATCH", "TRACE"}
+
+func methodRank(method string) int {
+	for i, m := range methodOrder {
+		if m == method {
+			return i
+		}
+	}
+	return len(methodOrder)
+}
@@ -0,0 +1,102 @@
+package compatreport
+
+import (
+	"embed"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+//go:embed templates/*.tmpl
+var templates embed.FS
+
+// htmlReport has every asset (styles included) inline, so the output is one
+// self-contained file that can be attached to a PR or a release.
+var htmlReport = htmltemplate.Must(htmltemplate.ParseFS(templates, "templates/report.html.tmpl"))
+
+var markdownReport = texttemplate.Must(texttemplate.New("report.md.tmpl").Funcs(texttemplate.FuncMap{
+	"code":    mdCode,
+	"text":    mdText,
+	"cell":    mdCell,
+	"servers": mdServers,
+}).ParseFS(templates, "templates/report.md.tmpl"))
+
+// HTMLWriter renders a comparison as a single HTML page.
+type HTMLWriter struct {
+	cfg config
+}
+
+// NewHTMLWriter builds an HTMLWriter with safe defaults.
+func NewHTMLWriter(opts ...Option) *HTMLWriter {
+	return &HTMLWriter{cfg: newConfig(DefaultTitle, opts)}
+}
+
+// WriteComparison renders c; spec content is escaped by html/template.
+func (w *HTMLWriter) WriteComparison(out io.Writer, c report.Comparison) error {
+	return htmlReport.Execute(out, newView(w.cfg, c))
+}
+
+// MarkdownWriter renders a comparison as GitHub-flavoured Markdown.
+type MarkdownWriter struct {
+	cfg config
+}
+
+// NewMarkdownWriter builds a MarkdownWriter with safe defaults.
+func NewMarkdownWriter(opts ...Option) *MarkdownWriter {
+	return &MarkdownWriter{cfg: newConfig(DefaultTitle, opts)}
+}
+
+// WriteComparison renders c; spec content is escaped for Markdown.
+func (w *MarkdownWriter) WriteComparison(out io.Writer, c report.Comparison) error {
+	return markdownReport.Execute(out, newView(w.cfg, c))
+}
+
+// mdEscaper neutralizes inline Markdown and raw HTML in spec-provided text.
+var mdEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", "&lt;", ">", "&gt;", "|", `\|`, "\n", " ",
+)
+
+func mdText(s string) string { return mdEscaper.Replace(s) }
+
+// mdCell is mdText for table cells, with a dash for empty values.
+func mdCell(s string) string {
+	if s == "" {
+		return "–"
+	}
+	return mdText(s)
+}
+
+// mdCode wraps s in a code span long enough not to be closed by s itself.
+// Pipes are escaped too, since code spans in GFM tables still split cells.
+func mdCode(s string) string {
+	fence := "`"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+	s = strings.ReplaceAll(strings.ReplaceAll(s, "\n", " "), "|", `\|`)
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+	return fence + s + fence
+}
+
+func mdServers(servers []string) string {
+	if len(servers) == 0 {
+		return "–"
+	}
+	codes := make([]string, len(servers))
+	for i, s := range servers {
+		codes[i] = mdCode(s)
+	}
+	return strings.Join(codes, "<br>")
+}
+
+// compile-time checks
+var (
+	_ report.ComparisonWriter = (*HTMLWriter)(nil)
+	_ report.ComparisonWriter = (*MarkdownWriter)(nil)
+)
@@ -0,0 +1,127 @@
+package compatreport_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/betoth/contractcheck/internal/adapter/compatreport"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// golden compares got with testdata/<name>, or rewrites it with -update.
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run with -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s (run with -update to accept):\n%s", path, got)
+	}
+}
+
+func petComparison() report.Comparison {
+	return report.Comparison{
+		Base: "a1b2c3d4e5f6:api/openapi.yaml",
+		Head: "0f9e8d7c6b5a:api/openapi.yaml",
+		BaseSpec: report.SpecInfo{
+			Title: "Petstore", Version: "1.4.0", OpenAPIVersion: "3.0.3",
+			Servers: []string{"https://api.example.com/v1"},
+		},
+		HeadSpec: report.SpecInfo{
+			Title: "Petstore", Version: "1.5.0", OpenAPIVersion: "3.1.0",
+			Servers: []string{"https://api.example.com/v1", "https://{region}.example.com/v1"},
+		},
+		Operations: []string{"DELETE /pets/{id}", "GET /pets", "GET /pets/{id}", "POST /pets"},
+		Changes: []report.Change{
+			{ID: "operation-removed", Level: report.CHANGE_BREAKING, Operation: "DELETE /pets/{id}", Message: "operation was removed"},
+			{ID: "enum-value-added", Level: report.CHANGE_BREAKING, Operation: "GET /pets/{id}", Location: "response 200 application/json: status", Message: "enum value pending was added to status"},
+			{ID: "parameter-added", Level: report.CHANGE_NON_BREAKING, Operation: "GET /pets/{id}", Location: "query parameter fields", Message: `optional query parameter "fields" was added`},
+			{ID: "property-removed", Level: report.CHANGE_BREAKING, Operation: "GET /pets/{id}", Location: "response 200 application/json: name", Message: "property name was removed"},
+			{ID: "property-added", Level: report.CHANGE_NON_BREAKING, Operation: "POST /pets", Location: "request body application/json: tag", Message: "property tag was added"},
+		},
+	}
+}
+
+func TestWriters_Golden(t *testing.T) {
+	cases := []struct {
+		name string
+		cmp  report.Comparison
+	}{
+		{"breaking", petComparison()},
+		{"unchanged", report.Comparison{
+			Base: "old.yaml", Head: "new.yaml",
+			BaseSpec: report.SpecInfo{Title: "Petstore", Version: "1.0.0", OpenAPIVersion: "3.0.3"},
+			HeadSpec: report.SpecInfo{Title: "Petstore", Version: "1.0.1", OpenAPIVersion: "3.0.3"},
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var html, md bytes.Buffer
+			if err := compatreport.NewHTMLWriter().WriteComparison(&html, tc.cmp); err != nil {
+				t.Fatal(err)
+			}
+			if err := compatreport.NewMarkdownWriter().WriteComparison(&md, tc.cmp); err != nil {
+				t.Fatal(err)
+			}
+			golden(t, tc.name+".html", html.Bytes())
+			golden(t, tc.name+".md", md.Bytes())
+		})
+	}
+}
+
+func TestHTMLWriter_SelfContainedAndEscaped(t *testing.T) {
+	cmp := petComparison()
+	cmp.HeadSpec.Title = `<script>alert("x")</script>`
+	cmp.Changes[0].Message = "removed <b>now</b>"
+
+	var buf bytes.Buffer
+	if err := compatreport.NewHTMLWriter(compatreport.WithTitle("Release 1.5 review")).WriteComparison(&buf, cmp); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if strings.Contains(out, "<script>") || strings.Contains(out, "<b>now</b>") {
+		t.Fatal("spec content was not escaped")
+	}
+	// Nothing is loaded from elsewhere: no linked stylesheets, scripts or images.
+	if external := regexp.MustCompile(`(?i)<(link|script|img|iframe)\b|\bsrc=|@import`); external.MatchString(out) {
+		t.Fatalf("report references external resources: %s", external.FindString(out))
+	}
+	if !strings.Contains(out, "<h1>Release 1.5 review</h1>") {
+		t.Fatal("custom title missing")
+	}
+}
+
+func TestMarkdownWriter_EscapesSpecText(t *testing.T) {
+	cmp := petComparison()
+	cmp.HeadSpec.Title = "Pets | *v2* <beta>"
+	cmp.Changes = cmp.Changes[:1]
+	cmp.Changes[0].Message = "removed `legacy` [link](x)"
+
+	var buf bytes.Buffer
+	if err := compatreport.NewMarkdownWriter().WriteComparison(&buf, cmp); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`| Title | Petstore | Pets \| \*v2\* &lt;beta&gt; |`,
+		"- **BREAKING** removed \\`legacy\\` \\[link\\](x) `operation-removed`",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
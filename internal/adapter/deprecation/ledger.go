@@ -0,0 +1,96 @@
+// Package deprecation stores the deprecation ledger: when each element of a
+// spec was first seen deprecated.
+package deprecation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+// ledgerFormat versions the file layout; readers reject newer formats.
+const ledgerFormat = 1
+
+// ledgerFile is the on-disk layout. It is indented JSON with one record per
+// element, sorted, so that the file diffs well when committed next to a spec.
+type ledgerFile struct {
+	Format       int                        `json:"format"`
+	Deprecations []report.DeprecationRecord `json:"deprecations"`
+}
+
+// FileLedger keeps the ledger in a JSON file.
+type FileLedger struct{}
+
+// NewFileLedger builds a FileLedger.
+func NewFileLedger() *FileLedger {
+	return &FileLedger{}
+}
+
+// Load reads the records at path; a missing file is an empty ledger.
+func (l *FileLedger) Load(_ context.Context, path string) ([]report.DeprecationRecord, error) {
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f ledgerFile
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return nil, invalidLedger(path, err)
+	}
+	if f.Format > ledgerFormat {
+		return nil, invalidLedger(path, fmt.Errorf("format %d is newer than the supported format %d", f.Format, ledgerFormat))
+	}
+	return f.Deprecations, nil
+}
+
+// Save replaces the file at path atomically, creating its directory if needed.
+func (l *FileLedger) Save(_ context.Context, path string, records []report.DeprecationRecord) error {
+	if records == nil {
+		records = []report.DeprecationRecord{}
+	}
+	raw, err := json.MarshalIndent(ledgerFile{Format: ledgerFormat, Deprecations: records}, "", "  ")
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	// CreateTemp uses 0600; the ledger is meant to be committed and shared.
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if _, err := tmp.Write(append(raw, '\n')); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func invalidLedger(path string, cause error) error {
+	return customerrors.NewValidationError("Invalid deprecation ledger", cause, map[string]any{customerrors.DetailFile: path})
+}
+
+// compile-time check
+var _ output.DeprecationLedger = (*FileLedger)(nil)
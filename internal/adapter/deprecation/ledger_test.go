@@ -0,0 +1,68 @@
+package deprecation_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/betoth/contractcheck/internal/adapter/deprecation"
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+func TestFileLedger_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	l := deprecation.NewFileLedger()
+	path := filepath.Join(t.TempDir(), "contract", "deprecations.json")
+
+	records, err := l.Load(ctx, path)
+	if err != nil || len(records) != 0 {
+		t.Fatalf("missing ledger: records = %v, err = %v", records, err)
+	}
+
+	want := []report.DeprecationRecord{
+		{Element: "GET /pets parameter query sort", Operation: "GET /pets", Since: "2026-06-01", Revision: "a1b2c3:api.yaml", Sunset: "2026-12-01"},
+	}
+	if err := l.Save(ctx, path, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := l.Load(ctx, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("records = %+v, want %+v", got, want)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o644 {
+		t.Fatalf("mode = %v, want 0644", perm)
+	}
+	if leftovers, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*.tmp")); len(leftovers) > 0 {
+		t.Fatalf("temp files left behind: %v", leftovers)
+	}
+}
+
+func TestFileLedger_RejectsUnreadableFiles(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"corrupt.json": "{not json",
+		"newer.json":   `{"format": 99, "deprecations": []}`,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := deprecation.NewFileLedger().Load(context.Background(), path)
+		var ae *customerrors.AppError
+		if !errors.As(err, &ae) || ae.Type != customerrors.VALIDATION_ERROR || ae.Details[customerrors.DetailFile] != path {
+			t.Errorf("%s: err = %v, want a validation error naming the file", name, err)
+		}
+	}
+}
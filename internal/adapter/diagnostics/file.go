@@ -0,0 +1,36 @@
+package diagnostics
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+)
+
+// FileName returns the conventional bundle name for t.
+func FileName(t time.Time) string {
+	return "contractcheck-diagnostics-" + t.UTC().Format("20060102T150405Z") + ".zip"
+}
+
+// WriteFile exports a bundle into a new file at path, creating parent dirs.
+// A partially written file is removed on failure.
+func WriteFile(ctx context.Context, exporter input.ExportDiagnostics, path string) (err error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			_ = os.Remove(path)
+		}
+	}()
+	return exporter.Export(ctx, f)
+}
@@ -0,0 +1,240 @@
+// Package git implements output.RevisionSource by shelling out to the git CLI.
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/betoth/contractcheck/internal/adapter/refscan"
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+)
+
+// Source reads specs from a local repository through the git CLI.
+type Source struct {
+	binary string
+}
+
+// Option configures a Source.
+type Option func(*Source)
+
+// WithBinary overrides the git executable (default: "git" from PATH).
+func WithBinary(path string) Option {
+	return func(s *Source) {
+		if path != "" {
+			s.binary = path
+		}
+	}
+}
+
+// NewSource builds a Source with safe defaults.
+func NewSource(opts ...Option) *Source {
+	s := &Source{binary: "git"}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Resolve returns the commit ID rev points to.
+func (s *Source) Resolve(ctx context.Context, repo, rev string) (string, error) {
+	if _, err := s.toplevel(ctx, repo); err != nil {
+		return "", err
+	}
+	out, err := s.git(ctx, repo, "rev-parse", "--verify", "--quiet", "--end-of-options", rev+"^{commit}")
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", revisionError(openapi.UNKNOWN_REVISION, "Unknown revision", repo, rev, fmt.Errorf("%q does not name a commit: %w", rev, err))
+	}
+	return out, nil
+}
+
+// MergeBase returns the best common ancestor of a and b.
+func (s *Source) MergeBase(ctx context.Context, repo, a, b string) (string, error) {
+	ca, err := s.Resolve(ctx, repo, a)
+	if err != nil {
+		return "", err
+	}
+	cb, err := s.Resolve(ctx, repo, b)
+	if err != nil {
+		return "", err
+	}
+	out, err := s.git(ctx, repo, "merge-base", ca, cb)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", revisionError(openapi.UNKNOWN_REVISION, "No common ancestor", repo, a+"..."+b, err)
+	}
+	return out, nil
+}
+
+// Materialize writes path and every local file it references (transitively),
+// as of rev, under a fresh temp dir that mirrors the repository root. Refs
+// leaving the repository are not copied; the loader reports them.
+func (s *Source) Materialize(ctx context.Context, repo, rev, file string) (output.Checkout, func(), error) {
+	top, err := s.toplevel(ctx, repo)
+	if err != nil {
+		return output.Checkout{}, nil, err
+	}
+	commit, err := s.Resolve(ctx, repo, rev)
+	if err != nil {
+		return output.Checkout{}, nil, err
+	}
+	rel, err := repoRelative(top, repo, file)
+	if err != nil {
+		return output.Checkout{}, nil, openapi.NewValidationError(openapi.FILE_NOT_IN_REVISION, "File is outside the repository", file, err)
+	}
+
+	dir, err := os.MkdirTemp("", "contractcheck-git-*")
+	if err != nil {
+		return output.Checkout{}, nil, err
+	}
+	cleanup := func() { _ = os.RemoveAll(dir) }
+
+	seen := map[string]bool{}
+	queue := []string{rel}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if seen[cur] {
+			continue
+		}
+		seen[cur] = true
+
+		data, err := s.blob(ctx, repo, commit, cur)
+		if err != nil {
+			if cur != rel {
+				// Missing or bogus ref target: leave it to the loader, which
+				// reports unresolved refs with their location.
+				continue
+			}
+			cleanup()
+			if ctx.Err() != nil {
+				return output.Checkout{}, nil, ctx.Err()
+			}
+			return output.Checkout{}, nil, revisionError(openapi.FILE_NOT_IN_REVISION, "File does not exist at revision", file, rev, err)
+		}
+		dst := filepath.Join(dir, filepath.FromSlash(cur))
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			cleanup()
+			return output.Checkout{}, nil, err
+		}
+		if err := os.WriteFile(dst, data, 0o644); err != nil {
+			cleanup()
+			return output.Checkout{}, nil, err
+		}
+
+		refs, _ := refscan.Refs(data)
+		for _, target := range refs {
+			next := path.Join(path.Dir(cur), filepath.ToSlash(target))
+			if next == ".." || strings.HasPrefix(next, "../") || path.IsAbs(next) {
+				continue
+			}
+			queue = append(queue, next)
+		}
+	}
+
+	return output.Checkout{Dir: dir, Root: filepath.Join(dir, filepath.FromSlash(rel)), Commit: commit}, cleanup, nil
+}
+
+// blob reads "<commit>:<path>" from the object database.
+func (s *Source) blob(ctx context.Context, repo, commit, rel string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, s.binary, "-C", repo, "cat-file", "blob", commit+":"+rel)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, commandError(err, stderr.String())
+	}
+	return out, nil
+}
+
+// toplevel returns the repository root containing repo.
+func (s *Source) toplevel(ctx context.Context, repo string) (string, error) {
+	out, err := s.git(ctx, repo, "rev-parse", "--show-toplevel")
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		// Anything but a non-zero exit means git could not be started.
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return "", openapi.NewValidationError(openapi.GIT_UNAVAILABLE, "git executable not found", repo, err)
+		}
+		return "", openapi.NewValidationError(openapi.NOT_A_REPOSITORY, "Not a git repository", repo, err)
+	}
+	return out, nil
+}
+
+// git runs a command in repo and returns its trimmed stdout.
+func (s *Source) git(ctx context.Context, repo string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, s.binary, append([]string{"-C", repo}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", commandError(err, stderr.String())
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// commandError keeps git's own message as the technical cause.
+func commandError(err error, stderr string) error {
+	if msg := strings.TrimSpace(stderr); msg != "" {
+		return fmt.Errorf("%w: %s", err, msg)
+	}
+	return err
+}
+
+// repoRelative converts file (absolute, or relative to repo) into a
+// slash-separated path relative to the repository root top.
+func repoRelative(top, repo, file string) (string, error) {
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(repo, file)
+	}
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return "", err
+	}
+	// Resolve symlinks on the directory only: the file may not exist in the
+	// working tree (deleted, or only present at the revision).
+	if d, err := filepath.EvalSymlinks(filepath.Dir(abs)); err == nil {
+		abs = filepath.Join(d, filepath.Base(abs))
+	}
+	if t, err := filepath.EvalSymlinks(top); err == nil {
+		top = t
+	}
+	rel, err := filepath.Rel(top, abs)
+	if err != nil {
+		return "", err
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", fmt.Errorf("%s is not under %s", abs, top)
+	}
+	return rel, nil
+}
+
+// revisionError builds a typed error carrying the revision.
+func revisionError(kind openapi.ErrorKind, message, file, rev string, cause error) error {
+	err := openapi.NewValidationError(kind, message, file, cause)
+	var ae *customerrors.AppError
+	if errors.As(err, &ae) {
+		ae.Details[customerrors.DetailRevision] = rev
+	}
+	return err
+}
+
+// compile-time check
+var _ output.RevisionSource = (*Source)(nil)
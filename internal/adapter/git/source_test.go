@@ -0,0 +1,160 @@
+package git_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/betoth/contractcheck/internal/adapter/git"
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+)
+
+// testRepo creates a repository with one commit on main and a feature
+// branch that edits the spec and adds a referenced file.
+func testRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+			"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_NOSYSTEM=1",
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "-q", "-b", "main")
+	write("api/openapi.yaml", "openapi: 3.0.3\npaths: {}\n")
+	run("add", "-A")
+	run("commit", "-qm", "base")
+
+	run("checkout", "-qb", "feature")
+	write("api/openapi.yaml", "openapi: 3.0.3\npaths:\n  /pets:\n    $ref: './paths/pets.yaml'\n  /gone:\n    $ref: './paths/missing.yaml'\n  /escape:\n    $ref: '../../outside.yaml'\n")
+	write("api/paths/pets.yaml", "get:\n  responses:\n    '200':\n      $ref: '../shared/ok.yaml'\n")
+	write("api/shared/ok.yaml", "description: ok\n")
+	write("api/unrelated.yaml", "x: 1\n")
+	run("add", "-A")
+	run("commit", "-qm", "feature")
+
+	// Working tree changes must not leak into materialized revisions.
+	write("api/openapi.yaml", "dirty\n")
+	return dir
+}
+
+func appErrorKind(t *testing.T, err error) (openapi.ErrorKind, *customerrors.AppError) {
+	t.Helper()
+	var ae *customerrors.AppError
+	if !errors.As(err, &ae) {
+		t.Fatalf("expected AppError, got %v", err)
+	}
+	kind, _ := ae.Details[customerrors.DetailKind].(string)
+	return openapi.ErrorKind(kind), ae
+}
+
+func TestSource_MergeBaseAndMaterialize(t *testing.T) {
+	repo := testRepo(t)
+	ctx := context.Background()
+	src := git.NewSource()
+
+	main, err := src.Resolve(ctx, repo, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := src.Resolve(ctx, repo, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head == main {
+		t.Fatal("HEAD should be ahead of main")
+	}
+	base, err := src.MergeBase(ctx, repo, head, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if base != main {
+		t.Fatalf("merge-base = %s, want %s", base, main)
+	}
+
+	co, cleanup, err := src.Materialize(ctx, repo, head, "api/openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if co.Commit != head || co.Root != filepath.Join(co.Dir, "api", "openapi.yaml") {
+		t.Fatalf("checkout = %+v", co)
+	}
+	root, err := os.ReadFile(co.Root)
+	if err != nil || !strings.Contains(string(root), "/pets") {
+		t.Fatalf("root = %q, %v", root, err)
+	}
+	for _, f := range []string{"api/paths/pets.yaml", "api/shared/ok.yaml"} {
+		if _, err := os.Stat(filepath.Join(co.Dir, filepath.FromSlash(f))); err != nil {
+			t.Errorf("referenced file %s not materialized: %v", f, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(co.Dir, "api", "unrelated.yaml")); err == nil {
+		t.Error("unreferenced file was materialized")
+	}
+	cleanup()
+	if _, err := os.Stat(co.Dir); !os.IsNotExist(err) {
+		t.Fatalf("cleanup left %s behind", co.Dir)
+	}
+
+	// The base revision has the original spec and none of the new files.
+	co, cleanup, err = src.Materialize(ctx, repo, base, filepath.Join(repo, "api", "openapi.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+	if root, _ := os.ReadFile(co.Root); string(root) != "openapi: 3.0.3\npaths: {}\n" {
+		t.Fatalf("base root = %q", root)
+	}
+}
+
+func TestSource_TypedErrors(t *testing.T) {
+	repo := testRepo(t)
+	ctx := context.Background()
+	src := git.NewSource()
+
+	_, err := src.Resolve(ctx, repo, "no-such-branch")
+	if kind, ae := appErrorKind(t, err); kind != openapi.UNKNOWN_REVISION || ae.Details[customerrors.DetailRevision] != "no-such-branch" {
+		t.Fatalf("unknown revision: %v (%v)", kind, ae.Details)
+	}
+
+	_, _, err = src.Materialize(ctx, repo, "main", "api/paths/pets.yaml")
+	if kind, ae := appErrorKind(t, err); kind != openapi.FILE_NOT_IN_REVISION || ae.Details[customerrors.DetailRevision] != "main" {
+		t.Fatalf("missing file: %v (%v)", kind, ae.Details)
+	}
+
+	_, err = src.Resolve(ctx, t.TempDir(), "HEAD")
+	if kind, _ := appErrorKind(t, err); kind != openapi.NOT_A_REPOSITORY {
+		t.Fatalf("not a repository: %v", kind)
+	}
+
+	_, err = git.NewSource(git.WithBinary(filepath.Join(t.TempDir(), "no-git"))).Resolve(ctx, repo, "HEAD")
+	if kind, _ := appErrorKind(t, err); kind != openapi.GIT_UNAVAILABLE {
+		t.Fatalf("git unavailable: %v", kind)
+	}
+}
@@ -0,0 +1,159 @@
+// Package gotest renders contract test plans as Go test files.
+package gotest
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/testgen"
+)
+
+// DEFAULT_RUNTIME is the import path of the helper package generated tests use.
+const DEFAULT_RUNTIME = "github.com/betoth/contractcheck/pkg/contracttest"
+
+// SCHEMAS_FILE holds the schema bundle shared by the generated files.
+const SCHEMAS_FILE = "contract_schemas_test.go"
+
+//go:embed templates/*.tmpl
+var templates embed.FS
+
+var goTemplates = template.Must(template.New("").Funcs(template.FuncMap{
+	"lit":      goString,
+	"strmap":   goStringMap,
+	"querymap": goQueryMap,
+}).ParseFS(templates, "templates/*.tmpl"))
+
+// Option customizes a Renderer.
+type Option func(*Renderer)
+
+// WithRuntime sets the import path of the runtime package, e.g. a vendored
+// copy; its package name must stay "contracttest".
+func WithRuntime(importPath string) Option {
+	return func(r *Renderer) { r.runtime = importPath }
+}
+
+// Renderer renders a suite as gofmt-formatted Go test files: one per file of
+// the plan, named "<name>_contract_test.go", plus SCHEMAS_FILE.
+type Renderer struct {
+	runtime string
+}
+
+// NewRenderer builds a Renderer with safe defaults.
+func NewRenderer(opts ...Option) *Renderer {
+	r := &Renderer{runtime: DEFAULT_RUNTIME}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// fileView is the data of tests.go.tmpl.
+type fileView struct {
+	Spec, Package, Runtime string
+	Cases                  []testgen.Case
+}
+
+// schemasView is the data of schemas.go.tmpl.
+type schemasView struct {
+	Spec, Package, Runtime string
+	Schemas                string
+}
+
+// Render renders s; the output is sorted by file name.
+func (r *Renderer) Render(s testgen.Suite) ([]testgen.Source, error) {
+	var out []testgen.Source
+	for _, f := range s.Files {
+		view := fileView{Spec: s.Spec, Package: s.Package, Runtime: r.runtime}
+		for _, c := range f.Cases {
+			c.Name = "Test" + c.Name
+			view.Cases = append(view.Cases, c)
+		}
+		src, err := render("tests.go.tmpl", view)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		out = append(out, testgen.Source{Name: f.Name + "_contract_test.go", Content: src})
+	}
+
+	bundle, err := openapi.EncodeSchemas(s.Schemas)
+	if err != nil {
+		return nil, err
+	}
+	src, err := render("schemas.go.tmpl", schemasView{Spec: s.Spec, Package: s.Package, Runtime: r.runtime, Schemas: string(bundle)})
+	if err != nil {
+		return nil, err
+	}
+	out = append(out, testgen.Source{Name: SCHEMAS_FILE, Content: src})
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// render executes a template and gofmt-formats the result.
+func render(name string, data any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := goTemplates.ExecuteTemplate(&buf, name, data); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// goString renders s as a Go string literal: a raw string when s has
+// quotes and can be one, an interpreted one otherwise.
+func goString(s string) string {
+	if strings.Contains(s, `"`) && !strings.ContainsAny(s, "`\r") && utf8.ValidString(s) {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
+
+// goStringMap renders a map[string]string literal with sorted keys.
+func goStringMap(m map[string]string) string {
+	var b strings.Builder
+	b.WriteString("map[string]string{")
+	for i, k := range sortedKeys(m) {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%s: %s", strconv.Quote(k), goString(m[k]))
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// goQueryMap renders a map[string][]string literal with sorted keys.
+func goQueryMap(m map[string][]string) string {
+	var b strings.Builder
+	b.WriteString("map[string][]string{")
+	for i, k := range sortedKeys(m) {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		values := make([]string, len(m[k]))
+		for j, v := range m[k] {
+			values[j] = goString(v)
+		}
+		fmt.Fprintf(&b, "%s: {%s}", strconv.Quote(k), strings.Join(values, ", "))
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// compile-time check
+var _ testgen.Renderer = (*Renderer)(nil)
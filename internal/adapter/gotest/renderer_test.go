@@ -0,0 +1,102 @@
+package gotest_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/betoth/contractcheck/internal/adapter/gotest"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/testgen"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// golden compares got with testdata/<name>, or rewrites it with -update.
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run with -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s (run with -update to accept):\n%s", path, got)
+	}
+}
+
+func petSuite() testgen.Suite {
+	pet := &openapi.Schema{Name: "Pet", Type: []string{"object"}, Required: []string{"id"}, Properties: map[string]*openapi.Schema{
+		"id": {Type: []string{"integer"}},
+	}}
+	return testgen.Suite{
+		Package: "contract",
+		Spec:    "api/openapi.yaml",
+		Schemas: map[string]*openapi.Schema{
+			"GET /pets/{id} 200 application/json": pet,
+		},
+		Files: []testgen.File{{
+			Name: "pets",
+			Cases: []testgen.Case{
+				{
+					Name: "GetPet_200", Operation: "GET /pets/{id}", Status: "200", Method: "GET", Path: "/pets/{id}",
+					PathParams: map[string]string{"id": "1"},
+					Query:      map[string][]string{"fields": {"id", "name"}},
+					Headers:    map[string]string{"X-Tenant": "acme", "Cookie": "session=abc"},
+					Content:    map[string]string{"application/json": "GET /pets/{id} 200 application/json"},
+				},
+				{
+					Name: "GetPet_401", Operation: "GET /pets/{id}", Status: "401", Method: "GET", Path: "/pets/{id}",
+					PathParams: map[string]string{"id": "1"}, Anonymous: true,
+				},
+				{
+					Name: "CreatePet_Default", Operation: "POST /pets", Status: "default", Method: "POST", Path: "/pets",
+					ContentType: "application/json", Body: `{"name":"rex"}`,
+					Skip: "no synthesized request is known to produce default",
+				},
+			},
+		}},
+	}
+}
+
+func TestRenderer_Render(t *testing.T) {
+	files, err := gotest.NewRenderer().Render(petSuite())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || files[0].Name != gotest.SCHEMAS_FILE || files[1].Name != "pets_contract_test.go" {
+		t.Fatalf("files = %v", files)
+	}
+	for _, f := range files {
+		golden(t, f.Name+".golden", f.Content)
+	}
+}
+
+func TestRenderer_Deterministic(t *testing.T) {
+	r := gotest.NewRenderer(gotest.WithRuntime("example.com/vendor/contracttest"))
+	first, err := r.Render(petSuite())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		again, err := r.Render(petSuite())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for j := range first {
+			if !bytes.Equal(first[j].Content, again[j].Content) {
+				t.Fatalf("%s differs between runs", first[j].Name)
+			}
+		}
+	}
+	if !bytes.Contains(first[1].Content, []byte(`"example.com/vendor/contracttest"`)) {
+		t.Errorf("runtime import not applied:\n%s", first[1].Content)
+	}
+}
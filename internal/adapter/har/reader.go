@@ -0,0 +1,179 @@
+// Package har reads recorded traffic from HAR (HTTP Archive 1.2) files, as
+// exported by browsers, proxies and load-testing tools.
+package har
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/traffic"
+)
+
+// harFile is the subset of the HAR 1.2 layout that describes exchanges;
+// timings, cookies lists and cache data are ignored.
+type harFile struct {
+	Log *struct {
+		Entries []entry `json:"entries"`
+	} `json:"log"`
+}
+
+type entry struct {
+	Request  request  `json:"request"`
+	Response response `json:"response"`
+}
+
+type request struct {
+	Method   string   `json:"method"`
+	URL      string   `json:"url"`
+	Headers  []header `json:"headers"`
+	PostData *struct {
+		MimeType string `json:"mimeType"`
+		Text     string `json:"text"`
+		Params   []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"params"`
+	} `json:"postData"`
+}
+
+type response struct {
+	Status  int      `json:"status"`
+	Headers []header `json:"headers"`
+	Content struct {
+		MimeType string `json:"mimeType"`
+		Text     string `json:"text"`
+		Encoding string `json:"encoding"`
+	} `json:"content"`
+}
+
+type header struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Reader reads HAR files from disk.
+type Reader struct{}
+
+// NewReader builds a Reader.
+func NewReader() *Reader {
+	return &Reader{}
+}
+
+// Read parses the HAR file at path. Entries without a response (status 0:
+// blocked, aborted or still pending when the file was saved) are skipped.
+func (r *Reader) Read(_ context.Context, path string) ([]traffic.Exchange, error) {
+	raw, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil, openapi.NewValidationError(openapi.FILE_NOT_FOUND, "File not found", path, err)
+	case errors.Is(err, fs.ErrPermission):
+		return nil, openapi.NewValidationError(openapi.PERMISSION_DENIED, "Permission denied", path, err)
+	case err != nil:
+		return nil, err
+	}
+
+	var f harFile
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return nil, openapi.NewValidationError(openapi.INVALID_SYNTAX, "Invalid JSON syntax", path, err)
+	}
+	if f.Log == nil {
+		return nil, invalidHAR(path, errors.New("the log object is required"))
+	}
+
+	exchanges := []traffic.Exchange{}
+	for i, e := range f.Log.Entries {
+		if e.Response.Status == 0 {
+			continue
+		}
+		ex, err := decodeEntry(e)
+		if err != nil {
+			return nil, invalidHAR(path, fmt.Errorf("entry %d: %w", i, err))
+		}
+		exchanges = append(exchanges, ex)
+	}
+	return exchanges, nil
+}
+
+func decodeEntry(e entry) (traffic.Exchange, error) {
+	if e.Request.Method == "" {
+		return traffic.Exchange{}, errors.New("request: method is required")
+	}
+	u, err := url.Parse(e.Request.URL)
+	if err != nil || !u.IsAbs() {
+		return traffic.Exchange{}, fmt.Errorf("request: url %q is not absolute", e.Request.URL)
+	}
+
+	req := output.HTTPRequest{
+		Method: strings.ToUpper(e.Request.Method),
+		URL:    e.Request.URL,
+		Header: decodeHeaders(e.Request.Headers),
+	}
+	if pd := e.Request.PostData; pd != nil {
+		switch {
+		case pd.Text != "":
+			req.Body = []byte(pd.Text)
+		case len(pd.Params) > 0:
+			form := url.Values{}
+			for _, p := range pd.Params {
+				form.Add(p.Name, p.Value)
+			}
+			req.Body = []byte(form.Encode())
+		}
+		if pd.MimeType != "" && req.Header.Get("Content-Type") == "" {
+			req.Header.Set("Content-Type", pd.MimeType)
+		}
+	}
+
+	resp := output.HTTPResponse{
+		Status: e.Response.Status,
+		Header: decodeHeaders(e.Response.Headers),
+	}
+	content := e.Response.Content
+	switch {
+	case content.Encoding == "base64":
+		if resp.Body, err = base64.StdEncoding.DecodeString(content.Text); err != nil {
+			return traffic.Exchange{}, fmt.Errorf("response content: %w", err)
+		}
+	case content.Encoding != "":
+		return traffic.Exchange{}, fmt.Errorf("response content: unsupported encoding %q", content.Encoding)
+	default:
+		resp.Body = []byte(content.Text)
+	}
+	if len(resp.Body) == 0 {
+		// Browsers record a placeholder type ("x-unknown") for empty bodies.
+		resp.Body = nil
+	} else if content.MimeType != "" && resp.Header.Get("Content-Type") == "" {
+		resp.Header.Set("Content-Type", content.MimeType)
+	}
+	return traffic.Exchange{Request: req, Response: resp}, nil
+}
+
+// decodeHeaders skips HTTP/2 pseudo-headers (":authority", ":path", ...),
+// which some browsers record alongside the real ones.
+func decodeHeaders(headers []header) http.Header {
+	h := http.Header{}
+	for _, hd := range headers {
+		if hd.Name == "" || strings.HasPrefix(hd.Name, ":") {
+			continue
+		}
+		h.Add(hd.Name, hd.Value)
+	}
+	return h
+}
+
+func invalidHAR(path string, cause error) error {
+	return openapi.NewValidationError(openapi.INVALID_HAR, "Invalid HAR file", path, cause)
+}
+
+// compile-time check
+var _ traffic.Reader = (*Reader)(nil)
@@ -0,0 +1,83 @@
+package har_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/betoth/contractcheck/internal/adapter/har"
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/traffic"
+)
+
+func TestReader_Read(t *testing.T) {
+	got, err := har.NewReader().Read(context.Background(), filepath.Join("testdata", "pets.har"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The aborted request (status 0) is skipped.
+	want := []traffic.Exchange{
+		{
+			Request: output.HTTPRequest{
+				Method: "GET",
+				URL:    "https://api.example.com/v1/pets?status=available&limit=10",
+				Header: http.Header{"Accept": {"application/json"}},
+			},
+			Response: output.HTTPResponse{
+				Status: 200,
+				Header: http.Header{"Content-Type": {"application/json"}},
+				Body:   []byte(`[{"id":1,"name":"rex"}]`),
+			},
+		},
+		{
+			Request: output.HTTPRequest{
+				Method: "POST",
+				URL:    "https://api.example.com/v1/pets",
+				Header: http.Header{"Content-Type": {"application/json"}},
+				Body:   []byte(`{"name":"rex"}`),
+			},
+			Response: output.HTTPResponse{Status: 201, Header: http.Header{}},
+		},
+		{
+			Request: output.HTTPRequest{
+				Method: "POST",
+				URL:    "https://api.example.com/v1/login",
+				Header: http.Header{"Content-Type": {"application/x-www-form-urlencoded"}},
+				Body:   []byte("pass=s3cret&user=ana"),
+			},
+			Response: output.HTTPResponse{
+				Status: 204,
+				Header: http.Header{"Content-Type": {"text/plain"}},
+				Body:   []byte("hello"),
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("exchanges =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestReader_Errors(t *testing.T) {
+	cases := map[string]openapi.ErrorKind{
+		"missing.har":      openapi.FILE_NOT_FOUND,
+		"truncated.har":    openapi.INVALID_SYNTAX,
+		"no_log.har":       openapi.INVALID_HAR,
+		"relative_url.har": openapi.INVALID_HAR,
+	}
+	for name, kind := range cases {
+		path := filepath.Join("testdata", name)
+		_, err := har.NewReader().Read(context.Background(), path)
+		var ae *customerrors.AppError
+		if !errors.As(err, &ae) {
+			t.Fatalf("%s: err = %v, want an AppError", name, err)
+		}
+		if ae.Details[customerrors.DetailKind] != string(kind) || ae.Details[customerrors.DetailFile] != path {
+			t.Errorf("%s: details = %v, want kind %s", name, ae.Details, kind)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// Package httpclient sends contract run requests with net/http.
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+)
+
+// DEFAULT_TIMEOUT bounds each request, including reading the body.
+const DEFAULT_TIMEOUT = 30 * time.Second
+
+// DEFAULT_MAX_BODY_BYTES caps how much of a response body is read.
+const DEFAULT_MAX_BODY_BYTES = 10 << 20
+
+// USER_AGENT identifies contractcheck to the service under test.
+const USER_AGENT = "contractcheck"
+
+// Option customizes a Client.
+type Option func(*Client)
+
+// WithTimeout sets the per-request timeout; 0 disables it.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) { c.http.Timeout = d }
+}
+
+// WithMaxBodyBytes caps response bodies; longer bodies are an error.
+func WithMaxBodyBytes(n int64) Option {
+	return func(c *Client) { c.maxBodyBytes = n }
+}
+
+// WithTransport replaces the HTTP transport, e.g. for custom TLS settings.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *Client) { c.http.Transport = rt }
+}
+
+// Client sends requests without following redirects: a 3xx is a response
+// the spec declares like any other.
+type Client struct {
+	http         *http.Client
+	maxBodyBytes int64
+}
+
+// NewClient builds a Client with safe defaults.
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		http: &http.Client{
+			Timeout: DEFAULT_TIMEOUT,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		maxBodyBytes: DEFAULT_MAX_BODY_BYTES,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Do sends req and reads the whole response.
+func (c *Client) Do(ctx context.Context, req output.HTTPRequest) (output.HTTPResponse, error) {
+	var body io.Reader
+	if req.Body != nil {
+		body = bytes.NewReader(req.Body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, req.URL, body)
+	if err != nil {
+		return output.HTTPResponse{}, err
+	}
+	for name, values := range req.Header {
+		httpReq.Header[name] = values
+	}
+	if httpReq.Header.Get("User-Agent") == "" {
+		httpReq.Header.Set("User-Agent", USER_AGENT)
+	}
+
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		return output.HTTPResponse{}, err
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, c.maxBodyBytes+1))
+	if err != nil {
+		return output.HTTPResponse{}, fmt.Errorf("reading response: %w", err)
+	}
+	if int64(len(raw)) > c.maxBodyBytes {
+		return output.HTTPResponse{}, fmt.Errorf("response body exceeds %d bytes", c.maxBodyBytes)
+	}
+	return output.HTTPResponse{Status: resp.StatusCode, Header: resp.Header, Body: raw}, nil
+}
+
+// compile-time check
+var _ output.HTTPClient = (*Client)(nil)
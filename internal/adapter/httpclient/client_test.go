@@ -0,0 +1,60 @@
+package httpclient_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/betoth/contractcheck/internal/adapter/httpclient"
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+)
+
+func TestClient_Do(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/echo":
+			raw, _ := io.ReadAll(r.Body)
+			w.Header().Set("X-Agent", r.UserAgent())
+			w.Header().Set("X-Key", r.Header.Get("X-Key"))
+			w.WriteHeader(http.StatusCreated)
+			w.Write(raw)
+		case "/moved":
+			http.Redirect(w, r, "/echo", http.StatusFound)
+		case "/big":
+			w.Write([]byte(strings.Repeat("x", 101)))
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	c := httpclient.NewClient(httpclient.WithMaxBodyBytes(100), httpclient.WithTimeout(50*time.Millisecond))
+	resp, err := c.Do(ctx, output.HTTPRequest{
+		Method: "POST",
+		URL:    srv.URL + "/echo",
+		Header: http.Header{"X-Key": {"k1"}},
+		Body:   []byte(`{"a":1}`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != http.StatusCreated || string(resp.Body) != `{"a":1}` || resp.Header.Get("X-Key") != "k1" || resp.Header.Get("X-Agent") != httpclient.USER_AGENT {
+		t.Errorf("response = %d %v %s", resp.Status, resp.Header, resp.Body)
+	}
+
+	resp, err = c.Do(ctx, output.HTTPRequest{Method: "GET", URL: srv.URL + "/moved"})
+	if err != nil || resp.Status != http.StatusFound {
+		t.Errorf("redirect: status %d, err %v; want the 302 itself", resp.Status, err)
+	}
+	if _, err := c.Do(ctx, output.HTTPRequest{Method: "GET", URL: srv.URL + "/big"}); err == nil {
+		t.Error("oversized body: want an error")
+	}
+	if _, err := c.Do(ctx, output.HTTPRequest{Method: "GET", URL: srv.URL + "/slow"}); err == nil {
+		t.Error("slow response: want a timeout")
+	}
+}
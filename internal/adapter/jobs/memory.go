@@ -0,0 +1,53 @@
+package jobs
+
+import (
+	"sync"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+)
+
+// DefaultCapacity is the number of jobs kept when no capacity is given.
+const DefaultCapacity = 100
+
+// MemoryHistory is a bounded, in-process job history (lost on restart).
+type MemoryHistory struct {
+	mu       sync.Mutex
+	capacity int
+	jobs     []output.JobRecord
+}
+
+// NewMemoryHistory builds a history keeping the last capacity jobs.
+func NewMemoryHistory(capacity int) *MemoryHistory {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	return &MemoryHistory{capacity: capacity}
+}
+
+// Record appends job, evicting the oldest entry when full.
+func (h *MemoryHistory) Record(job output.JobRecord) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.jobs = append(h.jobs, job)
+	if over := len(h.jobs) - h.capacity; over > 0 {
+		h.jobs = append(h.jobs[:0:0], h.jobs[over:]...)
+	}
+}
+
+// Recent returns up to limit most recent jobs, newest first (limit <= 0 means all).
+func (h *MemoryHistory) Recent(limit int) []output.JobRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	n := len(h.jobs)
+	if limit > 0 && limit < n {
+		n = limit
+	}
+	out := make([]output.JobRecord, 0, n)
+	for i := len(h.jobs) - 1; i >= 0 && len(out) < n; i-- {
+		out = append(out, h.jobs[i])
+	}
+	return out
+}
+
+// Ensure MemoryHistory implements output.JobHistory interface
+var _ output.JobHistory = (*MemoryHistory)(nil)
@@ -0,0 +1,44 @@
+package junit
+
+import "encoding/xml"
+
+// The JUnit XML elements ContractCheck emits, in the dialect understood by
+// Jenkins, GitLab, Azure DevOps and GitHub test-report actions.
+
+type testSuites struct {
+	XMLName  xml.Name    `xml:"testsuites"`
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Errors   int         `xml:"errors,attr"`
+	Suites   []testSuite `xml:"testsuite"`
+}
+
+type testSuite struct {
+	Name     string     `xml:"name,attr"`
+	Tests    int        `xml:"tests,attr"`
+	Failures int        `xml:"failures,attr"`
+	Errors   int        `xml:"errors,attr"`
+	Skipped  int        `xml:"skipped,attr"`
+	File     string     `xml:"file,attr,omitempty"`
+	Cases    []testCase `xml:"testcase"`
+}
+
+type testCase struct {
+	Name      string   `xml:"name,attr"`
+	ClassName string   `xml:"classname,attr"`
+	File      string   `xml:"file,attr,omitempty"`
+	Failure   *failure `xml:"failure,omitempty"`
+	SystemOut *output  `xml:"system-out,omitempty"`
+}
+
+type failure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",cdata"`
+}
+
+// output is a text block kept verbatim (multi-line) via CDATA.
+type output struct {
+	Text string `xml:",cdata"`
+}
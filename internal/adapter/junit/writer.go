@@ -0,0 +1,236 @@
+// Package junit renders reports as JUnit XML, the format CI dashboards and
+// test-result viewers ingest.
+//
+// Mapping:
+//   - every checked spec (report.Subject, or a file named by findings) is a <testsuite>;
+//   - every check and every operation of that spec is a <testcase>;
+//   - findings at or above the fail-on severity are the case's <failure>,
+//     lower ones are listed in its <system-out>.
+package junit
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+// DefaultName is reported as the <testsuites> name.
+const DefaultName = "ContractCheck"
+
+// noFile names the suite of findings without a file.
+const noFile = "(no file)"
+
+// Writer converts a report into a JUnit XML document.
+type Writer struct {
+	name   string
+	failOn report.Severity
+}
+
+// Option configures a Writer.
+type Option func(*Writer)
+
+// WithName overrides the <testsuites> name.
+func WithName(name string) Option {
+	return func(w *Writer) {
+		if name != "" {
+			w.name = name
+		}
+	}
+}
+
+// WithFailOn sets the lowest severity that fails a test case (default: error).
+func WithFailOn(sev report.Severity) Option {
+	return func(w *Writer) {
+		if sev != "" {
+			w.failOn = sev
+		}
+	}
+}
+
+// NewWriter builds a Writer with safe defaults.
+func NewWriter(opts ...Option) *Writer {
+	w := &Writer{name: DefaultName, failOn: report.SEVERITY_ERROR}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Write encodes r as an indented JUnit XML document. Output is
+// deterministic: no timestamps or durations are recorded.
+func (w *Writer) Write(out io.Writer, r report.Report) error {
+	doc := w.build(r)
+	if _, err := io.WriteString(out, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(out)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(out, "\n")
+	return err
+}
+
+// suiteBuilder collects the cases of one suite in report order.
+type suiteBuilder struct {
+	file  string
+	names []string
+	cases map[string][]report.Finding
+}
+
+func (s *suiteBuilder) add(name string) {
+	if _, ok := s.cases[name]; !ok {
+		s.cases[name] = nil
+		s.names = append(s.names, name)
+	}
+}
+
+func (w *Writer) build(r report.Report) testSuites {
+	var order []string
+	suites := map[string]*suiteBuilder{}
+	suite := func(file string) *suiteBuilder {
+		if file == "" {
+			file = noFile
+		}
+		s, ok := suites[file]
+		if !ok {
+			s = &suiteBuilder{file: file, cases: map[string][]report.Finding{}}
+			suites[file] = s
+			order = append(order, file)
+		}
+		return s
+	}
+
+	// Declared subjects first: checks in run order, then operations sorted.
+	for _, sub := range r.Subjects {
+		s := suite(sub.File)
+		for _, c := range sub.Checks {
+			s.add(c)
+		}
+		ops := append([]string(nil), sub.Operations...)
+		sort.Strings(ops)
+		for _, op := range ops {
+			s.add(op)
+		}
+	}
+
+	findings := append([]report.Finding(nil), r.Findings...)
+	report.Sort(findings)
+	for _, f := range findings {
+		s := suite(f.File)
+		name := caseName(f)
+		s.add(name)
+		s.cases[name] = append(s.cases[name], f)
+	}
+
+	doc := testSuites{Name: w.name}
+	for _, file := range order {
+		ts := w.suite(suites[file])
+		doc.Tests += ts.Tests
+		doc.Failures += ts.Failures
+		doc.Suites = append(doc.Suites, ts)
+	}
+	return doc
+}
+
+func (w *Writer) suite(s *suiteBuilder) testSuite {
+	ts := testSuite{Name: s.file, Tests: len(s.names)}
+	if s.file != noFile {
+		ts.File = s.file
+	}
+	for _, name := range s.names {
+		tc := testCase{Name: name, ClassName: s.file, File: ts.File}
+		var failed, other []report.Finding
+		for _, f := range s.cases[name] {
+			if f.Severity.Rank() <= w.failOn.Rank() {
+				failed = append(failed, f)
+			} else {
+				other = append(other, f)
+			}
+		}
+		if len(failed) > 0 {
+			tc.Failure = toFailure(failed)
+			ts.Failures++
+		}
+		if len(other) > 0 {
+			tc.SystemOut = &output{Text: describeAll(other)}
+		}
+		ts.Cases = append(ts.Cases, tc)
+	}
+	return ts
+}
+
+// caseName files a finding under its operation, else its check, else its rule.
+func caseName(f report.Finding) string {
+	switch {
+	case f.Operation != "":
+		return f.Operation
+	case f.Check != "":
+		return f.Check
+	default:
+		return f.RuleID
+	}
+}
+
+// toFailure folds every failing finding of a case into its single <failure>.
+func toFailure(findings []report.Finding) *failure {
+	typ := findings[0].RuleID
+	for _, f := range findings[1:] {
+		if f.RuleID != typ {
+			typ = "multiple"
+			break
+		}
+	}
+	msg := findings[0].Message
+	if len(findings) > 1 {
+		msg = fmt.Sprintf("%d violations, first: %s", len(findings), msg)
+	}
+	return &failure{Message: msg, Type: typ, Body: describeAll(findings)}
+}
+
+// describeAll renders findings as readable text blocks.
+func describeAll(findings []report.Finding) string {
+	blocks := make([]string, 0, len(findings))
+	for _, f := range findings {
+		blocks = append(blocks, describe(f))
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+func describe(f report.Finding) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s [%s] %s", f.Severity, f.RuleID, f.Message)
+	if loc := location(f); loc != "" {
+		fmt.Fprintf(&b, "\n  at %s", loc)
+	}
+	if f.Pointer != "" {
+		fmt.Fprintf(&b, "\n  pointer: %s", f.Pointer)
+	}
+	if f.Detail != "" {
+		fmt.Fprintf(&b, "\n  detail: %s", f.Detail)
+	}
+	return b.String()
+}
+
+// location renders "file[:line[:column]]".
+func location(f report.Finding) string {
+	if f.File == "" {
+		return ""
+	}
+	loc := f.File
+	if f.Line > 0 {
+		loc += fmt.Sprintf(":%d", f.Line)
+		if f.Column > 0 {
+			loc += fmt.Sprintf(":%d", f.Column)
+		}
+	}
+	return loc
+}
+
+// compile-time check
+var _ report.Writer = (*Writer)(nil)
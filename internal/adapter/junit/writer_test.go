@@ -0,0 +1,96 @@
+package junit_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/betoth/contractcheck/internal/adapter/junit"
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// golden compares got with testdata/<name>, or rewrites it with -update.
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run with -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s (run with -update to accept):\n%s", path, got)
+	}
+	// Whatever the golden says, the output must be well-formed XML.
+	if err := xml.Unmarshal(got, new(struct{})); err != nil {
+		t.Errorf("invalid XML: %v", err)
+	}
+}
+
+func render(t *testing.T, w *junit.Writer, r report.Report) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := w.Write(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestWriter_LoaderError(t *testing.T) {
+	err := openapi.NewValidationError(openapi.INVALID_SYNTAX, "Invalid YAML/JSON syntax", "api/openapi.yaml",
+		errors.New("yaml: line 7: did not find expected node content"))
+	var ae *customerrors.AppError
+	errors.As(err, &ae)
+	ae.Details[customerrors.DetailLine] = 7
+
+	golden(t, "load_error.xml", render(t, junit.NewWriter(), report.Report{
+		Subjects: []report.Subject{{File: "api/openapi.yaml", Checks: []string{report.CHECK_LOAD}}},
+		Findings: []report.Finding{report.FromError(err)},
+	}))
+}
+
+func TestWriter_BreakingChanges(t *testing.T) {
+	cmp := report.Comparison{
+		Base:       "a1b2c3d4e5f6:api/openapi.yaml",
+		Head:       "0f9e8d7c6b5a:api/openapi.yaml",
+		Operations: []string{"DELETE /pets/{id}", "GET /pets", "GET /pets/{id}", "POST /pets"},
+		Changes: []report.Change{
+			{ID: "operation-removed", Level: report.CHANGE_BREAKING, Operation: "DELETE /pets/{id}", Message: "operation was removed"},
+			{ID: "property-removed", Level: report.CHANGE_BREAKING, Operation: "GET /pets/{id}", Location: "response 200 application/json: name", Message: "property name was removed"},
+			{ID: "enum-value-added", Level: report.CHANGE_BREAKING, Operation: "GET /pets/{id}", Location: "response 200 application/json: status", Message: "enum value pending was added to status"},
+			{ID: "property-added", Level: report.CHANGE_NON_BREAKING, Operation: "POST /pets", Location: "request body application/json: tag", Message: "property tag was added"},
+		},
+	}
+	golden(t, "breaking_changes.xml", render(t, junit.NewWriter(), cmp.Report("api/openapi.yaml")))
+}
+
+// Findings of checks that declare no subject (e.g. validating recorded
+// traffic) still get a suite per file and a case per operation.
+func TestWriter_FindingsWithoutSubjectsAndFailOn(t *testing.T) {
+	r := report.Report{
+		Subjects: []report.Subject{{File: "api/openapi.yaml", Checks: []string{report.CHECK_LOAD}, Operations: []string{"GET /pets"}}},
+		Findings: []report.Finding{
+			{RuleID: "response-status-undocumented", Check: "traffic", Severity: report.SEVERITY_ERROR, Message: "status 500 is not documented", File: "api/openapi.yaml", Operation: "GET /pets", Detail: "GET /pets?limit=-1 -> 500"},
+			{RuleID: "missing-description", Check: "lint", Severity: report.SEVERITY_WARNING, Message: "operation has no description", File: "api/openapi.yaml", Line: 12, Column: 5, Pointer: "/paths/~1pets/get", Operation: "GET /pets"},
+			{RuleID: "unreachable-server", Check: "traffic", Severity: report.SEVERITY_WARNING, Message: "server did not answer", File: "traffic/session.har"},
+		},
+	}
+	golden(t, "fail_on_error.xml", render(t, junit.NewWriter(junit.WithName("contracts")), r))
+	golden(t, "fail_on_warning.xml", render(t, junit.NewWriter(junit.WithFailOn(report.SEVERITY_WARNING)), r))
+}
+
+func TestWriter_EmptyReport(t *testing.T) {
+	golden(t, "empty.xml", render(t, junit.NewWriter(), report.Report{}))
+}
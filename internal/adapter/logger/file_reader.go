@@ -0,0 +1,219 @@
+package logger
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	defaultReadLimit = 200
+	maxReadLimit     = 5000
+	// maxLineBytes bounds a single JSON line; longer lines are skipped.
+	maxLineBytes = 1 << 20
+)
+
+// FileReader reads back entries written by the JSON file sink, including
+// rotated backups, so the UI can tail and filter recent logs.
+type FileReader struct {
+	path string
+}
+
+// NewFileReader builds a reader over the active log file at path
+// (see LogFilePath). Backups are discovered next to it.
+func NewFileReader(path string) *FileReader {
+	return &FileReader{path: path}
+}
+
+// Path returns the active log file path.
+func (r *FileReader) Path() string { return r.path }
+
+// Recent returns up to q.Limit most recent entries matching q, oldest first.
+// Files are scanned newest first and scanning stops once the limit is met.
+func (r *FileReader) Recent(ctx context.Context, q output.LogQuery) ([]output.LogEntry, error) {
+	if r.path == "" {
+		return nil, fmt.Errorf("log file sink is disabled")
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = defaultReadLimit
+	}
+	if limit > maxReadLimit {
+		limit = maxReadLimit
+	}
+
+	match, err := newMatcher(q)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := r.files()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []output.LogEntry
+	for _, f := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		entries, err := readFile(f, match)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		out = append(entries, out...)
+		if len(out) >= limit {
+			break
+		}
+	}
+
+	if len(out) > limit {
+		out = out[len(out)-limit:]
+	}
+	return out, nil
+}
+
+// files lists the active file followed by rotated backups, newest first.
+// lumberjack timestamps sort lexically, so a reverse name sort is enough.
+func (r *FileReader) files() ([]string, error) {
+	ext := filepath.Ext(r.path)
+	prefix := strings.TrimSuffix(filepath.Base(r.path), ext) + "-"
+
+	dirEntries, err := os.ReadDir(filepath.Dir(r.path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var backups []string
+	for _, e := range dirEntries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if strings.HasSuffix(name, ext) || strings.HasSuffix(name, ext+".gz") {
+			backups = append(backups, filepath.Join(filepath.Dir(r.path), name))
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+
+	return append([]string{r.path}, backups...), nil
+}
+
+// readFile decodes every matching entry of a (possibly gzipped) JSON log file.
+// Malformed lines are skipped: the file may be mid-write or hand-edited.
+func readFile(path string, match func(output.LogEntry) bool) ([]output.LogEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var src io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		src = gz
+	}
+
+	var out []output.LogEntry
+	sc := bufio.NewScanner(src)
+	sc.Buffer(make([]byte, 64*1024), maxLineBytes)
+	for sc.Scan() {
+		entry, ok := decodeEntry(sc.Bytes())
+		if ok && match(entry) {
+			out = append(out, entry)
+		}
+	}
+	if err := sc.Err(); err != nil && err != bufio.ErrTooLong {
+		return out, err
+	}
+	return out, nil
+}
+
+// decodeEntry maps one JSON line into a LogEntry; non-reserved keys become Fields.
+func decodeEntry(line []byte) (output.LogEntry, bool) {
+	var raw map[string]any
+	if err := json.Unmarshal(line, &raw); err != nil {
+		return output.LogEntry{}, false
+	}
+
+	entry := output.LogEntry{
+		Level:   stringField(raw, keyLevel),
+		Logger:  stringField(raw, keyLogger),
+		Message: stringField(raw, keyMessage),
+		Caller:  stringField(raw, keyCaller),
+	}
+	if ts := stringField(raw, keyTime); ts != "" {
+		entry.Time, _ = time.Parse(time.RFC3339Nano, ts)
+	}
+
+	for _, k := range []string{keyTime, keyLevel, keyLogger, keyMessage, keyCaller} {
+		delete(raw, k)
+	}
+	if len(raw) > 0 {
+		entry.Fields = raw
+	}
+	return entry, true
+}
+
+func stringField(raw map[string]any, key string) string {
+	s, _ := raw[key].(string)
+	return s
+}
+
+// newMatcher compiles q into a predicate.
+func newMatcher(q output.LogQuery) (func(output.LogEntry) bool, error) {
+	minLevel := zapcore.DebugLevel
+	if q.Level != "" {
+		lvl, err := zapcore.ParseLevel(q.Level)
+		if err != nil {
+			return nil, fmt.Errorf("invalid level filter %q", q.Level)
+		}
+		minLevel = lvl
+	}
+	needle := strings.ToLower(q.Contains)
+
+	return func(e output.LogEntry) bool {
+		if lvl, err := zapcore.ParseLevel(e.Level); err == nil && lvl < minLevel {
+			return false
+		}
+		if q.Logger != "" && !strings.HasPrefix(e.Logger, q.Logger) {
+			return false
+		}
+		if needle == "" {
+			return true
+		}
+		if strings.Contains(strings.ToLower(e.Message), needle) {
+			return true
+		}
+		if len(e.Fields) == 0 {
+			return false
+		}
+		b, _ := json.Marshal(e.Fields)
+		return strings.Contains(strings.ToLower(string(b)), needle)
+	}, nil
+}
+
+// Ensure FileReader implements output.LogReader interface
+var _ output.LogReader = (*FileReader)(nil)
@@ -0,0 +1,69 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/betoth/contractcheck/internal/adapter/logger"
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/config"
+)
+
+func TestFileSink_RoundTripWithFilters(t *testing.T) {
+	cfg := config.LogConfig{Level: "info", Dir: t.TempDir(), MaxSizeMB: 1}
+
+	l := logger.New(cfg)
+	l.Debug("below level, not persisted")
+	l.Info("import started", "file", "petstore.yaml")
+	l.Named("ui").Warn("slow render", "ms", 1200)
+	l.Error("import failed", "file", "broken.yaml")
+	_ = l.Sync()
+
+	r := logger.NewFileReader(logger.LogFilePath(cfg))
+	ctx := context.Background()
+
+	all, err := r.Recent(ctx, output.LogQuery{})
+	if err != nil {
+		t.Fatalf("Recent: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("expected 3 persisted entries, got %d: %#v", len(all), all)
+	}
+	if all[0].Message != "import started" || all[0].Fields["file"] != "petstore.yaml" {
+		t.Fatalf("unexpected first entry: %#v", all[0])
+	}
+
+	cases := []struct {
+		name  string
+		query output.LogQuery
+		want  []string
+	}{
+		{"min level", output.LogQuery{Level: "warn"}, []string{"slow render", "import failed"}},
+		{"logger prefix", output.LogQuery{Logger: "ui"}, []string{"slow render"}},
+		{"contains field", output.LogQuery{Contains: "BROKEN"}, []string{"import failed"}},
+		{"limit keeps newest", output.LogQuery{Limit: 1}, []string{"import failed"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := r.Recent(ctx, tc.query)
+			if err != nil {
+				t.Fatalf("Recent: %v", err)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("expected %d entries, got %d: %#v", len(tc.want), len(got), got)
+			}
+			for i, w := range tc.want {
+				if got[i].Message != w {
+					t.Fatalf("entry %d: expected %q, got %q", i, w, got[i].Message)
+				}
+			}
+		})
+	}
+}
+
+func TestFileReader_DisabledSink(t *testing.T) {
+	r := logger.NewFileReader("")
+	if _, err := r.Recent(context.Background(), output.LogQuery{}); err == nil {
+		t.Fatal("expected error when file sink is disabled")
+	}
+}
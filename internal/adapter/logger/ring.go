@@ -0,0 +1,184 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"go.uber.org/zap"
+)
+
+// DefaultRingCapacity is the number of entries kept when no capacity is given.
+const DefaultRingCapacity = 1000
+
+// RingBuffer keeps the last N log entries in memory for diagnostics export.
+// It is safe for concurrent use and implements output.LogReader.
+type RingBuffer struct {
+	mu      sync.Mutex
+	entries []output.LogEntry
+	next    int
+	full    bool
+}
+
+// NewRingBuffer allocates a buffer holding up to capacity entries.
+func NewRingBuffer(capacity int) *RingBuffer {
+	if capacity <= 0 {
+		capacity = DefaultRingCapacity
+	}
+	return &RingBuffer{entries: make([]output.LogEntry, capacity)}
+}
+
+// add stores e, overwriting the oldest entry once the buffer is full.
+func (b *RingBuffer) add(e output.LogEntry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.entries[b.next] = e
+	b.next = (b.next + 1) % len(b.entries)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// Snapshot returns a copy of the buffered entries, oldest first.
+func (b *RingBuffer) Snapshot() []output.LogEntry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.full {
+		return append([]output.LogEntry(nil), b.entries[:b.next]...)
+	}
+	out := make([]output.LogEntry, 0, len(b.entries))
+	out = append(out, b.entries[b.next:]...)
+	return append(out, b.entries[:b.next]...)
+}
+
+// Recent filters the snapshot with the same semantics as FileReader.Recent.
+func (b *RingBuffer) Recent(ctx context.Context, q output.LogQuery) ([]output.LogEntry, error) {
+	match, err := newMatcher(q)
+	if err != nil {
+		return nil, err
+	}
+	var out []output.LogEntry
+	for _, e := range b.Snapshot() {
+		if match(e) {
+			out = append(out, e)
+		}
+	}
+	if q.Limit > 0 && len(out) > q.Limit {
+		out = out[len(out)-q.Limit:]
+	}
+	return out, nil
+}
+
+// Path returns "" because the buffer is not backed by a file.
+func (b *RingBuffer) Path() string { return "" }
+
+// RingLogger is an output.Logger decorator that records every call into a
+// shared RingBuffer before delegating to the wrapped logger.
+// Entries are captured regardless of the wrapped logger's level.
+type RingLogger struct {
+	next   output.Logger
+	buf    *RingBuffer
+	name   string
+	fields []any
+}
+
+// NewRingLogger decorates next so its entries are also kept in buf.
+// When next is a *ZapLogger its caller skip is bumped so reported callers
+// point at application code rather than this decorator.
+func NewRingLogger(next output.Logger, buf *RingBuffer) *RingLogger {
+	if zl, ok := next.(*ZapLogger); ok {
+		next = &ZapLogger{zl.SugaredLogger.WithOptions(zap.AddCallerSkip(1))}
+	}
+	return &RingLogger{next: next, buf: buf}
+}
+
+func (l *RingLogger) With(kv ...any) output.Logger {
+	fields := append(append([]any{}, l.fields...), kv...)
+	return &RingLogger{next: l.next.With(kv...), buf: l.buf, name: l.name, fields: fields}
+}
+
+func (l *RingLogger) Named(name string) output.Logger {
+	full := name
+	if l.name != "" {
+		full = l.name + "." + name
+	}
+	return &RingLogger{next: l.next.Named(name), buf: l.buf, name: full, fields: l.fields}
+}
+
+func (l *RingLogger) Info(msg string, kv ...any) {
+	l.record("info", msg, kv)
+	l.next.Info(msg, kv...)
+}
+
+func (l *RingLogger) Warn(msg string, kv ...any) {
+	l.record("warn", msg, kv)
+	l.next.Warn(msg, kv...)
+}
+
+func (l *RingLogger) Error(msg string, kv ...any) {
+	l.record("error", msg, kv)
+	l.next.Error(msg, kv...)
+}
+
+func (l *RingLogger) Debug(msg string, kv ...any) {
+	l.record("debug", msg, kv)
+	l.next.Debug(msg, kv...)
+}
+
+func (l *RingLogger) Sync() error { return l.next.Sync() }
+
+func (l *RingLogger) record(level, msg string, kv []any) {
+	entry := output.LogEntry{
+		Time:    time.Now().UTC(),
+		Level:   level,
+		Logger:  l.name,
+		Message: msg,
+	}
+	if n := len(l.fields) + len(kv); n > 0 {
+		entry.Fields = make(map[string]any, n/2+1)
+		putFields(entry.Fields, l.fields)
+		putFields(entry.Fields, kv)
+	}
+	l.buf.add(entry)
+}
+
+// putFields copies sugared key/value pairs into dst, mirroring zap's handling
+// of odd counts and non-string keys.
+func putFields(dst map[string]any, kv []any) {
+	for i := 0; i < len(kv); i += 2 {
+		key, ok := kv[i].(string)
+		if !ok {
+			key = fmt.Sprint(kv[i])
+		}
+		if i+1 >= len(kv) {
+			dst["ignored"] = key
+			break
+		}
+		dst[key] = plainValue(kv[i+1])
+	}
+}
+
+// plainValue keeps JSON-friendly values and stringifies everything else,
+// so snapshots can always be marshalled.
+func plainValue(v any) any {
+	switch t := v.(type) {
+	case nil, string, bool, int, int32, int64, uint, uint32, uint64, float32, float64,
+		[]int, []string, map[string]any, []any, json.Number:
+		return t
+	case error:
+		return t.Error()
+	case fmt.Stringer:
+		return t.String()
+	default:
+		return fmt.Sprintf("%v", t)
+	}
+}
+
+// Ensure RingLogger and RingBuffer implement their ports
+var (
+	_ output.Logger    = (*RingLogger)(nil)
+	_ output.LogReader = (*RingBuffer)(nil)
+)
@@ -0,0 +1,18 @@
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// NewSessionID returns a short random identifier for one application run.
+// It falls back to a timestamp if the system RNG is unavailable.
+func NewSessionID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b[:])
+}
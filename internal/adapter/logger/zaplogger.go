@@ -1,26 +1,90 @@
 package logger
 
 import (
+	"os"
+	"path/filepath"
+
 	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/config"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// LogFileName is the active log file inside LogConfig.Dir.
+// Rotated backups are named by lumberjack as "contractcheck-<timestamp>.log[.gz]".
+const LogFileName = "contractcheck.log"
+
+// JSON keys used by the file sink; FileReader decodes entries with the same keys.
+const (
+	keyTime    = "ts"
+	keyLevel   = "level"
+	keyLogger  = "logger"
+	keyMessage = "msg"
+	keyCaller  = "caller"
 )
 
 type ZapLogger struct {
 	*zap.SugaredLogger
 }
 
-func New() output.Logger {
-	cfg := zap.NewProductionConfig()
-	cfg.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
-	cfg.Encoding = "console"
-
-	l, err := cfg.Build()
+// New builds the backend logger from cfg.
+// Output always goes to stderr (console encoding) and, when cfg.Dir is set,
+// also to a size/age rotated JSON file under cfg.Dir.
+// If the file sink cannot be prepared the logger degrades to console only.
+func New(cfg config.LogConfig) output.Logger {
+	level, err := zapcore.ParseLevel(cfg.Level)
 	if err != nil {
-		return &ZapLogger{zap.NewNop().Sugar()}
+		level = zapcore.InfoLevel
+	}
+	atom := zap.NewAtomicLevelAt(level)
+
+	cores := []zapcore.Core{
+		zapcore.NewCore(zapcore.NewConsoleEncoder(encoderConfig()), zapcore.Lock(os.Stderr), atom),
+	}
+
+	path := LogFilePath(cfg)
+	var sinkErr error
+	if path != "" {
+		if sinkErr = os.MkdirAll(filepath.Dir(path), 0o755); sinkErr == nil {
+			rotator := &lumberjack.Logger{
+				Filename:   path,
+				MaxSize:    cfg.MaxSizeMB,
+				MaxBackups: cfg.MaxBackups,
+				MaxAge:     cfg.MaxAgeDays,
+				Compress:   cfg.Compress,
+			}
+			cores = append(cores, zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig()), zapcore.AddSync(rotator), atom))
+		}
+	}
+
+	l := zap.New(zapcore.NewTee(cores...), zap.AddCaller(), zap.AddCallerSkip(1))
+	if sinkErr != nil {
+		l.Warn("log file sink disabled", zap.String("dir", cfg.Dir), zap.Error(sinkErr))
 	}
 	return &ZapLogger{l.Sugar()}
 }
 
+// LogFilePath returns the active log file path for cfg, or "" if the file sink is disabled.
+func LogFilePath(cfg config.LogConfig) string {
+	if cfg.Dir == "" {
+		return ""
+	}
+	return filepath.Join(cfg.Dir, LogFileName)
+}
+
+// encoderConfig is shared by the console and file encoders so both carry the same keys.
+func encoderConfig() zapcore.EncoderConfig {
+	enc := zap.NewProductionEncoderConfig()
+	enc.TimeKey = keyTime
+	enc.LevelKey = keyLevel
+	enc.NameKey = keyLogger
+	enc.MessageKey = keyMessage
+	enc.CallerKey = keyCaller
+	enc.EncodeTime = zapcore.RFC3339NanoTimeEncoder
+	return enc
+}
+
 func (l *ZapLogger) With(kv ...any) output.Logger {
 	return &ZapLogger{l.SugaredLogger.With(kv...)}
 }
@@ -0,0 +1,70 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"gopkg.in/yaml.v3"
+)
+
+// DocWriter serializes an OpenAPIDoc as indented JSON or block-style YAML.
+// Key order of the document JSON is preserved in both formats.
+type DocWriter struct{}
+
+// NewDocWriter builds a DocWriter.
+func NewDocWriter() *DocWriter { return &DocWriter{} }
+
+// Write emits doc in the requested format.
+func (DocWriter) Write(w io.Writer, doc openapi.OpenAPIDoc, format openapi.Format) error {
+	switch format {
+	case openapi.FORMAT_JSON:
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, doc.JSON, "", "  "); err != nil {
+			return fmt.Errorf("encode JSON: %w", err)
+		}
+		buf.WriteByte('\n')
+		_, err := buf.WriteTo(w)
+		return err
+
+	case openapi.FORMAT_YAML:
+		node, err := jsonToYAMLNode(doc.JSON)
+		if err != nil {
+			return err
+		}
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(node); err != nil {
+			return fmt.Errorf("encode YAML: %w", err)
+		}
+		return enc.Close()
+
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// jsonToYAMLNode parses JSON (a YAML subset) into an order-preserving node tree
+// and switches it from flow style to block style.
+func jsonToYAMLNode(raw []byte) (*yaml.Node, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(raw, &node); err != nil {
+		return nil, fmt.Errorf("convert JSON to YAML: %w", err)
+	}
+	resetStyle(&node)
+	return &node, nil
+}
+
+// resetStyle clears flow/quoting styles so the encoder picks idiomatic YAML;
+// strings that would otherwise change type (e.g. "200", "true") stay quoted.
+func resetStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		resetStyle(c)
+	}
+}
+
+// Ensure DocWriter implements openapi.Writer interface
+var _ openapi.Writer = (*DocWriter)(nil)
@@ -0,0 +1,386 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"gopkg.in/yaml.v3"
+)
+
+// YAMLFormatter rewrites spec files in canonical order on top of yaml.v3 nodes.
+//
+// Design notes:
+//   - Working on the source node tree (not on kin-openapi's model) keeps the
+//     authoring format and lets comments travel with the keys they annotate.
+//   - Key order follows the OpenAPI specification's field order per object
+//     type; unknown keys keep their authored order, extensions (x-*) go last.
+//   - paths and every components section are sorted; schema properties keep
+//     their authored order since it is meaningful for readers.
+type YAMLFormatter struct{}
+
+// NewYAMLFormatter builds a YAMLFormatter.
+func NewYAMLFormatter() *YAMLFormatter { return &YAMLFormatter{} }
+
+// Format renders filePath canonically and reports whether it already was.
+func (YAMLFormatter) Format(ctx context.Context, filePath string, opts openapi.FormatOptions) (openapi.FormatResult, error) {
+	kin := NewKinLoader()
+
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return openapi.FormatResult{}, kin.normalizeError(filePath, err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(src, &root); err != nil {
+		return openapi.FormatResult{}, kin.normalizeError(filePath, err)
+	}
+	if err := ctx.Err(); err != nil {
+		return openapi.FormatResult{}, err
+	}
+
+	format := opts.Format
+	if format == "" {
+		format = formatFromPath(filePath)
+	}
+
+	canonicalize(&root, "document")
+	normalizeStyle(&root, opts.StripComments || format == openapi.FORMAT_JSON)
+
+	var out bytes.Buffer
+	switch format {
+	case openapi.FORMAT_YAML:
+		enc := yaml.NewEncoder(&out)
+		enc.SetIndent(2)
+		if err := enc.Encode(&root); err != nil {
+			return openapi.FormatResult{}, kin.normalizeError(filePath, err)
+		}
+		if err := enc.Close(); err != nil {
+			return openapi.FormatResult{}, kin.normalizeError(filePath, err)
+		}
+	case openapi.FORMAT_JSON:
+		if err := writeNodeJSON(&out, &root, ""); err != nil {
+			return openapi.FormatResult{}, kin.normalizeError(filePath, err)
+		}
+		out.WriteByte('\n')
+	default:
+		return openapi.FormatResult{}, fmt.Errorf("unsupported format %q", format)
+	}
+
+	return openapi.FormatResult{
+		Formatted: out.Bytes(),
+		Format:    format,
+		Canonical: bytes.Equal(src, out.Bytes()),
+	}, nil
+}
+
+// formatFromPath infers the source format from the file extension.
+func formatFromPath(p string) openapi.Format {
+	if strings.EqualFold(filepath.Ext(p), ".json") {
+		return openapi.FORMAT_JSON
+	}
+	return openapi.FORMAT_YAML
+}
+
+// shape describes canonical ordering for one OpenAPI object type.
+type shape struct {
+	order    []string          // known keys in specification order
+	children map[string]string // key -> shape of its value (sequences apply it per item)
+	each     string            // shape applied to every value (map-like objects)
+	sorted   bool              // sort keys lexically (paths, components sections)
+	codes    bool              // sort keys as response codes (default last)
+}
+
+var schemaKeys = []string{
+	"$ref", "title", "description", "type", "format", "enum", "const", "default",
+	"nullable", "readOnly", "writeOnly", "deprecated",
+	"multipleOf", "maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum",
+	"maxLength", "minLength", "pattern",
+	"items", "maxItems", "minItems", "uniqueItems",
+	"properties", "additionalProperties", "required", "maxProperties", "minProperties",
+	"allOf", "oneOf", "anyOf", "not", "discriminator", "xml", "externalDocs", "example", "examples",
+}
+
+var shapes = map[string]shape{
+	"document": {
+		order: []string{"openapi", "info", "jsonSchemaDialect", "servers", "paths", "webhooks", "components", "security", "tags", "externalDocs"},
+		children: map[string]string{
+			"info": "info", "paths": "paths", "webhooks": "paths", "components": "components",
+		},
+	},
+	"info":  {order: []string{"title", "summary", "description", "termsOfService", "contact", "license", "version"}},
+	"paths": {sorted: true, each: "pathItem"},
+	"pathItem": {
+		order: []string{"$ref", "summary", "description", "servers", "parameters", "get", "put", "post", "delete", "options", "head", "patch", "trace"},
+		children: map[string]string{
+			"parameters": "parameter",
+			"get":        "operation", "put": "operation", "post": "operation", "delete": "operation",
+			"options": "operation", "head": "operation", "patch": "operation", "trace": "operation",
+		},
+	},
+	"operation": {
+		order: []string{"tags", "summary", "description", "externalDocs", "operationId", "parameters", "requestBody", "responses", "callbacks", "deprecated", "security", "servers"},
+		children: map[string]string{
+			"parameters": "parameter", "requestBody": "requestBody", "responses": "responses", "callbacks": "callbacks",
+		},
+	},
+	"callbacks": {sorted: true, each: "paths"},
+	"responses": {codes: true, each: "response"},
+	"response": {
+		order:    []string{"$ref", "description", "headers", "content", "links"},
+		children: map[string]string{"headers": "headers", "content": "content"},
+	},
+	"headers": {sorted: true, each: "parameter"},
+	"content": {each: "mediaType"},
+	"mediaType": {
+		order:    []string{"schema", "example", "examples", "encoding"},
+		children: map[string]string{"schema": "schema"},
+	},
+	"parameter": {
+		order:    []string{"$ref", "name", "in", "description", "required", "deprecated", "allowEmptyValue", "style", "explode", "allowReserved", "schema", "example", "examples", "content"},
+		children: map[string]string{"schema": "schema", "content": "content"},
+	},
+	"requestBody": {
+		order:    []string{"$ref", "description", "required", "content"},
+		children: map[string]string{"content": "content"},
+	},
+	"schema": {
+		order: schemaKeys,
+		children: map[string]string{
+			"items": "schema", "additionalProperties": "schema", "not": "schema",
+			"allOf": "schema", "oneOf": "schema", "anyOf": "schema", "properties": "properties",
+		},
+	},
+	"properties": {each: "schema"},
+	"components": {
+		order: []string{"schemas", "responses", "parameters", "examples", "requestBodies", "headers", "securitySchemes", "links", "callbacks", "pathItems"},
+		children: map[string]string{
+			"schemas": "schemaSection", "responses": "responseSection", "parameters": "parameterSection",
+			"examples": "sortedSection", "requestBodies": "requestBodySection", "headers": "headers",
+			"securitySchemes": "sortedSection", "links": "sortedSection", "callbacks": "callbackSection",
+			"pathItems": "pathItemSection",
+		},
+	},
+	"schemaSection":      {sorted: true, each: "schema"},
+	"responseSection":    {sorted: true, each: "response"},
+	"parameterSection":   {sorted: true, each: "parameter"},
+	"requestBodySection": {sorted: true, each: "requestBody"},
+	"callbackSection":    {sorted: true, each: "callbacks"},
+	"pathItemSection":    {sorted: true, each: "pathItem"},
+	"sortedSection":      {sorted: true},
+}
+
+// canonicalize reorders n in place according to the named shape.
+func canonicalize(n *yaml.Node, kind string) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		for _, c := range n.Content {
+			canonicalize(c, kind)
+		}
+		return
+	case yaml.SequenceNode:
+		for _, c := range n.Content {
+			canonicalize(c, kind)
+		}
+		return
+	case yaml.MappingNode:
+	default:
+		return
+	}
+
+	sh, ok := shapes[kind]
+	if !ok {
+		return
+	}
+
+	// A comment above the first root key is the file header: keep it on top.
+	var header string
+	if kind == "document" && len(n.Content) > 0 {
+		header, n.Content[0].HeadComment = n.Content[0].HeadComment, ""
+	}
+	reorder(n, sh)
+	if header != "" {
+		first := n.Content[0]
+		first.HeadComment = strings.TrimSpace(header + "\n" + first.HeadComment)
+	}
+
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, val := n.Content[i].Value, n.Content[i+1]
+		switch {
+		case sh.children[key] != "":
+			canonicalize(val, sh.children[key])
+		case sh.each != "" && !strings.HasPrefix(key, "x-"):
+			canonicalize(val, sh.each)
+		}
+	}
+}
+
+// reorder stably sorts the key/value pairs of a mapping node.
+func reorder(n *yaml.Node, sh shape) {
+	type pair struct{ k, v *yaml.Node }
+	pairs := make([]pair, 0, len(n.Content)/2)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		pairs = append(pairs, pair{n.Content[i], n.Content[i+1]})
+	}
+
+	rank := make(map[string]int, len(sh.order))
+	for i, k := range sh.order {
+		rank[k] = i
+	}
+	group := func(k string) int {
+		if strings.HasPrefix(k, "x-") {
+			return 2
+		}
+		if _, known := rank[k]; known || sh.sorted || sh.codes {
+			return 0
+		}
+		return 1
+	}
+
+	sort.SliceStable(pairs, func(i, j int) bool {
+		a, b := pairs[i].k.Value, pairs[j].k.Value
+		ga, gb := group(a), group(b)
+		if ga != gb {
+			return ga < gb
+		}
+		switch {
+		case ga == 2:
+			return a < b
+		case ga == 1:
+			return false // unknown keys keep authored order
+		case sh.codes:
+			return codeRank(a) < codeRank(b) || (codeRank(a) == codeRank(b) && a < b)
+		case sh.sorted:
+			return a < b
+		default:
+			return rank[a] < rank[b]
+		}
+	})
+
+	for i, p := range pairs {
+		n.Content[2*i], n.Content[2*i+1] = p.k, p.v
+	}
+}
+
+// codeRank orders response keys: 100..599, then ranges (1XX..5XX), then default.
+func codeRank(code string) int {
+	if n, err := strconv.Atoi(code); err == nil {
+		return n
+	}
+	if len(code) == 3 && strings.EqualFold(code[1:], "XX") && code[0] >= '1' && code[0] <= '5' {
+		return 1000 + int(code[0]-'0')
+	}
+	return 2000
+}
+
+// normalizeStyle switches collections to block style and scalars to the
+// encoder's default quoting, keeping literal/folded blocks for long text.
+func normalizeStyle(n *yaml.Node, stripComments bool) {
+	switch n.Kind {
+	case yaml.MappingNode, yaml.SequenceNode:
+		n.Style = 0
+	case yaml.ScalarNode:
+		if n.Style != yaml.LiteralStyle && n.Style != yaml.FoldedStyle {
+			n.Style = 0
+		}
+	}
+	if stripComments {
+		n.HeadComment, n.LineComment, n.FootComment = "", "", ""
+	}
+	for _, c := range n.Content {
+		normalizeStyle(c, stripComments)
+	}
+}
+
+// writeNodeJSON encodes a node tree as indented JSON, preserving key order.
+func writeNodeJSON(buf *bytes.Buffer, n *yaml.Node, indent string) error {
+	const step = "  "
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			buf.WriteString("null")
+			return nil
+		}
+		return writeNodeJSON(buf, n.Content[0], indent)
+
+	case yaml.AliasNode:
+		return writeNodeJSON(buf, n.Alias, indent)
+
+	case yaml.MappingNode:
+		if len(n.Content) == 0 {
+			buf.WriteString("{}")
+			return nil
+		}
+		buf.WriteString("{\n")
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, err := marshalJSON(n.Content[i].Value)
+			if err != nil {
+				return err
+			}
+			buf.WriteString(indent + step)
+			buf.Write(key)
+			buf.WriteString(": ")
+			if err := writeNodeJSON(buf, n.Content[i+1], indent+step); err != nil {
+				return err
+			}
+			if i+2 < len(n.Content) {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(indent + "}")
+		return nil
+
+	case yaml.SequenceNode:
+		if len(n.Content) == 0 {
+			buf.WriteString("[]")
+			return nil
+		}
+		buf.WriteString("[\n")
+		for i, c := range n.Content {
+			buf.WriteString(indent + step)
+			if err := writeNodeJSON(buf, c, indent+step); err != nil {
+				return err
+			}
+			if i+1 < len(n.Content) {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(indent + "]")
+		return nil
+
+	default:
+		var v any
+		if err := n.Decode(&v); err != nil {
+			return err
+		}
+		raw, err := marshalJSON(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(raw)
+		return nil
+	}
+}
+
+// marshalJSON encodes v without HTML escaping, so "<" and "&" stay readable.
+func marshalJSON(v any) ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
+
+// Ensure YAMLFormatter implements openapi.Formatter interface
+var _ openapi.Formatter = (*YAMLFormatter)(nil)
@@ -0,0 +1,80 @@
+package openapi_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	kinopenapi "github.com/betoth/contractcheck/internal/adapter/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+)
+
+func TestYAMLFormatter_CanonicalOrderAndIdempotence(t *testing.T) {
+	f := kinopenapi.NewYAMLFormatter()
+	ctx := context.Background()
+
+	res, err := f.Format(ctx, filepath.Join("testdata", "unformatted.yaml"), openapi.FormatOptions{})
+	if err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+	if res.Canonical {
+		t.Fatal("expected unformatted input to be reported as non-canonical")
+	}
+	out := string(res.Formatted)
+
+	assertOrder(t, out, "# Pet store API", "openapi:", "info:", "paths:", "components:")
+	assertOrder(t, out, "title: Demo", "version:")
+	assertOrder(t, out, "/a:", "/z:")
+	assertOrder(t, out, "summary: create", "responses:", `"201":`, "default:")
+	assertOrder(t, out, "Alpha:", "Zed:")
+	// Schema properties keep their authored order.
+	assertOrder(t, out, "b:", "a:")
+	if !strings.Contains(out, "# Alpha schema") {
+		t.Fatalf("expected comments to be preserved:\n%s", out)
+	}
+
+	// Re-formatting canonical output is a no-op.
+	path := filepath.Join(t.TempDir(), "canonical.yaml")
+	if err := os.WriteFile(path, res.Formatted, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	again, err := f.Format(ctx, path, openapi.FormatOptions{})
+	if err != nil {
+		t.Fatalf("Format (again): %v", err)
+	}
+	if !again.Canonical {
+		t.Fatalf("expected formatted output to be canonical, got diff:\n%s", again.Formatted)
+	}
+}
+
+func TestYAMLFormatter_JSONOutput(t *testing.T) {
+	res, err := kinopenapi.NewYAMLFormatter().Format(context.Background(), filepath.Join("testdata", "unformatted.yaml"), openapi.FormatOptions{Format: openapi.FORMAT_JSON})
+	if err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+	out := string(res.Formatted)
+	if res.Format != openapi.FORMAT_JSON || !strings.HasPrefix(out, "{\n  \"openapi\": \"3.0.3\"") {
+		t.Fatalf("unexpected JSON output:\n%s", out)
+	}
+	if strings.Contains(out, "#") {
+		t.Fatalf("JSON output must not carry comments:\n%s", out)
+	}
+	if !strings.Contains(out, `"<a & b>"`) {
+		t.Fatalf("expected unescaped HTML characters:\n%s", out)
+	}
+}
+
+// assertOrder fails unless every needle appears in out, in the given order.
+func assertOrder(t *testing.T, out string, needles ...string) {
+	t.Helper()
+	pos := -1
+	for _, n := range needles {
+		i := strings.Index(out[pos+1:], n)
+		if i < 0 {
+			t.Fatalf("expected %q after position %d in:\n%s", n, pos, out)
+		}
+		pos += i + 1
+	}
+}
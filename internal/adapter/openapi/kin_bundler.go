@@ -0,0 +1,170 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+)
+
+const schemaRefPrefix = "#/components/schemas/"
+
+// KinBundler merges a multi-file spec into a single self-contained document.
+//
+// Design notes:
+//   - External $ref are resolved by kin-openapi, restricted to the sandbox
+//     (the root spec's directory unless overridden with WithBundleSandbox).
+//   - openapi3.T.InternalizeRefs hoists external definitions into `components`.
+//   - Structurally identical hoisted schemas are then merged and refs
+//     rewritten, which kin-openapi does not do when the same shape lives in
+//     different files. Schemas the root document declares itself are kept.
+type KinBundler struct {
+	sandbox string
+}
+
+// KinBundlerOption tweaks KinBundler behavior.
+type KinBundlerOption func(*KinBundler)
+
+// WithBundleSandbox sets the directory external refs must stay within.
+func WithBundleSandbox(dir string) KinBundlerOption {
+	return func(b *KinBundler) {
+		b.sandbox = dir
+	}
+}
+
+// NewKinBundler builds a KinBundler with safe defaults.
+func NewKinBundler(opts ...KinBundlerOption) *KinBundler {
+	b := &KinBundler{}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Bundle loads rootPath with its external refs and returns one bundled document.
+func (b *KinBundler) Bundle(ctx context.Context, rootPath string) (openapi.OpenAPIDoc, error) {
+	sandbox := b.sandbox
+	if sandbox == "" {
+		sandbox = filepath.Dir(rootPath)
+	}
+
+	// A fresh loader per call: kin-openapi loaders keep per-document state.
+	kin := NewKinLoader(WithExternalRefsAllowed(), WithSandbox(sandbox))
+
+	doc, err := kin.loader.LoadFromFile(rootPath)
+	if err != nil {
+		return openapi.OpenAPIDoc{}, kin.normalizeError(rootPath, err)
+	}
+
+	declared := make(map[string]bool)
+	if doc.Components != nil {
+		for name := range doc.Components.Schemas {
+			declared[name] = true
+		}
+	}
+	doc.InternalizeRefs(ctx, nil)
+
+	if err := kin.validateDoc(ctx, doc, rootPath); err != nil {
+		return openapi.OpenAPIDoc{}, kin.normalizeError(rootPath, err)
+	}
+
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return openapi.OpenAPIDoc{}, kin.normalizeError(rootPath, err)
+	}
+
+	raw, err = dedupeSchemas(raw, declared)
+	if err != nil {
+		return openapi.OpenAPIDoc{}, kin.normalizeError(rootPath, err)
+	}
+
+	return openapi.OpenAPIDoc{
+		JSON:    raw,
+		Version: openapi.OpenAPIVersion(doc.OpenAPI),
+		Model:   toModel(doc),
+	}, nil
+}
+
+// dedupeSchemas merges structurally identical `components.schemas` entries
+// hoisted from external files; declared (the root document's own schemas)
+// are never removed. A declared schema wins over hoisted ones, otherwise the
+// lexically smallest name does; refs to the others are rewritten. It runs to
+// a fixpoint because merging can make referencing schemas identical too.
+func dedupeSchemas(raw []byte, declared map[string]bool) ([]byte, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	components, _ := doc["components"].(map[string]any)
+	schemas, _ := components["schemas"].(map[string]any)
+	if len(schemas) < 2 {
+		return raw, nil
+	}
+
+	for {
+		names := make([]string, 0, len(schemas))
+		for name := range schemas {
+			names = append(names, name)
+		}
+		sort.Slice(names, func(i, j int) bool {
+			if declared[names[i]] != declared[names[j]] {
+				return declared[names[i]]
+			}
+			return names[i] < names[j]
+		})
+
+		seen := make(map[string]string, len(names))
+		aliases := make(map[string]string)
+		for _, name := range names {
+			fp, err := json.Marshal(schemas[name])
+			if err != nil {
+				return nil, err
+			}
+			switch keep, dup := seen[string(fp)]; {
+			case !dup:
+				seen[string(fp)] = name
+			case !declared[name]:
+				aliases[schemaRefPrefix+escapePointer(name)] = schemaRefPrefix + escapePointer(keep)
+				delete(schemas, name)
+			}
+		}
+		if len(aliases) == 0 {
+			break
+		}
+		rewriteRefs(doc, aliases)
+	}
+
+	return json.Marshal(doc)
+}
+
+// rewriteRefs replaces every "$ref" value found in aliases, at any depth.
+func rewriteRefs(node any, aliases map[string]string) {
+	switch t := node.(type) {
+	case map[string]any:
+		for k, v := range t {
+			if ref, ok := v.(string); ok && k == "$ref" {
+				if to, ok := aliases[ref]; ok {
+					t[k] = to
+				}
+				continue
+			}
+			rewriteRefs(v, aliases)
+		}
+	case []any:
+		for _, v := range t {
+			rewriteRefs(v, aliases)
+		}
+	}
+}
+
+// escapePointer escapes a component name for use in a JSON pointer (RFC 6901).
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+// Ensure KinBundler implements openapi.Bundler interface
+var _ openapi.Bundler = (*KinBundler)(nil)
@@ -0,0 +1,128 @@
+package openapi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	kinopenapi "github.com/betoth/contractcheck/internal/adapter/openapi"
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+)
+
+func TestKinBundler_HoistsAndDedupesExternalSchemas(t *testing.T) {
+	doc, err := kinopenapi.NewKinBundler().Bundle(context.Background(), filepath.Join("testdata", "bundle", "root.yaml"))
+	if err != nil {
+		t.Fatalf("Bundle: %v", err)
+	}
+	if doc.Version != "3.0.3" {
+		t.Fatalf("expected version 3.0.3, got %q", doc.Version)
+	}
+
+	var parsed struct {
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(doc.JSON, &parsed); err != nil {
+		t.Fatalf("bundled JSON: %v", err)
+	}
+
+	// Pet, Owner and one of the identical Tag/Label schemas remain.
+	if n := len(parsed.Components.Schemas); n != 3 {
+		t.Fatalf("expected 3 schemas after dedup, got %d: %v", n, keysOf(parsed.Components.Schemas))
+	}
+	if bytes.Contains(doc.JSON, []byte(".yaml")) {
+		t.Fatalf("expected no external refs left, got %s", doc.JSON)
+	}
+	for name := range parsed.Components.Schemas {
+		if strings.HasSuffix(name, "Tag") {
+			t.Fatalf("expected Tag to be merged into Label, still have %q", name)
+		}
+	}
+}
+
+func TestKinBundler_KeepsSchemasDeclaredInTheRoot(t *testing.T) {
+	doc, err := kinopenapi.NewKinBundler().Bundle(context.Background(), filepath.Join("testdata", "bundle", "declared.yaml"))
+	if err != nil {
+		t.Fatalf("Bundle: %v", err)
+	}
+
+	var parsed struct {
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(doc.JSON, &parsed); err != nil {
+		t.Fatalf("bundled JSON: %v", err)
+	}
+
+	// The identical Error and Problem are the root's own and both stay; the
+	// hoisted Tag has Badge's shape and is merged into it.
+	got := keysOf(parsed.Components.Schemas)
+	sort.Strings(got)
+	if len(got) != 4 || !slices.Contains(got, "Badge") || !slices.Contains(got, "Error") || !slices.Contains(got, "Problem") {
+		t.Fatalf("expected Badge, Error, Problem and the hoisted Pet, got %v", got)
+	}
+	if !strings.Contains(string(doc.JSON), `"$ref":"#/components/schemas/Badge"`) {
+		t.Fatalf("expected Pet.tag to point at Badge:\n%s", doc.JSON)
+	}
+}
+
+func TestKinBundler_RejectsRefsOutsideSandbox(t *testing.T) {
+	_, err := kinopenapi.NewKinBundler().Bundle(context.Background(), filepath.Join("testdata", "bundle", "escape.yaml"))
+
+	var ae *customerrors.AppError
+	if !errors.As(err, &ae) {
+		t.Fatalf("expected AppError, got %v", err)
+	}
+	if ae.Details[customerrors.DetailKind] != openapi.REF_OUTSIDE_SANDBOX {
+		t.Fatalf("expected kind %q, got %v", openapi.REF_OUTSIDE_SANDBOX, ae.Details[customerrors.DetailKind])
+	}
+}
+
+func TestKinBundler_WiderSandboxAllowsSharedRefs(t *testing.T) {
+	root := filepath.Join("testdata", "shared_refs", "api", "openapi.yaml")
+
+	var ae *customerrors.AppError
+	if _, err := kinopenapi.NewKinBundler().Bundle(context.Background(), root); !errors.As(err, &ae) || ae.Details[customerrors.DetailKind] != openapi.REF_OUTSIDE_SANDBOX {
+		t.Fatalf("default sandbox: expected %q, got %v", openapi.REF_OUTSIDE_SANDBOX, err)
+	}
+
+	doc, err := kinopenapi.NewKinBundler(kinopenapi.WithBundleSandbox(filepath.Join("testdata", "shared_refs"))).Bundle(context.Background(), root)
+	if err != nil {
+		t.Fatalf("Bundle: %v", err)
+	}
+	if strings.Contains(string(doc.JSON), "../shared") || !strings.Contains(string(doc.JSON), `"$ref":"#/components/schemas/`) {
+		t.Fatalf("expected the shared schema to be hoisted:\n%s", doc.JSON)
+	}
+}
+
+func TestDocWriter_YAMLKeepsStringTypes(t *testing.T) {
+	doc := openapi.OpenAPIDoc{JSON: []byte(`{"openapi":"3.0.3","paths":{"/a":{"get":{"responses":{"200":{"description":"ok"}}}}}}`)}
+
+	var buf bytes.Buffer
+	if err := kinopenapi.NewDocWriter().Write(&buf, doc, openapi.FORMAT_YAML); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"200":`) {
+		t.Fatalf("expected status code key to stay a quoted string:\n%s", buf.String())
+	}
+	if strings.Contains(buf.String(), "{") {
+		t.Fatalf("expected block-style YAML:\n%s", buf.String())
+	}
+}
+
+func keysOf(m map[string]json.RawMessage) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
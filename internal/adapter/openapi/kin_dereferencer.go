@@ -0,0 +1,395 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+)
+
+// truncatedRefKey marks where CYCLE_TRUNCATE cut a recursive reference.
+const truncatedRefKey = "x-contractcheck-truncated-ref"
+
+// ctxCheckEvery controls how often (in emitted nodes) cancellation is checked.
+const ctxCheckEvery = 1024
+
+// KinDereferencer inlines local $ref of an OpenAPI document.
+//
+// Design notes:
+//   - The document is first loaded through kin-openapi, so dangling refs and
+//     invalid specs are reported with the same taxonomy as KinLoader.
+//   - Inlining then runs over the generic JSON tree of the loaded openapi3.T:
+//     every local "$ref" in a position that can hold a Reference Object is
+//     replaced by a copy of its (recursively inlined) target. Example payloads,
+//     schema values (default, enum, const) and x-* extensions are copied as is.
+//   - `components` are dropped from the output, except securitySchemes (referenced
+//     by name, not $ref) and targets still referenced by kept cycles.
+type KinDereferencer struct{}
+
+// NewKinDereferencer builds a KinDereferencer.
+func NewKinDereferencer() *KinDereferencer { return &KinDereferencer{} }
+
+// Dereference returns a new document with local refs inlined, plus structural stats.
+func (KinDereferencer) Dereference(ctx context.Context, doc openapi.OpenAPIDoc, opts openapi.DerefOptions) (openapi.OpenAPIDoc, openapi.DerefStats, error) {
+	if opts.Cycles == "" {
+		opts.Cycles = openapi.CYCLE_ERROR
+	}
+	if opts.MaxNodes == 0 {
+		opts.MaxNodes = openapi.DEFAULT_MAX_NODES
+	}
+
+	kin := NewKinLoader()
+	t, err := kin.loader.LoadFromData(doc.JSON)
+	if err != nil {
+		return openapi.OpenAPIDoc{}, openapi.DerefStats{}, kin.normalizeError("", err)
+	}
+	raw, err := json.Marshal(t)
+	if err != nil {
+		return openapi.OpenAPIDoc{}, openapi.DerefStats{}, kin.normalizeError("", err)
+	}
+	var root map[string]any
+	if err := json.Unmarshal(raw, &root); err != nil {
+		return openapi.OpenAPIDoc{}, openapi.DerefStats{}, kin.normalizeError("", err)
+	}
+
+	w := &derefWalker{ctx: ctx, root: root, opts: opts, kept: map[string]bool{}}
+	w.stats.InputNodes, w.stats.InputDepth = measure(root, 1)
+
+	out := make(map[string]any, len(root))
+	for k, v := range root {
+		if k == "components" {
+			continue
+		}
+		if out[k], err = w.walk(v, posDoc.child(k), nil, 2); err != nil {
+			return openapi.OpenAPIDoc{}, openapi.DerefStats{}, err
+		}
+	}
+	if err := w.emitComponents(out); err != nil {
+		return openapi.OpenAPIDoc{}, openapi.DerefStats{}, err
+	}
+	w.stats.OutputNodes, w.stats.OutputDepth = measure(out, 1)
+
+	result, err := json.Marshal(out)
+	if err != nil {
+		return openapi.OpenAPIDoc{}, openapi.DerefStats{}, kin.normalizeError("", err)
+	}
+	// A fresh loader: the first one has already cached the input document.
+	inlined, err := NewKinLoader().loader.LoadFromData(result)
+	if err != nil {
+		return openapi.OpenAPIDoc{}, openapi.DerefStats{}, kin.normalizeError("", err)
+	}
+	model := toModel(inlined)
+	if doc.Model != nil {
+		model.Sources = slices.Clone(doc.Model.Sources)
+	}
+	return openapi.OpenAPIDoc{JSON: result, Version: openapi.OpenAPIVersion(t.OpenAPI), Model: model}, w.stats, nil
+}
+
+// position classifies where a node sits in the document, which decides
+// whether a "$ref" there is a Reference Object and how a cycle may end.
+type position int
+
+const (
+	posDoc      position = iota // any object that may be a Reference Object
+	posSchema                   // a Schema Object: cycles may be truncated
+	posNames                    // a map of names to schemas (properties, components.schemas, …)
+	posExamples                 // a map of names to Example Objects
+	posExample                  // an Example Object: its value is a payload
+	posLiteral                  // payloads and extensions, copied as is
+)
+
+// schemaMaps are the schema keywords whose values map names to schemas.
+var schemaMaps = []string{"properties", "patternProperties", "dependentSchemas", "$defs", "definitions"}
+
+// schemaValues are the schema keywords whose values are instances, not schemas.
+var schemaValues = []string{"default", "enum", "const"}
+
+// child returns the position of the value under key.
+func (p position) child(key string) position {
+	switch {
+	case p == posLiteral:
+		return posLiteral
+	case p == posNames:
+		return posSchema
+	case p == posExamples:
+		return posExample
+	case strings.HasPrefix(key, "x-"), key == "example":
+		return posLiteral
+	case p == posExample && key == "value":
+		return posLiteral
+	case p == posSchema && (key == "examples" || slices.Contains(schemaValues, key)):
+		return posLiteral
+	case p == posSchema && slices.Contains(schemaMaps, key), p == posDoc && key == "schemas":
+		return posNames
+	case key == "examples":
+		return posExamples
+	case key == "schema", p == posSchema:
+		return posSchema
+	default:
+		return posDoc
+	}
+}
+
+// pointerPosition returns the position of the node a local ref points at.
+func pointerPosition(ref string) position {
+	pos := posDoc
+	for _, seg := range pointerSegments(ref) {
+		pos = pos.child(seg)
+	}
+	return pos
+}
+
+// derefWalker carries state for one Dereference call.
+type derefWalker struct {
+	ctx     context.Context
+	root    map[string]any
+	opts    openapi.DerefOptions
+	stats   openapi.DerefStats
+	emitted int
+	kept    map[string]bool
+	queue   []string
+}
+
+// walk returns an inlined copy of node, found at pos. stack holds the refs
+// being expanded.
+func (w *derefWalker) walk(node any, pos position, stack []string, depth int) (any, error) {
+	switch t := node.(type) {
+	case map[string]any:
+		if ref, ok := t["$ref"].(string); ok && pos != posLiteral {
+			return w.inline(ref, t, pos, stack, depth)
+		}
+		if err := w.emit(); err != nil {
+			return nil, err
+		}
+		out := make(map[string]any, len(t))
+		for k, v := range t {
+			c, err := w.walk(v, pos.child(k), stack, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = c
+		}
+		return out, nil
+
+	case []any:
+		if err := w.emit(); err != nil {
+			return nil, err
+		}
+		out := make([]any, len(t))
+		for i, v := range t {
+			c, err := w.walk(v, pos, stack, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = c
+		}
+		return out, nil
+
+	default:
+		return t, w.emit()
+	}
+}
+
+// inline resolves ref found at pos (with optional sibling keys in node) or
+// applies the cycle strategy.
+func (w *derefWalker) inline(ref string, node map[string]any, pos position, stack []string, depth int) (any, error) {
+	if slices.Contains(stack, ref) {
+		w.stats.Cycles++
+		switch {
+		case w.opts.Cycles == openapi.CYCLE_KEEP_REF && strings.HasPrefix(ref, "#/components/"):
+			w.keep(ref)
+			return map[string]any{"$ref": ref}, w.emit()
+		case (w.opts.Cycles == openapi.CYCLE_KEEP_REF || w.opts.Cycles == openapi.CYCLE_TRUNCATE) && pos == posSchema:
+			// keep-ref can only point into components; elsewhere fall back to
+			// truncation, which only a schema can carry.
+			return map[string]any{truncatedRefKey: ref}, w.emit()
+		default:
+			return nil, openapi.NewValidationError(
+				openapi.CIRCULAR_REF,
+				"Circular reference",
+				"",
+				fmt.Errorf("%s -> %s", strings.Join(stack, " -> "), ref),
+			)
+		}
+	}
+
+	target, ok := lookupPointer(w.root, ref)
+	if !ok {
+		return nil, openapi.NewValidationError(
+			openapi.UNRESOLVED_REF,
+			"Unresolved reference",
+			"",
+			fmt.Errorf("cannot resolve %q", ref),
+		)
+	}
+	w.stats.InlinedRefs++
+
+	next := append(stack[:len(stack):len(stack)], ref)
+	resolved, err := w.walk(target, pointerPosition(ref), next, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	// OpenAPI 3.1 allows siblings next to $ref (e.g. description); they override the target.
+	if m, ok := resolved.(map[string]any); ok && len(node) > 1 {
+		for k, v := range node {
+			if k == "$ref" {
+				continue
+			}
+			if m[k], err = w.walk(v, pos.child(k), stack, depth+1); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return resolved, nil
+}
+
+// keep schedules a cycle target to be emitted under components.
+func (w *derefWalker) keep(ref string) {
+	if !w.kept[ref] {
+		w.kept[ref] = true
+		w.queue = append(w.queue, ref)
+	}
+}
+
+// emitComponents writes securitySchemes and kept cycle targets into out.components.
+func (w *derefWalker) emitComponents(out map[string]any) error {
+	components := map[string]any{}
+	if src, ok := w.root["components"].(map[string]any); ok {
+		if ss, ok := src["securitySchemes"]; ok {
+			components["securitySchemes"] = ss
+		}
+	}
+
+	for len(w.queue) > 0 {
+		ref := w.queue[0]
+		w.queue = w.queue[1:]
+
+		target, _ := lookupPointer(w.root, ref)
+		// Expand with the ref itself on the stack so self-references stay as $ref.
+		value, err := w.walk(target, pointerPosition(ref), []string{ref}, 3)
+		if err != nil {
+			return err
+		}
+		segments := pointerSegments(ref)[1:] // drop "components"
+		setPath(components, segments, value)
+	}
+
+	if len(components) > 0 {
+		out["components"] = components
+	}
+	return nil
+}
+
+// emit counts one output node and enforces MaxNodes and cancellation.
+func (w *derefWalker) emit() error {
+	w.emitted++
+	if w.opts.MaxNodes > 0 && w.emitted > w.opts.MaxNodes {
+		return openapi.NewValidationError(
+			openapi.EXPANSION_LIMIT,
+			"Dereferenced document exceeds node limit",
+			"",
+			fmt.Errorf("more than %d nodes", w.opts.MaxNodes),
+		)
+	}
+	if w.emitted%ctxCheckEvery == 0 {
+		return w.ctx.Err()
+	}
+	return nil
+}
+
+// measure returns the node count and maximum depth of a JSON tree.
+func measure(node any, depth int) (nodes, maxDepth int) {
+	nodes, maxDepth = 1, depth
+	visit := func(child any) {
+		n, d := measure(child, depth+1)
+		nodes += n
+		if d > maxDepth {
+			maxDepth = d
+		}
+	}
+	switch t := node.(type) {
+	case map[string]any:
+		for _, v := range t {
+			visit(v)
+		}
+	case []any:
+		for _, v := range t {
+			visit(v)
+		}
+	}
+	return nodes, maxDepth
+}
+
+// lookupPointer resolves a local JSON pointer ref ("#/a/b/0") against root.
+func lookupPointer(root map[string]any, ref string) (any, bool) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, false
+	}
+	var node any = root
+	for _, seg := range pointerSegments(ref) {
+		switch t := node.(type) {
+		case map[string]any:
+			v, ok := t[seg]
+			if !ok {
+				return nil, false
+			}
+			node = v
+		case []any:
+			i, err := strconv.Atoi(seg)
+			if err != nil || i < 0 || i >= len(t) {
+				return nil, false
+			}
+			node = t[i]
+		default:
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+// pointerUnescaper undoes RFC 6901 escaping in a reference token.
+var pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+// pointerSegments splits "#/a~1b/c%20d" into ["a/b", "c d"] (RFC 6901 + URI fragment decoding).
+func pointerSegments(ref string) []string {
+	frag := strings.TrimPrefix(strings.TrimPrefix(ref, "#"), "/")
+	if frag == "" {
+		return nil
+	}
+	parts := strings.Split(frag, "/")
+	for i, p := range parts {
+		if un, err := url.PathUnescape(p); err == nil {
+			p = un
+		}
+		if strings.Contains(p, "~") {
+			p = pointerUnescaper.Replace(p)
+		}
+		parts[i] = p
+	}
+	return parts
+}
+
+// setPath assigns value at the nested map path, creating intermediate maps.
+func setPath(dst map[string]any, path []string, value any) {
+	for i, seg := range path {
+		if i == len(path)-1 {
+			dst[seg] = value
+			return
+		}
+		next, ok := dst[seg].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			dst[seg] = next
+		}
+		dst = next
+	}
+}
+
+// Ensure KinDereferencer implements openapi.Dereferencer interface
+var _ openapi.Dereferencer = (*KinDereferencer)(nil)
@@ -0,0 +1,161 @@
+package openapi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	kinopenapi "github.com/betoth/contractcheck/internal/adapter/openapi"
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+)
+
+func loadTestdata(t *testing.T, name string) openapi.OpenAPIDoc {
+	t.Helper()
+	doc, err := kinopenapi.NewKinLoader().Load(context.Background(), filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("Load %s: %v", name, err)
+	}
+	return doc
+}
+
+func errorKind(t *testing.T, err error) any {
+	t.Helper()
+	var ae *customerrors.AppError
+	if !errors.As(err, &ae) {
+		t.Fatalf("expected AppError, got %v", err)
+	}
+	return ae.Details[customerrors.DetailKind]
+}
+
+func TestKinDereferencer_CycleStrategies(t *testing.T) {
+	doc := loadTestdata(t, "recursive.yaml")
+	deref := kinopenapi.NewKinDereferencer()
+	ctx := context.Background()
+
+	t.Run("error", func(t *testing.T) {
+		_, _, err := deref.Dereference(ctx, doc, openapi.DerefOptions{Cycles: openapi.CYCLE_ERROR})
+		if kind := errorKind(t, err); kind != string(openapi.CIRCULAR_REF) {
+			t.Fatalf("expected %s, got %v", openapi.CIRCULAR_REF, kind)
+		}
+	})
+
+	t.Run("keep-ref", func(t *testing.T) {
+		out, stats, err := deref.Dereference(ctx, doc, openapi.DerefOptions{Cycles: openapi.CYCLE_KEEP_REF})
+		if err != nil {
+			t.Fatalf("Dereference: %v", err)
+		}
+		if !bytes.Contains(out.JSON, []byte(`"$ref":"#/components/schemas/Node"`)) {
+			t.Fatalf("expected recursive ref to be kept: %s", out.JSON)
+		}
+		if bytes.Contains(out.JSON, []byte(`schemas/Name`)) {
+			t.Fatalf("expected non-recursive refs to be inlined and pruned: %s", out.JSON)
+		}
+		if stats.Cycles == 0 || stats.InlinedRefs == 0 {
+			t.Fatalf("expected cycles and inlined refs in stats, got %+v", stats)
+		}
+		// The flattened document must still load.
+		if _, _, err := deref.Dereference(ctx, out, openapi.DerefOptions{Cycles: openapi.CYCLE_KEEP_REF}); err != nil {
+			t.Fatalf("re-dereferencing output: %v", err)
+		}
+	})
+
+	t.Run("truncate", func(t *testing.T) {
+		out, stats, err := deref.Dereference(ctx, doc, openapi.DerefOptions{Cycles: openapi.CYCLE_TRUNCATE})
+		if err != nil {
+			t.Fatalf("Dereference: %v", err)
+		}
+		if bytes.Contains(out.JSON, []byte(`"$ref"`)) {
+			t.Fatalf("expected no refs left: %s", out.JSON)
+		}
+		if !bytes.Contains(out.JSON, []byte(`x-contractcheck-truncated-ref`)) {
+			t.Fatalf("expected truncation marker: %s", out.JSON)
+		}
+		if stats.OutputDepth <= 0 || stats.OutputNodes <= 0 {
+			t.Fatalf("expected structural stats, got %+v", stats)
+		}
+	})
+}
+
+func TestKinDereferencer_MaxNodes(t *testing.T) {
+	doc := loadTestdata(t, "recursive.yaml")
+	_, _, err := kinopenapi.NewKinDereferencer().Dereference(context.Background(), doc, openapi.DerefOptions{
+		Cycles:   openapi.CYCLE_TRUNCATE,
+		MaxNodes: 5,
+	})
+	if kind := errorKind(t, err); kind != string(openapi.EXPANSION_LIMIT) {
+		t.Fatalf("expected %s, got %v", openapi.EXPANSION_LIMIT, kind)
+	}
+}
+
+func TestKinDereferencer_KeepsRefsInPayloadsAndExtensions(t *testing.T) {
+	doc := loadTestdata(t, "literal_refs.yaml")
+	doc.Model.Sources = []string{"/specs/literal_refs.yaml"}
+
+	out, stats, err := kinopenapi.NewKinDereferencer().Dereference(context.Background(), doc, openapi.DerefOptions{})
+	if err != nil {
+		t.Fatalf("Dereference: %v", err)
+	}
+
+	var parsed struct {
+		Paths map[string]map[string]struct {
+			Codegen    map[string]any `json:"x-codegen"`
+			Parameters []struct {
+				Content map[string]struct {
+					Example map[string]any `json:"example"`
+				} `json:"content"`
+			} `json:"parameters"`
+			Responses map[string]struct {
+				Content map[string]struct {
+					Schema   map[string]any            `json:"schema"`
+					Examples map[string]map[string]any `json:"examples"`
+				} `json:"content"`
+			} `json:"responses"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(out.JSON, &parsed); err != nil {
+		t.Fatalf("dereferenced JSON: %v", err)
+	}
+	op := parsed.Paths["/links"]["get"]
+	media := op.Responses["200"].Content["application/json"]
+
+	if media.Schema["type"] != "object" || media.Schema["default"].(map[string]any)["$ref"] != "#/components/schemas/Link" {
+		t.Fatalf("expected the schema inlined with its default kept as is: %v", media.Schema)
+	}
+	if op.Parameters[0].Content["application/json"].Example["$ref"] != "#/components/schemas/Link" || op.Codegen["$ref"] != "#/components/schemas/Link" {
+		t.Fatalf("expected example and x-codegen kept as is: %s", out.JSON)
+	}
+	if stored := media.Examples["stored"]; stored["value"].(map[string]any)["$ref"] != "./other.yaml" {
+		t.Fatalf("expected the example object inlined with its value kept as is: %v", stored)
+	}
+	if stats.InlinedRefs != 3 {
+		t.Fatalf("expected the two schema refs and the example ref inlined, got %+v", stats)
+	}
+	if !slices.Equal(out.Model.Sources, doc.Model.Sources) {
+		t.Fatalf("expected sources carried over, got %v", out.Model.Sources)
+	}
+}
+
+func TestKinDereferencer_DefaultMaxNodes(t *testing.T) {
+	// Each schema references the previous one twice: the output doubles per level.
+	var spec strings.Builder
+	spec.WriteString("openapi: 3.0.3\ninfo: {title: Fanout, version: \"1.0\"}\npaths:\n  /x:\n    get:\n      responses:\n        \"200\":\n          description: ok\n          content:\n            application/json:\n              schema: {$ref: \"#/components/schemas/S20\"}\ncomponents:\n  schemas:\n    S0: {type: string}\n")
+	for i := 1; i <= 20; i++ {
+		fmt.Fprintf(&spec, "    S%d: {type: object, properties: {a: {$ref: \"#/components/schemas/S%d\"}, b: {$ref: \"#/components/schemas/S%d\"}}}\n", i, i-1, i-1)
+	}
+	doc, err := kinopenapi.NewKinLoader().Parse(context.Background(), []byte(spec.String()), "fanout.yaml")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	_, _, err = kinopenapi.NewKinDereferencer().Dereference(context.Background(), doc, openapi.DerefOptions{})
+	if kind := errorKind(t, err); kind != string(openapi.EXPANSION_LIMIT) {
+		t.Fatalf("expected %s, got %v", openapi.EXPANSION_LIMIT, kind)
+	}
+}
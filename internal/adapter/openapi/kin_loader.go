@@ -5,7 +5,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/betoth/contractcheck/internal/application/customerrors"
@@ -22,11 +27,12 @@ import (
 //   - It always returns the spec serialized as UTF-8 JSON ([]byte) plus the declared version,
 //     leaving higher layers free to persist or further transform as needed.
 type KinLoader struct {
-	loader *openapi3.Loader
+	loader  *openapi3.Loader
+	sandbox string
 }
 
 // KinLoaderOption is a functional option that allows callers to tweak the underlying
-type KinLoaderOption func(*openapi3.Loader)
+type KinLoaderOption func(*KinLoader)
 
 // NewKinLoader builds a KinLoader with safe defaults.
 // By default, external $ref are NOT allowed (security/portability reasons).
@@ -35,27 +41,53 @@ func NewKinLoader(opts ...KinLoaderOption) *KinLoader {
 	ldr := openapi3.NewLoader()
 	ldr.IsExternalRefsAllowed = false
 
+	kin := &KinLoader{loader: ldr}
 	for _, opt := range opts {
-		opt(ldr)
+		opt(kin)
 	}
-
-	return &KinLoader{loader: ldr}
+	return kin
 }
 
 // WithExternalRefsAllowed enables resolution of external $ref.
 func WithExternalRefsAllowed() KinLoaderOption {
-	return func(l *openapi3.Loader) {
-		l.IsExternalRefsAllowed = true
+	return func(kin *KinLoader) {
+		kin.loader.IsExternalRefsAllowed = true
+	}
+}
+
+// WithSandbox restricts external $ref resolution to local files under dir.
+// Remote URLs and paths escaping dir (including via symlinks) are rejected
+// with a REF_OUTSIDE_SANDBOX error.
+func WithSandbox(dir string) KinLoaderOption {
+	return func(kin *KinLoader) {
+		kin.sandbox = resolvePath(dir)
+		kin.loader.ReadFromURIFunc = sandboxedReader(kin.sandbox)
 	}
 }
 
 // Load reads and validates an OpenAPI file located at filePath.
 func (kin *KinLoader) Load(ctx context.Context, filePath string) (openapi.OpenAPIDoc, error) {
-	doc, err := kin.loader.LoadFromFile(filePath)
+	ldr, sources := kin.fresh()
+	doc, err := ldr.LoadFromFile(filePath)
 	if err != nil {
 		return openapi.OpenAPIDoc{}, kin.normalizeError(filePath, err)
 	}
 
+	return kin.finish(ctx, doc, filePath, sources)
+}
+
+// Parse validates an in-memory document exactly like Load validates files.
+func (kin *KinLoader) Parse(ctx context.Context, data []byte, name string) (openapi.OpenAPIDoc, error) {
+	ldr, sources := kin.fresh()
+	doc, err := ldr.LoadFromData(data)
+	if err != nil {
+		return openapi.OpenAPIDoc{}, kin.normalizeError(name, err)
+	}
+	return kin.finish(ctx, doc, name, sources)
+}
+
+// finish validates a loaded document and converts it to an OpenAPIDoc.
+func (kin *KinLoader) finish(ctx context.Context, doc *openapi3.T, filePath string, sources *sourceRecorder) (openapi.OpenAPIDoc, error) {
 	if err := kin.validateDoc(ctx, doc, filePath); err != nil {
 		return openapi.OpenAPIDoc{}, kin.normalizeError(filePath, err)
 	}
@@ -65,12 +97,90 @@ func (kin *KinLoader) Load(ctx context.Context, filePath string) (openapi.OpenAP
 		return openapi.OpenAPIDoc{}, kin.normalizeError(filePath, err)
 	}
 
+	model := toModel(doc)
+	model.Sources = sources.list()
 	return openapi.OpenAPIDoc{
 		JSON:    raw,
 		Version: openapi.OpenAPIVersion(doc.OpenAPI),
+		Model:   model,
 	}, nil
 }
 
+// fresh returns a per-call kin-openapi loader configured like kin.loader.
+// kin-openapi loaders memoize visited documents and its default reader caches
+// absolute paths process-wide; reusing either would serve stale content once
+// a file changes on disk. The returned recorder collects the local files read.
+func (kin *KinLoader) fresh() (*openapi3.Loader, *sourceRecorder) {
+	read := kin.loader.ReadFromURIFunc
+	if read == nil {
+		read = uncachedReader
+	}
+	rec := &sourceRecorder{seen: map[string]bool{}}
+
+	ldr := openapi3.NewLoader()
+	ldr.IsExternalRefsAllowed = kin.loader.IsExternalRefsAllowed
+	ldr.ReadFromURIFunc = func(l *openapi3.Loader, loc *url.URL) ([]byte, error) {
+		data, err := read(l, loc)
+		if err == nil {
+			rec.add(loc)
+		}
+		return data, err
+	}
+	return ldr, rec
+}
+
+// uncachedReader is kin-openapi's default reader without its URI cache.
+var uncachedReader = openapi3.ReadFromURIs(openapi3.ReadFromHTTP(http.DefaultClient), openapi3.ReadFromFile)
+
+// sourceRecorder collects the absolute paths of local files, in read order.
+type sourceRecorder struct {
+	seen  map[string]bool
+	files []string
+}
+
+func (r *sourceRecorder) add(loc *url.URL) {
+	if loc.Host != "" || (loc.Scheme != "" && loc.Scheme != "file") {
+		return
+	}
+	p, err := filepath.Abs(filepath.FromSlash(loc.Path))
+	if err != nil || r.seen[p] {
+		return
+	}
+	r.seen[p] = true
+	r.files = append(r.files, p)
+}
+
+func (r *sourceRecorder) list() []string { return r.files }
+
+// BuildModel rebuilds the typed model for a document previously produced by
+// Load (e.g. restored from a cache), skipping validation. filePath anchors
+// relative external refs left in doc.JSON.
+func (kin *KinLoader) BuildModel(doc openapi.OpenAPIDoc, filePath string) (*openapi.Document, error) {
+	ldr, sources := kin.fresh()
+
+	abs, err := filepath.Abs(filePath)
+	if err != nil {
+		return nil, kin.normalizeError(filePath, err)
+	}
+	root := &url.URL{Path: filepath.ToSlash(abs)}
+	sources.add(root)
+	t, err := ldr.LoadFromDataWithPath(doc.JSON, root)
+	if err != nil {
+		return nil, kin.normalizeError(filePath, err)
+	}
+	model := toModel(t)
+	model.Sources = sources.list()
+	return model, nil
+}
+
+// Fingerprint describes the options that affect Load results, for use in
+// cache keys. The sandbox is included as a resolved path: the same tree may
+// load under one sandbox and be rejected under a narrower one.
+func (kin *KinLoader) Fingerprint() string {
+	return fmt.Sprintf("kin-openapi;external_refs=%t;custom_reader=%t;sandbox=%s",
+		kin.loader.IsExternalRefsAllowed, kin.loader.ReadFromURIFunc != nil, kin.sandbox)
+}
+
 // validateDoc centralizes structural validation and version checks.
 func (kin *KinLoader) validateDoc(ctx context.Context, doc *openapi3.T, filePath string) error {
 	if err := doc.Validate(ctx); err != nil {
@@ -151,12 +261,22 @@ func (kin *KinLoader) normalizeError(filePath string, err error) error {
 		// Heuristics for YAML/JSON parse errors (based on typical vendor messages).
 		msg := err.Error()
 		if strings.Contains(msg, "yaml:") || strings.Contains(msg, "json:") {
+			details := map[string]any{
+				customerrors.DetailFile: filePath,
+				customerrors.DetailKind: openapi.INVALID_SYNTAX,
+			}
+			if line := syntaxLine(msg); line > 0 {
+				details[customerrors.DetailLine] = line
+			}
+			return customerrors.NewValidationError("Invalid YAML/JSON syntax", err, details)
+		}
+		if strings.Contains(msg, errOutsideSandbox.Error()) {
 			return customerrors.NewValidationError(
-				"Invalid YAML/JSON syntax",
+				"External reference outside sandbox",
 				err,
 				map[string]any{
 					customerrors.DetailFile: filePath,
-					customerrors.DetailKind: openapi.INVALID_SYNTAX,
+					customerrors.DetailKind: openapi.REF_OUTSIDE_SANDBOX,
 				},
 			)
 		}
@@ -183,5 +303,22 @@ func (kin *KinLoader) normalizeError(filePath string, err error) error {
 	}
 }
 
-// Ensure KinLoader implements openapi.Loader interface
-var _ openapi.Loader = (*KinLoader)(nil)
+// yamlLine matches the position YAML parsers put in their messages.
+// JSON input is parsed as YAML too, so this covers both formats.
+var yamlLine = regexp.MustCompile(`yaml: line (\d+):`)
+
+// syntaxLine extracts the 1-based line of a parse error, or 0.
+func syntaxLine(msg string) int {
+	m := yamlLine.FindStringSubmatch(msg)
+	if m == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(m[1])
+	return n
+}
+
+// Ensure KinLoader implements openapi.Loader and openapi.Parser interfaces
+var (
+	_ openapi.Loader = (*KinLoader)(nil)
+	_ openapi.Parser = (*KinLoader)(nil)
+)
@@ -0,0 +1,343 @@
+package openapi
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const componentSchemaPrefix = "#/components/schemas/"
+
+// toModel maps a loaded kin-openapi document into the library-independent
+// domain model. Refs must already be resolved (as after LoadFromFile).
+func toModel(t *openapi3.T) *openapi.Document {
+	m := &modelMapper{schemas: map[*openapi3.Schema]*openapi.Schema{}}
+
+	doc := &openapi.Document{
+		Version:    openapi.OpenAPIVersion(t.OpenAPI),
+		Servers:    m.servers(&t.Servers),
+		Security:   m.security(&t.Security),
+		Extensions: t.Extensions,
+	}
+	if t.Info != nil {
+		doc.Info = openapi.Info{Title: t.Info.Title, Version: openapi.APIVersion(t.Info.Version), Description: t.Info.Description}
+	}
+
+	// Components first, so shared nodes carry their component name.
+	if t.Components != nil {
+		doc.Schemas = make(map[string]*openapi.Schema, len(t.Components.Schemas))
+		for name, ref := range t.Components.Schemas {
+			if ref == nil || ref.Value == nil {
+				continue
+			}
+			s := m.schema(ref)
+			if s.Name == "" {
+				s.Name, s.Ref = name, componentSchemaPrefix+name
+			}
+			doc.Schemas[name] = s
+		}
+		doc.SecuritySchemes = make(map[string]openapi.SecurityScheme, len(t.Components.SecuritySchemes))
+		for name, ref := range t.Components.SecuritySchemes {
+			if ref != nil && ref.Value != nil {
+				doc.SecuritySchemes[name] = m.securityScheme(ref.Value)
+			}
+		}
+	}
+
+	if t.Paths != nil {
+		for path, item := range t.Paths.Map() {
+			if item == nil {
+				continue
+			}
+			for method, op := range item.Operations() {
+				doc.Operations = append(doc.Operations, m.operation(path, method, item, op, doc.Servers))
+			}
+		}
+	}
+	openapi.SortOperations(doc.Operations)
+	return doc
+}
+
+// modelMapper memoizes schemas by identity so `$ref` reuse and recursion
+// map onto shared (possibly cyclic) *openapi.Schema nodes.
+type modelMapper struct {
+	schemas map[*openapi3.Schema]*openapi.Schema
+}
+
+func (m *modelMapper) operation(path, method string, item *openapi3.PathItem, op *openapi3.Operation, docServers []openapi.Server) openapi.Operation {
+	out := openapi.Operation{
+		Path:        path,
+		Method:      strings.ToUpper(method),
+		ID:          op.OperationID,
+		Summary:     op.Summary,
+		Description: op.Description,
+		Tags:        op.Tags,
+		Deprecated:  op.Deprecated,
+		Parameters:  m.parameters(path, method, item.Parameters, op.Parameters),
+		Security:    m.security(op.Security),
+		Extensions:  op.Extensions,
+	}
+	switch {
+	case op.Servers != nil && len(*op.Servers) > 0:
+		out.Servers = m.servers(op.Servers)
+	case len(item.Servers) > 0:
+		out.Servers = m.servers(&item.Servers)
+	default:
+		out.Servers = docServers
+	}
+
+	if op.RequestBody != nil && op.RequestBody.Value != nil {
+		rb := op.RequestBody.Value
+		out.RequestBody = &openapi.RequestBody{
+			Description: rb.Description,
+			Required:    rb.Required,
+			Content:     m.content(rb.Content),
+		}
+	}
+
+	if op.Responses != nil {
+		for status, ref := range op.Responses.Map() {
+			if ref == nil || ref.Value == nil {
+				continue
+			}
+			out.Responses = append(out.Responses, m.response(status, ref.Value))
+		}
+		sort.Slice(out.Responses, func(i, j int) bool { return out.Responses[i].Status < out.Responses[j].Status })
+	}
+	return out
+}
+
+// parameters merges path-level and operation-level parameters; the latter
+// override the former when name and location match.
+func (m *modelMapper) parameters(path, method string, pathLevel, opLevel openapi3.Parameters) []openapi.Parameter {
+	type key struct{ name, in string }
+	var out []openapi.Parameter
+	index := map[key]int{}
+	itemPointer := "/paths/" + escapePointer(path)
+	lists := []struct {
+		params  openapi3.Parameters
+		pointer string
+	}{
+		{pathLevel, itemPointer + "/parameters/"},
+		{opLevel, itemPointer + "/" + strings.ToLower(method) + "/parameters/"},
+	}
+	for _, list := range lists {
+		for i, ref := range list.params {
+			if ref == nil || ref.Value == nil {
+				continue
+			}
+			p := ref.Value
+			mp := openapi.Parameter{
+				Name:        p.Name,
+				In:          p.In,
+				Description: p.Description,
+				Required:    p.Required,
+				Deprecated:  p.Deprecated,
+				Style:       p.Style,
+				Explode:     p.Explode,
+				Schema:      m.schema(p.Schema),
+				Extensions:  p.Extensions,
+				Pointer:     list.pointer + strconv.Itoa(i),
+			}
+			k := key{p.Name, p.In}
+			if i, ok := index[k]; ok {
+				out[i] = mp
+				continue
+			}
+			index[k] = len(out)
+			out = append(out, mp)
+		}
+	}
+	return out
+}
+
+func (m *modelMapper) response(status string, r *openapi3.Response) openapi.Response {
+	out := openapi.Response{Status: status, Content: m.content(r.Content)}
+	if r.Description != nil {
+		out.Description = *r.Description
+	}
+	if len(r.Headers) > 0 {
+		out.Headers = make(map[string]openapi.Header, len(r.Headers))
+		for name, ref := range r.Headers {
+			if ref == nil || ref.Value == nil {
+				continue
+			}
+			h := ref.Value
+			out.Headers[name] = openapi.Header{
+				Description: h.Description,
+				Required:    h.Required,
+				Deprecated:  h.Deprecated,
+				Schema:      m.schema(h.Schema),
+			}
+		}
+	}
+	return out
+}
+
+func (m *modelMapper) content(c openapi3.Content) map[string]openapi.MediaType {
+	if len(c) == 0 {
+		return nil
+	}
+	out := make(map[string]openapi.MediaType, len(c))
+	for mt, v := range c {
+		if v == nil {
+			continue
+		}
+		out[mt] = openapi.MediaType{Schema: m.schema(v.Schema), Example: v.Example}
+	}
+	return out
+}
+
+func (m *modelMapper) schema(ref *openapi3.SchemaRef) *openapi.Schema {
+	if ref == nil || ref.Value == nil {
+		return nil
+	}
+	if s, ok := m.schemas[ref.Value]; ok {
+		return s
+	}
+
+	v := ref.Value
+	s := &openapi.Schema{
+		Type:             v.Type.Slice(),
+		Format:           v.Format,
+		Title:            v.Title,
+		Description:      v.Description,
+		Nullable:         v.Nullable,
+		Enum:             v.Enum,
+		Default:          v.Default,
+		Example:          v.Example,
+		Deprecated:       v.Deprecated,
+		ReadOnly:         v.ReadOnly,
+		WriteOnly:        v.WriteOnly,
+		Minimum:          v.Min,
+		Maximum:          v.Max,
+		ExclusiveMinimum: v.ExclusiveMin,
+		ExclusiveMaximum: v.ExclusiveMax,
+		MultipleOf:       v.MultipleOf,
+		MinLength:        v.MinLength,
+		MaxLength:        v.MaxLength,
+		Pattern:          v.Pattern,
+		MinItems:         v.MinItems,
+		MaxItems:         v.MaxItems,
+		UniqueItems:      v.UniqueItems,
+		Required:         v.Required,
+		MinProperties:    v.MinProps,
+		MaxProperties:    v.MaxProps,
+		Extensions:       v.Extensions,
+
+		AdditionalPropertiesAllowed: v.AdditionalProperties.Has,
+	}
+	if ref.Ref != "" {
+		s.Ref = ref.Ref
+		s.Name = strings.TrimPrefix(ref.Ref, componentSchemaPrefix)
+		if s.Name == ref.Ref {
+			s.Name = ""
+		}
+	}
+	// Register before descending so recursion terminates.
+	m.schemas[v] = s
+
+	s.Items = m.schema(v.Items)
+	s.Not = m.schema(v.Not)
+	s.AdditionalProperties = m.schema(v.AdditionalProperties.Schema)
+	s.AllOf = m.schemaList(v.AllOf)
+	s.OneOf = m.schemaList(v.OneOf)
+	s.AnyOf = m.schemaList(v.AnyOf)
+	if len(v.Properties) > 0 {
+		s.Properties = make(map[string]*openapi.Schema, len(v.Properties))
+		for name, p := range v.Properties {
+			if ps := m.schema(p); ps != nil {
+				s.Properties[name] = ps
+			}
+		}
+	}
+	if v.Discriminator != nil {
+		s.Discriminator = &openapi.Discriminator{
+			PropertyName: v.Discriminator.PropertyName,
+			Mapping:      map[string]string(v.Discriminator.Mapping),
+		}
+	}
+	return s
+}
+
+func (m *modelMapper) schemaList(refs openapi3.SchemaRefs) []*openapi.Schema {
+	if len(refs) == 0 {
+		return nil
+	}
+	out := make([]*openapi.Schema, 0, len(refs))
+	for _, r := range refs {
+		if s := m.schema(r); s != nil {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func (m *modelMapper) servers(in *openapi3.Servers) []openapi.Server {
+	if in == nil {
+		return nil
+	}
+	out := make([]openapi.Server, 0, len(*in))
+	for _, s := range *in {
+		if s == nil {
+			continue
+		}
+		srv := openapi.Server{URL: s.URL, Description: s.Description}
+		if len(s.Variables) > 0 {
+			srv.Variables = make(map[string]openapi.ServerVariable, len(s.Variables))
+			for name, v := range s.Variables {
+				if v != nil {
+					srv.Variables[name] = openapi.ServerVariable{Default: v.Default, Enum: v.Enum, Description: v.Description}
+				}
+			}
+		}
+		out = append(out, srv)
+	}
+	return out
+}
+
+// security keeps the nil/empty distinction: nil means "not declared".
+func (m *modelMapper) security(in *openapi3.SecurityRequirements) []openapi.SecurityRequirement {
+	if in == nil {
+		return nil
+	}
+	out := make([]openapi.SecurityRequirement, 0, len(*in))
+	for _, req := range *in {
+		out = append(out, openapi.SecurityRequirement(req))
+	}
+	return out
+}
+
+func (m *modelMapper) securityScheme(s *openapi3.SecurityScheme) openapi.SecurityScheme {
+	out := openapi.SecurityScheme{
+		Type:             s.Type,
+		Description:      s.Description,
+		Name:             s.Name,
+		In:               s.In,
+		Scheme:           s.Scheme,
+		BearerFormat:     s.BearerFormat,
+		OpenIDConnectURL: s.OpenIdConnectUrl,
+	}
+	if f := s.Flows; f != nil {
+		out.Flows = map[string]openapi.OAuthFlow{}
+		for name, flow := range map[string]*openapi3.OAuthFlow{
+			"implicit":          f.Implicit,
+			"password":          f.Password,
+			"clientCredentials": f.ClientCredentials,
+			"authorizationCode": f.AuthorizationCode,
+		} {
+			if flow != nil {
+				out.Flows[name] = openapi.OAuthFlow{
+					AuthorizationURL: flow.AuthorizationURL,
+					TokenURL:         flow.TokenURL,
+					RefreshURL:       flow.RefreshURL,
+					Scopes:           map[string]string(flow.Scopes),
+				}
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,191 @@
+package openapi_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	kinopenapi "github.com/betoth/contractcheck/internal/adapter/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+)
+
+func TestKinLoader_MapsDomainModel(t *testing.T) {
+	m := loadTestdata(t, "petstore.yaml").Model
+	if m == nil {
+		t.Fatal("expected Model to be populated")
+	}
+	if m.Info.Title != "Petstore" || m.Version != "3.0.3" {
+		t.Fatalf("unexpected info: %+v / %s", m.Info, m.Version)
+	}
+
+	var keys []string
+	for _, op := range m.Operations {
+		keys = append(keys, op.Key())
+	}
+	want := []string{"POST /pets", "DELETE /pets/{petId}", "GET /pets/{petId}"}
+	if len(keys) != len(want) {
+		t.Fatalf("operations = %v, want %v", keys, want)
+	}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Fatalf("operations = %v, want %v", keys, want)
+		}
+	}
+
+	get := m.Operation("get", "/pets/{petId}")
+	if get == nil || get.ID != "getPet" {
+		t.Fatalf("Operation lookup failed: %+v", get)
+	}
+	if len(get.Parameters) != 2 || get.Parameters[0].Description != "overridden" || !get.Parameters[0].Schema.HasType("integer") {
+		t.Fatalf("expected operation parameter to override path parameter: %+v", get.Parameters)
+	}
+	if get.Parameters[1].In != openapi.PARAM_IN_HEADER {
+		t.Fatalf("expected inherited header parameter: %+v", get.Parameters[1])
+	}
+	if p0, p1 := get.Parameters[0].Pointer, get.Parameters[1].Pointer; p0 != "/paths/~1pets~1{petId}/get/parameters/0" || p1 != "/paths/~1pets~1{petId}/parameters/1" {
+		t.Fatalf("expected pointers at the declarations, got %q and %q", p0, p1)
+	}
+	if len(get.Responses) != 2 || get.Responses[0].Status != "200" {
+		t.Fatalf("expected responses sorted by status: %+v", get.Responses)
+	}
+	if _, ok := get.Response("200").Headers["X-Rate-Limit"]; !ok {
+		t.Fatal("expected response header to be mapped")
+	}
+	if got := get.Servers; len(got) != 1 || got[0].Variables["stage"].Default != "v1" {
+		t.Fatalf("expected document servers to be inherited: %+v", got)
+	}
+
+	// Security: inherited, explicitly disabled, overridden.
+	if sec := get.EffectiveSecurity(m); len(sec) != 1 || sec[0]["apiKey"] == nil {
+		t.Fatalf("expected inherited security, got %v", sec)
+	}
+	del := m.Operation("DELETE", "/pets/{petId}")
+	if sec := del.EffectiveSecurity(m); sec == nil || len(sec) != 0 || !del.Deprecated {
+		t.Fatalf("expected explicit opt-out, got %v", sec)
+	}
+	post := m.Operation("POST", "/pets")
+	if sec := post.EffectiveSecurity(m); len(sec) != 1 || sec[0]["oauth"][0] != "pets:write" {
+		t.Fatalf("expected overridden security, got %v", sec)
+	}
+	if flow := m.SecuritySchemes["oauth"].Flows["clientCredentials"]; flow.TokenURL == "" {
+		t.Fatalf("expected oauth flow to be mapped: %+v", m.SecuritySchemes["oauth"])
+	}
+
+	// Schemas: $ref uses share the component node, recursion is a cycle.
+	pet := m.Schemas["Pet"]
+	if pet == nil || pet.Name != "Pet" || pet.Ref != "#/components/schemas/Pet" {
+		t.Fatalf("unexpected component schema: %+v", pet)
+	}
+	if post.RequestBody.Content["application/json"].Schema != pet {
+		t.Fatal("expected request body schema to share the component node")
+	}
+	if pet.Properties["parent"] != pet {
+		t.Fatal("expected recursive property to point back at the component")
+	}
+	if !pet.IsRequired("name") || pet.IsRequired("tags") {
+		t.Fatalf("unexpected required set: %v", pet.Required)
+	}
+	if *pet.Properties["name"].MaxLength != 64 || *pet.Properties["id"].Minimum != 1 {
+		t.Fatal("expected constraints to be mapped")
+	}
+	if pet.AdditionalPropertiesAllowed == nil || *pet.AdditionalPropertiesAllowed {
+		t.Fatal("expected additionalProperties: false")
+	}
+	if got := pet.PropertyNames(); len(got) != 4 || got[0] != "id" {
+		t.Fatalf("PropertyNames = %v", got)
+	}
+}
+
+func TestKinLoader_BuildModelFromJSON(t *testing.T) {
+	path := filepath.Join("testdata", "bundle", "root.yaml")
+	kin := kinopenapi.NewKinLoader(kinopenapi.WithExternalRefsAllowed())
+	doc, err := kin.Load(context.Background(), path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	// The JSON form keeps relative external refs; BuildModel resolves them
+	// against path, as a cache restoring a persisted document would.
+	m, err := kin.BuildModel(openapi.OpenAPIDoc{JSON: doc.JSON, Version: doc.Version}, path)
+	if err != nil {
+		t.Fatalf("BuildModel: %v", err)
+	}
+	if len(m.Operations) != len(doc.Model.Operations) || len(m.Operations) == 0 {
+		t.Fatalf("operations: got %d, want %d", len(m.Operations), len(doc.Model.Operations))
+	}
+}
+
+func TestKinLoader_ReloadsChangedFilesAndRecordsSources(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "root.yaml")
+	pet := filepath.Join(dir, "pet.yaml")
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(root, `openapi: 3.0.3
+info: {title: T, version: "1"}
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: "pet.yaml#/Pet"}
+`)
+	write(pet, "Pet: {type: object}\n")
+
+	kin := kinopenapi.NewKinLoader(kinopenapi.WithExternalRefsAllowed())
+	ctx := context.Background()
+	first, err := kin.Load(ctx, root)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := first.Model.Sources; len(got) != 2 || got[0] != root || got[1] != pet {
+		t.Fatalf("Sources = %v", got)
+	}
+
+	// The same loader must observe edits to referenced files.
+	write(pet, "Pet: {type: string}\n")
+	second, err := kin.Load(ctx, root)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	schema := second.Model.Operation("GET", "/pets").Response("200").Content["application/json"].Schema
+	if !schema.HasType("string") {
+		t.Fatalf("expected reloaded schema type string, got %v", schema.Type)
+	}
+}
+
+func TestKinLoader_ParseValidatesInMemoryDocuments(t *testing.T) {
+	kin := kinopenapi.NewKinLoader()
+	doc, err := kin.Parse(context.Background(), []byte(`{
+		"openapi": "3.0.3",
+		"info": {"title": "Pets", "version": "0.1.0"},
+		"paths": {"/pets/{petId}": {"get": {
+			"parameters": [{"name": "petId", "in": "path", "required": true, "schema": {"type": "integer"}}],
+			"responses": {"200": {"description": "OK"}}
+		}}}
+	}`), "inferred.json")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if doc.Model == nil || len(doc.Model.Operations) != 1 || doc.Version != "3.0.3" {
+		t.Fatalf("unexpected doc: %+v", doc)
+	}
+
+	// A path parameter that is not declared fails validation.
+	_, err = kin.Parse(context.Background(), []byte(`{
+		"openapi": "3.0.3",
+		"info": {"title": "Pets", "version": "0.1.0"},
+		"paths": {"/pets/{petId}": {"get": {"responses": {"200": {"description": "OK"}}}}}
+	}`), "inferred.json")
+	if err == nil {
+		t.Fatal("Parse: want a validation error")
+	}
+}
@@ -0,0 +1,55 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// errOutsideSandbox marks $ref targets rejected by the sandboxed reader.
+// kin-openapi does not always wrap reader errors, so normalizeError matches its text.
+var errOutsideSandbox = errors.New("reference outside sandbox")
+
+// sandboxedReader returns a kin-openapi URI reader that only serves local
+// files located under dir.
+func sandboxedReader(dir string) openapi3.ReadFromURIFunc {
+	root := resolvePath(dir)
+
+	return func(_ *openapi3.Loader, loc *url.URL) ([]byte, error) {
+		if loc.Host != "" || (loc.Scheme != "" && loc.Scheme != "file") {
+			return nil, fmt.Errorf("%w: %s", errOutsideSandbox, loc.String())
+		}
+		target := resolvePath(filepath.FromSlash(loc.Path))
+		if !within(root, target) {
+			return nil, fmt.Errorf("%w: %s", errOutsideSandbox, loc.Path)
+		}
+		return os.ReadFile(target)
+	}
+}
+
+// resolvePath makes p absolute and resolves symlinks when the target exists,
+// so a link inside the sandbox cannot point outside of it.
+func resolvePath(p string) string {
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		abs = filepath.Clean(p)
+	}
+	if real, err := filepath.EvalSymlinks(abs); err == nil {
+		return real
+	}
+	return abs
+}
+
+// within reports whether target is root or one of its descendants.
+func within(root, target string) bool {
+	rel, err := filepath.Rel(root, target)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
@@ -0,0 +1,334 @@
+// Package pactfile reads Pact contract files (specification v2 to v4).
+package pactfile
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/pact"
+)
+
+// INTERACTION_HTTP is the v4 type of request/response interactions; other
+// types (asynchronous and synchronous messages) are skipped.
+const INTERACTION_HTTP = "Synchronous/HTTP"
+
+// pactFile is the on-disk layout shared by all versions; the fields that
+// changed shape between versions are decoded lazily.
+type pactFile struct {
+	Consumer     party             `json:"consumer"`
+	Provider     party             `json:"provider"`
+	Interactions []json.RawMessage `json:"interactions"`
+	Messages     []json.RawMessage `json:"messages"` // v3 message pacts
+	Metadata     struct {
+		PactSpecification   specVersion `json:"pactSpecification"`
+		PactSpecificationV2 specVersion `json:"pact-specification"`
+		PactSpecificationV1 string      `json:"pactSpecificationVersion"`
+	} `json:"metadata"`
+}
+
+type party struct {
+	Name string `json:"name"`
+}
+
+type specVersion struct {
+	Version string `json:"version"`
+}
+
+type interaction struct {
+	Type           string          `json:"type"` // v4 only
+	Description    string          `json:"description"`
+	ProviderState  string          `json:"providerState"` // v2
+	ProviderStates []party         `json:"providerStates"`
+	Request        json.RawMessage `json:"request"`
+	Response       json.RawMessage `json:"response"`
+}
+
+type request struct {
+	Method  string                     `json:"method"`
+	Path    string                     `json:"path"`
+	Query   json.RawMessage            `json:"query"`
+	Headers map[string]json.RawMessage `json:"headers"`
+	Body    json.RawMessage            `json:"body"`
+}
+
+type response struct {
+	Status  int                        `json:"status"`
+	Headers map[string]json.RawMessage `json:"headers"`
+	Body    json.RawMessage            `json:"body"`
+}
+
+// v4Body is the v4 body envelope.
+type v4Body struct {
+	Content     json.RawMessage `json:"content"`
+	ContentType string          `json:"contentType"`
+	Encoded     any             `json:"encoded"` // false, "base64" or "json"
+}
+
+// Reader reads Pact JSON files from disk.
+type Reader struct{}
+
+// NewReader builds a Reader.
+func NewReader() *Reader {
+	return &Reader{}
+}
+
+// Read parses the Pact file at path. Interactions that are not synchronous
+// HTTP are counted in Pact.Skipped rather than returned.
+func (r *Reader) Read(_ context.Context, path string) (pact.Pact, error) {
+	raw, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return pact.Pact{}, openapi.NewValidationError(openapi.FILE_NOT_FOUND, "File not found", path, err)
+	case errors.Is(err, fs.ErrPermission):
+		return pact.Pact{}, openapi.NewValidationError(openapi.PERMISSION_DENIED, "Permission denied", path, err)
+	case err != nil:
+		return pact.Pact{}, err
+	}
+
+	var f pactFile
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return pact.Pact{}, openapi.NewValidationError(openapi.INVALID_SYNTAX, "Invalid JSON syntax", path, err)
+	}
+	if f.Consumer.Name == "" || f.Provider.Name == "" {
+		return pact.Pact{}, invalidPact(path, errors.New("consumer and provider names are required"))
+	}
+
+	p := pact.Pact{
+		Consumer:    f.Consumer.Name,
+		Provider:    f.Provider.Name,
+		SpecVersion: f.specVersion(),
+		Skipped:     len(f.Messages),
+	}
+	v4 := strings.HasPrefix(p.SpecVersion, "4")
+	for i, rawInteraction := range f.Interactions {
+		it, skip, err := decodeInteraction(rawInteraction, v4)
+		if err != nil {
+			return pact.Pact{}, invalidPact(path, fmt.Errorf("interaction %d: %w", i, err))
+		}
+		if skip {
+			p.Skipped++
+			continue
+		}
+		p.Interactions = append(p.Interactions, it)
+	}
+	return p, nil
+}
+
+// specVersion returns the declared specification version; files that do
+// not declare one are read as v2, the format of the oldest tooling.
+func (f pactFile) specVersion() string {
+	for _, v := range []string{f.Metadata.PactSpecification.Version, f.Metadata.PactSpecificationV2.Version, f.Metadata.PactSpecificationV1} {
+		if v != "" {
+			return v
+		}
+	}
+	return "2.0.0"
+}
+
+func decodeInteraction(raw json.RawMessage, v4 bool) (it pact.Interaction, skip bool, err error) {
+	var in interaction
+	if err := json.Unmarshal(raw, &in); err != nil {
+		return it, false, err
+	}
+	if v4 && in.Type != "" && in.Type != INTERACTION_HTTP {
+		return it, true, nil
+	}
+	it.Description = in.Description
+	if in.ProviderState != "" {
+		it.ProviderStates = append(it.ProviderStates, in.ProviderState)
+	}
+	for _, s := range in.ProviderStates {
+		it.ProviderStates = append(it.ProviderStates, s.Name)
+	}
+
+	var req request
+	if err := json.Unmarshal(in.Request, &req); err != nil {
+		return it, false, fmt.Errorf("request: %w", err)
+	}
+	if req.Method == "" {
+		return it, false, errors.New("request: method is required")
+	}
+	it.Request = pact.Request{
+		Method: strings.ToUpper(req.Method),
+		Path:   req.Path,
+	}
+	if it.Request.Path == "" {
+		it.Request.Path = "/"
+	}
+	if it.Request.Query, err = decodeQuery(req.Query); err != nil {
+		return it, false, fmt.Errorf("request query: %w", err)
+	}
+	if it.Request.Headers, err = decodeHeaders(req.Headers); err != nil {
+		return it, false, fmt.Errorf("request headers: %w", err)
+	}
+	if it.Request.Body, err = decodeBody(req.Body, it.Request.Headers, v4); err != nil {
+		return it, false, fmt.Errorf("request body: %w", err)
+	}
+
+	var resp response
+	if len(in.Response) > 0 {
+		if err := json.Unmarshal(in.Response, &resp); err != nil {
+			return it, false, fmt.Errorf("response: %w", err)
+		}
+	}
+	it.Response.Status = resp.Status
+	if it.Response.Status == 0 {
+		it.Response.Status = 200
+	}
+	if it.Response.Headers, err = decodeHeaders(resp.Headers); err != nil {
+		return it, false, fmt.Errorf("response headers: %w", err)
+	}
+	if it.Response.Body, err = decodeBody(resp.Body, it.Response.Headers, v4); err != nil {
+		return it, false, fmt.Errorf("response body: %w", err)
+	}
+	return it, false, nil
+}
+
+// decodeQuery accepts a v2 query string ("a=1&b=2") or a v3/v4 map of
+// values (each a string or a list of strings).
+func decodeQuery(raw json.RawMessage) (map[string][]string, error) {
+	if isAbsent(raw) {
+		return nil, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		q, err := url.ParseQuery(s)
+		if err != nil {
+			return nil, err
+		}
+		return q, nil
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	q := make(map[string][]string, len(m))
+	for name, v := range m {
+		values, err := stringOrList(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		q[name] = values
+	}
+	return q, nil
+}
+
+// decodeHeaders lower-cases header names and joins v4 value lists with ", ".
+func decodeHeaders(m map[string]json.RawMessage) (map[string]string, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	h := make(map[string]string, len(m))
+	for name, v := range m {
+		values, err := stringOrList(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		h[strings.ToLower(name)] = strings.Join(values, ", ")
+	}
+	return h, nil
+}
+
+func stringOrList(raw json.RawMessage) ([]string, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return []string{s}, nil
+	}
+	var list []string
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, errors.New("want a string or a list of strings")
+	}
+	return list, nil
+}
+
+// decodeBody reads a v2/v3 body (the JSON value itself) or a v4 body
+// envelope. The content type comes from the envelope, then the headers; a
+// body without one is JSON unless it is a plain string.
+func decodeBody(raw json.RawMessage, headers map[string]string, v4 bool) (*pact.Body, error) {
+	if isAbsent(raw) {
+		return nil, nil
+	}
+	contentType := mediaType(headers["content-type"])
+	content := raw
+	if v4 {
+		var env v4Body
+		if err := json.Unmarshal(raw, &env); err != nil {
+			return nil, err
+		}
+		if isAbsent(env.Content) {
+			return nil, nil
+		}
+		if env.ContentType != "" {
+			contentType = mediaType(env.ContentType)
+		}
+		content = env.Content
+		switch env.Encoded {
+		case "base64":
+			var s string
+			if err := json.Unmarshal(content, &s); err != nil {
+				return nil, errors.New("base64 content must be a string")
+			}
+			decoded, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				return nil, err
+			}
+			if !isJSONMediaType(contentType) {
+				return &pact.Body{ContentType: contentType, Content: string(decoded)}, nil
+			}
+			content = decoded
+		case "json":
+			var s string
+			if err := json.Unmarshal(content, &s); err != nil {
+				return nil, errors.New("json-encoded content must be a string")
+			}
+			content = json.RawMessage(s)
+		}
+	}
+
+	var v any
+	if err := json.Unmarshal(content, &v); err != nil {
+		return nil, err
+	}
+	if s, ok := v.(string); ok && !isJSONMediaType(contentType) {
+		return &pact.Body{ContentType: contentType, Content: s}, nil
+	}
+	return &pact.Body{ContentType: contentType, Content: v, JSON: true}, nil
+}
+
+// mediaType strips parameters: "application/json; charset=utf-8" is
+// "application/json".
+func mediaType(value string) string {
+	if value == "" {
+		return ""
+	}
+	if mt, _, err := mime.ParseMediaType(value); err == nil {
+		return mt
+	}
+	mt, _, _ := strings.Cut(value, ";")
+	return strings.ToLower(strings.TrimSpace(mt))
+}
+
+// isJSONMediaType accepts application/json and the +json suffix family.
+func isJSONMediaType(mt string) bool {
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
+
+func isAbsent(raw json.RawMessage) bool {
+	return len(raw) == 0 || string(raw) == "null"
+}
+
+func invalidPact(path string, cause error) error {
+	return openapi.NewValidationError(openapi.INVALID_PACT, "Invalid Pact file", path, cause)
+}
+
+// compile-time check
+var _ pact.Reader = (*Reader)(nil)
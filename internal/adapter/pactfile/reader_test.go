@@ -0,0 +1,118 @@
+package pactfile_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/betoth/contractcheck/internal/adapter/pactfile"
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/pact"
+)
+
+func read(t *testing.T, name string) pact.Pact {
+	t.Helper()
+	p, err := pactfile.NewReader().Read(context.Background(), filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestReader_V2(t *testing.T) {
+	p := read(t, "v2.json")
+	if p.Consumer != "billing" || p.Provider != "pets" || p.SpecVersion != "2.0.0" || len(p.Interactions) != 1 {
+		t.Fatalf("pact = %+v", p)
+	}
+	want := pact.Interaction{
+		Description:    "list available pets",
+		ProviderStates: []string{"pets exist"},
+		Request: pact.Request{
+			Method:  "GET",
+			Path:    "/pets",
+			Query:   map[string][]string{"status": {"available"}, "limit": {"10"}},
+			Headers: map[string]string{"accept": "application/json"},
+		},
+		Response: pact.Response{
+			Status:  200,
+			Headers: map[string]string{"content-type": "application/json; charset=utf-8"},
+			Body: &pact.Body{
+				ContentType: "application/json",
+				Content:     []any{map[string]any{"id": 1.0, "name": "rex"}},
+				JSON:        true,
+			},
+		},
+	}
+	if !reflect.DeepEqual(p.Interactions[0], want) {
+		t.Errorf("interaction =\n%+v\nwant\n%+v", p.Interactions[0], want)
+	}
+}
+
+func TestReader_V3(t *testing.T) {
+	p := read(t, "v3.json")
+	if p.SpecVersion != "3.0.0" || len(p.Interactions) != 2 || p.Skipped != 1 {
+		t.Fatalf("pact = %+v", p)
+	}
+	create, text := p.Interactions[0], p.Interactions[1]
+	if !reflect.DeepEqual(create.ProviderStates, []string{"no pets", "user is admin"}) {
+		t.Errorf("provider states = %v", create.ProviderStates)
+	}
+	if !reflect.DeepEqual(create.Request.Query, map[string][]string{"dryRun": {"true"}}) {
+		t.Errorf("query = %v", create.Request.Query)
+	}
+	if want := (&pact.Body{ContentType: "application/json", Content: map[string]any{"name": "rex"}, JSON: true}); !reflect.DeepEqual(create.Request.Body, want) {
+		t.Errorf("request body = %+v", create.Request.Body)
+	}
+	if create.Response.Status != 201 || create.Response.Body != nil {
+		t.Errorf("response = %+v", create.Response)
+	}
+	// The status defaults to 200; a string body of a non-JSON type is text.
+	if want := (&pact.Body{ContentType: "text/plain", Content: "rex"}); text.Response.Status != 200 || !reflect.DeepEqual(text.Response.Body, want) {
+		t.Errorf("text response = %+v", text.Response)
+	}
+}
+
+func TestReader_V4(t *testing.T) {
+	p := read(t, "v4.json")
+	if p.SpecVersion != "4.0" || len(p.Interactions) != 3 || p.Skipped != 1 {
+		t.Fatalf("pact = %+v", p)
+	}
+	get, upload, encoded := p.Interactions[0], p.Interactions[1], p.Interactions[2]
+	if got := get.Request.Headers["accept"]; got != "application/json, text/plain" {
+		t.Errorf("accept = %q", got)
+	}
+	if !reflect.DeepEqual(get.Request.Query, map[string][]string{"fields": {"id", "name"}}) {
+		t.Errorf("query = %v", get.Request.Query)
+	}
+	if want := (&pact.Body{ContentType: "application/json", Content: map[string]any{"id": 1.0, "name": "rex"}, JSON: true}); !reflect.DeepEqual(get.Response.Body, want) {
+		t.Errorf("response body = %+v", get.Response.Body)
+	}
+	if want := (&pact.Body{ContentType: "text/plain", Content: "hello"}); !reflect.DeepEqual(upload.Request.Body, want) {
+		t.Errorf("base64 body = %+v", upload.Request.Body)
+	}
+	if want := (&pact.Body{ContentType: "application/json", Content: map[string]any{"name": "rex"}, JSON: true}); !reflect.DeepEqual(encoded.Request.Body, want) {
+		t.Errorf("json-encoded body = %+v", encoded.Request.Body)
+	}
+}
+
+func TestReader_Errors(t *testing.T) {
+	cases := map[string]openapi.ErrorKind{
+		"missing.json":     openapi.FILE_NOT_FOUND,
+		"truncated.json":   openapi.INVALID_SYNTAX,
+		"no_consumer.json": openapi.INVALID_PACT,
+	}
+	for name, kind := range cases {
+		path := filepath.Join("testdata", name)
+		_, err := pactfile.NewReader().Read(context.Background(), path)
+		var ae *customerrors.AppError
+		if !errors.As(err, &ae) {
+			t.Fatalf("%s: err = %v, want an AppError", name, err)
+		}
+		if ae.Details[customerrors.DetailKind] != string(kind) || ae.Details[customerrors.DetailFile] != path {
+			t.Errorf("%s: details = %v, want kind %s", name, ae.Details, kind)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// Package refscan finds the files a spec references through external `$ref`s
+// without parsing it, so callers can track dependencies cheaply.
+package refscan
+
+import (
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// refPattern matches `$ref` values in both YAML and JSON sources, quoted or not.
+// It is a deliberately loose scan: false positives only add files to the set.
+var refPattern = regexp.MustCompile(`["']?\$ref["']?\s*:\s*["']?([^"'\s,}\]]+)`)
+
+// Refs returns the local file targets referenced by data, relative to the
+// referencing file and in order of appearance (duplicates removed).
+// remote reports whether any ref points at a URL.
+func Refs(data []byte) (files []string, remote bool) {
+	seen := map[string]bool{}
+	for _, m := range refPattern.FindAllSubmatch(data, -1) {
+		target, local := Target(string(m[1]))
+		if !local {
+			remote = true
+			continue
+		}
+		if target != "" && !seen[target] {
+			seen[target] = true
+			files = append(files, target)
+		}
+	}
+	return files, remote
+}
+
+// Target extracts the file part of a ref. It returns "" for in-document
+// refs ("#/...") and local=false for remote URLs.
+func Target(ref string) (target string, local bool) {
+	ref = strings.TrimSpace(ref)
+	file, _, _ := strings.Cut(ref, "#")
+	if file == "" {
+		return "", true
+	}
+	// Single-letter schemes are Windows drive letters, not URLs.
+	if u, err := url.Parse(file); err == nil && u.Scheme != "" && u.Scheme != "file" && len(u.Scheme) > 1 {
+		return "", false
+	}
+	if unescaped, err := url.PathUnescape(file); err == nil {
+		file = unescaped
+	}
+	return filepath.FromSlash(strings.TrimPrefix(file, "file://")), true
+}
@@ -0,0 +1,220 @@
+// Package sarif renders findings as SARIF 2.1.0 logs, the format
+// code-scanning services (GitHub, Azure DevOps, …) ingest as annotations.
+package sarif
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+	"github.com/betoth/contractcheck/internal/version"
+)
+
+const (
+	SARIF_VERSION = "2.1.0"
+	SARIF_SCHEMA  = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	// DefaultToolName is reported as tool.driver.name.
+	DefaultToolName = "ContractCheck"
+
+	// fingerprintKey names our entry in result.partialFingerprints.
+	fingerprintKey = "contractcheck/v1"
+)
+
+// Writer converts findings into a single-run SARIF log.
+type Writer struct {
+	toolName    string
+	toolVersion string
+	infoURI     string
+	rules       map[string]report.Rule
+}
+
+// Option configures a Writer.
+type Option func(*Writer)
+
+// WithTool overrides the driver name and version (default: ContractCheck and
+// the build version).
+func WithTool(name, version string) Option {
+	return func(w *Writer) {
+		if name != "" {
+			w.toolName = name
+		}
+		w.toolVersion = version
+	}
+}
+
+// WithInformationURI sets tool.driver.informationUri.
+func WithInformationURI(uri string) Option {
+	return func(w *Writer) { w.infoURI = uri }
+}
+
+// WithRules registers rule metadata. Rules are emitted only when a finding
+// uses them; unknown rule IDs get a minimal descriptor.
+func WithRules(rules ...report.Rule) Option {
+	return func(w *Writer) {
+		for _, r := range rules {
+			w.rules[r.ID] = r
+		}
+	}
+}
+
+// NewWriter builds a Writer with safe defaults.
+func NewWriter(opts ...Option) *Writer {
+	w := &Writer{
+		toolName:    DefaultToolName,
+		toolVersion: version.Get().Version,
+		rules:       map[string]report.Rule{},
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Write encodes the findings of r as an indented SARIF log. A report
+// without findings yields a valid log with no results.
+func (w *Writer) Write(out io.Writer, r report.Report) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(w.log(r.Findings))
+}
+
+func (w *Writer) log(findings []report.Finding) sarifLog {
+	sorted := append([]report.Finding(nil), findings...)
+	report.Sort(sorted)
+
+	ids := map[string]bool{}
+	for _, f := range sorted {
+		ids[f.RuleID] = true
+	}
+	ruleIDs := make([]string, 0, len(ids))
+	for id := range ids {
+		ruleIDs = append(ruleIDs, id)
+	}
+	sort.Strings(ruleIDs)
+
+	index := make(map[string]int, len(ruleIDs))
+	rules := make([]rule, 0, len(ruleIDs))
+	for i, id := range ruleIDs {
+		index[id] = i
+		rules = append(rules, w.rule(id, sorted))
+	}
+
+	results := make([]result, 0, len(sorted))
+	for _, f := range sorted {
+		results = append(results, toResult(f, index[f.RuleID]))
+	}
+
+	return sarifLog{
+		Schema:  SARIF_SCHEMA,
+		Version: SARIF_VERSION,
+		Runs: []run{{
+			Tool: tool{Driver: driver{
+				Name:           w.toolName,
+				Version:        w.toolVersion,
+				InformationURI: w.infoURI,
+				Rules:          rules,
+			}},
+			ColumnKind: "unicodeCodePoints",
+			Results:    results,
+		}},
+	}
+}
+
+// rule describes id from the registered metadata, falling back to the
+// severity of its first finding.
+func (w *Writer) rule(id string, findings []report.Finding) rule {
+	meta, ok := w.rules[id]
+	if !ok {
+		meta = report.Rule{ID: id, Summary: id}
+		for _, f := range findings {
+			if f.RuleID == id {
+				meta.Severity = f.Severity
+				break
+			}
+		}
+	}
+	return rule{
+		ID:               id,
+		Name:             meta.Name,
+		ShortDescription: &message{Text: meta.Summary},
+		DefaultConfiguration: &configuration{
+			Level: level(meta.Severity),
+		},
+	}
+}
+
+func toResult(f report.Finding, ruleIndex int) result {
+	text := f.Message
+	if f.Detail != "" {
+		text += ": " + f.Detail
+	}
+	r := result{
+		RuleID:    f.RuleID,
+		RuleIndex: ruleIndex,
+		Level:     level(f.Severity),
+		Message:   message{Text: text},
+		PartialFingerprints: map[string]string{
+			fingerprintKey: fingerprint(f),
+		},
+	}
+	if f.File != "" {
+		loc := location{PhysicalLocation: physicalLocation{
+			ArtifactLocation: artifactLocation{URI: fileURI(f.File)},
+		}}
+		if f.Line > 0 {
+			loc.PhysicalLocation.Region = &region{StartLine: f.Line, StartColumn: f.Column}
+		}
+		r.Locations = []location{loc}
+	}
+	if f.Operation != "" || f.Pointer != "" {
+		r.Properties = map[string]string{}
+		if f.Operation != "" {
+			r.Properties["operation"] = f.Operation
+		}
+		if f.Pointer != "" {
+			r.Properties["pointer"] = f.Pointer
+		}
+	}
+	return r
+}
+
+// level maps our severities onto SARIF's result levels.
+func level(s report.Severity) string {
+	switch s {
+	case report.SEVERITY_ERROR:
+		return "error"
+	case report.SEVERITY_WARNING:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// fingerprint is stable across runs and line shifts (see Finding.Key).
+func fingerprint(f report.Finding) string {
+	sum := sha256.Sum256([]byte(f.Key()))
+	return hex.EncodeToString(sum[:16])
+}
+
+// fileURI keeps relative paths relative (code-scanning resolves them against
+// the checkout) and turns absolute paths into file:// URIs.
+func fileURI(path string) string {
+	p := filepath.ToSlash(path)
+	if !filepath.IsAbs(path) {
+		return (&url.URL{Path: p}).String()
+	}
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p // Windows drive letter
+	}
+	return (&url.URL{Scheme: "file", Path: p}).String()
+}
+
+// compile-time check
+var _ report.Writer = (*Writer)(nil)
@@ -0,0 +1,190 @@
+package sarif_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/betoth/contractcheck/internal/adapter/sarif"
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+// sarifLog mirrors the parts of the log the tests assert on.
+type sarifLog struct {
+	Schema  string `json:"$schema"`
+	Version string `json:"version"`
+	Runs    []struct {
+		Tool struct {
+			Driver struct {
+				Name    string `json:"name"`
+				Version string `json:"version"`
+				Rules   []struct {
+					ID               string `json:"id"`
+					Name             string `json:"name"`
+					ShortDescription struct {
+						Text string `json:"text"`
+					} `json:"shortDescription"`
+					DefaultConfiguration struct {
+						Level string `json:"level"`
+					} `json:"defaultConfiguration"`
+				} `json:"rules"`
+			} `json:"driver"`
+		} `json:"tool"`
+		Results []struct {
+			RuleID    string `json:"ruleId"`
+			RuleIndex int    `json:"ruleIndex"`
+			Level     string `json:"level"`
+			Message   struct {
+				Text string `json:"text"`
+			} `json:"message"`
+			Locations []struct {
+				PhysicalLocation struct {
+					ArtifactLocation struct {
+						URI string `json:"uri"`
+					} `json:"artifactLocation"`
+					Region *struct {
+						StartLine   int `json:"startLine"`
+						StartColumn int `json:"startColumn"`
+					} `json:"region"`
+				} `json:"physicalLocation"`
+			} `json:"locations"`
+			PartialFingerprints map[string]string `json:"partialFingerprints"`
+			Properties          map[string]string `json:"properties"`
+		} `json:"results"`
+	} `json:"runs"`
+}
+
+func write(t *testing.T, w *sarif.Writer, findings []report.Finding) sarifLog {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := w.Write(&buf, report.Report{Findings: findings}); err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if log.Version != "2.1.0" || log.Schema == "" || len(log.Runs) != 1 {
+		t.Fatalf("not a single-run SARIF 2.1.0 log: %s", buf.String())
+	}
+	return log
+}
+
+func TestWriter_LoaderFailureBecomesResult(t *testing.T) {
+	err := openapi.NewValidationError(openapi.INVALID_SYNTAX, "Invalid YAML/JSON syntax", "api/openapi.yaml", errors.New("yaml: line 7: did not find expected node content"))
+	var ae *customerrors.AppError
+	errors.As(err, &ae)
+	ae.Details[customerrors.DetailLine] = 7
+
+	w := sarif.NewWriter(sarif.WithTool("ContractCheck", "1.2.3"), sarif.WithRules(report.ErrorRules()...))
+	log := write(t, w, []report.Finding{report.FromError(err)})
+
+	run := log.Runs[0]
+	if run.Tool.Driver.Name != "ContractCheck" || run.Tool.Driver.Version != "1.2.3" {
+		t.Fatalf("driver = %+v", run.Tool.Driver)
+	}
+	if len(run.Tool.Driver.Rules) != 1 {
+		t.Fatalf("rules = %+v, want only the rule in use", run.Tool.Driver.Rules)
+	}
+	rule := run.Tool.Driver.Rules[0]
+	if rule.ID != "invalid_syntax" || rule.Name != "InvalidSyntax" || rule.ShortDescription.Text == "" || rule.DefaultConfiguration.Level != "error" {
+		t.Fatalf("rule = %+v", rule)
+	}
+
+	if len(run.Results) != 1 {
+		t.Fatalf("results = %d, want 1", len(run.Results))
+	}
+	res := run.Results[0]
+	if res.RuleID != "invalid_syntax" || res.RuleIndex != 0 || res.Level != "error" {
+		t.Fatalf("result = %+v", res)
+	}
+	if res.Message.Text != "Invalid YAML/JSON syntax: yaml: line 7: did not find expected node content" {
+		t.Fatalf("message = %q", res.Message.Text)
+	}
+	loc := res.Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != "api/openapi.yaml" || loc.Region == nil || loc.Region.StartLine != 7 {
+		t.Fatalf("location = %+v", loc)
+	}
+	if res.PartialFingerprints["contractcheck/v1"] == "" {
+		t.Fatal("missing fingerprint")
+	}
+}
+
+func TestWriter_RulesLevelsAndLocations(t *testing.T) {
+	abs := filepath.Join(t.TempDir(), "my spec.yaml")
+	findings := []report.Finding{
+		{RuleID: "property-added", Severity: report.SEVERITY_INFO, Message: "property tag was added", File: "api.yaml", Operation: "POST /pets"},
+		{RuleID: "operation-removed", Severity: report.SEVERITY_ERROR, Message: "operation was removed", File: "api.yaml", Operation: "DELETE /pets/{id}"},
+		{RuleID: "custom-lint", Severity: report.SEVERITY_WARNING, Message: "no description", File: abs, Line: 3, Column: 5, Pointer: "/info"},
+	}
+	w := sarif.NewWriter(sarif.WithTool("", "dev"), sarif.WithRules(report.Rule{
+		ID: "operation-removed", Name: "OperationRemoved", Summary: "An operation was removed.", Severity: report.SEVERITY_ERROR,
+	}))
+	run := write(t, w, findings).Runs[0]
+
+	var ids []string
+	for _, r := range run.Tool.Driver.Rules {
+		ids = append(ids, r.ID)
+	}
+	if want := []string{"custom-lint", "operation-removed", "property-added"}; !slices.Equal(ids, want) {
+		t.Fatalf("rule ids = %q, want %q", ids, want)
+	}
+	// Unknown rules fall back to their ID and the finding's severity.
+	if r := run.Tool.Driver.Rules[0]; r.ShortDescription.Text != "custom-lint" || r.DefaultConfiguration.Level != "warning" {
+		t.Fatalf("fallback rule = %+v", r)
+	}
+
+	levels := map[string]string{}
+	for _, res := range run.Results {
+		if got := run.Tool.Driver.Rules[res.RuleIndex].ID; got != res.RuleID {
+			t.Fatalf("ruleIndex %d points at %q, want %q", res.RuleIndex, got, res.RuleID)
+		}
+		levels[res.RuleID] = res.Level
+		switch res.RuleID {
+		case "operation-removed":
+			if res.Properties["operation"] != "DELETE /pets/{id}" {
+				t.Fatalf("properties = %v", res.Properties)
+			}
+		case "custom-lint":
+			loc := res.Locations[0].PhysicalLocation
+			want := "file://" + filepath.ToSlash(abs)
+			if runtime.GOOS == "windows" {
+				want = "file:///" + filepath.ToSlash(abs)
+			}
+			if loc.ArtifactLocation.URI != strings.ReplaceAll(want, " ", "%20") || loc.Region.StartLine != 3 || loc.Region.StartColumn != 5 {
+				t.Fatalf("location = %+v", loc)
+			}
+			if res.Properties["pointer"] != "/info" {
+				t.Fatalf("properties = %v", res.Properties)
+			}
+		}
+	}
+	if levels["operation-removed"] != "error" || levels["custom-lint"] != "warning" || levels["property-added"] != "note" {
+		t.Fatalf("levels = %v", levels)
+	}
+}
+
+func TestWriter_EmptyFindingsIsValidLog(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sarif.NewWriter().Write(&buf, report.Report{}); err != nil {
+		t.Fatal(err)
+	}
+	var raw struct {
+		Runs []struct {
+			Results json.RawMessage `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &raw); err != nil {
+		t.Fatal(err)
+	}
+	if len(raw.Runs) != 1 || string(raw.Runs[0].Results) != "[]" {
+		t.Fatalf("want one run with empty results, got %s", buf.String())
+	}
+}
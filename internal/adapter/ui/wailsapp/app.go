@@ -4,7 +4,9 @@ import (
 	"context"
 	"io/fs"
 
+	"github.com/betoth/contractcheck/internal/application/ports/input"
 	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/version"
 	"github.com/wailsapp/wails/v2/pkg/options"
 	"github.com/wailsapp/wails/v2/pkg/options/assetserver"
 )
@@ -51,14 +53,66 @@ func (a *App) Shutdown(ctx context.Context) {
 
 // Version exposes the app version to the frontend.
 func (a *App) Version() string {
-	// TODO: inject real version (ex: build flag, pkg/version)
-	return "0.1.0"
+	return version.Get().Version
+}
+
+// BuildInfo exposes structured build metadata (version, commit, dirty flag,
+// build time, toolchain) to the frontend, e.g. for an "About" dialog.
+func (a *App) BuildInfo() version.BuildInfo {
+	return version.Get()
+}
+
+// UIOption customizes optional dependencies of the bound frontend APIs.
+type UIOption func(*uiDeps)
+
+// uiDeps collects optional collaborators injected through UIOption.
+type uiDeps struct {
+	logReader      output.LogReader
+	metaPolicy     MetaPolicy
+	diagnostics    input.ExportDiagnostics
+	diagnosticsDir string
+	watch          input.WatchOpenAPISpec
+}
+
+// WithLogReader wires the persistent log reader used by the LogViewer binding.
+func WithLogReader(r output.LogReader) UIOption {
+	return func(d *uiDeps) {
+		d.logReader = r
+	}
+}
+
+// WithMetaPolicy sets the limits/redaction applied to frontend log metadata.
+func WithMetaPolicy(p MetaPolicy) UIOption {
+	return func(d *uiDeps) {
+		d.metaPolicy = p
+	}
+}
+
+// WithDiagnostics wires the diagnostics exporter and its default output dir.
+func WithDiagnostics(exporter input.ExportDiagnostics, defaultDir string) UIOption {
+	return func(d *uiDeps) {
+		d.diagnostics = exporter
+		d.diagnosticsDir = defaultDir
+	}
+}
+
+// WithWatch wires the watch-mode use case used by the SpecWatcher binding.
+func WithWatch(watch input.WatchOpenAPISpec) UIOption {
+	return func(d *uiDeps) {
+		d.watch = watch
+	}
 }
 
 // UIOptions builds the Wails app options, binding all frontend-facing APIs.
 // This is the single entrypoint consumed by main.go.
-func UIOptions(assets fs.FS, log output.Logger) *options.App {
+func UIOptions(assets fs.FS, log output.Logger, opts ...UIOption) *options.App {
+	deps := uiDeps{metaPolicy: DefaultMetaPolicy()}
+	for _, opt := range opts {
+		opt(&deps)
+	}
+
 	app := New(log)
+	watcher := NewSpecWatcher(deps.watch)
 
 	return &options.App{
 		Title:            "ContractCheck",
@@ -66,12 +120,21 @@ func UIOptions(assets fs.FS, log output.Logger) *options.App {
 		AssetServer: &assetserver.Options{
 			Assets: assets,
 		},
-		OnStartup:  app.Startup,
+		OnStartup: func(ctx context.Context) {
+			app.Startup(ctx)
+			watcher.startup(ctx)
+		},
 		OnDomReady: app.DomReady,
-		OnShutdown: app.Shutdown,
+		OnShutdown: func(ctx context.Context) {
+			watcher.Stop()
+			app.Shutdown(ctx)
+		},
 		Bind: []interface{}{
-			app,                  // Provides Version()
-			NewLoggerBridge(log), // Provides frontend logging bridge
+			app,                                   // Provides Version() and BuildInfo()
+			NewLoggerBridge(log, deps.metaPolicy), // Provides frontend logging bridge
+			NewLogViewer(deps.logReader),          // Provides persisted log tail/filter
+			NewDiagnostics(deps.diagnostics, deps.diagnosticsDir), // Provides diagnostics export
+			watcher, // Provides watch mode (results via events)
 		},
 	}
 }
@@ -0,0 +1,45 @@
+package wailsapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/betoth/contractcheck/internal/adapter/diagnostics"
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+)
+
+// errDiagnosticsUnavailable is returned when no diagnostics service is wired.
+var errDiagnosticsUnavailable = errors.New("diagnostics export unavailable")
+
+// Diagnostics exposes the "export diagnostics" action to the frontend (via Wails).
+type Diagnostics struct {
+	exporter   input.ExportDiagnostics
+	defaultDir string
+}
+
+// NewDiagnostics constructs the binding. defaultDir is used when Export is
+// called without a destination path.
+func NewDiagnostics(exporter input.ExportDiagnostics, defaultDir string) *Diagnostics {
+	return &Diagnostics{exporter: exporter, defaultDir: defaultDir}
+}
+
+// Export writes a diagnostics zip to path (or to a timestamped file under the
+// default dir when path is empty) and returns the written path.
+func (d *Diagnostics) Export(path string) (string, error) {
+	if d.exporter == nil {
+		return "", errDiagnosticsUnavailable
+	}
+	if path == "" {
+		if d.defaultDir == "" {
+			return "", fmt.Errorf("%w: no destination path", errDiagnosticsUnavailable)
+		}
+		path = filepath.Join(d.defaultDir, diagnostics.FileName(time.Now()))
+	}
+	if err := diagnostics.WriteFile(context.Background(), d.exporter, path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,38 @@
+package wailsapp
+
+import (
+	"context"
+	"errors"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+)
+
+// errLogViewerUnavailable is returned when no persistent log sink is configured.
+var errLogViewerUnavailable = errors.New("log viewer unavailable: file logging is disabled")
+
+// LogViewer exposes recent persisted log entries to the frontend (via Wails),
+// so users can inspect and attach logs to bug reports.
+type LogViewer struct {
+	reader output.LogReader
+}
+
+// NewLogViewer constructs the binding. A nil reader is allowed; calls then fail gracefully.
+func NewLogViewer(reader output.LogReader) *LogViewer {
+	return &LogViewer{reader: reader}
+}
+
+// Recent returns the most recent log entries matching query, oldest first.
+func (v *LogViewer) Recent(query output.LogQuery) ([]output.LogEntry, error) {
+	if v.reader == nil {
+		return nil, errLogViewerUnavailable
+	}
+	return v.reader.Recent(context.Background(), query)
+}
+
+// LogFilePath returns the active log file path (empty when file logging is disabled).
+func (v *LogViewer) LogFilePath() string {
+	if v.reader == nil {
+		return ""
+	}
+	return v.reader.Path()
+}
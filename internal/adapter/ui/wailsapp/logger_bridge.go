@@ -5,31 +5,48 @@ import "github.com/betoth/contractcheck/internal/application/ports/output"
 
 // LoggerBridge exposes logging methods to the frontend (via Wails).
 // It proxies calls from JS -> Go -> zap logger.
+//
+// Metadata arrives as a JSON string; object keys become individual zap fields
+// (sanitized per MetaPolicy) and every entry is tagged with source=frontend.
+// The session field is inherited from the injected logger, so frontend and
+// backend entries of the same run share it.
 type LoggerBridge struct {
 	logger output.Logger
+	meta   *metaSanitizer
 }
 
-// NewLoggerBridge constructs a bridge with the injected logger.
-func NewLoggerBridge(logger output.Logger) *LoggerBridge {
-	return &LoggerBridge{logger: logger}
+// NewLoggerBridge constructs a bridge with the injected logger and metadata policy.
+func NewLoggerBridge(logger output.Logger, policy MetaPolicy) *LoggerBridge {
+	if logger != nil {
+		logger = logger.With("source", "frontend")
+	}
+	return &LoggerBridge{logger: logger, meta: newMetaSanitizer(policy)}
 }
 
 // Info logs at info level
 func (l *LoggerBridge) Info(msg string, meta string) {
-	l.logger.Info(msg, "meta", meta)
+	if l.logger != nil {
+		l.logger.Info(msg, l.meta.Fields(meta)...)
+	}
 }
 
 // Warn logs at warn level
 func (l *LoggerBridge) Warn(msg string, meta string) {
-	l.logger.Warn(msg, "meta", meta)
+	if l.logger != nil {
+		l.logger.Warn(msg, l.meta.Fields(meta)...)
+	}
 }
 
 // Error logs at error level
 func (l *LoggerBridge) Error(msg string, meta string) {
-	l.logger.Error(msg, "meta", meta)
+	if l.logger != nil {
+		l.logger.Error(msg, l.meta.Fields(meta)...)
+	}
 }
 
 // Debug logs at debug level
 func (l *LoggerBridge) Debug(msg string, meta string) {
-	l.logger.Debug(msg, "meta", meta)
+	if l.logger != nil {
+		l.logger.Debug(msg, l.meta.Fields(meta)...)
+	}
 }
@@ -0,0 +1,101 @@
+package wailsapp_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/betoth/contractcheck/internal/adapter/ui/wailsapp"
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+)
+
+// kvLogger records the key/value pairs of the last call, including With() fields.
+// Loggers derived via With() share the same sink.
+type kvLogger struct {
+	with []any
+	sink *map[string]any
+}
+
+func (l *kvLogger) With(kv ...any) output.Logger {
+	return &kvLogger{with: append(append([]any{}, l.with...), kv...), sink: l.sink}
+}
+func (l *kvLogger) Named(string) output.Logger  { return l }
+func (l *kvLogger) Info(msg string, kv ...any)  { l.record(kv) }
+func (l *kvLogger) Warn(msg string, kv ...any)  { l.record(kv) }
+func (l *kvLogger) Error(msg string, kv ...any) { l.record(kv) }
+func (l *kvLogger) Debug(msg string, kv ...any) { l.record(kv) }
+func (l *kvLogger) Sync() error                 { return nil }
+
+func (l *kvLogger) record(kv []any) {
+	last := map[string]any{}
+	all := append(append([]any{}, l.with...), kv...)
+	for i := 0; i+1 < len(all); i += 2 {
+		last[all[i].(string)] = all[i+1]
+	}
+	*l.sink = last
+}
+
+func newBridge(policy wailsapp.MetaPolicy) (*wailsapp.LoggerBridge, func() map[string]any) {
+	var last map[string]any
+	b := wailsapp.NewLoggerBridge(&kvLogger{sink: &last}, policy)
+	return b, func() map[string]any { return last }
+}
+
+func TestLoggerBridge_StructuredMeta(t *testing.T) {
+	b, fields := newBridge(wailsapp.MetaPolicy{MaxBytes: 1024, MaxDepth: 2, RedactKeys: []string{"api_key"}})
+
+	b.Info("clicked", `{"route":"/projects","projectId":42,"API-Key":"s3cr3t","ctx":{"a":{"b":1}},"level":"x"}`)
+	got := fields()
+
+	if got["source"] != "frontend" {
+		t.Fatalf("expected source=frontend, got %#v", got["source"])
+	}
+	if got["route"] != "/projects" {
+		t.Fatalf("expected route to be hoisted, got %#v", got["route"])
+	}
+	if n, ok := got["projectId"]; !ok || n.(interface{ String() string }).String() != "42" {
+		t.Fatalf("expected projectId=42, got %#v", n)
+	}
+	if got["API-Key"] != "[REDACTED]" {
+		t.Fatalf("expected API-Key redacted, got %#v", got["API-Key"])
+	}
+	ctx, _ := got["ctx"].(map[string]any)
+	if ctx["a"] != "[TRUNCATED]" {
+		t.Fatalf("expected nested value truncated at depth 2, got %#v", got["ctx"])
+	}
+	if got["meta_level"] != "x" {
+		t.Fatalf("expected reserved key renamed to meta_level, got %#v", got)
+	}
+}
+
+func TestLoggerBridge_OversizedAndInvalidMeta(t *testing.T) {
+	b, fields := newBridge(wailsapp.MetaPolicy{MaxBytes: 16})
+
+	b.Warn("big", `{"payload":"`+strings.Repeat("x", 64)+`"}`)
+	if _, ok := fields()["meta_dropped"]; !ok {
+		t.Fatalf("expected oversized metadata to be dropped, got %#v", fields())
+	}
+
+	b.Error("bad", `{not json`)
+	if fields()["meta_error"] != "invalid JSON" {
+		t.Fatalf("expected invalid JSON marker, got %#v", fields())
+	}
+}
+
+func TestLoggerBridge_DefaultPolicyRedactsKeysContainingSecrets(t *testing.T) {
+	b, fields := newBridge(wailsapp.DefaultMetaPolicy())
+
+	b.Info("login", `{"authToken":"a","sessionToken":"b","x-api-key":"c","ctx":{"client_secret":"d"},"user":"ana"}`)
+	got := fields()
+
+	for _, k := range []string{"authToken", "sessionToken", "x-api-key"} {
+		if got[k] != "[REDACTED]" {
+			t.Fatalf("expected %s redacted, got %#v", k, got[k])
+		}
+	}
+	if ctx, _ := got["ctx"].(map[string]any); ctx["client_secret"] != "[REDACTED]" {
+		t.Fatalf("expected nested client_secret redacted, got %#v", got["ctx"])
+	}
+	if got["user"] != "ana" {
+		t.Fatalf("expected non-sensitive key kept, got %#v", got["user"])
+	}
+}
@@ -0,0 +1,152 @@
+package wailsapp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/config"
+)
+
+const (
+	// redactedValue replaces values of sensitive keys.
+	redactedValue = "[REDACTED]"
+	// truncatedValue replaces values nested deeper than MetaPolicy.MaxDepth.
+	truncatedValue = "[TRUNCATED]"
+)
+
+// reservedFieldKeys are keys owned by the backend logger; frontend keys that
+// collide with them are logged as "meta_<key>" instead.
+var reservedFieldKeys = map[string]struct{}{
+	"ts": {}, "level": {}, "logger": {}, "msg": {}, "caller": {},
+	"source": {}, "session": {}, "route": {},
+}
+
+// MetaPolicy bounds and sanitizes structured metadata sent by the frontend.
+// - MaxBytes: raw JSON larger than this is dropped (only its size is logged).
+// - MaxDepth: objects/arrays nested deeper are replaced with "[TRUNCATED]".
+// - RedactKeys: values under keys containing one of these (case/"-"/"_" insensitive) are masked at any depth.
+type MetaPolicy struct {
+	MaxBytes   int
+	MaxDepth   int
+	RedactKeys []string
+}
+
+// DefaultMetaPolicy returns the limits and redact keys of the default
+// configuration, used when none are configured.
+func DefaultMetaPolicy() MetaPolicy {
+	def := config.Default().Log
+	return MetaPolicy{
+		MaxBytes:   def.MetaMaxBytes,
+		MaxDepth:   def.MetaMaxDepth,
+		RedactKeys: def.RedactKeys,
+	}
+}
+
+// metaSanitizer applies a MetaPolicy to raw JSON metadata.
+type metaSanitizer struct {
+	maxBytes int
+	maxDepth int
+	redact   []string
+}
+
+func newMetaSanitizer(p MetaPolicy) *metaSanitizer {
+	def := DefaultMetaPolicy()
+	if p.MaxBytes <= 0 {
+		p.MaxBytes = def.MaxBytes
+	}
+	if p.MaxDepth <= 0 {
+		p.MaxDepth = def.MaxDepth
+	}
+	return &metaSanitizer{maxBytes: p.MaxBytes, maxDepth: p.MaxDepth, redact: output.RedactionKeys(p.RedactKeys)}
+}
+
+// Fields converts raw JSON metadata into zap key/value pairs.
+// A top-level "route" key is hoisted as the "route" field. Non-object or
+// invalid JSON is kept as a single "meta" string so nothing is silently lost.
+func (s *metaSanitizer) Fields(raw string) []any {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || raw == "{}" || raw == "null" {
+		return nil
+	}
+	if len(raw) > s.maxBytes {
+		return []any{"meta_dropped", fmt.Sprintf("metadata exceeds %d bytes", s.maxBytes), "meta_size", len(raw)}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader([]byte(raw)))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return []any{"meta", raw, "meta_error", "invalid JSON"}
+	}
+
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return []any{"meta", s.sanitize(v, 1)}
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	kv := make([]any, 0, 2*len(keys))
+	for _, k := range keys {
+		val := obj[k]
+		switch {
+		case s.isSensitive(k):
+			val = redactedValue
+		case k == "route":
+			if route, ok := val.(string); ok {
+				kv = append(kv, "route", route)
+				continue
+			}
+			val = s.sanitize(val, 1)
+		default:
+			val = s.sanitize(val, 1)
+		}
+		if _, reserved := reservedFieldKeys[k]; reserved {
+			k = "meta_" + k
+		}
+		kv = append(kv, k, val)
+	}
+	return kv
+}
+
+// sanitize walks v, redacting sensitive keys and truncating beyond maxDepth.
+func (s *metaSanitizer) sanitize(v any, depth int) any {
+	switch t := v.(type) {
+	case map[string]any:
+		if depth >= s.maxDepth {
+			return truncatedValue
+		}
+		out := make(map[string]any, len(t))
+		for k, child := range t {
+			if s.isSensitive(k) {
+				out[k] = redactedValue
+				continue
+			}
+			out[k] = s.sanitize(child, depth+1)
+		}
+		return out
+	case []any:
+		if depth >= s.maxDepth {
+			return truncatedValue
+		}
+		out := make([]any, len(t))
+		for i, child := range t {
+			out[i] = s.sanitize(child, depth+1)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func (s *metaSanitizer) isSensitive(key string) bool {
+	return output.IsSensitiveKey(key, s.redact)
+}
@@ -0,0 +1,89 @@
+package wailsapp
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Events emitted by SpecWatcher.
+const (
+	// EventWatchResult carries an input.WatchResult after each run with changes.
+	EventWatchResult = "spec:watch:result"
+	// EventWatchError carries the error message when a session stops on failure.
+	EventWatchError = "spec:watch:error"
+)
+
+// errWatchUnavailable is returned when no watch service is wired.
+var errWatchUnavailable = errors.New("watch mode unavailable")
+
+// SpecWatcher exposes watch mode to the frontend (via Wails). Results are
+// pushed as Wails events rather than returned, since a session outlives the call.
+type SpecWatcher struct {
+	watch input.WatchOpenAPISpec
+	emit  func(ctx context.Context, name string, data ...any)
+
+	mu     sync.Mutex
+	appCtx context.Context
+	cancel context.CancelFunc
+	file   string
+}
+
+// NewSpecWatcher constructs the binding. A nil service is allowed; Start then fails gracefully.
+func NewSpecWatcher(watch input.WatchOpenAPISpec) *SpecWatcher {
+	return &SpecWatcher{watch: watch, emit: runtime.EventsEmit, appCtx: context.Background()}
+}
+
+// startup records the Wails context, required to emit events.
+func (w *SpecWatcher) startup(ctx context.Context) {
+	w.mu.Lock()
+	w.appCtx = ctx
+	w.mu.Unlock()
+}
+
+// Start watches path, replacing any running session.
+func (w *SpecWatcher) Start(path string) error {
+	if w.watch == nil {
+		return errWatchUnavailable
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.stopLocked()
+
+	ctx, cancel := context.WithCancel(w.appCtx)
+	w.cancel, w.file = cancel, path
+	appCtx := w.appCtx
+	go func() {
+		err := w.watch.Watch(ctx, path, input.WatchOptions{}, func(r input.WatchResult) {
+			w.emit(appCtx, EventWatchResult, r)
+		})
+		if err != nil && ctx.Err() == nil {
+			w.emit(appCtx, EventWatchError, err.Error())
+		}
+	}()
+	return nil
+}
+
+// Stop ends the running session, if any.
+func (w *SpecWatcher) Stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.stopLocked()
+}
+
+// Watching returns the spec being watched ("" when idle).
+func (w *SpecWatcher) Watching() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file
+}
+
+func (w *SpecWatcher) stopLocked() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.cancel, w.file = nil, ""
+}
@@ -0,0 +1,129 @@
+// Package watch implements output.FileWatcher on top of fsnotify
+// (inotify on Linux, FSEvents/kqueue on macOS, ReadDirectoryChangesW on Windows).
+package watch
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultDebounce is the quiet period that closes a burst of events.
+const DefaultDebounce = 200 * time.Millisecond
+
+// FSWatcher watches files through their parent directories, so the
+// write-to-temp-then-rename saves used by most editors are still observed.
+type FSWatcher struct {
+	debounce time.Duration
+}
+
+// Option configures an FSWatcher.
+type Option func(*FSWatcher)
+
+// WithDebounce sets the quiet period that closes a burst of events.
+func WithDebounce(d time.Duration) Option {
+	return func(w *FSWatcher) {
+		if d > 0 {
+			w.debounce = d
+		}
+	}
+}
+
+// NewFSWatcher builds a watcher with safe defaults.
+func NewFSWatcher(opts ...Option) *FSWatcher {
+	w := &FSWatcher{debounce: DefaultDebounce}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Watch implements output.FileWatcher. Batches are sorted; a burst touching
+// the same file many times reports it once.
+func (w *FSWatcher) Watch(ctx context.Context, files []string) (<-chan []string, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make(map[string]bool, len(files))
+	dirs := map[string]bool{}
+	for _, f := range files {
+		abs, err := filepath.Abs(f)
+		if err != nil {
+			fsw.Close()
+			return nil, err
+		}
+		targets[abs] = true
+		dirs[filepath.Dir(abs)] = true
+	}
+	for dir := range dirs {
+		if err := fsw.Add(dir); err != nil {
+			fsw.Close()
+			return nil, err
+		}
+	}
+
+	out := make(chan []string, 1)
+	go w.loop(ctx, fsw, targets, out)
+	return out, nil
+}
+
+// loop collects matching events into a pending set and flushes it once no
+// event arrived for the debounce period.
+func (w *FSWatcher) loop(ctx context.Context, fsw *fsnotify.Watcher, targets map[string]bool, out chan<- []string) {
+	defer close(out)
+	defer fsw.Close()
+
+	pending := map[string]bool{}
+	timer := time.NewTimer(w.debounce)
+	timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case ev, ok := <-fsw.Events:
+			if !ok {
+				return
+			}
+			if ev.Op == fsnotify.Chmod {
+				continue
+			}
+			name := filepath.Clean(ev.Name)
+			if !targets[name] {
+				continue
+			}
+			pending[name] = true
+			timer.Reset(w.debounce)
+
+		case _, ok := <-fsw.Errors:
+			// Overflows and transient errors are not fatal: the next event
+			// still triggers a full run, which re-reads every file.
+			if !ok {
+				return
+			}
+
+		case <-timer.C:
+			batch := make([]string, 0, len(pending))
+			for name := range pending {
+				batch = append(batch, name)
+			}
+			sort.Strings(batch)
+			pending = map[string]bool{}
+			select {
+			case out <- batch:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// compile-time check
+var _ output.FileWatcher = (*FSWatcher)(nil)
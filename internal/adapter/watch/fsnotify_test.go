@@ -0,0 +1,56 @@
+package watch_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/betoth/contractcheck/internal/adapter/watch"
+)
+
+func TestFSWatcher_DebouncesBurstsAndFiltersFiles(t *testing.T) {
+	dir := t.TempDir()
+	spec := filepath.Join(dir, "spec.yaml")
+	other := filepath.Join(dir, "notes.txt")
+	for _, f := range []string{spec, other} {
+		if err := os.WriteFile(f, []byte("a"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := watch.NewFSWatcher(watch.WithDebounce(50*time.Millisecond)).Watch(ctx, []string{spec})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A burst of writes, plus an editor-style atomic save, plus an unrelated file.
+	for i := 0; i < 5; i++ {
+		os.WriteFile(spec, []byte{byte('a' + i)}, 0o644)
+		os.WriteFile(other, []byte{byte('a' + i)}, 0o644)
+	}
+	tmp := filepath.Join(dir, ".spec.yaml.swp")
+	os.WriteFile(tmp, []byte("z"), 0o644)
+	os.Rename(tmp, spec)
+
+	select {
+	case batch := <-events:
+		if len(batch) != 1 || batch[0] != spec {
+			t.Fatalf("batch = %v, want [%s]", batch, spec)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a batch")
+	}
+	select {
+	case batch := <-events:
+		t.Fatalf("expected the burst to be debounced into one batch, got another: %v", batch)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	cancel()
+	for range events {
+	}
+}
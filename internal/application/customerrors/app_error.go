@@ -20,6 +20,9 @@ const (
 	DetailVersion   = "version"
 	DetailComponent = "component"
 	DetailExpected  = "expected"
+	DetailRevision  = "revision"
+	DetailLine      = "line"
+	DetailColumn    = "column"
 )
 
 // AppError is the central application error.
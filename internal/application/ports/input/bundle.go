@@ -0,0 +1,21 @@
+package input
+
+import (
+	"context"
+	"io"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+)
+
+// BundleOptions tune a bundle.
+//   - Sandbox: the directory external refs must stay within; empty means the
+//     root spec's directory.
+type BundleOptions struct {
+	Sandbox string
+}
+
+// BundleOpenAPISpec defines the input port (use case) that merges a multi-file
+// spec into a single self-contained document and writes it in the given format.
+type BundleOpenAPISpec interface {
+	Bundle(ctx context.Context, rootPath string, w io.Writer, format openapi.Format, opts BundleOptions) (openapi.OpenAPIDoc, error)
+}
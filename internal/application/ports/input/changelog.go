@@ -0,0 +1,23 @@
+package input
+
+import (
+	"context"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+// ChangelogOptions tunes changelog generation.
+//   - Detail: endpoint- or field-level entries (default: fields).
+//   - Version/Title: default to the head spec's info.version and info.title.
+//   - Date: ISO 8601 release date; empty marks the entry as unreleased.
+type ChangelogOptions struct {
+	Detail  report.ChangelogDetail
+	Version string
+	Title   string
+	Date    string
+}
+
+// GenerateChangelog turns a spec comparison into a Keep a Changelog entry.
+type GenerateChangelog interface {
+	Changelog(ctx context.Context, cmp report.Comparison, opts ChangelogOptions) (report.Changelog, error)
+}
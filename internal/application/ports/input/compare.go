@@ -0,0 +1,46 @@
+package input
+
+import (
+	"context"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+// GitCompareRequest selects a spec in a local repository and the revisions to compare.
+//   - Repo: any directory inside the repository (default: current directory).
+//   - Path: the spec, relative to Repo or absolute.
+//   - Target: the branch the change will merge into, e.g. "main".
+//   - Head: the revision under review (default: "HEAD").
+//
+// The base side is the merge-base of Head and Target, so changes that landed
+// on Target after the branch point are not attributed to the PR.
+type GitCompareRequest struct {
+	Repo   string
+	Path   string
+	Target string
+	Head   string
+}
+
+// CompareOpenAPISpecs detects contract changes between two versions of a spec.
+type CompareOpenAPISpecs interface {
+	// Compare diffs two spec files on disk.
+	Compare(ctx context.Context, basePath, headPath string) (report.Comparison, error)
+	// CompareGit diffs a spec between the merge-base of Head/Target and Head.
+	CompareGit(ctx context.Context, req GitCompareRequest) (report.Comparison, error)
+}
+
+// Baseline is a fixed base to diff a spec against: a spec file, or the spec
+// itself as of a git revision (branch, tag, SHA, …). Set one of them.
+type Baseline struct {
+	File string
+	Rev  string
+}
+
+// IsZero reports whether no baseline is set.
+func (b Baseline) IsZero() bool { return b.File == "" && b.Rev == "" }
+
+// BuildBaselineCheck loads a baseline once and returns a check that reports
+// the contract changes of every document it is given against it.
+type BuildBaselineCheck interface {
+	BaselineCheck(ctx context.Context, specPath string, base Baseline) (report.Check, error)
+}
@@ -0,0 +1,15 @@
+package input
+
+import (
+	"context"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+// MeasureCoverage matches recorded traffic (HAR files captured by a
+// browser, a proxy or a test run) to the operations of a spec and reports
+// which paths, methods, status codes, parameters and enum values it
+// exercised.
+type MeasureCoverage interface {
+	Coverage(ctx context.Context, specPath string, trafficPaths []string) (report.Coverage, error)
+}
@@ -0,0 +1,30 @@
+package input
+
+import (
+	"context"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+// DeprecationOptions tunes the deprecation lifecycle check.
+//   - Ledger: file holding the records of earlier runs; empty disables
+//     tracking, so deprecation dates of removed elements are unknown.
+//   - Frozen: read the ledger without saving this run's records (e.g. on
+//     pull request builds, where the head may never merge).
+//   - GraceDays: minimum days between first deprecation and removal.
+//   - Date: ISO 8601 date to check as (default: today).
+type DeprecationOptions struct {
+	Ledger    string
+	Frozen    bool
+	GraceDays int
+	Date      string
+}
+
+// CheckDeprecations follows deprecations across revisions of a spec and
+// reports elements removed without, or too soon after, a deprecation.
+type CheckDeprecations interface {
+	// CheckFiles checks the removals from one spec file to another.
+	CheckFiles(ctx context.Context, basePath, headPath string, opts DeprecationOptions) (report.DeprecationReport, error)
+	// CheckGit checks the removals between the merge-base of Head/Target and Head.
+	CheckGit(ctx context.Context, req GitCompareRequest, opts DeprecationOptions) (report.DeprecationReport, error)
+}
@@ -0,0 +1,13 @@
+package input
+
+import (
+	"context"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+)
+
+// DereferenceOpenAPISpec defines the input port (use case) that inlines all
+// local $ref of an imported document for consumers that cannot follow refs.
+type DereferenceOpenAPISpec interface {
+	Dereference(ctx context.Context, doc openapi.OpenAPIDoc, opts openapi.DerefOptions) (openapi.OpenAPIDoc, openapi.DerefStats, error)
+}
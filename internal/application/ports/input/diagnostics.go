@@ -0,0 +1,13 @@
+package input
+
+import (
+	"context"
+	"io"
+)
+
+// ExportDiagnostics defines the input port (use case) that packages recent logs,
+// effective configuration, version/OS info and job history into a zip archive
+// users can attach to bug reports. Secrets are redacted before writing.
+type ExportDiagnostics interface {
+	Export(ctx context.Context, w io.Writer) error
+}
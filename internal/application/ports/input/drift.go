@@ -0,0 +1,15 @@
+package input
+
+import (
+	"context"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+// DetectDrift compares recorded traffic (HAR files captured by a browser, a
+// proxy or a test run) with a spec and reports where they disagree:
+// undocumented operations, status codes, query parameters and fields, and
+// documented fields that never appear, most frequent first.
+type DetectDrift interface {
+	Drift(ctx context.Context, specPath string, trafficPaths []string) (report.DriftReport, error)
+}
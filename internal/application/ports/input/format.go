@@ -0,0 +1,13 @@
+package input
+
+import (
+	"context"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+)
+
+// FormatOpenAPISpec defines the input port (use case) that validates a spec file
+// and renders it in canonical order. FormatResult.Canonical backs "check" mode.
+type FormatOpenAPISpec interface {
+	Format(ctx context.Context, filePath string, opts openapi.FormatOptions) (openapi.FormatResult, error)
+}
@@ -0,0 +1,28 @@
+package input
+
+import (
+	"context"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+// FuzzOptions configures a fuzz run against a live service.
+//   - BaseURL, Credentials, Concurrency: as in RunOptions.
+//   - Seed: drives every random choice; 0 picks one, reported in the result.
+//   - Cases: the most mutated requests sent per operation; 0 means the
+//     service default.
+type FuzzOptions struct {
+	BaseURL     string
+	Credentials map[string]string
+	Concurrency int
+	Seed        int64
+	Cases       int
+}
+
+// FuzzAPI sends mutated requests (boundary values, wrong types, missing
+// required fields, oversized strings, invalid enums) to a running service
+// and checks that invalid ones are rejected with documented 4xx responses
+// and that no request produces an undocumented 5xx.
+type FuzzAPI interface {
+	Fuzz(ctx context.Context, specPath string, opts FuzzOptions) (report.FuzzRun, error)
+}
@@ -0,0 +1,27 @@
+package input
+
+import (
+	"context"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+)
+
+// InferOptions configures spec inference.
+//   - BaseURL: the service whose traffic is used, e.g.
+//     "https://api.example.com/v1"; its path is the server base path.
+//     Defaults to the origin most requests went to; other origins (CDNs,
+//     analytics) are ignored.
+//   - Title, Version: the info of the draft; defaults name it after the host.
+type InferOptions struct {
+	BaseURL string
+	Title   string
+	Version string
+}
+
+// InferOpenAPISpec drafts an OpenAPI 3 document from recorded traffic (HAR
+// files captured by a browser, a proxy or a test run): URLs are grouped
+// into templated paths and schemas inferred from the bodies. The draft is
+// validated like any loaded spec.
+type InferOpenAPISpec interface {
+	Infer(ctx context.Context, trafficPaths []string, opts InferOptions) (openapi.OpenAPIDoc, error)
+}
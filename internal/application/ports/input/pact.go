@@ -0,0 +1,14 @@
+package input
+
+import (
+	"context"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+// VerifyPacts checks consumer contracts (Pact files) against a provider spec:
+// every interaction must hit a declared operation with an accepted request,
+// and expect a response the spec declares.
+type VerifyPacts interface {
+	Verify(ctx context.Context, specPath string, pactPaths []string) (report.PactVerification, error)
+}
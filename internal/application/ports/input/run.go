@@ -0,0 +1,25 @@
+package input
+
+import (
+	"context"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+// RunOptions configures a contract run against a live service.
+//   - BaseURL: the service to call; defaults to the spec's first absolute server URL.
+//   - Credentials: values per security scheme name: the token of http bearer,
+//     oauth2 and openIdConnect schemes, "user:password" for http basic, the
+//     key of apiKey schemes.
+//   - Concurrency: requests in flight at once; 0 means the service default.
+type RunOptions struct {
+	BaseURL     string
+	Credentials map[string]string
+	Concurrency int
+}
+
+// RunContractTests calls every operation of a spec on a running service and
+// checks the responses against the spec.
+type RunContractTests interface {
+	Run(ctx context.Context, specPath string, opts RunOptions) (report.ContractRun, error)
+}
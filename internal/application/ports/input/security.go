@@ -0,0 +1,15 @@
+package input
+
+import (
+	"context"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+// AuditSecurity checks how a spec protects its operations: operations
+// without security requirements, inconsistent OAuth scopes within a
+// resource, API keys in query strings, HTTP basic auth over plain HTTP and
+// OAuth flows missing their URLs.
+type AuditSecurity interface {
+	Audit(ctx context.Context, specPath string) (report.Report, error)
+}
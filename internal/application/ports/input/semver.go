@@ -0,0 +1,13 @@
+package input
+
+import (
+	"context"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+// RecommendVersion derives the info.version bump a spec comparison calls for
+// and checks it against the version the head spec declares.
+type RecommendVersion interface {
+	RecommendVersion(ctx context.Context, cmp report.Comparison) (report.VersionAdvice, error)
+}
@@ -0,0 +1,20 @@
+package input
+
+import (
+	"context"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/testgen"
+)
+
+// TestGenOptions tunes contract test generation.
+//   - Package: package clause of the generated files (default "contract").
+type TestGenOptions struct {
+	Package string
+}
+
+// GenerateContractTests turns a spec into runnable contract tests: one per
+// operation and declared response, sending synthesized requests to a live
+// server and checking the status and body against the spec.
+type GenerateContractTests interface {
+	Generate(ctx context.Context, specPath string, opts TestGenOptions) (testgen.Generated, error)
+}
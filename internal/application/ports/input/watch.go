@@ -0,0 +1,33 @@
+package input
+
+import (
+	"context"
+	"time"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+// WatchResult is emitted after every pipeline run of a watch session.
+// Delta holds only the findings that appeared or disappeared since the
+// previous run; the first run reports every finding as added.
+type WatchResult struct {
+	Run      int           `json:"run"`
+	File     string        `json:"file"`
+	Changed  []string      `json:"changed,omitempty"`
+	Watched  []string      `json:"watched"`
+	Delta    report.Delta  `json:"delta"`
+	Duration time.Duration `json:"duration"`
+}
+
+// WatchOptions tunes a watch session.
+//   - Base: what every run is diffed against; the zero value skips the diff.
+type WatchOptions struct {
+	Base Baseline
+}
+
+// WatchOpenAPISpec re-runs import, the configured checks and, with a base,
+// the diff whenever the spec or one of its referenced files changes, until
+// ctx is done.
+type WatchOpenAPISpec interface {
+	Watch(ctx context.Context, filePath string, opts WatchOptions, emit func(WatchResult)) error
+}
@@ -0,0 +1,17 @@
+package output
+
+import (
+	"context"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+// DeprecationLedger persists deprecation records between runs, so removals
+// can be checked against when an element was first deprecated.
+// Implementations should:
+//   - return no records and no error when nothing was saved at path yet;
+//   - replace the stored records atomically on Save.
+type DeprecationLedger interface {
+	Load(ctx context.Context, path string) ([]report.DeprecationRecord, error)
+	Save(ctx context.Context, path string, records []report.DeprecationRecord) error
+}
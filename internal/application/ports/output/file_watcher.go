@@ -0,0 +1,12 @@
+package output
+
+import "context"
+
+// FileWatcher reports changes to a set of files.
+// Implementations debounce bursts (editors often write, rename and chmod in
+// quick succession) and emit each burst as one batch of changed paths.
+type FileWatcher interface {
+	// Watch emits batches of changed paths among files until ctx is done,
+	// then closes the channel. Paths are absolute and cleaned.
+	Watch(ctx context.Context, files []string) (<-chan []string, error)
+}
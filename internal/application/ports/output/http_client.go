@@ -0,0 +1,28 @@
+package output
+
+import (
+	"context"
+	"net/http"
+)
+
+// HTTPRequest is a request to a service under test. URL is absolute.
+type HTTPRequest struct {
+	Method string
+	URL    string
+	Header http.Header
+	Body   []byte
+}
+
+// HTTPResponse is the answer of a service under test.
+type HTTPResponse struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// HTTPClient sends requests to a running service. Any HTTP status is a
+// response; only transport failures (refused connections, timeouts,
+// cancellation) are errors.
+type HTTPClient interface {
+	Do(ctx context.Context, req HTTPRequest) (HTTPResponse, error)
+}
@@ -0,0 +1,29 @@
+package output
+
+import "time"
+
+// JobStatus is the terminal state of a recorded job.
+type JobStatus string
+
+const (
+	JOB_SUCCEEDED JobStatus = "succeeded"
+	JOB_FAILED    JobStatus = "failed"
+)
+
+// JobRecord describes one finished unit of work (e.g., a spec import).
+type JobRecord struct {
+	Kind      string        `json:"kind"`
+	Target    string        `json:"target"`
+	Status    JobStatus     `json:"status"`
+	StartedAt time.Time     `json:"startedAt"`
+	Duration  time.Duration `json:"duration"`
+	Error     string        `json:"error,omitempty"`
+	ErrorKind string        `json:"errorKind,omitempty"`
+}
+
+// JobHistory is the output port that keeps a bounded history of recent jobs.
+// Implementations must be safe for concurrent use.
+type JobHistory interface {
+	Record(job JobRecord)
+	Recent(limit int) []JobRecord
+}
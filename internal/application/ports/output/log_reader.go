@@ -0,0 +1,35 @@
+package output
+
+import (
+	"context"
+	"time"
+)
+
+// LogEntry is a single persisted log record, decoded from the log sink.
+type LogEntry struct {
+	Time    time.Time      `json:"time"`
+	Level   string         `json:"level"`
+	Logger  string         `json:"logger,omitempty"`
+	Message string         `json:"message"`
+	Caller  string         `json:"caller,omitempty"`
+	Fields  map[string]any `json:"fields,omitempty"`
+}
+
+// LogQuery filters the entries returned by a LogReader.
+// - Level: minimum level (debug, info, warn, error); empty means all.
+// - Logger: logger name prefix (e.g., "ui").
+// - Contains: case-insensitive substring matched against message and fields.
+// - Limit: maximum number of most recent entries; <= 0 uses the reader default.
+type LogQuery struct {
+	Level    string `json:"level"`
+	Logger   string `json:"logger"`
+	Contains string `json:"contains"`
+	Limit    int    `json:"limit"`
+}
+
+// LogReader is the output port for reading back recent persisted log entries.
+// Entries are returned in chronological order (oldest first).
+type LogReader interface {
+	Recent(ctx context.Context, q LogQuery) ([]LogEntry, error)
+	Path() string
+}
@@ -0,0 +1,216 @@
+package openapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// APIVersion is the `info.version` field: the version of the API described
+// by the document, unrelated to the OpenAPIVersion of the format itself.
+// Specs are free to use any scheme ("2024-06-01", "v2"); only semantic
+// versions can be compared and bumped.
+type APIVersion string
+
+// String returns the raw version string (e.g., "1.4.0").
+func (v APIVersion) String() string { return string(v) }
+
+// Semver parses v as MAJOR.MINOR.PATCH[-PRERELEASE][+BUILD] (SemVer 2.0.0).
+// A leading "v" is tolerated since many specs declare "v1.2.3".
+// ok is false for anything else, including "1.2" and leading zeros.
+func (v APIVersion) Semver() (s Semver, ok bool) {
+	str := strings.TrimPrefix(strings.TrimSpace(string(v)), "v")
+	var hasBuild, hasPre bool
+	str, s.Build, hasBuild = strings.Cut(str, "+")
+	str, s.Prerelease, hasPre = strings.Cut(str, "-")
+	if hasBuild && s.Build == "" || hasPre && s.Prerelease == "" {
+		return Semver{}, false
+	}
+	parts := strings.Split(str, ".")
+	if len(parts) != 3 {
+		return Semver{}, false
+	}
+	nums := [3]*int{&s.Major, &s.Minor, &s.Patch}
+	for i, p := range parts {
+		n, ok := semverNumber(p)
+		if !ok {
+			return Semver{}, false
+		}
+		*nums[i] = n
+	}
+	for _, ids := range []string{s.Prerelease, s.Build} {
+		if ids == "" {
+			continue
+		}
+		for _, id := range strings.Split(ids, ".") {
+			if !isSemverIdentifier(id) {
+				return Semver{}, false
+			}
+		}
+	}
+	return s, true
+}
+
+// Semver is a parsed semantic version.
+type Semver struct {
+	Major, Minor, Patch int
+	Prerelease          string
+	Build               string
+}
+
+// String renders the version without a "v" prefix.
+func (s Semver) String() string {
+	out := fmt.Sprintf("%d.%d.%d", s.Major, s.Minor, s.Patch)
+	if s.Prerelease != "" {
+		out += "-" + s.Prerelease
+	}
+	if s.Build != "" {
+		out += "+" + s.Build
+	}
+	return out
+}
+
+// Compare orders versions by SemVer precedence: -1, 0 or +1.
+// Build metadata is ignored; a pre-release sorts before its release.
+func (s Semver) Compare(o Semver) int {
+	for _, d := range [3]int{s.Major - o.Major, s.Minor - o.Minor, s.Patch - o.Patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+	switch {
+	case s.Prerelease == o.Prerelease:
+		return 0
+	case s.Prerelease == "":
+		return 1
+	case o.Prerelease == "":
+		return -1
+	}
+	a, b := strings.Split(s.Prerelease, "."), strings.Split(o.Prerelease, ".")
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := comparePrereleaseID(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	return sign(len(a) - len(b))
+}
+
+// Bump returns the next version for b, dropping pre-release and build
+// metadata. BUMP_NONE returns s unchanged. As in SemVer, a pre-release
+// already stands for its release: when b does not go past the component the
+// pre-release leads up to, the bump only drops the pre-release, so
+// 2.0.0-rc.1 bumps to 2.0.0 for BUMP_MAJOR and 1.5.0 for BUMP_MINOR.
+func (s Semver) Bump(b VersionBump) Semver {
+	if s.Prerelease != "" && b != BUMP_NONE && !s.releaseBump().Less(b) {
+		return Semver{Major: s.Major, Minor: s.Minor, Patch: s.Patch}
+	}
+	switch b {
+	case BUMP_MAJOR:
+		return Semver{Major: s.Major + 1}
+	case BUMP_MINOR:
+		return Semver{Major: s.Major, Minor: s.Minor + 1}
+	case BUMP_PATCH:
+		return Semver{Major: s.Major, Minor: s.Minor, Patch: s.Patch + 1}
+	}
+	return s
+}
+
+// VersionBump names the semver component a release increments.
+type VersionBump string
+
+const (
+	BUMP_NONE  VersionBump = "none"
+	BUMP_PATCH VersionBump = "patch"
+	BUMP_MINOR VersionBump = "minor"
+	BUMP_MAJOR VersionBump = "major"
+)
+
+var bumpRank = map[VersionBump]int{BUMP_NONE: 0, BUMP_PATCH: 1, BUMP_MINOR: 2, BUMP_MAJOR: 3}
+
+// Less reports whether b increments a lower component than o.
+func (b VersionBump) Less(o VersionBump) bool { return bumpRank[b] < bumpRank[o] }
+
+// releaseBump names the component a pre-release leads up to: 2.0.0-rc.1
+// is a major release, 1.5.0-rc.1 a minor one and 1.4.2-rc.1 a patch.
+func (s Semver) releaseBump() VersionBump {
+	switch {
+	case s.Minor == 0 && s.Patch == 0:
+		return BUMP_MAJOR
+	case s.Patch == 0:
+		return BUMP_MINOR
+	}
+	return BUMP_PATCH
+}
+
+// BumpBetween names the highest component that grows from base to head.
+// Going to a lower or equal version is BUMP_NONE. From a pre-release, the
+// release it leads up to (or a later pre-release of it) is that release's
+// own component, matching Bump: 2.0.0-rc.1 to 2.0.0 is BUMP_MAJOR.
+func BumpBetween(base, head Semver) VersionBump {
+	sameRelease := head.Major == base.Major && head.Minor == base.Minor && head.Patch == base.Patch
+	switch {
+	case head.Compare(base) <= 0:
+		return BUMP_NONE
+	case base.Prerelease != "" && sameRelease:
+		return base.releaseBump()
+	case head.Major != base.Major:
+		return BUMP_MAJOR
+	case head.Minor != base.Minor:
+		return BUMP_MINOR
+	}
+	return BUMP_PATCH
+}
+
+// semverNumber parses a numeric identifier: digits, no leading zeros.
+func semverNumber(s string) (int, bool) {
+	if s == "" || (len(s) > 1 && s[0] == '0') {
+		return 0, false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, false
+		}
+	}
+	n, err := strconv.Atoi(s)
+	return n, err == nil
+}
+
+// isSemverIdentifier accepts a non-empty [0-9A-Za-z-] identifier.
+func isSemverIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// comparePrereleaseID compares dot-separated pre-release identifiers:
+// numeric ones numerically and below alphanumeric ones, which sort in ASCII order.
+func comparePrereleaseID(a, b string) int {
+	na, aNum := semverNumber(a)
+	nb, bNum := semverNumber(b)
+	switch {
+	case aNum && bNum:
+		return sign(na - nb)
+	case aNum:
+		return -1
+	case bNum:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
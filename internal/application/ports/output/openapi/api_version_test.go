@@ -0,0 +1,94 @@
+package openapi_test
+
+import (
+	"testing"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+)
+
+func TestAPIVersion_Semver(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string // "" when not a semantic version
+	}{
+		{"1.4.0", "1.4.0"},
+		{"v2.0.1", "2.0.1"},
+		{" 1.0.0-rc.1+build.5 ", "1.0.0-rc.1+build.5"},
+		{"0.0.0", "0.0.0"},
+		{"1.4", ""},
+		{"01.4.0", ""},
+		{"1.4.0-", ""},
+		{"1.4.0-beta..1", ""},
+		{"2024-06-01", ""},
+		{"", ""},
+	}
+	for _, tc := range cases {
+		s, ok := openapi.APIVersion(tc.in).Semver()
+		if got := map[bool]string{true: s.String()}[ok]; got != tc.want {
+			t.Errorf("Semver(%q) = %q, %v; want %q", tc.in, s, ok, tc.want)
+		}
+	}
+}
+
+func TestSemver_CompareAndBump(t *testing.T) {
+	parse := func(v string) openapi.Semver {
+		s, ok := openapi.APIVersion(v).Semver()
+		if !ok {
+			t.Fatalf("%q is not a semantic version", v)
+		}
+		return s
+	}
+	// SemVer 2.0.0 §11 precedence example, ascending.
+	order := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1+build"}
+	for i := 1; i < len(order); i++ {
+		if c := parse(order[i-1]).Compare(parse(order[i])); c != -1 {
+			t.Errorf("Compare(%s, %s) = %d, want -1", order[i-1], order[i], c)
+		}
+	}
+	if c := parse("1.0.0+a").Compare(parse("1.0.0+b")); c != 0 {
+		t.Errorf("build metadata affected precedence: %d", c)
+	}
+
+	// A pre-release bumps to its own release unless the bump goes past it.
+	for _, tc := range []struct {
+		base string
+		bump openapi.VersionBump
+		want string
+	}{
+		{"1.4.2", openapi.BUMP_MAJOR, "2.0.0"},
+		{"1.4.2", openapi.BUMP_MINOR, "1.5.0"},
+		{"1.4.2+build", openapi.BUMP_PATCH, "1.4.3"},
+		{"1.4.2-rc.1", openapi.BUMP_MAJOR, "2.0.0"},
+		{"1.4.2-rc.1", openapi.BUMP_MINOR, "1.5.0"},
+		{"1.4.2-rc.1", openapi.BUMP_PATCH, "1.4.2"},
+		{"1.4.2-rc.1", openapi.BUMP_NONE, "1.4.2-rc.1"},
+		{"1.5.0-rc.1", openapi.BUMP_MAJOR, "2.0.0"},
+		{"1.5.0-rc.1", openapi.BUMP_MINOR, "1.5.0"},
+		{"1.5.0-rc.1", openapi.BUMP_PATCH, "1.5.0"},
+		{"2.0.0-rc.1", openapi.BUMP_MAJOR, "2.0.0"},
+		{"2.0.0-rc.1", openapi.BUMP_MINOR, "2.0.0"},
+	} {
+		if got := parse(tc.base).Bump(tc.bump).String(); got != tc.want {
+			t.Errorf("%s.Bump(%s) = %s, want %s", tc.base, tc.bump, got, tc.want)
+		}
+	}
+
+	for _, tc := range []struct {
+		base, head string
+		want       openapi.VersionBump
+	}{
+		{"1.4.2", "2.0.0", openapi.BUMP_MAJOR},
+		{"1.4.2", "1.5.0", openapi.BUMP_MINOR},
+		{"1.4.2", "1.4.3", openapi.BUMP_PATCH},
+		{"1.4.2-rc.1", "1.4.2", openapi.BUMP_PATCH},
+		{"2.0.0-rc.1", "2.0.0", openapi.BUMP_MAJOR},
+		{"1.5.0-rc.1", "1.5.0-rc.2", openapi.BUMP_MINOR},
+		{"1.5.0-rc.1", "2.0.0", openapi.BUMP_MAJOR},
+		{"1.4.2", "1.4.2", openapi.BUMP_NONE},
+		{"1.4.2", "1.3.9", openapi.BUMP_NONE},
+	} {
+		if got := openapi.BumpBetween(parse(tc.base), parse(tc.head)); got != tc.want {
+			t.Errorf("BumpBetween(%s, %s) = %s, want %s", tc.base, tc.head, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format is a serialization format for OpenAPI documents.
+type Format string
+
+const (
+	FORMAT_JSON Format = "json"
+	FORMAT_YAML Format = "yaml"
+)
+
+// ParseFormat maps user input ("json", "yaml", "yml") to a Format.
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "json":
+		return FORMAT_JSON, nil
+	case "yaml", "yml":
+		return FORMAT_YAML, nil
+	default:
+		return "", fmt.Errorf("unsupported format %q (expected json or yaml)", s)
+	}
+}
+
+// Bundler is the output port that merges a multi-file spec into one document.
+// Implementations should:
+//   - resolve external $ref only within a sandbox (the root spec's directory),
+//   - hoist external definitions into `components` and rewrite refs locally,
+//   - deduplicate structurally identical schemas.
+type Bundler interface {
+	Bundle(ctx context.Context, rootPath string) (OpenAPIDoc, error)
+}
+
+// Writer is the output port that emits an OpenAPIDoc in the requested format.
+type Writer interface {
+	Write(w io.Writer, doc OpenAPIDoc, format Format) error
+}
@@ -0,0 +1,67 @@
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// CycleStrategy tells a Dereferencer what to do with recursive references.
+type CycleStrategy string
+
+const (
+	// CYCLE_ERROR fails with a CIRCULAR_REF error on the first cycle.
+	CYCLE_ERROR CycleStrategy = "error"
+	// CYCLE_KEEP_REF leaves the recursive $ref in place (its target is kept in components).
+	CYCLE_KEEP_REF CycleStrategy = "keep-ref"
+	// CYCLE_TRUNCATE replaces the recursive $ref with an empty schema marked
+	// with the x-contractcheck-truncated-ref extension.
+	CYCLE_TRUNCATE CycleStrategy = "truncate"
+)
+
+// DEFAULT_MAX_NODES bounds dereferenced output when DerefOptions.MaxNodes is
+// zero, so a small spec with fan-out refs cannot exhaust memory.
+const DEFAULT_MAX_NODES = 1_000_000
+
+// ParseCycleStrategy maps user input to a CycleStrategy.
+func ParseCycleStrategy(s string) (CycleStrategy, error) {
+	switch c := CycleStrategy(strings.ToLower(strings.TrimSpace(s))); c {
+	case CYCLE_ERROR, CYCLE_KEEP_REF, CYCLE_TRUNCATE:
+		return c, nil
+	default:
+		return "", fmt.Errorf("unsupported cycle strategy %q (expected error, keep-ref or truncate)", s)
+	}
+}
+
+// DerefOptions configures dereferencing.
+// - Cycles: strategy for recursive refs (default CYCLE_ERROR).
+// - MaxNodes: abort with EXPANSION_LIMIT past this many output nodes (0 = DEFAULT_MAX_NODES, negative = unlimited).
+type DerefOptions struct {
+	Cycles   CycleStrategy
+	MaxNodes int
+}
+
+// DerefStats reports the structural impact of inlining, so callers can spot
+// explosion before exporting. A "node" is any JSON object, array or scalar.
+type DerefStats struct {
+	InputNodes  int `json:"inputNodes"`
+	OutputNodes int `json:"outputNodes"`
+	InputDepth  int `json:"inputDepth"`
+	OutputDepth int `json:"outputDepth"`
+	InlinedRefs int `json:"inlinedRefs"`
+	Cycles      int `json:"cycles"`
+}
+
+// Growth is the output/input node ratio (1.0 means no growth).
+func (s DerefStats) Growth() float64 {
+	if s.InputNodes == 0 {
+		return 0
+	}
+	return float64(s.OutputNodes) / float64(s.InputNodes)
+}
+
+// Dereferencer is the output port that inlines local $ref of a document and
+// returns a new, ref-free (except for kept cycles) OpenAPIDoc.
+type Dereferencer interface {
+	Dereference(ctx context.Context, doc OpenAPIDoc, opts DerefOptions) (OpenAPIDoc, DerefStats, error)
+}
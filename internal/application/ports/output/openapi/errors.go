@@ -12,6 +12,35 @@ const (
 	EXTERNAL_REF_NOT_ALLOWED ErrorKind = "external_ref_not_allowed"
 	INVALID_SPEC             ErrorKind = "invalid_spec"
 	INVALID_VERSION_FORMAT   ErrorKind = "invalid_version_format"
+	REF_OUTSIDE_SANDBOX      ErrorKind = "ref_outside_sandbox"
+	UNRESOLVED_REF           ErrorKind = "unresolved_ref"
+	CIRCULAR_REF             ErrorKind = "circular_ref"
+	EXPANSION_LIMIT          ErrorKind = "expansion_limit"
+
+	// Lint findings: the spec is valid but hampers clients or tooling.
+	OPERATION_ID_MISSING      ErrorKind = "operation_id_missing"
+	SUCCESS_RESPONSE_MISSING  ErrorKind = "success_response_missing"
+	OPERATION_SUMMARY_MISSING ErrorKind = "operation_summary_missing"
+
+	// Revision sources (git).
+	GIT_UNAVAILABLE      ErrorKind = "git_unavailable"
+	NOT_A_REPOSITORY     ErrorKind = "not_a_repository"
+	UNKNOWN_REVISION     ErrorKind = "unknown_revision"
+	FILE_NOT_IN_REVISION ErrorKind = "file_not_in_revision"
+
+	// Consumer contracts (Pact files).
+	INVALID_PACT ErrorKind = "invalid_pact"
+
+	// Recorded traffic (HAR files).
+	INVALID_HAR ErrorKind = "invalid_har"
+
+	// Security audit. These classify findings about a valid spec, not
+	// failures to load one.
+	SECURITY_MISSING             ErrorKind = "security_missing"
+	SECURITY_INCONSISTENT_SCOPES ErrorKind = "security_inconsistent_scopes"
+	SECURITY_API_KEY_IN_QUERY    ErrorKind = "security_api_key_in_query"
+	SECURITY_BASIC_WITHOUT_TLS   ErrorKind = "security_basic_without_tls"
+	SECURITY_OAUTH_URL_MISSING   ErrorKind = "security_oauth_url_missing"
 )
 
 // NewValidationError wraps a technical cause and returns a standardized validation error.
@@ -0,0 +1,25 @@
+package openapi
+
+import "context"
+
+// FormatOptions configures canonical formatting.
+// - Format: output format; empty keeps the source format (by file extension).
+// - StripComments: drop YAML comments instead of carrying them along (JSON output never has comments).
+type FormatOptions struct {
+	Format        Format
+	StripComments bool
+}
+
+// FormatResult is the canonical rendering of a spec file.
+// Canonical reports whether the source bytes already equal Formatted.
+type FormatResult struct {
+	Formatted []byte
+	Format    Format
+	Canonical bool
+}
+
+// Formatter is the output port that rewrites a spec file in canonical order:
+// OpenAPI-conventional key order, sorted paths and sorted components.
+type Formatter interface {
+	Format(ctx context.Context, filePath string, opts FormatOptions) (FormatResult, error)
+}
@@ -9,9 +9,11 @@ import (
 // OpenAPIDoc is the normalized representation returned by loaders/adapters.
 // - JSON: canonical UTF-8 JSON of the OpenAPI document (source may be YAML/JSON).
 // - Version: the declared semantic version from the `openapi` field (e.g., "3.0.3").
+// - Model: the typed view of the same document; nil when the producer does not build one.
 type OpenAPIDoc struct {
 	JSON    []byte
 	Version OpenAPIVersion
+	Model   *Document `json:"-"`
 }
 
 // OpenAPIVersion is a thin wrapper to provide safe helpers over the `openapi` field.
@@ -95,3 +97,10 @@ func (v OpenAPIVersion) IsValid() bool {
 type Loader interface {
 	Load(ctx context.Context, filePath string) (OpenAPIDoc, error)
 }
+
+// Parser is the output port for validating a document held in memory, such
+// as one generated rather than read from disk. name identifies it in errors.
+// Implementations apply the same validation as their Loader.
+type Parser interface {
+	Parse(ctx context.Context, data []byte, name string) (OpenAPIDoc, error)
+}
@@ -0,0 +1,296 @@
+package openapi
+
+import (
+	"sort"
+	"strings"
+)
+
+// Document is a library-independent, typed view of an OpenAPI document.
+// Adapters build it from their parser of choice so services can walk
+// operations and schemas without re-parsing OpenAPIDoc.JSON.
+//
+// Schemas are shared by pointer: every use of a `$ref` points at the same
+// *Schema as the component it names, so recursive specs form cyclic graphs.
+// Walkers must track visited nodes, and the model must not be marshalled
+// as-is (use OpenAPIDoc.JSON for serialization).
+type Document struct {
+	Version         OpenAPIVersion
+	Info            Info
+	Servers         []Server
+	Security        []SecurityRequirement
+	Operations      []Operation // sorted by Path, then Method
+	Schemas         map[string]*Schema
+	SecuritySchemes map[string]SecurityScheme
+	Extensions      map[string]any
+
+	// Sources lists the absolute paths of the local files read to build the
+	// document, root first. Watchers and caches use it to track dependencies.
+	Sources []string
+}
+
+// Info mirrors the `info` object.
+type Info struct {
+	Title       string
+	Version     APIVersion
+	Description string
+}
+
+// Server is an entry of a `servers` list.
+type Server struct {
+	URL         string
+	Description string
+	Variables   map[string]ServerVariable
+}
+
+// ServerVariable is a substitution variable of a server URL template.
+type ServerVariable struct {
+	Default     string
+	Enum        []string
+	Description string
+}
+
+// Operation is one HTTP method on one path. Path-level parameters and
+// servers are already merged in; operation-level parameters win on conflict.
+type Operation struct {
+	Path        string
+	Method      string // upper-case, e.g. "GET"
+	ID          string
+	Summary     string
+	Description string
+	Tags        []string
+	Deprecated  bool
+	Parameters  []Parameter
+	RequestBody *RequestBody
+	Responses   []Response // sorted by Status
+	// Security is nil when the operation inherits the document-level
+	// requirements, and empty (non-nil) when it explicitly opts out.
+	Security   []SecurityRequirement
+	Servers    []Server
+	Extensions map[string]any
+}
+
+// Key identifies the operation as "METHOD /path".
+func (op Operation) Key() string { return op.Method + " " + op.Path }
+
+// EffectiveSecurity resolves inheritance against the document-level requirements.
+func (op Operation) EffectiveSecurity(doc *Document) []SecurityRequirement {
+	if op.Security != nil || doc == nil {
+		return op.Security
+	}
+	return doc.Security
+}
+
+// Response returns the response declared for status (e.g. "200", "4XX",
+// "default"), or nil.
+func (op Operation) Response(status string) *Response {
+	for i := range op.Responses {
+		if op.Responses[i].Status == status {
+			return &op.Responses[i]
+		}
+	}
+	return nil
+}
+
+// Parameter locations.
+const (
+	PARAM_IN_PATH   = "path"
+	PARAM_IN_QUERY  = "query"
+	PARAM_IN_HEADER = "header"
+	PARAM_IN_COOKIE = "cookie"
+)
+
+// Parameter is an operation parameter.
+// Pointer is the JSON pointer of the declaration it was read from: the
+// operation's parameter list, or the path item's for inherited parameters.
+type Parameter struct {
+	Name        string
+	In          string
+	Description string
+	Required    bool
+	Deprecated  bool
+	Style       string
+	Explode     *bool
+	Schema      *Schema
+	Extensions  map[string]any
+	Pointer     string
+}
+
+// RequestBody is an operation request body.
+type RequestBody struct {
+	Description string
+	Required    bool
+	Content     map[string]MediaType // keyed by media type, e.g. "application/json"
+}
+
+// MediaType is one entry of a `content` map.
+type MediaType struct {
+	Schema  *Schema
+	Example any
+}
+
+// Response is one entry of an operation's `responses` map.
+type Response struct {
+	Status      string
+	Description string
+	Headers     map[string]Header
+	Content     map[string]MediaType
+}
+
+// Header is a response header.
+type Header struct {
+	Description string
+	Required    bool
+	Deprecated  bool
+	Schema      *Schema
+}
+
+// Schema is a JSON Schema node as used by OpenAPI 3.x.
+type Schema struct {
+	// Ref is the original `$ref` (e.g. "#/components/schemas/Pet") when this
+	// node was reached through one; Name is the component name, if any.
+	Ref  string
+	Name string
+
+	Type        []string
+	Format      string
+	Title       string
+	Description string
+	Nullable    bool
+	Enum        []any
+	Default     any
+	Example     any
+	Deprecated  bool
+	ReadOnly    bool
+	WriteOnly   bool
+
+	// Numbers.
+	Minimum          *float64
+	Maximum          *float64
+	ExclusiveMinimum bool
+	ExclusiveMaximum bool
+	MultipleOf       *float64
+
+	// Strings.
+	MinLength uint64
+	MaxLength *uint64
+	Pattern   string
+
+	// Arrays.
+	Items       *Schema
+	MinItems    uint64
+	MaxItems    *uint64
+	UniqueItems bool
+
+	// Objects. AdditionalProperties is set when it is a schema;
+	// AdditionalPropertiesAllowed is set when it is a boolean.
+	Properties                  map[string]*Schema
+	Required                    []string
+	MinProperties               uint64
+	MaxProperties               *uint64
+	AdditionalProperties        *Schema
+	AdditionalPropertiesAllowed *bool
+
+	// Composition.
+	AllOf []*Schema
+	OneOf []*Schema
+	AnyOf []*Schema
+	Not   *Schema
+
+	Discriminator *Discriminator
+	Extensions    map[string]any
+}
+
+// Discriminator mirrors the `discriminator` object.
+type Discriminator struct {
+	PropertyName string
+	Mapping      map[string]string
+}
+
+// HasType reports whether t is one of the declared types.
+func (s *Schema) HasType(t string) bool {
+	if s == nil {
+		return false
+	}
+	for _, v := range s.Type {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRequired reports whether property name is listed in `required`.
+func (s *Schema) IsRequired(name string) bool {
+	if s == nil {
+		return false
+	}
+	for _, r := range s.Required {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+// PropertyNames returns the property names in sorted order.
+func (s *Schema) PropertyNames() []string {
+	if s == nil {
+		return nil
+	}
+	names := make([]string, 0, len(s.Properties))
+	for n := range s.Properties {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SecurityScheme mirrors `components.securitySchemes` entries.
+type SecurityScheme struct {
+	Type             string // apiKey, http, oauth2, openIdConnect, mutualTLS
+	Description      string
+	Name             string // apiKey
+	In               string // apiKey
+	Scheme           string // http, e.g. "bearer"
+	BearerFormat     string
+	OpenIDConnectURL string
+	Flows            map[string]OAuthFlow // keyed by flow name, e.g. "authorizationCode"
+}
+
+// OAuthFlow is one OAuth2 flow.
+type OAuthFlow struct {
+	AuthorizationURL string
+	TokenURL         string
+	RefreshURL       string
+	Scopes           map[string]string
+}
+
+// SecurityRequirement maps scheme names to required scopes. All entries of
+// one requirement apply together; alternatives are separate requirements.
+type SecurityRequirement map[string][]string
+
+// Operation returns the operation for method and path, or nil.
+// method is matched case-insensitively.
+func (d *Document) Operation(method, path string) *Operation {
+	if d == nil {
+		return nil
+	}
+	method = strings.ToUpper(method)
+	for i := range d.Operations {
+		if d.Operations[i].Method == method && d.Operations[i].Path == path {
+			return &d.Operations[i]
+		}
+	}
+	return nil
+}
+
+// SortOperations orders ops by Path, then Method, which is the order
+// adapters must use when building a Document.
+func SortOperations(ops []Operation) {
+	sort.Slice(ops, func(i, j int) bool {
+		if ops[i].Path != ops[j].Path {
+			return ops[i].Path < ops[j].Path
+		}
+		return ops[i].Method < ops[j].Method
+	})
+}
@@ -0,0 +1,234 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// schemaDefsPrefix is how bundled schemas refer to shared definitions.
+const schemaDefsPrefix = "#/defs/"
+
+// schemaBundle is the JSON layout of EncodeSchemas: named schemas are stored
+// once under defs and referenced as {"$ref": "#/defs/Name"}, so recursive
+// schemas stay finite.
+type schemaBundle struct {
+	Defs    map[string]*schemaJSON `json:"defs,omitempty"`
+	Schemas map[string]*schemaJSON `json:"schemas"`
+}
+
+// schemaJSON holds the validation keywords of a Schema; annotations
+// (titles, descriptions, examples) are dropped.
+type schemaJSON struct {
+	Ref                  string                 `json:"$ref,omitempty"`
+	Type                 []string               `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Nullable             bool                   `json:"nullable,omitempty"`
+	Enum                 []any                  `json:"enum,omitempty"`
+	ReadOnly             bool                   `json:"readOnly,omitempty"`
+	WriteOnly            bool                   `json:"writeOnly,omitempty"`
+	Minimum              *float64               `json:"minimum,omitempty"`
+	Maximum              *float64               `json:"maximum,omitempty"`
+	ExclusiveMinimum     bool                   `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     bool                   `json:"exclusiveMaximum,omitempty"`
+	MultipleOf           *float64               `json:"multipleOf,omitempty"`
+	MinLength            uint64                 `json:"minLength,omitempty"`
+	MaxLength            *uint64                `json:"maxLength,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Items                *schemaJSON            `json:"items,omitempty"`
+	MinItems             uint64                 `json:"minItems,omitempty"`
+	MaxItems             *uint64                `json:"maxItems,omitempty"`
+	UniqueItems          bool                   `json:"uniqueItems,omitempty"`
+	Properties           map[string]*schemaJSON `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	MinProperties        uint64                 `json:"minProperties,omitempty"`
+	MaxProperties        *uint64                `json:"maxProperties,omitempty"`
+	AdditionalProperties json.RawMessage        `json:"additionalProperties,omitempty"` // a schema or a boolean
+	AllOf                []*schemaJSON          `json:"allOf,omitempty"`
+	OneOf                []*schemaJSON          `json:"oneOf,omitempty"`
+	AnyOf                []*schemaJSON          `json:"anyOf,omitempty"`
+	Not                  *schemaJSON            `json:"not,omitempty"`
+}
+
+// EncodeSchemas renders schemas, keyed by caller-chosen names, as one
+// compact JSON document that DecodeSchemas reads back with the same
+// validation behavior. The output is deterministic.
+func EncodeSchemas(schemas map[string]*Schema) ([]byte, error) {
+	e := schemaEncoder{names: map[*Schema]string{}, taken: map[string]bool{}, bundle: schemaBundle{
+		Defs:    map[string]*schemaJSON{},
+		Schemas: map[string]*schemaJSON{},
+	}}
+	for key, s := range schemas {
+		e.bundle.Schemas[key] = e.encode(s, true)
+	}
+	return json.Marshal(e.bundle)
+}
+
+type schemaEncoder struct {
+	names  map[*Schema]string // named schemas already in defs
+	taken  map[string]bool
+	bundle schemaBundle
+}
+
+// encode converts s; top is false for nested schemas, which are stored in
+// defs when they have a component name.
+func (e *schemaEncoder) encode(s *Schema, top bool) *schemaJSON {
+	if s == nil {
+		return &schemaJSON{}
+	}
+	if s.Name != "" && !top {
+		name, ok := e.names[s]
+		if !ok {
+			name = e.defName(s.Name)
+			e.names[s] = name
+			e.bundle.Defs[name] = nil // reserve before recursing: s may contain itself
+			e.bundle.Defs[name] = e.encode(s, true)
+		}
+		return &schemaJSON{Ref: schemaDefsPrefix + name}
+	}
+
+	out := &schemaJSON{
+		Type: s.Type, Format: s.Format, Nullable: s.Nullable, Enum: s.Enum,
+		ReadOnly: s.ReadOnly, WriteOnly: s.WriteOnly,
+		Minimum: s.Minimum, Maximum: s.Maximum, ExclusiveMinimum: s.ExclusiveMinimum, ExclusiveMaximum: s.ExclusiveMaximum,
+		MultipleOf: s.MultipleOf, MinLength: s.MinLength, MaxLength: s.MaxLength, Pattern: s.Pattern,
+		MinItems: s.MinItems, MaxItems: s.MaxItems, UniqueItems: s.UniqueItems,
+		Required: s.Required, MinProperties: s.MinProperties, MaxProperties: s.MaxProperties,
+	}
+	if s.Items != nil {
+		out.Items = e.encode(s.Items, false)
+	}
+	if len(s.Properties) > 0 {
+		out.Properties = make(map[string]*schemaJSON, len(s.Properties))
+		for name, p := range s.Properties {
+			out.Properties[name] = e.encode(p, false)
+		}
+	}
+	switch {
+	case s.AdditionalProperties != nil:
+		out.AdditionalProperties, _ = json.Marshal(e.encode(s.AdditionalProperties, false))
+	case s.AdditionalPropertiesAllowed != nil:
+		out.AdditionalProperties = json.RawMessage(strconv.FormatBool(*s.AdditionalPropertiesAllowed))
+	}
+	for _, group := range []struct {
+		from []*Schema
+		to   *[]*schemaJSON
+	}{{s.AllOf, &out.AllOf}, {s.OneOf, &out.OneOf}, {s.AnyOf, &out.AnyOf}} {
+		for _, sub := range group.from {
+			*group.to = append(*group.to, e.encode(sub, false))
+		}
+	}
+	if s.Not != nil {
+		out.Not = e.encode(s.Not, false)
+	}
+	return out
+}
+
+// defName returns name, or name_2, name_3... when distinct schemas share a
+// component name (e.g. from different files).
+func (e *schemaEncoder) defName(name string) string {
+	candidate := name
+	for i := 2; e.taken[candidate]; i++ {
+		candidate = fmt.Sprintf("%s_%d", name, i)
+	}
+	e.taken[candidate] = true
+	return candidate
+}
+
+// DecodeSchemas parses the output of EncodeSchemas.
+func DecodeSchemas(raw []byte) (map[string]*Schema, error) {
+	var b schemaBundle
+	if err := json.Unmarshal(raw, &b); err != nil {
+		return nil, err
+	}
+	d := schemaDecoder{defs: b.Defs, resolved: map[string]*Schema{}}
+	out := make(map[string]*Schema, len(b.Schemas))
+	for key, s := range b.Schemas {
+		decoded, err := d.decode(s)
+		if err != nil {
+			return nil, fmt.Errorf("schema %q: %w", key, err)
+		}
+		out[key] = decoded
+	}
+	return out, nil
+}
+
+type schemaDecoder struct {
+	defs     map[string]*schemaJSON
+	resolved map[string]*Schema
+}
+
+func (d *schemaDecoder) decode(in *schemaJSON) (*Schema, error) {
+	if in == nil {
+		return nil, nil
+	}
+	if in.Ref != "" {
+		name, ok := strings.CutPrefix(in.Ref, schemaDefsPrefix)
+		def, found := d.defs[name]
+		if !ok || !found {
+			return nil, fmt.Errorf("unresolved $ref %q", in.Ref)
+		}
+		if s, ok := d.resolved[name]; ok {
+			return s, nil
+		}
+		s := &Schema{Name: name}
+		d.resolved[name] = s // register before filling: the definition may refer to itself
+		if err := d.fill(s, def); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+	s := &Schema{}
+	return s, d.fill(s, in)
+}
+
+func (d *schemaDecoder) fill(s *Schema, in *schemaJSON) error {
+	s.Type, s.Format, s.Nullable, s.Enum = in.Type, in.Format, in.Nullable, in.Enum
+	s.ReadOnly, s.WriteOnly = in.ReadOnly, in.WriteOnly
+	s.Minimum, s.Maximum, s.ExclusiveMinimum, s.ExclusiveMaximum = in.Minimum, in.Maximum, in.ExclusiveMinimum, in.ExclusiveMaximum
+	s.MultipleOf, s.MinLength, s.MaxLength, s.Pattern = in.MultipleOf, in.MinLength, in.MaxLength, in.Pattern
+	s.MinItems, s.MaxItems, s.UniqueItems = in.MinItems, in.MaxItems, in.UniqueItems
+	s.Required, s.MinProperties, s.MaxProperties = in.Required, in.MinProperties, in.MaxProperties
+
+	var err error
+	if s.Items, err = d.decode(in.Items); err != nil {
+		return err
+	}
+	if len(in.Properties) > 0 {
+		s.Properties = make(map[string]*Schema, len(in.Properties))
+		for name, p := range in.Properties {
+			if s.Properties[name], err = d.decode(p); err != nil {
+				return err
+			}
+		}
+	}
+	if len(in.AdditionalProperties) > 0 {
+		var allowed bool
+		if json.Unmarshal(in.AdditionalProperties, &allowed) == nil {
+			s.AdditionalPropertiesAllowed = &allowed
+		} else {
+			var sub schemaJSON
+			if err := json.Unmarshal(in.AdditionalProperties, &sub); err != nil {
+				return fmt.Errorf("additionalProperties: %w", err)
+			}
+			if s.AdditionalProperties, err = d.decode(&sub); err != nil {
+				return err
+			}
+		}
+	}
+	for _, group := range []struct {
+		from []*schemaJSON
+		to   *[]*Schema
+	}{{in.AllOf, &s.AllOf}, {in.OneOf, &s.OneOf}, {in.AnyOf, &s.AnyOf}} {
+		for _, sub := range group.from {
+			decoded, err := d.decode(sub)
+			if err != nil {
+				return err
+			}
+			*group.to = append(*group.to, decoded)
+		}
+	}
+	s.Not, err = d.decode(in.Not)
+	return err
+}
@@ -0,0 +1,63 @@
+package openapi_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+)
+
+func TestEncodeSchemas_RoundTrip(t *testing.T) {
+	no := false
+	node := &openapi.Schema{Name: "Node", Type: []string{"object"}, Required: []string{"id"}}
+	node.Properties = map[string]*openapi.Schema{
+		"id":       {Type: []string{"integer"}, Minimum: float(1)},
+		"children": {Type: []string{"array"}, MaxItems: size(2), Items: node},
+	}
+	node.AdditionalPropertiesAllowed = &no
+	tags := &openapi.Schema{
+		Type:                 []string{"object"},
+		AdditionalProperties: &openapi.Schema{Type: []string{"string"}, Enum: []any{"a", "b"}},
+	}
+	// A distinct schema with the same component name, e.g. from another file.
+	other := &openapi.Schema{Name: "Node", Type: []string{"string"}}
+	choice := &openapi.Schema{OneOf: []*openapi.Schema{node, other}}
+
+	in := map[string]*openapi.Schema{"tree": node, "tags": tags, "choice": choice}
+	raw, err := openapi.EncodeSchemas(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := openapi.EncodeSchemas(in)
+	if !bytes.Equal(raw, again) {
+		t.Error("encoding is not deterministic")
+	}
+	out, err := openapi.DecodeSchemas(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		key, value string
+	}{
+		{"tree", `{"id":1,"children":[{"id":2,"children":[{"id":3}]}]}`},
+		{"tree", `{"id":0,"children":[{"children":[]},{"id":2},{"id":3}],"extra":true}`},
+		{"tags", `{"x":"a","y":"c"}`},
+		{"choice", `"leaf"`},
+		{"choice", `{"id":4}`},
+		{"choice", `4`},
+	}
+	for _, c := range cases {
+		v := decode(t, c.value)
+		want := violations(in[c.key].Validate(v, openapi.SCHEMA_RESPONSE))
+		if got := violations(out[c.key].Validate(v, openapi.SCHEMA_RESPONSE)); got != want {
+			t.Errorf("%s %s: decoded schema reports %q, want %q", c.key, c.value, got, want)
+		}
+	}
+}
+
+func TestDecodeSchemas_UnresolvedRef(t *testing.T) {
+	if _, err := openapi.DecodeSchemas([]byte(`{"schemas":{"a":{"$ref":"#/defs/Missing"}}}`)); err == nil {
+		t.Error("want an error for an unresolved $ref")
+	}
+}
@@ -0,0 +1,308 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/mail"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// SchemaDirection tells Validate which side of an exchange a value is on:
+// readOnly properties are not required in requests, writeOnly ones not in
+// responses.
+type SchemaDirection int
+
+const (
+	SCHEMA_REQUEST SchemaDirection = iota
+	SCHEMA_RESPONSE
+)
+
+// SchemaViolation is one way a value does not conform to a schema.
+// Pointer is the JSON pointer to the offending value ("" for the root).
+type SchemaViolation struct {
+	Pointer string
+	Message string
+}
+
+// String renders "pointer: message", or the message alone at the root.
+func (v SchemaViolation) String() string {
+	if v.Pointer == "" {
+		return v.Message
+	}
+	return v.Pointer + ": " + v.Message
+}
+
+// maxValidationDepth bounds recursion through composed schemas that never
+// consume the value (e.g. an allOf cycle).
+const maxValidationDepth = 64
+
+// Validate checks a decoded JSON value (nil, bool, float64, string, []any,
+// map[string]any) against s. A nil schema accepts anything. Formats other
+// than date, date-time, email, uuid and int32/int64 ranges are not checked,
+// and patterns RE2 cannot compile are skipped.
+func (s *Schema) Validate(v any, dir SchemaDirection) []SchemaViolation {
+	var out []SchemaViolation
+	validateValue(s, v, dir, "", 0, &out)
+	return out
+}
+
+func validateValue(s *Schema, v any, dir SchemaDirection, ptr string, depth int, out *[]SchemaViolation) {
+	if s == nil || depth > maxValidationDepth {
+		return
+	}
+	fail := func(format string, args ...any) {
+		*out = append(*out, SchemaViolation{Pointer: ptr, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, sub := range s.AllOf {
+		validateValue(sub, v, dir, ptr, depth+1, out)
+	}
+	if len(s.AnyOf) > 0 && matching(s.AnyOf, v, dir, depth) == 0 {
+		fail("does not match any of the anyOf schemas")
+	}
+	if len(s.OneOf) > 0 {
+		if n := matching(s.OneOf, v, dir, depth); n != 1 {
+			fail("matches %d of the oneOf schemas, want exactly 1", n)
+		}
+	}
+	if s.Not != nil && len(s.Not.Validate(v, dir)) == 0 {
+		fail("matches a schema it must not match")
+	}
+
+	if v == nil {
+		if len(s.Type) > 0 && !s.Nullable && !s.HasType("null") {
+			fail("is null, want %s", strings.Join(s.Type, " or "))
+		}
+		return
+	}
+	kind := jsonKind(v)
+	if len(s.Type) > 0 && !typeAllows(s.Type, kind) {
+		fail("is %s, want %s", kind, strings.Join(s.Type, " or "))
+		return
+	}
+	if len(s.Enum) > 0 && !inEnum(s.Enum, v) {
+		fail("%s is not one of the allowed values", shortJSON(v))
+	}
+
+	switch v := v.(type) {
+	case string:
+		validateString(s, v, fail)
+	case float64:
+		validateNumber(s, v, fail)
+	case []any:
+		if s.MaxItems != nil && uint64(len(v)) > *s.MaxItems {
+			fail("has %d items, want at most %d", len(v), *s.MaxItems)
+		}
+		if uint64(len(v)) < s.MinItems {
+			fail("has %d items, want at least %d", len(v), s.MinItems)
+		}
+		if s.UniqueItems {
+			seen := map[string]bool{}
+			for _, item := range v {
+				k := canonicalJSON(item)
+				if seen[k] {
+					fail("has duplicate item %s", shortJSON(item))
+					break
+				}
+				seen[k] = true
+			}
+		}
+		for i, item := range v {
+			validateValue(s.Items, item, dir, ptr+"/"+strconv.Itoa(i), depth+1, out)
+		}
+	case map[string]any:
+		validateObject(s, v, dir, ptr, depth, out, fail)
+	}
+}
+
+func validateString(s *Schema, v string, fail func(string, ...any)) {
+	n := uint64(utf8.RuneCountInString(v))
+	if s.MaxLength != nil && n > *s.MaxLength {
+		fail("is %d characters long, want at most %d", n, *s.MaxLength)
+	}
+	if n < s.MinLength {
+		fail("is %d characters long, want at least %d", n, s.MinLength)
+	}
+	if s.Pattern != "" {
+		if re, err := regexp.Compile(s.Pattern); err == nil && !re.MatchString(v) {
+			fail("%q does not match pattern %s", v, s.Pattern)
+		}
+	}
+	if !formatValid(s.Format, v) {
+		fail("%q is not a valid %s", v, s.Format)
+	}
+}
+
+func validateNumber(s *Schema, v float64, fail func(string, ...any)) {
+	switch {
+	case s.Maximum != nil && s.ExclusiveMaximum && v >= *s.Maximum:
+		fail("%v is not below %v", v, *s.Maximum)
+	case s.Maximum != nil && v > *s.Maximum:
+		fail("%v is above the maximum %v", v, *s.Maximum)
+	}
+	switch {
+	case s.Minimum != nil && s.ExclusiveMinimum && v <= *s.Minimum:
+		fail("%v is not above %v", v, *s.Minimum)
+	case s.Minimum != nil && v < *s.Minimum:
+		fail("%v is below the minimum %v", v, *s.Minimum)
+	}
+	if s.MultipleOf != nil && *s.MultipleOf > 0 {
+		if q := v / *s.MultipleOf; math.Abs(q-math.Round(q)) > 1e-9 {
+			fail("%v is not a multiple of %v", v, *s.MultipleOf)
+		}
+	}
+	switch s.Format {
+	case "int32":
+		if v < math.MinInt32 || v > math.MaxInt32 {
+			fail("%v does not fit in int32", v)
+		}
+	case "int64":
+		if v < math.MinInt64 || v > math.MaxInt64 {
+			fail("%v does not fit in int64", v)
+		}
+	}
+}
+
+func validateObject(s *Schema, v map[string]any, dir SchemaDirection, ptr string, depth int, out *[]SchemaViolation, fail func(string, ...any)) {
+	for _, name := range s.Required {
+		if _, ok := v[name]; ok {
+			continue
+		}
+		if p := s.Properties[name]; p != nil && ((dir == SCHEMA_REQUEST && p.ReadOnly) || (dir == SCHEMA_RESPONSE && p.WriteOnly)) {
+			continue
+		}
+		fail("is missing required property %q", name)
+	}
+	if s.MaxProperties != nil && uint64(len(v)) > *s.MaxProperties {
+		fail("has %d properties, want at most %d", len(v), *s.MaxProperties)
+	}
+	if uint64(len(v)) < s.MinProperties {
+		fail("has %d properties, want at least %d", len(v), s.MinProperties)
+	}
+	for _, name := range sortedNames(v) {
+		child := ptr + "/" + escapePointer(name)
+		if p, ok := s.Properties[name]; ok {
+			validateValue(p, v[name], dir, child, depth+1, out)
+			continue
+		}
+		switch {
+		case s.AdditionalProperties != nil:
+			validateValue(s.AdditionalProperties, v[name], dir, child, depth+1, out)
+		case s.AdditionalPropertiesAllowed != nil && !*s.AdditionalPropertiesAllowed:
+			fail("has unexpected property %q", name)
+		}
+	}
+}
+
+// matching counts the schemas v conforms to.
+func matching(schemas []*Schema, v any, dir SchemaDirection, depth int) int {
+	n := 0
+	for _, sub := range schemas {
+		var errs []SchemaViolation
+		validateValue(sub, v, dir, "", depth+1, &errs)
+		if len(errs) == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// jsonKind names the JSON type of a decoded value; integral numbers are
+// "integer".
+func jsonKind(v any) string {
+	switch v := v.(type) {
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) && !math.IsInf(v, 0) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func typeAllows(types []string, kind string) bool {
+	for _, t := range types {
+		if t == kind || (t == "number" && kind == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func inEnum(enum []any, v any) bool {
+	k := canonicalJSON(v)
+	for _, e := range enum {
+		if canonicalJSON(e) == k {
+			return true
+		}
+	}
+	return false
+}
+
+// canonicalJSON renders v as compact JSON (object keys sorted), so equal
+// values compare equal.
+func canonicalJSON(v any) string {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(raw)
+}
+
+// shortJSON is canonicalJSON truncated for messages.
+func shortJSON(v any) string {
+	raw := canonicalJSON(v)
+	if len(raw) > 80 {
+		return raw[:77] + "..."
+	}
+	return raw
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func formatValid(format, v string) bool {
+	switch format {
+	case "date":
+		_, err := time.Parse(time.DateOnly, v)
+		return err == nil
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, v)
+		return err == nil
+	case "email":
+		addr, err := mail.ParseAddress(v)
+		return err == nil && addr.Address == v
+	case "uuid":
+		return uuidPattern.MatchString(v)
+	}
+	return true
+}
+
+func sortedNames(m map[string]any) []string {
+	names := make([]string, 0, len(m))
+	for k := range m {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// escapePointer escapes a JSON pointer reference token (RFC 6901).
+func escapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,112 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+)
+
+func float(v float64) *float64 { return &v }
+func size(v uint64) *uint64    { return &v }
+
+func decode(t *testing.T, raw string) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func violations(vs []openapi.SchemaViolation) string {
+	out := make([]string, len(vs))
+	for i, v := range vs {
+		out[i] = v.String()
+	}
+	return strings.Join(out, "; ")
+}
+
+func TestSchema_Validate(t *testing.T) {
+	no := false
+	pet := &openapi.Schema{
+		Type:     []string{"object"},
+		Required: []string{"id", "name", "secret"},
+		Properties: map[string]*openapi.Schema{
+			"id":     {Type: []string{"integer"}, Format: "int32", Minimum: float(1), ReadOnly: true},
+			"name":   {Type: []string{"string"}, MinLength: 1, MaxLength: size(8), Pattern: "^[a-z]+$"},
+			"secret": {Type: []string{"string"}, WriteOnly: true},
+			"tags": {
+				Type:        []string{"array"},
+				MaxItems:    size(2),
+				UniqueItems: true,
+				Items:       &openapi.Schema{Type: []string{"string"}, Enum: []any{"cat", "dog"}},
+			},
+			"born":  {Type: []string{"string"}, Format: "date", Nullable: true},
+			"price": {Type: []string{"number"}, MultipleOf: float(0.5), Maximum: float(100), ExclusiveMaximum: true},
+		},
+		AdditionalPropertiesAllowed: &no,
+	}
+
+	cases := []struct {
+		name  string
+		dir   openapi.SchemaDirection
+		value string
+		want  string // joined violations, "" when valid
+	}{
+		{"valid response", openapi.SCHEMA_RESPONSE, `{"id":1,"name":"rex","born":null,"price":9.5}`, ""},
+		{"readOnly not required in requests", openapi.SCHEMA_REQUEST, `{"name":"rex","secret":"s"}`, ""},
+		{"writeOnly not required in responses", openapi.SCHEMA_RESPONSE, `{"id":1,"name":"rex"}`, ""},
+		{"missing required", openapi.SCHEMA_RESPONSE, `{"id":1}`, `is missing required property "name"`},
+		{"wrong type", openapi.SCHEMA_RESPONSE, `{"id":"1","name":"rex"}`, `/id: is string, want integer`},
+		{"integer bounds", openapi.SCHEMA_RESPONSE, `{"id":0,"name":"rex"}`, `/id: 0 is below the minimum 1`},
+		{"int32 range", openapi.SCHEMA_RESPONSE, `{"id":3000000000,"name":"rex"}`, `/id: 3e+09 does not fit in int32`},
+		{"fraction is not an integer", openapi.SCHEMA_RESPONSE, `{"id":1.5,"name":"rex"}`, `/id: is number, want integer`},
+		{"string constraints", openapi.SCHEMA_RESPONSE, `{"id":1,"name":"Rexanderthegreat"}`, `/name: is 16 characters long, want at most 8; /name: "Rexanderthegreat" does not match pattern ^[a-z]+$`},
+		{"array constraints", openapi.SCHEMA_RESPONSE, `{"id":1,"name":"rex","tags":["cat","cat","fish"]}`, `/tags: has 3 items, want at most 2; /tags: has duplicate item "cat"; /tags/2: "fish" is not one of the allowed values`},
+		{"date format", openapi.SCHEMA_RESPONSE, `{"id":1,"name":"rex","born":"2024-13-01"}`, `/born: "2024-13-01" is not a valid date`},
+		{"number constraints", openapi.SCHEMA_RESPONSE, `{"id":1,"name":"rex","price":100}`, `/price: 100 is not below 100`},
+		{"multipleOf", openapi.SCHEMA_RESPONSE, `{"id":1,"name":"rex","price":0.3}`, `/price: 0.3 is not a multiple of 0.5`},
+		{"additional properties", openapi.SCHEMA_RESPONSE, `{"id":1,"name":"rex","owner":"x"}`, `has unexpected property "owner"`},
+		{"null root", openapi.SCHEMA_RESPONSE, `null`, `is null, want object`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := violations(pet.Validate(decode(t, tc.value), tc.dir)); got != tc.want {
+				t.Errorf("got  %s\nwant %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSchema_ValidateComposition(t *testing.T) {
+	cat := &openapi.Schema{Type: []string{"object"}, Required: []string{"meow"}}
+	dog := &openapi.Schema{Type: []string{"object"}, Required: []string{"bark"}}
+	oneOf := &openapi.Schema{OneOf: []*openapi.Schema{cat, dog}}
+	anyOf := &openapi.Schema{AnyOf: []*openapi.Schema{cat, dog}}
+	allOf := &openapi.Schema{AllOf: []*openapi.Schema{cat, dog}}
+	not := &openapi.Schema{Not: &openapi.Schema{Type: []string{"string"}}}
+
+	cases := []struct {
+		name   string
+		schema *openapi.Schema
+		value  string
+		want   string
+	}{
+		{"oneOf exactly one", oneOf, `{"meow":true}`, ""},
+		{"oneOf both", oneOf, `{"meow":true,"bark":true}`, "matches 2 of the oneOf schemas, want exactly 1"},
+		{"anyOf both", anyOf, `{"meow":true,"bark":true}`, ""},
+		{"anyOf none", anyOf, `{}`, "does not match any of the anyOf schemas"},
+		{"allOf", allOf, `{"meow":true}`, `is missing required property "bark"`},
+		{"not", not, `"x"`, "matches a schema it must not match"},
+		{"nil schema", nil, `{"any":1}`, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := violations(tc.schema.Validate(decode(t, tc.value), openapi.SCHEMA_RESPONSE)); got != tc.want {
+				t.Errorf("got  %s\nwant %s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+// Package pact defines the consumer-driven contract model: what consumers
+// expect from a provider, as recorded in Pact files.
+package pact
+
+import "context"
+
+// Pact is the contract between one consumer and one provider.
+//   - SpecVersion: the Pact specification the file follows, e.g. "3.0.0".
+//   - Skipped: interactions that are not synchronous HTTP (e.g. messages).
+type Pact struct {
+	Consumer     string
+	Provider     string
+	SpecVersion  string
+	Interactions []Interaction
+	Skipped      int
+}
+
+// Interaction is one request the consumer sends and the response it expects.
+type Interaction struct {
+	Description    string
+	ProviderStates []string
+	Request        Request
+	Response       Response
+}
+
+// Request is the request of an interaction. Header names are lower-case.
+type Request struct {
+	Method  string // upper-case
+	Path    string
+	Query   map[string][]string
+	Headers map[string]string
+	Body    *Body
+}
+
+// Response is the expected response. Header names are lower-case.
+type Response struct {
+	Status  int
+	Headers map[string]string
+	Body    *Body
+}
+
+// Body is a request or response body.
+//   - ContentType: the media type without parameters, e.g. "application/json";
+//     empty when the file does not say.
+//   - Content: the decoded JSON value when the body is JSON, the raw text otherwise.
+//   - JSON: Content holds a decoded JSON value.
+type Body struct {
+	ContentType string
+	Content     any
+	JSON        bool
+}
+
+// Reader is the output port for reading Pact files.
+// Implementations should support Pact specification v2 to v4 and fail with
+// an AppError naming the file when it cannot be read.
+type Reader interface {
+	Read(ctx context.Context, path string) (Pact, error)
+}
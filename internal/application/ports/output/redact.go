@@ -0,0 +1,34 @@
+package output
+
+import "strings"
+
+// RedactionKey is the form sensitive metadata keys are compared in: lower
+// case without "-" and "_", so "API_Key" matches "apikey". Log sinks and
+// diagnostics bundles share it so they redact the same keys.
+func RedactionKey(k string) string {
+	return strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(k))
+}
+
+// RedactionKeys returns keys in RedactionKey form, dropping empty ones.
+func RedactionKeys(keys []string) []string {
+	out := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if k = RedactionKey(k); k != "" {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+// IsSensitiveKey reports whether k names a secret: its RedactionKey form
+// contains one of sensitive (given in that form), so "token" also masks
+// "authToken" and "apikey" masks "x-api-key".
+func IsSensitiveKey(k string, sensitive []string) bool {
+	k = RedactionKey(k)
+	for _, s := range sensitive {
+		if strings.Contains(k, s) {
+			return true
+		}
+	}
+	return false
+}
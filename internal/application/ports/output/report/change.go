@@ -0,0 +1,124 @@
+package report
+
+import (
+	"fmt"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+)
+
+// ChangeLevel classifies a contract change by its impact on existing clients.
+type ChangeLevel string
+
+const (
+	// CHANGE_BREAKING may break existing clients (e.g. a removed operation).
+	CHANGE_BREAKING ChangeLevel = "breaking"
+	// CHANGE_NON_BREAKING is backwards compatible (e.g. a new optional field).
+	CHANGE_NON_BREAKING ChangeLevel = "non-breaking"
+)
+
+// Change is one difference between two versions of a contract.
+//   - ID: stable change type, e.g. "operation-removed", "response-property-type-changed".
+//   - Operation: "METHOD /path" when the change is scoped to one operation.
+//   - Location: where in the operation it happened, e.g. "response 200 application/json: /items/name".
+type Change struct {
+	ID        string      `json:"id"`
+	Level     ChangeLevel `json:"level"`
+	Operation string      `json:"operation,omitempty"`
+	Location  string      `json:"location,omitempty"`
+	Message   string      `json:"message"`
+}
+
+// Breaking reports whether the change may break existing clients.
+func (c Change) Breaking() bool { return c.Level == CHANGE_BREAKING }
+
+// String renders a one-line, human-readable form.
+func (c Change) String() string {
+	s := fmt.Sprintf("%s [%s] %s", c.Level, c.ID, c.Message)
+	if c.Operation != "" {
+		s += " (" + c.Operation
+		if c.Location != "" {
+			s += ", " + c.Location
+		}
+		s += ")"
+	}
+	return s
+}
+
+// Finding maps the change onto the findings model: breaking changes are
+// errors, everything else is informational. file names the head spec.
+func (c Change) Finding(file string) Finding {
+	sev := SEVERITY_INFO
+	if c.Breaking() {
+		sev = SEVERITY_ERROR
+	}
+	return Finding{
+		RuleID:    c.ID,
+		Check:     CHECK_COMPARE,
+		Severity:  sev,
+		Message:   c.Message,
+		Detail:    c.Location,
+		File:      file,
+		Operation: c.Operation,
+	}
+}
+
+// Comparison is the outcome of comparing a base contract with a head contract.
+// Base and Head are human-readable labels (paths, or "rev:path" for git sources).
+// Operations lists every operation compared, from either side, sorted.
+type Comparison struct {
+	Base       string   `json:"base"`
+	Head       string   `json:"head"`
+	BaseSpec   SpecInfo `json:"baseSpec"`
+	HeadSpec   SpecInfo `json:"headSpec"`
+	Operations []string `json:"operations"`
+	Changes    []Change `json:"changes"`
+}
+
+// SpecInfo is the metadata of one compared spec, for report headers.
+type SpecInfo struct {
+	Title          string                 `json:"title,omitempty"`
+	Version        string                 `json:"version,omitempty"`
+	OpenAPIVersion openapi.OpenAPIVersion `json:"openapiVersion,omitempty"`
+	Servers        []string               `json:"servers,omitempty"`
+}
+
+// SpecInfoOf summarizes doc's metadata.
+func SpecInfoOf(doc *openapi.Document) SpecInfo {
+	info := SpecInfo{Title: doc.Info.Title, Version: doc.Info.Version.String(), OpenAPIVersion: doc.Version}
+	for _, srv := range doc.Servers {
+		info.Servers = append(info.Servers, srv.URL)
+	}
+	return info
+}
+
+// Breaking returns the breaking changes.
+func (c Comparison) Breaking() []Change {
+	var out []Change
+	for _, ch := range c.Changes {
+		if ch.Breaking() {
+			out = append(out, ch)
+		}
+	}
+	return out
+}
+
+// Findings maps every change onto the findings model (see Change.Finding).
+func (c Comparison) Findings() []Finding {
+	out := make([]Finding, 0, len(c.Changes))
+	for _, ch := range c.Changes {
+		out = append(out, ch.Finding(c.Head))
+	}
+	return out
+}
+
+// Report is the comparison as a report for writers; file names the head spec.
+func (c Comparison) Report(file string) Report {
+	findings := c.Findings()
+	for i := range findings {
+		findings[i].File = file
+	}
+	return Report{
+		Subjects: []Subject{{File: file, Checks: []string{CHECK_COMPARE}, Operations: c.Operations}},
+		Findings: findings,
+	}
+}
@@ -0,0 +1,77 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ChangelogDetail selects how fine-grained changelog entries are.
+type ChangelogDetail string
+
+const (
+	// CHANGELOG_ENDPOINTS lists endpoints added, deprecated or removed, and
+	// one summary entry per otherwise changed endpoint.
+	CHANGELOG_ENDPOINTS ChangelogDetail = "endpoints"
+	// CHANGELOG_FIELDS lists every parameter, body, response and property change.
+	CHANGELOG_FIELDS ChangelogDetail = "fields"
+)
+
+// ParseChangelogDetail validates a user-provided detail level.
+func ParseChangelogDetail(s string) (ChangelogDetail, error) {
+	switch d := ChangelogDetail(strings.ToLower(strings.TrimSpace(s))); d {
+	case CHANGELOG_ENDPOINTS, CHANGELOG_FIELDS:
+		return d, nil
+	}
+	return "", fmt.Errorf("unknown changelog detail %q (want %s or %s)", s, CHANGELOG_ENDPOINTS, CHANGELOG_FIELDS)
+}
+
+// ChangelogCategory is a Keep a Changelog section.
+type ChangelogCategory string
+
+const (
+	CHANGELOG_ADDED      ChangelogCategory = "Added"
+	CHANGELOG_CHANGED    ChangelogCategory = "Changed"
+	CHANGELOG_DEPRECATED ChangelogCategory = "Deprecated"
+	CHANGELOG_REMOVED    ChangelogCategory = "Removed"
+)
+
+// ChangelogCategories lists the sections in Keep a Changelog order.
+var ChangelogCategories = []ChangelogCategory{CHANGELOG_ADDED, CHANGELOG_CHANGED, CHANGELOG_DEPRECATED, CHANGELOG_REMOVED}
+
+// Changelog is one release entry of a Keep a Changelog document.
+//   - Version: the release, usually the head spec's info.version.
+//   - Date: ISO 8601 release date; empty for unreleased changes.
+//   - Sections: only non-empty ones, in ChangelogCategories order.
+type Changelog struct {
+	Title    string             `json:"title,omitempty"`
+	Version  string             `json:"version,omitempty"`
+	Date     string             `json:"date,omitempty"`
+	Base     string             `json:"base"`
+	Head     string             `json:"head"`
+	Detail   ChangelogDetail    `json:"detail"`
+	Breaking int                `json:"breaking"`
+	Sections []ChangelogSection `json:"sections"`
+}
+
+// ChangelogSection groups the entries of one category.
+type ChangelogSection struct {
+	Category ChangelogCategory `json:"category"`
+	Entries  []ChangelogEntry  `json:"entries"`
+}
+
+// ChangelogEntry is one line of the changelog.
+//   - Operation: "METHOD /path" the entry is about, if any.
+//   - Text: what changed, without the operation.
+//   - Changes: the change IDs behind the entry.
+type ChangelogEntry struct {
+	Operation string   `json:"operation,omitempty"`
+	Text      string   `json:"text"`
+	Breaking  bool     `json:"breaking,omitempty"`
+	Changes   []string `json:"changes"`
+}
+
+// ChangelogWriter renders a changelog (Markdown, …).
+type ChangelogWriter interface {
+	WriteChangelog(w io.Writer, c Changelog) error
+}
@@ -0,0 +1,28 @@
+package report
+
+import (
+	"context"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+)
+
+// Names of the built-in checks, as set in Finding.Check.
+const (
+	CHECK_LOAD        = "load"
+	CHECK_COMPARE     = "compare"
+	CHECK_LINT        = "lint"
+	CHECK_DEPRECATION = "deprecation"
+	CHECK_PACT        = "pact"
+	CHECK_RUN         = "run"
+	CHECK_FUZZ        = "fuzz"
+	CHECK_DRIFT       = "drift"
+	CHECK_SECURITY    = "security"
+)
+
+// Check analyses a loaded document. Lint rule sets, diffs against a
+// baseline and similar analyses plug into pipelines (e.g. watch mode)
+// through it. A returned error aborts the check, not the pipeline.
+type Check interface {
+	Name() string
+	Check(ctx context.Context, doc openapi.OpenAPIDoc) ([]Finding, error)
+}
@@ -0,0 +1,132 @@
+package report
+
+import (
+	"io"
+	"math"
+)
+
+// Kinds of uncovered items.
+const (
+	COVERAGE_OPERATION  = "operation"
+	COVERAGE_STATUS     = "status"
+	COVERAGE_PARAMETER  = "parameter"
+	COVERAGE_ENUM_VALUE = "enum-value"
+)
+
+// CoverageCount is how many items of one kind were exercised.
+// Percent is rounded to one decimal; it is 100 when there is nothing to cover.
+type CoverageCount struct {
+	Covered int     `json:"covered"`
+	Total   int     `json:"total"`
+	Percent float64 `json:"percent"`
+}
+
+// NewCoverageCount computes the percentage of covered out of total.
+func NewCoverageCount(covered, total int) CoverageCount {
+	c := CoverageCount{Covered: covered, Total: total, Percent: 100}
+	if total > 0 {
+		c.Percent = math.Round(float64(covered)*1000/float64(total)) / 10
+	}
+	return c
+}
+
+// CoverageTotals counts each kind of item of a group of operations.
+type CoverageTotals struct {
+	Operations CoverageCount `json:"operations"`
+	Statuses   CoverageCount `json:"statuses"`
+	Parameters CoverageCount `json:"parameters"`
+	EnumValues CoverageCount `json:"enumValues"`
+}
+
+// PathCoverage is the coverage of the operations of one path.
+type PathCoverage struct {
+	Path string `json:"path"`
+	CoverageTotals
+}
+
+// MethodCoverage is the coverage of the operations of one HTTP method.
+type MethodCoverage struct {
+	Method string `json:"method"`
+	CoverageTotals
+}
+
+// OperationCoverage tells what the traffic exercised of one operation.
+//   - Calls: exchanges that matched the operation and conform to it.
+//   - Invalid: matched exchanges whose response breaks the contract; they
+//     cover nothing. Issues lists their distinct problems.
+type OperationCoverage struct {
+	Operation  string              `json:"operation"`
+	Path       string              `json:"path"`
+	Method     string              `json:"method"`
+	Calls      int                 `json:"calls"`
+	Invalid    int                 `json:"invalid"`
+	Issues     []string            `json:"issues,omitempty"`
+	Statuses   []StatusCoverage    `json:"statuses"`
+	Parameters []ParameterCoverage `json:"parameters"`
+	Enums      []EnumCoverage      `json:"enums"`
+}
+
+// StatusCoverage counts the calls answered with a declared response
+// ("200", "4XX", "default").
+type StatusCoverage struct {
+	Status string `json:"status"`
+	Calls  int    `json:"calls"`
+}
+
+// ParameterCoverage counts the calls that sent a parameter.
+type ParameterCoverage struct {
+	Name  string `json:"name"`
+	In    string `json:"in"`
+	Calls int    `json:"calls"`
+}
+
+// EnumCoverage counts the calls that used each value of an enum, e.g. at
+// `query parameter "status"` or "response 200 body /status".
+type EnumCoverage struct {
+	Location string              `json:"location"`
+	Values   []EnumValueCoverage `json:"values"`
+}
+
+// EnumValueCoverage counts the calls that used one enum value.
+type EnumValueCoverage struct {
+	Value string `json:"value"`
+	Calls int    `json:"calls"`
+}
+
+// UncoveredItem is something the traffic never exercised. An operation
+// that was never called is listed alone, without its statuses, parameters
+// and enum values.
+type UncoveredItem struct {
+	Operation string `json:"operation"`
+	Kind      string `json:"kind"`
+	Item      string `json:"item,omitempty"`
+}
+
+// UnmatchedRequest counts recorded requests that match no operation.
+type UnmatchedRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Calls  int    `json:"calls"`
+}
+
+// Coverage is how much of a spec recorded traffic exercised.
+//   - Sources: the traffic files, in the order given.
+//   - Exchanges: all recorded exchanges; Unmatched groups those that match
+//     no operation of the spec.
+type Coverage struct {
+	Spec       string              `json:"spec"`
+	Sources    []string            `json:"sources"`
+	Exchanges  int                 `json:"exchanges"`
+	Paths      CoverageCount       `json:"paths"`
+	Totals     CoverageTotals      `json:"totals"`
+	ByPath     []PathCoverage      `json:"byPath"`
+	ByMethod   []MethodCoverage    `json:"byMethod"`
+	Operations []OperationCoverage `json:"operations"`
+	Uncovered  []UncoveredItem     `json:"uncovered"`
+	Unmatched  []UnmatchedRequest  `json:"unmatched"`
+}
+
+// CoverageWriter renders a coverage report, e.g. as an HTML page.
+type CoverageWriter interface {
+	WriteCoverage(w io.Writer, c Coverage) error
+}
@@ -0,0 +1,42 @@
+package report
+
+// DeprecationRecord remembers when an element was first seen deprecated.
+//   - Element: stable key, e.g. "GET /pets/{id}" or
+//     "GET /pets/{id} parameter query limit".
+//   - Since: ISO 8601 date of the first run that saw it deprecated.
+//   - Revision: the spec label of that run (a path, or "rev:path").
+//   - Sunset: announced removal date (ISO 8601), if any.
+type DeprecationRecord struct {
+	Element   string `json:"element"`
+	Operation string `json:"operation"`
+	Since     string `json:"since"`
+	Revision  string `json:"revision,omitempty"`
+	Sunset    string `json:"sunset,omitempty"`
+}
+
+// DeprecationReport is the outcome of a deprecation lifecycle check.
+//   - Date: ISO 8601 date the check ran as.
+//   - GraceDays: the minimum time between deprecation and removal.
+//   - Ledger: the records after this run, sorted by element.
+type DeprecationReport struct {
+	Base       string              `json:"base"`
+	Head       string              `json:"head"`
+	Date       string              `json:"date"`
+	GraceDays  int                 `json:"graceDays"`
+	Operations []string            `json:"operations"`
+	Findings   []Finding           `json:"findings"`
+	Ledger     []DeprecationRecord `json:"ledger"`
+}
+
+// Report is the check as a report for writers; file names the head spec.
+func (d DeprecationReport) Report(file string) Report {
+	findings := make([]Finding, len(d.Findings))
+	for i, f := range d.Findings {
+		f.File = file
+		findings[i] = f
+	}
+	return Report{
+		Subjects: []Subject{{File: file, Checks: []string{CHECK_DEPRECATION}, Operations: d.Operations}},
+		Findings: findings,
+	}
+}
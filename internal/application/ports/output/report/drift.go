@@ -0,0 +1,54 @@
+package report
+
+// Kinds of drift between a spec and recorded traffic.
+const (
+	DRIFT_UNDOCUMENTED_OPERATION = "undocumented-operation"
+	DRIFT_UNDOCUMENTED_STATUS    = "undocumented-status"
+	DRIFT_UNDOCUMENTED_PARAMETER = "undocumented-parameter"
+	DRIFT_UNDOCUMENTED_FIELD     = "undocumented-field"
+	DRIFT_UNSEEN_FIELD           = "unseen-field"
+)
+
+// Drift is one way recorded traffic disagrees with the spec.
+//   - Operation: the operation, or "METHOD /path" of requests that match
+//     none, with IDs in the path templated ("/orders/{orderId}").
+//   - Item: what disagrees, e.g. "418", `query parameter "page"` or
+//     "response 200 body /items/*/nickname"; empty for operations.
+//   - Count: exchanges that show the drift. Of: exchanges that could have,
+//     i.e. the calls of the operation, all exchanges for undocumented
+//     operations, and the responses holding the field's object for unseen
+//     fields (where Count is those responses too).
+type Drift struct {
+	Kind      string `json:"kind"`
+	Operation string `json:"operation"`
+	Item      string `json:"item,omitempty"`
+	Count     int    `json:"count"`
+	Of        int    `json:"of"`
+}
+
+// DriftReport lists where recorded traffic disagrees with a spec, most
+// frequent first. Findings are the same drifts, in the same order, for
+// report writers.
+type DriftReport struct {
+	Spec      string    `json:"spec"`
+	Sources   []string  `json:"sources"`
+	Exchanges int       `json:"exchanges"`
+	Drifts    []Drift   `json:"drifts"`
+	Findings  []Finding `json:"findings"`
+}
+
+// Report is the drift as a report for writers, with the spec as its subject.
+func (r DriftReport) Report() Report {
+	seen := map[string]bool{}
+	var ops []string
+	for _, f := range r.Findings {
+		if f.Operation != "" && !seen[f.Operation] {
+			seen[f.Operation] = true
+			ops = append(ops, f.Operation)
+		}
+	}
+	return Report{
+		Subjects: []Subject{{File: r.Spec, Checks: []string{CHECK_DRIFT}, Operations: ops}},
+		Findings: r.Findings,
+	}
+}
@@ -0,0 +1,163 @@
+// Package report defines the findings model shared by every check
+// (import validation, lint, diff, …) and by the report writers.
+package report
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+)
+
+// Severity ranks findings; writers map it to their own scales.
+type Severity string
+
+const (
+	SEVERITY_ERROR   Severity = "error"
+	SEVERITY_WARNING Severity = "warning"
+	SEVERITY_INFO    Severity = "info"
+)
+
+// Rank orders severities from most (0) to least severe.
+func (s Severity) Rank() int {
+	switch s {
+	case SEVERITY_ERROR:
+		return 0
+	case SEVERITY_WARNING:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Finding is one diagnostic produced by a check.
+//   - RuleID: stable identifier (an ErrorKind, a lint rule, a diff change type).
+//   - Check: name of the check that produced it (e.g. CHECK_LOAD), if known.
+//   - Pointer: JSON pointer into the spec when known (e.g. "/paths/~1pets/get").
+//   - Operation: "METHOD /path" when the finding is about one operation.
+//   - Line/Column: 1-based source position when known, 0 otherwise.
+type Finding struct {
+	RuleID    string   `json:"ruleId"`
+	Check     string   `json:"check,omitempty"`
+	Severity  Severity `json:"severity"`
+	Message   string   `json:"message"`
+	Detail    string   `json:"detail,omitempty"`
+	File      string   `json:"file,omitempty"`
+	Line      int      `json:"line,omitempty"`
+	Column    int      `json:"column,omitempty"`
+	Pointer   string   `json:"pointer,omitempty"`
+	Operation string   `json:"operation,omitempty"`
+}
+
+// Key identifies a finding across runs, so consumers can tell new, unchanged
+// and resolved findings apart. Positions are excluded: they shift on
+// unrelated edits.
+func (f Finding) Key() string {
+	return strings.Join([]string{f.RuleID, f.File, f.Pointer, f.Operation, f.Message, f.Detail}, "\x00")
+}
+
+// String renders a one-line, human-readable form.
+func (f Finding) String() string {
+	var b strings.Builder
+	if f.File != "" {
+		b.WriteString(f.File)
+		if f.Line > 0 {
+			fmt.Fprintf(&b, ":%d", f.Line)
+			if f.Column > 0 {
+				fmt.Fprintf(&b, ":%d", f.Column)
+			}
+		}
+		b.WriteString(": ")
+	}
+	fmt.Fprintf(&b, "%s [%s] %s", f.Severity, f.RuleID, f.Message)
+	if f.Operation != "" {
+		fmt.Fprintf(&b, " (%s)", f.Operation)
+	}
+	if f.Detail != "" {
+		b.WriteString(": " + f.Detail)
+	}
+	return b.String()
+}
+
+// FromError converts a failure to load a spec into an error-severity finding
+// of CHECK_LOAD. AppError details supply the rule ID (its kind), file and
+// position; other errors map to the "error" rule.
+func FromError(err error) Finding {
+	f := Finding{RuleID: "error", Check: CHECK_LOAD, Severity: SEVERITY_ERROR, Message: err.Error()}
+
+	var ae *customerrors.AppError
+	if !errors.As(err, &ae) {
+		return f
+	}
+	f.Message = ae.Message
+	f.RuleID = string(ae.Type)
+	if kind, ok := ae.Details[customerrors.DetailKind]; ok {
+		f.RuleID = fmt.Sprint(kind)
+	} else if _, ok := ae.Details[customerrors.DetailExpected]; ok {
+		f.RuleID = "unsupported_version"
+	}
+	if file, ok := ae.Details[customerrors.DetailFile].(string); ok {
+		f.File = file
+	}
+	f.Line, _ = ae.Details[customerrors.DetailLine].(int)
+	f.Column, _ = ae.Details[customerrors.DetailColumn].(int)
+	if cause := ae.Unwrap(); cause != nil {
+		f.Detail = cause.Error()
+	}
+	return f
+}
+
+// Sort orders findings by file, severity, position, then key, for stable output.
+func Sort(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Severity.Rank() != b.Severity.Rank() {
+			return a.Severity.Rank() < b.Severity.Rank()
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Key() < b.Key()
+	})
+}
+
+// Delta is the difference between two runs of the same checks.
+type Delta struct {
+	Added   []Finding `json:"added"`
+	Removed []Finding `json:"removed"`
+	// Total is the number of findings in the current run.
+	Total int `json:"total"`
+}
+
+// Empty reports whether nothing changed.
+func (d Delta) Empty() bool { return len(d.Added) == 0 && len(d.Removed) == 0 }
+
+// Diff compares two runs by Finding.Key. Findings present in both are
+// omitted so consumers only re-emit what changed.
+func Diff(previous, current []Finding) Delta {
+	prev := make(map[string]bool, len(previous))
+	for _, f := range previous {
+		prev[f.Key()] = true
+	}
+	cur := make(map[string]bool, len(current))
+	d := Delta{Added: []Finding{}, Removed: []Finding{}, Total: len(current)}
+	for _, f := range current {
+		cur[f.Key()] = true
+		if !prev[f.Key()] {
+			d.Added = append(d.Added, f)
+		}
+	}
+	for _, f := range previous {
+		if !cur[f.Key()] {
+			d.Removed = append(d.Removed, f)
+		}
+	}
+	Sort(d.Added)
+	Sort(d.Removed)
+	return d
+}
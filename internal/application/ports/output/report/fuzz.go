@@ -0,0 +1,56 @@
+package report
+
+// Mutation kinds of fuzz cases.
+const (
+	MUTATION_BOUNDARY         = "boundary"
+	MUTATION_WRONG_TYPE       = "wrong-type"
+	MUTATION_MISSING_REQUIRED = "missing-required"
+	MUTATION_OVERSIZED        = "oversized"
+	MUTATION_INVALID_ENUM     = "invalid-enum"
+)
+
+// FuzzFailure is a mutated request the service mishandled, minimized to
+// the smallest request that still fails the same way.
+//   - Target: what was mutated, e.g. `query parameter "limit"` or "request body /name".
+//   - Mutation: one of the MUTATION_* kinds; Description tells the value.
+//   - Body: the request body, shortened when long.
+type FuzzFailure struct {
+	RuleID      string `json:"ruleId"`
+	Operation   string `json:"operation"`
+	Target      string `json:"target"`
+	Mutation    string `json:"mutation"`
+	Description string `json:"description"`
+	Method      string `json:"method"`
+	URL         string `json:"url"`
+	Body        string `json:"body,omitempty"`
+	Status      int    `json:"status,omitempty"`
+}
+
+// FuzzRun is the outcome of sending mutated requests to a live service.
+// Seed reproduces the run: the same seed, spec and options send the same
+// requests.
+type FuzzRun struct {
+	Spec       string        `json:"spec"`
+	BaseURL    string        `json:"baseUrl"`
+	Seed       int64         `json:"seed"`
+	Operations int           `json:"operations"`
+	Cases      int           `json:"cases"`
+	Failures   []FuzzFailure `json:"failures"`
+	Findings   []Finding     `json:"findings"`
+}
+
+// Report is the run as a report for writers, with the spec as its subject.
+func (r FuzzRun) Report() Report {
+	seen := map[string]bool{}
+	var ops []string
+	for _, f := range r.Findings {
+		if f.Operation != "" && !seen[f.Operation] {
+			seen[f.Operation] = true
+			ops = append(ops, f.Operation)
+		}
+	}
+	return Report{
+		Subjects: []Subject{{File: r.Spec, Checks: []string{CHECK_FUZZ}, Operations: ops}},
+		Findings: r.Findings,
+	}
+}
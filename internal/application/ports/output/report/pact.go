@@ -0,0 +1,36 @@
+package report
+
+// PactResult is the verification of one consumer's Pact file.
+//   - Interactions: the synchronous HTTP interactions checked.
+//   - Failing: interactions with at least one error finding.
+//   - Skipped: interactions that are not synchronous HTTP (e.g. messages).
+//   - Operations: the spec operations the interactions exercised, sorted.
+type PactResult struct {
+	File         string    `json:"file"`
+	Consumer     string    `json:"consumer"`
+	Provider     string    `json:"provider"`
+	SpecVersion  string    `json:"specVersion"`
+	Interactions int       `json:"interactions"`
+	Failing      int       `json:"failing"`
+	Skipped      int       `json:"skipped"`
+	Operations   []string  `json:"operations"`
+	Findings     []Finding `json:"findings"`
+}
+
+// PactVerification is the outcome of checking consumer contracts against a
+// provider spec, one result per Pact file.
+type PactVerification struct {
+	Spec  string       `json:"spec"`
+	Pacts []PactResult `json:"pacts"`
+}
+
+// Report is the verification as a report for writers, one subject per Pact
+// file.
+func (v PactVerification) Report() Report {
+	r := Report{}
+	for _, p := range v.Pacts {
+		r.Subjects = append(r.Subjects, Subject{File: p.File, Checks: []string{CHECK_PACT}, Operations: p.Operations})
+		r.Findings = append(r.Findings, p.Findings...)
+	}
+	return r
+}
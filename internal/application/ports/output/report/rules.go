@@ -0,0 +1,33 @@
+package report
+
+import (
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+)
+
+// ErrorRules describes the rule IDs FromError produces: every OpenAPI
+// ErrorKind plus the fallbacks for untyped errors.
+func ErrorRules() []Rule {
+	return []Rule{
+		{ID: string(openapi.FILE_NOT_FOUND), Name: "FileNotFound", Summary: "The spec file, or a file it references, does not exist.", Severity: SEVERITY_ERROR},
+		{ID: string(openapi.PERMISSION_DENIED), Name: "PermissionDenied", Summary: "The spec file cannot be read.", Severity: SEVERITY_ERROR},
+		{ID: string(openapi.INVALID_SYNTAX), Name: "InvalidSyntax", Summary: "The spec is not valid YAML or JSON.", Severity: SEVERITY_ERROR},
+		{ID: string(openapi.EXTERNAL_REF_NOT_ALLOWED), Name: "ExternalRefNotAllowed", Summary: "The spec references another file but external refs are disabled.", Severity: SEVERITY_ERROR},
+		{ID: string(openapi.INVALID_SPEC), Name: "InvalidSpec", Summary: "The document violates the OpenAPI specification.", Severity: SEVERITY_ERROR},
+		{ID: string(openapi.INVALID_VERSION_FORMAT), Name: "InvalidVersionFormat", Summary: "The `openapi` field is not a valid version.", Severity: SEVERITY_ERROR},
+		{ID: string(openapi.REF_OUTSIDE_SANDBOX), Name: "RefOutsideSandbox", Summary: "A $ref points outside the allowed directory.", Severity: SEVERITY_ERROR},
+		{ID: string(openapi.UNRESOLVED_REF), Name: "UnresolvedRef", Summary: "A $ref target does not exist.", Severity: SEVERITY_ERROR},
+		{ID: string(openapi.CIRCULAR_REF), Name: "CircularRef", Summary: "A $ref cycle cannot be inlined.", Severity: SEVERITY_ERROR},
+		{ID: string(openapi.EXPANSION_LIMIT), Name: "ExpansionLimit", Summary: "Dereferencing exceeded the configured size limits.", Severity: SEVERITY_ERROR},
+		{ID: string(openapi.GIT_UNAVAILABLE), Name: "GitUnavailable", Summary: "The git executable could not be started.", Severity: SEVERITY_ERROR},
+		{ID: string(openapi.NOT_A_REPOSITORY), Name: "NotARepository", Summary: "The directory is not inside a git repository.", Severity: SEVERITY_ERROR},
+		{ID: string(openapi.UNKNOWN_REVISION), Name: "UnknownRevision", Summary: "The git revision does not exist.", Severity: SEVERITY_ERROR},
+		{ID: string(openapi.FILE_NOT_IN_REVISION), Name: "FileNotInRevision", Summary: "The spec file does not exist at the git revision.", Severity: SEVERITY_ERROR},
+		{ID: string(openapi.INVALID_PACT), Name: "InvalidPact", Summary: "A Pact file is not a valid Pact contract.", Severity: SEVERITY_ERROR},
+		{ID: string(openapi.INVALID_HAR), Name: "InvalidHAR", Summary: "A traffic file is not a valid HAR archive.", Severity: SEVERITY_ERROR},
+		{ID: "unsupported_version", Name: "UnsupportedVersion", Summary: "The OpenAPI major version is not supported.", Severity: SEVERITY_ERROR},
+		{ID: string(customerrors.VALIDATION_ERROR), Name: "ValidationError", Summary: "The input was rejected.", Severity: SEVERITY_ERROR},
+		{ID: string(customerrors.DEPENDENCY_ERROR), Name: "DependencyError", Summary: "An internal component is not configured.", Severity: SEVERITY_ERROR},
+		{ID: "error", Name: "Error", Summary: "An unexpected error occurred.", Severity: SEVERITY_ERROR},
+	}
+}
@@ -0,0 +1,52 @@
+package report
+
+// Outcomes of an operation in a contract run.
+const (
+	OUTCOME_PASSED  = "passed"
+	OUTCOME_FAILED  = "failed"
+	OUTCOME_SKIPPED = "skipped"
+)
+
+// OperationRun is the call of one operation in a contract run.
+//   - Status: the HTTP status received, 0 when no response was received.
+//   - Outcome: OUTCOME_FAILED when a finding is an error, OUTCOME_SKIPPED
+//     when no request was sent.
+type OperationRun struct {
+	Operation  string `json:"operation"`
+	URL        string `json:"url,omitempty"`
+	Status     int    `json:"status,omitempty"`
+	Outcome    string `json:"outcome"`
+	DurationMS int64  `json:"durationMs"`
+}
+
+// ContractRun is the outcome of calling a spec's operations on a live
+// service, one entry per operation in spec order.
+type ContractRun struct {
+	Spec       string         `json:"spec"`
+	BaseURL    string         `json:"baseUrl"`
+	Operations []OperationRun `json:"operations"`
+	Findings   []Finding      `json:"findings"`
+}
+
+// Count returns how many operations had outcome.
+func (r ContractRun) Count(outcome string) int {
+	n := 0
+	for _, op := range r.Operations {
+		if op.Outcome == outcome {
+			n++
+		}
+	}
+	return n
+}
+
+// Report is the run as a report for writers, with the spec as its subject.
+func (r ContractRun) Report() Report {
+	ops := make([]string, len(r.Operations))
+	for i, op := range r.Operations {
+		ops[i] = op.Operation
+	}
+	return Report{
+		Subjects: []Subject{{File: r.Spec, Checks: []string{CHECK_RUN}, Operations: ops}},
+		Findings: r.Findings,
+	}
+}
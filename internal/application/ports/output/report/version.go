@@ -0,0 +1,25 @@
+package report
+
+import "github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+
+// VersionAdvice is the info.version bump a comparison calls for.
+//   - Required: the smallest bump that matches the changes.
+//   - Declared: the bump from BaseVersion to HeadVersion; empty when either
+//     is not a semantic version.
+//   - Recommended: the next version after BaseVersion; empty when BaseVersion
+//     is not a semantic version.
+//   - Understated: Declared is smaller than Required.
+//   - Reasons: the change IDs that call for the Required bump.
+//   - Notes: human-readable caveats (pre-1.0 rules, unparseable versions, …).
+type VersionAdvice struct {
+	Base        string              `json:"base"`
+	Head        string              `json:"head"`
+	BaseVersion string              `json:"baseVersion,omitempty"`
+	HeadVersion string              `json:"headVersion,omitempty"`
+	Required    openapi.VersionBump `json:"required"`
+	Declared    openapi.VersionBump `json:"declared,omitempty"`
+	Recommended string              `json:"recommended,omitempty"`
+	Understated bool                `json:"understated"`
+	Reasons     []string            `json:"reasons"`
+	Notes       []string            `json:"notes,omitempty"`
+}
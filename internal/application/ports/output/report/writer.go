@@ -0,0 +1,43 @@
+package report
+
+import "io"
+
+// Writer renders a report in one output format (SARIF, JUnit, …).
+// Writers must produce a well-formed document without findings too.
+type Writer interface {
+	Write(w io.Writer, r Report) error
+}
+
+// ComparisonWriter renders a spec comparison as a standalone document for
+// people (HTML, Markdown), as opposed to the finding-oriented Writer.
+type ComparisonWriter interface {
+	WriteComparison(w io.Writer, c Comparison) error
+}
+
+// Report is what writers render: the findings of one run and what was
+// checked. Subjects are optional; they let writers that list passing cases
+// (JUnit) report the operations and checks that produced no findings.
+type Report struct {
+	Subjects []Subject
+	Findings []Finding
+}
+
+// Subject is one checked spec: its file, the checks run against it (e.g.
+// CHECK_LOAD) and the operations ("METHOD /path") they covered.
+type Subject struct {
+	File       string
+	Checks     []string
+	Operations []string
+}
+
+// Rule is the metadata of a rule ID, for formats that describe their rules
+// (e.g. SARIF's tool.driver.rules).
+//   - Name: short PascalCase identifier, e.g. "FileNotFound".
+//   - Summary: one sentence describing what the rule reports.
+//   - Severity: the level findings of this rule usually have.
+type Rule struct {
+	ID       string
+	Name     string
+	Summary  string
+	Severity Severity
+}
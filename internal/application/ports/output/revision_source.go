@@ -0,0 +1,26 @@
+package output
+
+import "context"
+
+// Checkout is a spec materialized from a revision into a private directory.
+//   - Dir: the directory mirroring the repository root; refs cannot leave it.
+//   - Root: the spec file inside Dir.
+//   - Commit: the full commit ID the files were read from.
+type Checkout struct {
+	Dir    string
+	Root   string
+	Commit string
+}
+
+// RevisionSource reads specs as of a version-control revision (e.g. git).
+// Failures are AppErrors with kinds such as UNKNOWN_REVISION or
+// FILE_NOT_IN_REVISION.
+type RevisionSource interface {
+	// Resolve returns the commit ID that rev (branch, tag, SHA, "HEAD~1", …) points to.
+	Resolve(ctx context.Context, repo, rev string) (string, error)
+	// MergeBase returns the best common ancestor commit of a and b.
+	MergeBase(ctx context.Context, repo, a, b string) (string, error)
+	// Materialize writes path and the local files it references, as of rev,
+	// into a fresh directory. The caller must call cleanup when done.
+	Materialize(ctx context.Context, repo, rev, path string) (checkout Checkout, cleanup func(), err error)
+}
@@ -0,0 +1,68 @@
+// Package testgen defines the plan of a generated contract test suite and
+// the port that renders it as source code.
+package testgen
+
+import "github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+
+// Suite is the plan of a generated test suite.
+//   - Package: the package clause of the generated files.
+//   - Spec: the spec the suite was generated from, for the file header.
+//   - Schemas: response schemas keyed as referenced by Case.Content.
+type Suite struct {
+	Package string
+	Spec    string
+	Files   []File
+	Schemas map[string]*openapi.Schema
+}
+
+// File is one generated file; Name has no directory and no extension
+// beyond what the renderer adds (e.g. "pets" becomes "pets_contract_test.go").
+type File struct {
+	Name  string
+	Cases []Case
+}
+
+// Case is one test: a request to send and the response to expect.
+//   - Name: unique identifier within the suite, e.g. "GetPet_200".
+//   - Status: the declared status, e.g. "200", "4XX".
+//   - Path: the path template; PathParams fills it in.
+//   - Body: JSON text (or raw text for other media types); empty for none.
+//   - Anonymous: send without the configured credentials.
+//   - Content: expected media types mapped to a key of Suite.Schemas, or
+//     "" when the media type has no schema.
+//   - Skip: when set, the test is generated but skipped with this reason.
+type Case struct {
+	Name        string
+	Operation   string
+	Status      string
+	Method      string
+	Path        string
+	PathParams  map[string]string
+	Query       map[string][]string
+	Headers     map[string]string
+	ContentType string
+	Body        string
+	Anonymous   bool
+	Content     map[string]string
+	Skip        string
+}
+
+// Source is a rendered file.
+type Source struct {
+	Name    string
+	Content []byte
+}
+
+// Renderer is the output port that turns a suite into source files.
+// Output must be deterministic: the same suite renders byte-identical files.
+type Renderer interface {
+	Render(s Suite) ([]Source, error)
+}
+
+// Generated is the outcome of a generation run.
+//   - Tests: generated test functions; Skipped of them skip themselves.
+type Generated struct {
+	Files   []Source
+	Tests   int
+	Skipped int
+}
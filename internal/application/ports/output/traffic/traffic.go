@@ -0,0 +1,24 @@
+// Package traffic defines recorded HTTP traffic: the requests a browser, a
+// proxy or a test run sent and the responses they got, as saved in HAR files.
+package traffic
+
+import (
+	"context"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+)
+
+// Exchange is one recorded request and the response it got.
+//   - Request.URL: the absolute URL, query included.
+//   - Request.Method: upper-case.
+type Exchange struct {
+	Request  output.HTTPRequest
+	Response output.HTTPResponse
+}
+
+// Reader is the output port for reading recorded traffic.
+// Implementations skip requests that got no response and fail with an
+// AppError naming the file when it cannot be read.
+type Reader interface {
+	Read(ctx context.Context, path string) ([]Exchange, error)
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"io"
+
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+)
+
+// OpenAPIBundleParams declares the hard dependencies required to build the service.
+//   - BundlerFor returns a bundler whose external refs are confined to
+//     sandboxDir; an empty sandboxDir means the root spec's directory.
+type OpenAPIBundleParams struct {
+	BundlerFor    func(sandboxDir string) openapi.Bundler
+	Writer        openapi.Writer
+	Logger        output.Logger
+	VersionPolicy input.VersionPolicy
+}
+
+// validate performs defensive checks on constructor params.
+func (p OpenAPIBundleParams) validate() error {
+	if p.BundlerFor == nil {
+		return customerrors.NewDependencyError("bundlerFor")
+	}
+	if p.Writer == nil {
+		return customerrors.NewDependencyError("writer")
+	}
+	if p.Logger == nil {
+		return customerrors.NewDependencyError("logger")
+	}
+	if p.VersionPolicy == nil {
+		return customerrors.NewDependencyError("versionPolicy")
+	}
+	return nil
+}
+
+// OpenAPIBundleService merges multi-file specs into one document (input port implementation).
+type OpenAPIBundleService struct {
+	bundlerFor    func(sandboxDir string) openapi.Bundler
+	writer        openapi.Writer
+	logger        output.Logger
+	versionPolicy input.VersionPolicy
+}
+
+// NewOpenAPIBundleService constructs the service after validating dependencies.
+func NewOpenAPIBundleService(params OpenAPIBundleParams) (*OpenAPIBundleService, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	return &OpenAPIBundleService{
+		bundlerFor:    params.BundlerFor,
+		writer:        params.Writer,
+		logger:        params.Logger,
+		versionPolicy: params.VersionPolicy,
+	}, nil
+}
+
+// Bundle resolves rootPath and its external refs into a single document, enforces
+// the VersionPolicy and writes the result to w in the requested format.
+func (s *OpenAPIBundleService) Bundle(ctx context.Context, rootPath string, w io.Writer, format openapi.Format, opts input.BundleOptions) (openapi.OpenAPIDoc, error) {
+	log := s.logger.With("local", "service.OpenAPIBundleService.Bundle")
+
+	log.Info("starting to bundle OpenAPI spec", "file", rootPath, "format", string(format), "sandbox", opts.Sandbox)
+	doc, err := s.bundlerFor(opts.Sandbox).Bundle(ctx, rootPath)
+	if err != nil {
+		log.Error("failed to bundle OpenAPI spec", "file", rootPath)
+		return openapi.OpenAPIDoc{}, err
+	}
+
+	if !s.versionPolicy.IsSupported(doc.Version.Major()) {
+		log.Error(
+			"invalid OpenAPI version",
+			"file", rootPath,
+			"version", doc.Version.String(),
+			"accepted", s.versionPolicy.SupportedVersions(),
+		)
+		return openapi.OpenAPIDoc{}, customerrors.NewUnsupportedVersionError(
+			rootPath,
+			doc.Version.String(),
+			s.versionPolicy.SupportedVersions(),
+		)
+	}
+
+	if err := s.writer.Write(w, doc, format); err != nil {
+		log.Error("failed to write bundled spec", "file", rootPath, "error", err)
+		return openapi.OpenAPIDoc{}, err
+	}
+
+	log.Debug("successfully bundled OpenAPI spec")
+	return doc, nil
+}
+
+// compile-time check
+var _ input.BundleOpenAPISpec = (*OpenAPIBundleService)(nil)
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+// changelogCategory files each change type under a Keep a Changelog section.
+// Unknown IDs are "Changed".
+var changelogCategory = map[string]report.ChangelogCategory{
+	CHANGE_OPERATION_ADDED:          report.CHANGELOG_ADDED,
+	CHANGE_PARAMETER_ADDED:          report.CHANGELOG_ADDED,
+	CHANGE_PARAMETER_ADDED_REQUIRED: report.CHANGELOG_ADDED,
+	CHANGE_REQUEST_BODY_ADDED:       report.CHANGELOG_ADDED,
+	CHANGE_MEDIA_TYPE_ADDED:         report.CHANGELOG_ADDED,
+	CHANGE_RESPONSE_ADDED:           report.CHANGELOG_ADDED,
+	CHANGE_ENUM_VALUE_ADDED:         report.CHANGELOG_ADDED,
+	CHANGE_PROPERTY_ADDED:           report.CHANGELOG_ADDED,
+	CHANGE_PROPERTY_ADDED_REQUIRED:  report.CHANGELOG_ADDED,
+
+	CHANGE_OPERATION_DEPRECATED: report.CHANGELOG_DEPRECATED,
+	CHANGE_PARAMETER_DEPRECATED: report.CHANGELOG_DEPRECATED,
+	CHANGE_PROPERTY_DEPRECATED:  report.CHANGELOG_DEPRECATED,
+
+	CHANGE_OPERATION_REMOVED:       report.CHANGELOG_REMOVED,
+	CHANGE_PARAMETER_REMOVED:       report.CHANGELOG_REMOVED,
+	CHANGE_REQUEST_BODY_REMOVED:    report.CHANGELOG_REMOVED,
+	CHANGE_MEDIA_TYPE_REMOVED:      report.CHANGELOG_REMOVED,
+	CHANGE_RESPONSE_REMOVED:        report.CHANGELOG_REMOVED,
+	CHANGE_RESPONSE_HEADER_REMOVED: report.CHANGELOG_REMOVED,
+	CHANGE_ENUM_VALUE_REMOVED:      report.CHANGELOG_REMOVED,
+	CHANGE_PROPERTY_REMOVED:        report.CHANGELOG_REMOVED,
+}
+
+// endpointChanges are the changes about a whole operation; they are listed
+// as-is at every detail level.
+var endpointChanges = map[string]bool{
+	CHANGE_OPERATION_ADDED:      true,
+	CHANGE_OPERATION_DEPRECATED: true,
+	CHANGE_OPERATION_REMOVED:    true,
+}
+
+// namedInMessage are the changes whose message already says where they
+// happened (e.g. `query parameter "limit" was added`).
+var namedInMessage = map[string]bool{
+	CHANGE_PARAMETER_ADDED:          true,
+	CHANGE_PARAMETER_ADDED_REQUIRED: true,
+	CHANGE_PARAMETER_REMOVED:        true,
+	CHANGE_PARAMETER_REQUIRED:       true,
+	CHANGE_PARAMETER_OPTIONAL:       true,
+	CHANGE_PARAMETER_DEPRECATED:     true,
+	CHANGE_REQUEST_BODY_ADDED:       true,
+	CHANGE_REQUEST_BODY_REMOVED:     true,
+	CHANGE_REQUEST_BODY_REQUIRED:    true,
+	CHANGE_RESPONSE_ADDED:           true,
+	CHANGE_RESPONSE_REMOVED:         true,
+}
+
+// summaryNoun names what a collapsed entry counts, per category.
+var summaryNoun = map[report.ChangelogCategory][2]string{
+	report.CHANGELOG_ADDED:      {"addition", "additions"},
+	report.CHANGELOG_CHANGED:    {"change", "changes"},
+	report.CHANGELOG_DEPRECATED: {"deprecation", "deprecations"},
+	report.CHANGELOG_REMOVED:    {"removal", "removals"},
+}
+
+// ChangelogParams declares the dependencies required to build the service.
+type ChangelogParams struct {
+	Logger output.Logger
+}
+
+// validate performs defensive checks on constructor params.
+func (p ChangelogParams) validate() error {
+	if p.Logger == nil {
+		return customerrors.NewDependencyError("logger")
+	}
+	return nil
+}
+
+// ChangelogService builds changelogs from comparisons (input port implementation).
+type ChangelogService struct {
+	logger output.Logger
+}
+
+// NewChangelogService constructs the service after validating dependencies.
+func NewChangelogService(params ChangelogParams) (*ChangelogService, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	return &ChangelogService{logger: params.Logger}, nil
+}
+
+// Changelog groups cmp's changes into Added/Changed/Deprecated/Removed.
+func (s *ChangelogService) Changelog(_ context.Context, cmp report.Comparison, opts input.ChangelogOptions) (report.Changelog, error) {
+	detail := opts.Detail
+	if detail == "" {
+		detail = report.CHANGELOG_FIELDS
+	}
+	if _, err := report.ParseChangelogDetail(string(detail)); err != nil {
+		return report.Changelog{}, customerrors.NewValidationError("Invalid changelog detail", err, nil)
+	}
+
+	cl := report.Changelog{
+		Title:    opts.Title,
+		Version:  opts.Version,
+		Date:     opts.Date,
+		Base:     cmp.Base,
+		Head:     cmp.Head,
+		Detail:   detail,
+		Breaking: len(cmp.Breaking()),
+		Sections: []report.ChangelogSection{},
+	}
+	if cl.Title == "" {
+		cl.Title = cmp.HeadSpec.Title
+	}
+	if cl.Version == "" {
+		cl.Version = cmp.HeadSpec.Version
+	}
+
+	entries := map[report.ChangelogCategory][]report.ChangelogEntry{}
+	// In endpoint mode, field-level changes collapse into one entry per
+	// operation and category, in first-seen order.
+	type group struct {
+		category  report.ChangelogCategory
+		operation string
+	}
+	var order []group
+	grouped := map[group][]report.Change{}
+
+	for _, ch := range cmp.Changes {
+		cat, ok := changelogCategory[ch.ID]
+		if !ok {
+			cat = report.CHANGELOG_CHANGED
+		}
+		switch {
+		case endpointChanges[ch.ID]:
+			entries[cat] = append(entries[cat], report.ChangelogEntry{
+				Operation: ch.Operation,
+				Breaking:  ch.Breaking(),
+				Changes:   []string{ch.ID},
+			})
+		case detail == report.CHANGELOG_FIELDS:
+			text := ch.Message
+			if ch.Location != "" && !namedInMessage[ch.ID] {
+				text += " (" + ch.Location + ")"
+			}
+			entries[cat] = append(entries[cat], report.ChangelogEntry{
+				Operation: ch.Operation,
+				Text:      text,
+				Breaking:  ch.Breaking(),
+				Changes:   []string{ch.ID},
+			})
+		default:
+			g := group{category: cat, operation: ch.Operation}
+			if _, seen := grouped[g]; !seen {
+				order = append(order, g)
+			}
+			grouped[g] = append(grouped[g], ch)
+		}
+	}
+	for _, g := range order {
+		entries[g.category] = append(entries[g.category], summarize(g.category, g.operation, grouped[g]))
+	}
+
+	for _, cat := range report.ChangelogCategories {
+		if len(entries[cat]) == 0 {
+			continue
+		}
+		sort.SliceStable(entries[cat], func(i, j int) bool {
+			return entries[cat][i].Operation < entries[cat][j].Operation
+		})
+		cl.Sections = append(cl.Sections, report.ChangelogSection{Category: cat, Entries: entries[cat]})
+	}
+
+	s.logger.With("local", "service.ChangelogService.Changelog").Debug("changelog built",
+		"head", cmp.Head,
+		"detail", detail,
+		"changes", len(cmp.Changes),
+		"sections", len(cl.Sections),
+	)
+	return cl, nil
+}
+
+// summarize folds the field-level changes of one operation and category,
+// e.g. "3 additions".
+func summarize(cat report.ChangelogCategory, operation string, changes []report.Change) report.ChangelogEntry {
+	e := report.ChangelogEntry{Operation: operation}
+	ids := map[string]bool{}
+	for _, ch := range changes {
+		e.Breaking = e.Breaking || ch.Breaking()
+		if !ids[ch.ID] {
+			ids[ch.ID] = true
+			e.Changes = append(e.Changes, ch.ID)
+		}
+	}
+	sort.Strings(e.Changes)
+	noun := summaryNoun[cat]
+	if len(changes) == 1 {
+		e.Text = "1 " + noun[0]
+	} else {
+		e.Text = fmt.Sprintf("%d %s", len(changes), noun[1])
+	}
+	return e
+}
+
+// compile-time check
+var _ input.GenerateChangelog = (*ChangelogService)(nil)
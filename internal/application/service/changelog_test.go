@@ -0,0 +1,122 @@
+package service_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+	"github.com/betoth/contractcheck/internal/application/service"
+)
+
+func releaseComparison(t *testing.T) report.Comparison {
+	t.Helper()
+	head := petAPI(func(d *openapi.Document) {
+		d.Info = openapi.Info{Title: "Petstore", Version: "1.5.0"}
+		d.Operations = d.Operations[:2] // DELETE removed
+		get := &d.Operations[1]
+		get.Deprecated = true
+		get.Parameters = append(get.Parameters, openapi.Parameter{Name: "fields", In: openapi.PARAM_IN_QUERY, Deprecated: true, Schema: str()})
+		pet := get.Responses[0].Content["application/json"].Schema
+		pet.Properties["status"].Deprecated = true
+		pet.Properties["tag"] = str()
+		d.Operations = append(d.Operations, openapi.Operation{Path: "/owners", Method: "GET", Responses: []openapi.Response{{Status: "200"}}})
+	})
+	svc := newCompareService(t, map[string]*openapi.Document{"v1.yaml": petAPI(nil), "v2.yaml": head}, nil, nil)
+	cmp, err := svc.Compare(context.Background(), "v1.yaml", "v2.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cmp
+}
+
+type entryLine struct {
+	category report.ChangelogCategory
+	text     string
+}
+
+func changelogLines(cl report.Changelog) []entryLine {
+	var lines []entryLine
+	for _, s := range cl.Sections {
+		for _, e := range s.Entries {
+			text := e.Operation
+			if e.Text != "" {
+				text += ": " + e.Text
+			}
+			if e.Breaking {
+				text = "BREAKING " + text
+			}
+			lines = append(lines, entryLine{s.Category, text})
+		}
+	}
+	return lines
+}
+
+func newChangelogService(t *testing.T) *service.ChangelogService {
+	t.Helper()
+	svc, err := service.NewChangelogService(service.ChangelogParams{Logger: nopLogger{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svc
+}
+
+func TestChangelogService_FieldLevel(t *testing.T) {
+	cl, err := newChangelogService(t).Changelog(context.Background(), releaseComparison(t), input.ChangelogOptions{Date: "2026-10-18"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cl.Title != "Petstore" || cl.Version != "1.5.0" || cl.Date != "2026-10-18" || cl.Detail != report.CHANGELOG_FIELDS {
+		t.Fatalf("header = %+v", cl)
+	}
+	want := []entryLine{
+		{report.CHANGELOG_ADDED, "GET /owners"},
+		{report.CHANGELOG_ADDED, `GET /pets/{id}: optional query parameter "fields" was added`},
+		{report.CHANGELOG_ADDED, "GET /pets/{id}: property tag was added (response 200 application/json)"},
+		{report.CHANGELOG_DEPRECATED, "GET /pets/{id}"},
+		{report.CHANGELOG_DEPRECATED, "GET /pets/{id}: property status was deprecated (response 200 application/json)"},
+		{report.CHANGELOG_REMOVED, "BREAKING DELETE /pets/{id}"},
+	}
+	if got := changelogLines(cl); !slices.Equal(got, want) {
+		t.Fatalf("entries:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestChangelogService_EndpointLevel(t *testing.T) {
+	cl, err := newChangelogService(t).Changelog(context.Background(), releaseComparison(t), input.ChangelogOptions{
+		Detail:  report.CHANGELOG_ENDPOINTS,
+		Version: "2.0.0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cl.Version != "2.0.0" || cl.Breaking != 1 {
+		t.Fatalf("header = %+v", cl)
+	}
+	want := []entryLine{
+		{report.CHANGELOG_ADDED, "GET /owners"},
+		{report.CHANGELOG_ADDED, "GET /pets/{id}: 2 additions"},
+		{report.CHANGELOG_DEPRECATED, "GET /pets/{id}"},
+		{report.CHANGELOG_DEPRECATED, "GET /pets/{id}: 1 deprecation"},
+		{report.CHANGELOG_REMOVED, "BREAKING DELETE /pets/{id}"},
+	}
+	if got := changelogLines(cl); !slices.Equal(got, want) {
+		t.Fatalf("entries:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestChangelogService_NoChangesAndInvalidDetail(t *testing.T) {
+	svc := newChangelogService(t)
+	cl, err := svc.Changelog(context.Background(), report.Comparison{}, input.ChangelogOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cl.Sections == nil || len(cl.Sections) != 0 {
+		t.Fatalf("sections = %#v, want empty", cl.Sections)
+	}
+	if _, err := svc.Changelog(context.Background(), report.Comparison{}, input.ChangelogOptions{Detail: "verbose"}); err == nil {
+		t.Fatal("expected an error for an unknown detail level")
+	}
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+// OpenAPICompareParams declares the dependencies required to build the service.
+//   - LoaderFor returns a loader whose external refs are confined to sandboxDir
+//     (the spec's directory for files, the checkout for git revisions).
+//   - Revisions is optional; without it CompareGit fails with a dependency error.
+type OpenAPICompareParams struct {
+	LoaderFor     func(sandboxDir string) openapi.Loader
+	Revisions     output.RevisionSource
+	Logger        output.Logger
+	VersionPolicy input.VersionPolicy
+}
+
+// validate performs defensive checks on constructor params.
+func (p OpenAPICompareParams) validate() error {
+	if p.LoaderFor == nil {
+		return customerrors.NewDependencyError("loaderFor")
+	}
+	if p.Logger == nil {
+		return customerrors.NewDependencyError("logger")
+	}
+	if p.VersionPolicy == nil {
+		return customerrors.NewDependencyError("versionPolicy")
+	}
+	return nil
+}
+
+// OpenAPICompareService detects contract changes between two versions of a
+// spec (input port implementation).
+type OpenAPICompareService struct {
+	specs  specPairs
+	logger output.Logger
+}
+
+// NewOpenAPICompareService constructs the service after validating dependencies.
+func NewOpenAPICompareService(params OpenAPICompareParams) (*OpenAPICompareService, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	return &OpenAPICompareService{
+		specs: specPairs{
+			loaderFor:     params.LoaderFor,
+			revisions:     params.Revisions,
+			logger:        params.Logger,
+			versionPolicy: params.VersionPolicy,
+		},
+		logger: params.Logger,
+	}, nil
+}
+
+// Compare diffs two spec files on disk.
+func (s *OpenAPICompareService) Compare(ctx context.Context, basePath, headPath string) (report.Comparison, error) {
+	p, err := s.specs.files(ctx, basePath, headPath)
+	if err != nil {
+		return report.Comparison{}, err
+	}
+	return s.compare(p.baseLabel, p.headLabel, p.base, p.head), nil
+}
+
+// CompareGit diffs req.Path between the merge-base of req.Head and
+// req.Target, and req.Head.
+func (s *OpenAPICompareService) CompareGit(ctx context.Context, req input.GitCompareRequest) (report.Comparison, error) {
+	p, err := s.specs.git(ctx, req)
+	if err != nil {
+		return report.Comparison{}, err
+	}
+	return s.compare(p.baseLabel, p.headLabel, p.base, p.head), nil
+}
+
+// BaselineCheck loads base once, from a file or as specPath at a git
+// revision, and returns a check that diffs every document against it.
+func (s *OpenAPICompareService) BaselineCheck(ctx context.Context, specPath string, base input.Baseline) (report.Check, error) {
+	if base.File != "" {
+		doc, err := s.specs.load(ctx, base.File, filepath.Dir(base.File), base.File)
+		if err != nil {
+			return nil, err
+		}
+		return &baselineCheck{label: base.File, base: doc}, nil
+	}
+	doc, label, err := s.specs.revision(ctx, specPath, base.Rev)
+	if err != nil {
+		return nil, err
+	}
+	return &baselineCheck{label: label, base: doc}, nil
+}
+
+func (s *OpenAPICompareService) compare(baseLabel, headLabel string, base, head *openapi.Document) report.Comparison {
+	cmp := report.Comparison{
+		Base:       baseLabel,
+		Head:       headLabel,
+		BaseSpec:   report.SpecInfoOf(base),
+		HeadSpec:   report.SpecInfoOf(head),
+		Operations: comparedOperations(base, head),
+		Changes:    compareDocuments(base, head),
+	}
+	s.logger.With("local", "service.OpenAPICompareService").Debug("comparison completed",
+		"base", baseLabel,
+		"head", headLabel,
+		"changes", len(cmp.Changes),
+		"breaking", len(cmp.Breaking()),
+	)
+	return cmp
+}
+
+// baselineCheck reports the changes of checked documents against a base
+// loaded once. Findings carry no file; callers that know it set it.
+type baselineCheck struct {
+	label string
+	base  *openapi.Document
+}
+
+// Name implements report.Check.
+func (c *baselineCheck) Name() string { return report.CHECK_COMPARE }
+
+// Check implements report.Check.
+func (c *baselineCheck) Check(_ context.Context, doc openapi.OpenAPIDoc) ([]report.Finding, error) {
+	if doc.Model == nil {
+		return nil, customerrors.NewDependencyError("loader model")
+	}
+	cmp := report.Comparison{Base: c.label, Changes: compareDocuments(c.base, doc.Model)}
+	return cmp.Findings(), nil
+}
+
+// compile-time check
+var (
+	_ input.CompareOpenAPISpecs = (*OpenAPICompareService)(nil)
+	_ input.BuildBaselineCheck  = (*OpenAPICompareService)(nil)
+	_ report.Check              = (*baselineCheck)(nil)
+)
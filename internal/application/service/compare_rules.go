@@ -0,0 +1,580 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+// Change IDs emitted by compareDocuments. They are stable: reports and the
+// changelog key on them.
+const (
+	CHANGE_OPERATION_REMOVED        = "operation-removed"
+	CHANGE_OPERATION_ADDED          = "operation-added"
+	CHANGE_OPERATION_DEPRECATED     = "operation-deprecated"
+	CHANGE_PARAMETER_ADDED_REQUIRED = "parameter-added-required"
+	CHANGE_PARAMETER_ADDED          = "parameter-added"
+	CHANGE_PARAMETER_REMOVED        = "parameter-removed"
+	CHANGE_PARAMETER_REQUIRED       = "parameter-became-required"
+	CHANGE_PARAMETER_OPTIONAL       = "parameter-became-optional"
+	CHANGE_PARAMETER_DEPRECATED     = "parameter-deprecated"
+	CHANGE_REQUEST_BODY_ADDED       = "request-body-added"
+	CHANGE_REQUEST_BODY_REMOVED     = "request-body-removed"
+	CHANGE_REQUEST_BODY_REQUIRED    = "request-body-became-required"
+	CHANGE_MEDIA_TYPE_REMOVED       = "media-type-removed"
+	CHANGE_MEDIA_TYPE_ADDED         = "media-type-added"
+	CHANGE_RESPONSE_REMOVED         = "response-removed"
+	CHANGE_RESPONSE_ADDED           = "response-added"
+	CHANGE_RESPONSE_HEADER_REMOVED  = "response-header-removed"
+	CHANGE_SECURITY_ADDED           = "security-requirement-added"
+	CHANGE_SECURITY_REMOVED         = "security-requirement-removed"
+	CHANGE_TYPE_CHANGED             = "type-changed"
+	CHANGE_FORMAT_CHANGED           = "format-changed"
+	CHANGE_NULLABLE_CHANGED         = "nullable-changed"
+	CHANGE_ENUM_VALUE_REMOVED       = "enum-value-removed"
+	CHANGE_ENUM_VALUE_ADDED         = "enum-value-added"
+	CHANGE_CONSTRAINT_TIGHTENED     = "constraint-tightened"
+	CHANGE_PROPERTY_REMOVED         = "property-removed"
+	CHANGE_PROPERTY_ADDED           = "property-added"
+	CHANGE_PROPERTY_ADDED_REQUIRED  = "property-added-required"
+	CHANGE_PROPERTY_REQUIRED        = "property-became-required"
+	CHANGE_PROPERTY_OPTIONAL        = "property-became-optional"
+	CHANGE_PROPERTY_DEPRECATED      = "property-deprecated"
+)
+
+// ChangeRules describes the change IDs for report writers. Severity is the
+// usual level; direction-dependent changes (e.g. a removed property is only
+// breaking in responses) are reported per finding.
+func ChangeRules() []report.Rule {
+	breaking, info := report.SEVERITY_ERROR, report.SEVERITY_INFO
+	return []report.Rule{
+		{ID: CHANGE_OPERATION_REMOVED, Name: "OperationRemoved", Summary: "An operation was removed.", Severity: breaking},
+		{ID: CHANGE_OPERATION_ADDED, Name: "OperationAdded", Summary: "An operation was added.", Severity: info},
+		{ID: CHANGE_OPERATION_DEPRECATED, Name: "OperationDeprecated", Summary: "An operation was marked deprecated.", Severity: info},
+		{ID: CHANGE_PARAMETER_ADDED_REQUIRED, Name: "ParameterAddedRequired", Summary: "A required parameter was added.", Severity: breaking},
+		{ID: CHANGE_PARAMETER_ADDED, Name: "ParameterAdded", Summary: "An optional parameter was added.", Severity: info},
+		{ID: CHANGE_PARAMETER_REMOVED, Name: "ParameterRemoved", Summary: "A parameter was removed.", Severity: info},
+		{ID: CHANGE_PARAMETER_REQUIRED, Name: "ParameterBecameRequired", Summary: "An optional parameter became required.", Severity: breaking},
+		{ID: CHANGE_PARAMETER_OPTIONAL, Name: "ParameterBecameOptional", Summary: "A required parameter became optional.", Severity: info},
+		{ID: CHANGE_PARAMETER_DEPRECATED, Name: "ParameterDeprecated", Summary: "A parameter was marked deprecated.", Severity: info},
+		{ID: CHANGE_REQUEST_BODY_ADDED, Name: "RequestBodyAdded", Summary: "A request body was added.", Severity: info},
+		{ID: CHANGE_REQUEST_BODY_REMOVED, Name: "RequestBodyRemoved", Summary: "The request body was removed.", Severity: breaking},
+		{ID: CHANGE_REQUEST_BODY_REQUIRED, Name: "RequestBodyBecameRequired", Summary: "An optional request body became required.", Severity: breaking},
+		{ID: CHANGE_MEDIA_TYPE_REMOVED, Name: "MediaTypeRemoved", Summary: "A media type was removed.", Severity: breaking},
+		{ID: CHANGE_MEDIA_TYPE_ADDED, Name: "MediaTypeAdded", Summary: "A media type was added.", Severity: info},
+		{ID: CHANGE_RESPONSE_REMOVED, Name: "ResponseRemoved", Summary: "A response status was removed.", Severity: breaking},
+		{ID: CHANGE_RESPONSE_ADDED, Name: "ResponseAdded", Summary: "A response status was added.", Severity: info},
+		{ID: CHANGE_RESPONSE_HEADER_REMOVED, Name: "ResponseHeaderRemoved", Summary: "A response header was removed.", Severity: breaking},
+		{ID: CHANGE_SECURITY_ADDED, Name: "SecurityRequirementAdded", Summary: "A security alternative was added.", Severity: info},
+		{ID: CHANGE_SECURITY_REMOVED, Name: "SecurityRequirementRemoved", Summary: "A security alternative is no longer accepted.", Severity: breaking},
+		{ID: CHANGE_TYPE_CHANGED, Name: "TypeChanged", Summary: "A schema type changed.", Severity: breaking},
+		{ID: CHANGE_FORMAT_CHANGED, Name: "FormatChanged", Summary: "A schema format changed.", Severity: breaking},
+		{ID: CHANGE_NULLABLE_CHANGED, Name: "NullableChanged", Summary: "A schema's nullability changed.", Severity: breaking},
+		{ID: CHANGE_ENUM_VALUE_REMOVED, Name: "EnumValueRemoved", Summary: "An enum value was removed.", Severity: breaking},
+		{ID: CHANGE_ENUM_VALUE_ADDED, Name: "EnumValueAdded", Summary: "An enum value was added.", Severity: info},
+		{ID: CHANGE_CONSTRAINT_TIGHTENED, Name: "ConstraintTightened", Summary: "A schema constraint became stricter.", Severity: breaking},
+		{ID: CHANGE_PROPERTY_REMOVED, Name: "PropertyRemoved", Summary: "An object property was removed.", Severity: breaking},
+		{ID: CHANGE_PROPERTY_ADDED, Name: "PropertyAdded", Summary: "An optional object property was added.", Severity: info},
+		{ID: CHANGE_PROPERTY_ADDED_REQUIRED, Name: "PropertyAddedRequired", Summary: "A required object property was added.", Severity: breaking},
+		{ID: CHANGE_PROPERTY_REQUIRED, Name: "PropertyBecameRequired", Summary: "An optional object property became required.", Severity: breaking},
+		{ID: CHANGE_PROPERTY_OPTIONAL, Name: "PropertyBecameOptional", Summary: "A required object property became optional.", Severity: info},
+		{ID: CHANGE_PROPERTY_DEPRECATED, Name: "PropertyDeprecated", Summary: "An object property was marked deprecated.", Severity: info},
+	}
+}
+
+// direction tells schema rules which side of the exchange a schema describes.
+// Requests must keep accepting what clients send; responses must keep
+// returning what clients expect.
+type direction int
+
+const (
+	dirRequest direction = iota
+	dirResponse
+)
+
+// pathParam matches path template variables, e.g. "{petId}".
+var pathParam = regexp.MustCompile(`\{[^}]*\}`)
+
+// compareDocuments returns the contract changes from base to head, sorted by
+// operation, ID and location.
+func compareDocuments(base, head *openapi.Document) []report.Change {
+	c := &comparer{changes: []report.Change{}}
+
+	headOps := make(map[string]*openapi.Operation, len(head.Operations))
+	for i := range head.Operations {
+		headOps[operationShape(head.Operations[i])] = &head.Operations[i]
+	}
+	matched := map[string]bool{}
+	for i := range base.Operations {
+		b := &base.Operations[i]
+		shape := operationShape(*b)
+		h, ok := headOps[shape]
+		if !ok {
+			c.add(CHANGE_OPERATION_REMOVED, report.CHANGE_BREAKING, b.Key(), "", "operation was removed")
+			continue
+		}
+		matched[shape] = true
+		c.operation(base, head, b, h)
+	}
+	for i := range head.Operations {
+		h := &head.Operations[i]
+		if !matched[operationShape(*h)] {
+			c.add(CHANGE_OPERATION_ADDED, report.CHANGE_NON_BREAKING, h.Key(), "", "operation was added")
+		}
+	}
+
+	sort.SliceStable(c.changes, func(i, j int) bool {
+		a, b := c.changes[i], c.changes[j]
+		if a.Operation != b.Operation {
+			return a.Operation < b.Operation
+		}
+		if a.Location != b.Location {
+			return a.Location < b.Location
+		}
+		return a.ID < b.ID
+	})
+	return c.changes
+}
+
+// comparedOperations lists the operations of head plus those of base that
+// head no longer has, by the key they are reported under.
+func comparedOperations(base, head *openapi.Document) []string {
+	shapes := make(map[string]bool, len(head.Operations))
+	ops := make([]string, 0, len(head.Operations))
+	for _, op := range head.Operations {
+		shapes[operationShape(op)] = true
+		ops = append(ops, op.Key())
+	}
+	for _, op := range base.Operations {
+		if !shapes[operationShape(op)] {
+			ops = append(ops, op.Key())
+		}
+	}
+	sort.Strings(ops)
+	return ops
+}
+
+// operationShape keys operations so renaming a path variable
+// ("/pets/{id}" -> "/pets/{petId}") is not reported as remove + add.
+func operationShape(op openapi.Operation) string {
+	return op.Method + " " + pathParam.ReplaceAllString(op.Path, "{}")
+}
+
+type comparer struct {
+	changes []report.Change
+}
+
+func (c *comparer) add(id string, level report.ChangeLevel, op, loc, format string, args ...any) {
+	c.changes = append(c.changes, report.Change{
+		ID:        id,
+		Level:     level,
+		Operation: op,
+		Location:  loc,
+		Message:   fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *comparer) operation(baseDoc, headDoc *openapi.Document, b, h *openapi.Operation) {
+	op := h.Key()
+	if !b.Deprecated && h.Deprecated {
+		c.add(CHANGE_OPERATION_DEPRECATED, report.CHANGE_NON_BREAKING, op, "", "operation was deprecated")
+	}
+	c.parameters(op, b, h)
+	c.requestBody(op, b.RequestBody, h.RequestBody)
+	c.responses(op, b, h)
+	c.security(op, b.EffectiveSecurity(baseDoc), h.EffectiveSecurity(headDoc))
+}
+
+func (c *comparer) parameters(op string, b, h *openapi.Operation) {
+	// Path variables are matched by position, since their names are not
+	// visible to clients; everything else by location and name.
+	key := func(o *openapi.Operation, p openapi.Parameter) string {
+		if p.In == openapi.PARAM_IN_PATH {
+			vars := pathParam.FindAllString(o.Path, -1)
+			if i := slices.Index(vars, "{"+p.Name+"}"); i >= 0 {
+				return fmt.Sprintf("path#%d", i)
+			}
+		}
+		return p.In + ":" + strings.ToLower(p.Name)
+	}
+	headParams := map[string]openapi.Parameter{}
+	for _, p := range h.Parameters {
+		headParams[key(h, p)] = p
+	}
+	seen := map[string]bool{}
+	for _, bp := range b.Parameters {
+		k := key(b, bp)
+		loc := fmt.Sprintf("parameter %s %s", bp.In, bp.Name)
+		hp, ok := headParams[k]
+		if !ok {
+			c.add(CHANGE_PARAMETER_REMOVED, report.CHANGE_NON_BREAKING, op, loc, "%s parameter %q was removed", bp.In, bp.Name)
+			continue
+		}
+		seen[k] = true
+		switch {
+		case !bp.Required && hp.Required:
+			c.add(CHANGE_PARAMETER_REQUIRED, report.CHANGE_BREAKING, op, loc, "%s parameter %q became required", bp.In, bp.Name)
+		case bp.Required && !hp.Required:
+			c.add(CHANGE_PARAMETER_OPTIONAL, report.CHANGE_NON_BREAKING, op, loc, "%s parameter %q became optional", bp.In, bp.Name)
+		}
+		if !bp.Deprecated && hp.Deprecated {
+			c.add(CHANGE_PARAMETER_DEPRECATED, report.CHANGE_NON_BREAKING, op, loc, "%s parameter %q was deprecated", bp.In, bp.Name)
+		}
+		c.schema(op, loc, dirRequest, bp.Schema, hp.Schema, "", map[[2]*openapi.Schema]bool{})
+	}
+	for _, hp := range h.Parameters {
+		if seen[key(h, hp)] {
+			continue
+		}
+		loc := fmt.Sprintf("parameter %s %s", hp.In, hp.Name)
+		if hp.Required {
+			c.add(CHANGE_PARAMETER_ADDED_REQUIRED, report.CHANGE_BREAKING, op, loc, "required %s parameter %q was added", hp.In, hp.Name)
+		} else {
+			c.add(CHANGE_PARAMETER_ADDED, report.CHANGE_NON_BREAKING, op, loc, "optional %s parameter %q was added", hp.In, hp.Name)
+		}
+	}
+}
+
+func (c *comparer) requestBody(op string, b, h *openapi.RequestBody) {
+	const loc = "request body"
+	switch {
+	case b == nil && h == nil:
+		return
+	case b == nil:
+		level := report.CHANGE_NON_BREAKING
+		if h.Required {
+			level = report.CHANGE_BREAKING
+		}
+		c.add(CHANGE_REQUEST_BODY_ADDED, level, op, loc, "request body was added (required: %t)", h.Required)
+		return
+	case h == nil:
+		c.add(CHANGE_REQUEST_BODY_REMOVED, report.CHANGE_BREAKING, op, loc, "request body was removed")
+		return
+	}
+	if !b.Required && h.Required {
+		c.add(CHANGE_REQUEST_BODY_REQUIRED, report.CHANGE_BREAKING, op, loc, "request body became required")
+	}
+	c.content(op, loc, dirRequest, b.Content, h.Content)
+}
+
+func (c *comparer) responses(op string, b, h *openapi.Operation) {
+	for _, br := range b.Responses {
+		loc := "response " + br.Status
+		hr := h.Response(br.Status)
+		if hr == nil {
+			// Clients rely on documented success responses; a removed error
+			// response only means it is no longer produced.
+			level := report.CHANGE_NON_BREAKING
+			if strings.HasPrefix(br.Status, "2") {
+				level = report.CHANGE_BREAKING
+			}
+			c.add(CHANGE_RESPONSE_REMOVED, level, op, loc, "response %s was removed", br.Status)
+			continue
+		}
+		for _, name := range sortedKeys(br.Headers) {
+			bh := br.Headers[name]
+			hh, ok := hr.Headers[name]
+			if !ok {
+				c.add(CHANGE_RESPONSE_HEADER_REMOVED, report.CHANGE_BREAKING, op, loc, "response header %q was removed", name)
+				continue
+			}
+			c.schema(op, loc+" header "+name, dirResponse, bh.Schema, hh.Schema, "", map[[2]*openapi.Schema]bool{})
+		}
+		c.content(op, loc, dirResponse, br.Content, hr.Content)
+	}
+	for _, hr := range h.Responses {
+		if b.Response(hr.Status) == nil {
+			c.add(CHANGE_RESPONSE_ADDED, report.CHANGE_NON_BREAKING, op, "response "+hr.Status, "response %s was added", hr.Status)
+		}
+	}
+}
+
+func (c *comparer) content(op, loc string, dir direction, b, h map[string]openapi.MediaType) {
+	for _, mt := range sortedKeys(b) {
+		hm, ok := h[mt]
+		if !ok {
+			c.add(CHANGE_MEDIA_TYPE_REMOVED, report.CHANGE_BREAKING, op, loc, "media type %s was removed", mt)
+			continue
+		}
+		c.schema(op, loc+" "+mt, dir, b[mt].Schema, hm.Schema, "", map[[2]*openapi.Schema]bool{})
+	}
+	for _, mt := range sortedKeys(h) {
+		if _, ok := b[mt]; !ok {
+			c.add(CHANGE_MEDIA_TYPE_ADDED, report.CHANGE_NON_BREAKING, op, loc, "media type %s was added", mt)
+		}
+	}
+}
+
+// security compares the alternatives a client may satisfy. Dropping an
+// alternative (including anonymous access) locks existing clients out.
+func (c *comparer) security(op string, b, h []openapi.SecurityRequirement) {
+	alts := func(reqs []openapi.SecurityRequirement) map[string]bool {
+		out := map[string]bool{}
+		if len(reqs) == 0 {
+			out["anonymous"] = true
+		}
+		for _, r := range reqs {
+			out[securityKey(r)] = true
+		}
+		return out
+	}
+	ba, ha := alts(b), alts(h)
+	for _, k := range sortedKeys(ba) {
+		if !ha[k] {
+			c.add(CHANGE_SECURITY_REMOVED, report.CHANGE_BREAKING, op, "security", "security alternative %s is no longer accepted", k)
+		}
+	}
+	for _, k := range sortedKeys(ha) {
+		if !ba[k] {
+			c.add(CHANGE_SECURITY_ADDED, report.CHANGE_NON_BREAKING, op, "security", "security alternative %s was added", k)
+		}
+	}
+}
+
+// securityKey renders a requirement as "a[scope1,scope2]+b".
+func securityKey(r openapi.SecurityRequirement) string {
+	if len(r) == 0 {
+		return "anonymous"
+	}
+	parts := make([]string, 0, len(r))
+	for _, name := range sortedKeys(r) {
+		scopes := slices.Clone(r[name])
+		sort.Strings(scopes)
+		if len(scopes) > 0 {
+			name += "[" + strings.Join(scopes, ",") + "]"
+		}
+		parts = append(parts, name)
+	}
+	return strings.Join(parts, "+")
+}
+
+// schema compares two schemas in place. ptr is the JSON-pointer-like path
+// below the media type or parameter; seen guards recursive schemas.
+func (c *comparer) schema(op, loc string, dir direction, b, h *openapi.Schema, ptr string, seen map[[2]*openapi.Schema]bool) {
+	if b == nil || h == nil || seen[[2]*openapi.Schema{b, h}] {
+		return
+	}
+	seen[[2]*openapi.Schema{b, h}] = true
+
+	where := loc
+	if ptr != "" {
+		where += ": " + ptr
+	}
+	field := "value"
+	if ptr != "" {
+		field = strings.TrimPrefix(ptr, "/")
+	}
+
+	if len(b.Type) > 0 && len(h.Type) > 0 && !typesCompatible(dir, b.Type, h.Type) {
+		c.add(CHANGE_TYPE_CHANGED, report.CHANGE_BREAKING, op, where, "type of %s changed from %s to %s",
+			field, strings.Join(b.Type, "|"), strings.Join(h.Type, "|"))
+	}
+	if b.Format != "" && h.Format != "" && b.Format != h.Format {
+		c.add(CHANGE_FORMAT_CHANGED, report.CHANGE_BREAKING, op, where, "format of %s changed from %s to %s", field, b.Format, h.Format)
+	}
+	if (dir == dirRequest && b.Nullable && !h.Nullable) || (dir == dirResponse && !b.Nullable && h.Nullable) {
+		c.add(CHANGE_NULLABLE_CHANGED, report.CHANGE_BREAKING, op, where, "%s nullable changed from %t to %t", field, b.Nullable, h.Nullable)
+	}
+	c.enum(op, where, field, dir, b, h)
+	if dir == dirRequest {
+		c.constraints(op, where, field, b, h)
+	}
+	c.properties(op, loc, dir, b, h, ptr, seen)
+
+	c.schema(op, loc, dir, b.Items, h.Items, ptr+"/items", seen)
+	c.schema(op, loc, dir, b.AdditionalProperties, h.AdditionalProperties, ptr+"/additionalProperties", seen)
+}
+
+// typesCompatible reports whether head can stand in for base: requests may
+// widen (accept more), responses may narrow (return less). "integer" is a
+// subset of "number".
+func typesCompatible(dir direction, base, head []string) bool {
+	covers := func(set []string, t string) bool {
+		return slices.Contains(set, t) || (t == "integer" && slices.Contains(set, "number"))
+	}
+	sub, super := base, head
+	if dir == dirResponse {
+		sub, super = head, base
+	}
+	for _, t := range sub {
+		if !covers(super, t) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *comparer) enum(op, where, field string, dir direction, b, h *openapi.Schema) {
+	if len(b.Enum) == 0 && len(h.Enum) == 0 {
+		return
+	}
+	key := func(v any) string { return fmt.Sprintf("%#v", v) }
+	bset, hset := map[string]any{}, map[string]any{}
+	for _, v := range b.Enum {
+		bset[key(v)] = v
+	}
+	for _, v := range h.Enum {
+		hset[key(v)] = v
+	}
+
+	// An enum appearing on a previously open request field restricts it.
+	if len(b.Enum) == 0 {
+		if dir == dirRequest {
+			c.add(CHANGE_CONSTRAINT_TIGHTENED, report.CHANGE_BREAKING, op, where, "%s is now restricted to an enum", field)
+		}
+		return
+	}
+	if len(h.Enum) == 0 {
+		return
+	}
+	for _, k := range sortedKeys(bset) {
+		if _, ok := hset[k]; !ok {
+			level := report.CHANGE_NON_BREAKING
+			if dir == dirRequest {
+				level = report.CHANGE_BREAKING
+			}
+			c.add(CHANGE_ENUM_VALUE_REMOVED, level, op, where, "enum value %v was removed from %s", bset[k], field)
+		}
+	}
+	for _, k := range sortedKeys(hset) {
+		if _, ok := bset[k]; !ok {
+			// Clients with exhaustive switches may not handle new response values.
+			level := report.CHANGE_NON_BREAKING
+			if dir == dirResponse {
+				level = report.CHANGE_BREAKING
+			}
+			c.add(CHANGE_ENUM_VALUE_ADDED, level, op, where, "enum value %v was added to %s", hset[k], field)
+		}
+	}
+}
+
+// constraints flags request validation that now rejects previously valid input.
+func (c *comparer) constraints(op, where, field string, b, h *openapi.Schema) {
+	tightened := func(what string) {
+		c.add(CHANGE_CONSTRAINT_TIGHTENED, report.CHANGE_BREAKING, op, where, "%s of %s was tightened", what, field)
+	}
+	if lowered(b.MaxLength, h.MaxLength) {
+		tightened("maxLength")
+	}
+	if h.MinLength > b.MinLength {
+		tightened("minLength")
+	}
+	if lowered(b.MaxItems, h.MaxItems) {
+		tightened("maxItems")
+	}
+	if h.MinItems > b.MinItems {
+		tightened("minItems")
+	}
+	if lowered(b.Maximum, h.Maximum) {
+		tightened("maximum")
+	}
+	if raised(b.Minimum, h.Minimum) {
+		tightened("minimum")
+	}
+	if h.Pattern != "" && h.Pattern != b.Pattern {
+		tightened("pattern")
+	}
+}
+
+// lowered reports whether an upper bound appeared or decreased.
+func lowered[T uint64 | float64](b, h *T) bool {
+	return h != nil && (b == nil || *h < *b)
+}
+
+// raised reports whether a lower bound appeared or increased.
+func raised[T uint64 | float64](b, h *T) bool {
+	return h != nil && (b == nil || *h > *b)
+}
+
+func (c *comparer) properties(op, loc string, dir direction, b, h *openapi.Schema, ptr string, seen map[[2]*openapi.Schema]bool) {
+	bprops, breq := flattenObject(b)
+	hprops, hreq := flattenObject(h)
+	if len(bprops) == 0 && len(hprops) == 0 {
+		return
+	}
+	where := loc
+	if ptr != "" {
+		where += ": " + ptr
+	}
+
+	for _, name := range sortedKeys(bprops) {
+		field := strings.TrimPrefix(ptr+"/"+name, "/")
+		hp, ok := hprops[name]
+		if !ok {
+			level := report.CHANGE_NON_BREAKING
+			if dir == dirResponse {
+				level = report.CHANGE_BREAKING
+			}
+			c.add(CHANGE_PROPERTY_REMOVED, level, op, where, "property %s was removed", field)
+			continue
+		}
+		switch {
+		case !breq[name] && hreq[name]:
+			level := report.CHANGE_NON_BREAKING
+			if dir == dirRequest {
+				level = report.CHANGE_BREAKING
+			}
+			c.add(CHANGE_PROPERTY_REQUIRED, level, op, where, "property %s became required", field)
+		case breq[name] && !hreq[name]:
+			level := report.CHANGE_NON_BREAKING
+			if dir == dirResponse {
+				level = report.CHANGE_BREAKING
+			}
+			c.add(CHANGE_PROPERTY_OPTIONAL, level, op, where, "property %s became optional", field)
+		}
+		if bp := bprops[name]; bp != nil && hp != nil && !bp.Deprecated && hp.Deprecated {
+			c.add(CHANGE_PROPERTY_DEPRECATED, report.CHANGE_NON_BREAKING, op, where, "property %s was deprecated", field)
+		}
+		c.schema(op, loc, dir, bprops[name], hp, ptr+"/"+name, seen)
+	}
+	for _, name := range sortedKeys(hprops) {
+		if _, ok := bprops[name]; ok {
+			continue
+		}
+		field := strings.TrimPrefix(ptr+"/"+name, "/")
+		if dir == dirRequest && hreq[name] {
+			c.add(CHANGE_PROPERTY_ADDED_REQUIRED, report.CHANGE_BREAKING, op, where, "required property %s was added", field)
+		} else {
+			c.add(CHANGE_PROPERTY_ADDED, report.CHANGE_NON_BREAKING, op, where, "property %s was added", field)
+		}
+	}
+}
+
+// flattenObject merges properties and required lists across allOf members,
+// which is how most specs compose object schemas.
+func flattenObject(s *openapi.Schema) (map[string]*openapi.Schema, map[string]bool) {
+	props, req := map[string]*openapi.Schema{}, map[string]bool{}
+	var walk func(s *openapi.Schema, depth int)
+	walk = func(s *openapi.Schema, depth int) {
+		if s == nil || depth > 16 {
+			return
+		}
+		for name, p := range s.Properties {
+			if _, ok := props[name]; !ok {
+				props[name] = p
+			}
+		}
+		for _, r := range s.Required {
+			req[r] = true
+		}
+		for _, m := range s.AllOf {
+			walk(m, depth+1)
+		}
+	}
+	walk(s, 0)
+	return props, req
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,361 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+	"github.com/betoth/contractcheck/internal/application/service"
+)
+
+// modelLoader serves prebuilt models by path.
+type modelLoader struct {
+	docs map[string]*openapi.Document
+}
+
+func (l modelLoader) Load(_ context.Context, path string) (openapi.OpenAPIDoc, error) {
+	doc, ok := l.docs[path]
+	if !ok {
+		return openapi.OpenAPIDoc{}, openapi.NewValidationError(openapi.FILE_NOT_FOUND, "File not found", path, errors.New("missing"))
+	}
+	return openapi.OpenAPIDoc{Version: "3.0.3", Model: doc}, nil
+}
+
+// fakeRevisions maps revisions to commits and materializes to fixed paths.
+type fakeRevisions struct {
+	commits   map[string]string
+	mergeBase string
+	mergeArgs []string
+}
+
+func (f *fakeRevisions) Resolve(_ context.Context, _, rev string) (string, error) {
+	if c, ok := f.commits[rev]; ok {
+		return c, nil
+	}
+	return "", openapi.NewValidationError(openapi.UNKNOWN_REVISION, "Unknown revision", rev, errors.New("bad rev"))
+}
+
+func (f *fakeRevisions) MergeBase(_ context.Context, _, a, b string) (string, error) {
+	f.mergeArgs = []string{a, b}
+	return f.mergeBase, nil
+}
+
+func (f *fakeRevisions) Materialize(_ context.Context, _, rev, path string) (output.Checkout, func(), error) {
+	dir := "/checkout/" + rev
+	return output.Checkout{Dir: dir, Root: dir + "/" + path, Commit: rev}, func() {}, nil
+}
+
+func object(required []string, props map[string]*openapi.Schema) *openapi.Schema {
+	return &openapi.Schema{Type: []string{"object"}, Required: required, Properties: props}
+}
+
+func str() *openapi.Schema { return &openapi.Schema{Type: []string{"string"}} }
+
+func petAPI(mutate func(*openapi.Document)) *openapi.Document {
+	pet := object([]string{"id", "name"}, map[string]*openapi.Schema{
+		"id":     {Type: []string{"integer"}},
+		"name":   str(),
+		"status": {Type: []string{"string"}, Enum: []any{"available", "sold"}},
+	})
+	doc := &openapi.Document{
+		Operations: []openapi.Operation{
+			{
+				Path: "/pets", Method: "POST",
+				RequestBody: &openapi.RequestBody{Required: true, Content: map[string]openapi.MediaType{
+					"application/json": {Schema: object([]string{"name"}, map[string]*openapi.Schema{"name": str(), "status": {Type: []string{"string"}, Enum: []any{"available", "sold"}}})},
+				}},
+				Responses: []openapi.Response{{Status: "201"}},
+			},
+			{
+				Path: "/pets/{id}", Method: "GET",
+				Parameters: []openapi.Parameter{{Name: "id", In: openapi.PARAM_IN_PATH, Required: true, Schema: str()}},
+				Responses: []openapi.Response{{Status: "200", Content: map[string]openapi.MediaType{
+					"application/json": {Schema: pet},
+				}}},
+			},
+			{Path: "/pets/{id}", Method: "DELETE", Responses: []openapi.Response{{Status: "204"}}},
+		},
+	}
+	if mutate != nil {
+		mutate(doc)
+	}
+	openapi.SortOperations(doc.Operations)
+	return doc
+}
+
+func newCompareService(t *testing.T, docs map[string]*openapi.Document, revisions output.RevisionSource, sandboxes *[]string) *service.OpenAPICompareService {
+	t.Helper()
+	svc, err := service.NewOpenAPICompareService(service.OpenAPICompareParams{
+		LoaderFor: func(sandbox string) openapi.Loader {
+			if sandboxes != nil {
+				*sandboxes = append(*sandboxes, sandbox)
+			}
+			return modelLoader{docs: docs}
+		},
+		Revisions:     revisions,
+		Logger:        nopLogger{},
+		VersionPolicy: service.NewOpenAPIVersionPolicy([]int{3}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svc
+}
+
+func changeIDs(changes []report.Change) []string {
+	ids := make([]string, 0, len(changes))
+	for _, c := range changes {
+		ids = append(ids, c.Operation+" "+c.ID+" "+string(c.Level))
+	}
+	return ids
+}
+
+func TestOpenAPICompareService_ClassifiesChanges(t *testing.T) {
+	head := petAPI(func(d *openapi.Document) {
+		// Renaming a path variable is not a change; dropping DELETE is.
+		d.Operations = d.Operations[:2]
+		get := &d.Operations[1]
+		get.Path = "/pets/{petId}"
+		get.Parameters[0].Name = "petId"
+		get.Parameters = append(get.Parameters, openapi.Parameter{Name: "fields", In: openapi.PARAM_IN_QUERY, Schema: str()})
+		pet := get.Responses[0].Content["application/json"].Schema
+		delete(pet.Properties, "name")
+		pet.Properties["status"].Enum = append(pet.Properties["status"].Enum, "pending")
+
+		post := &d.Operations[0]
+		body := post.RequestBody.Content["application/json"].Schema
+		body.Properties["status"].Enum = []any{"available"}
+		body.Properties["tag"] = str()
+	})
+	svc := newCompareService(t, map[string]*openapi.Document{
+		"specs/base.yaml": petAPI(nil),
+		"specs/head.yaml": head,
+	}, nil, nil)
+
+	cmp, err := svc.Compare(context.Background(), "specs/base.yaml", "specs/head.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"DELETE /pets/{id} operation-removed breaking",
+		"GET /pets/{petId} enum-value-added breaking",
+		"GET /pets/{petId} parameter-added non-breaking",
+		"GET /pets/{petId} property-removed breaking",
+		"POST /pets enum-value-removed breaking",
+		"POST /pets property-added non-breaking",
+	}
+	got := changeIDs(cmp.Changes)
+	slices.Sort(got)
+	if !slices.Equal(got, want) {
+		t.Fatalf("changes:\n got %q\nwant %q", got, want)
+	}
+	if cmp.Base != "specs/base.yaml" || cmp.Head != "specs/head.yaml" {
+		t.Fatalf("labels = %q -> %q", cmp.Base, cmp.Head)
+	}
+	if n := len(cmp.Breaking()); n != 4 {
+		t.Fatalf("breaking = %d, want 4", n)
+	}
+}
+
+func TestChangeRules_SeverityMatchesEmittedLevel(t *testing.T) {
+	// petAPI builds POST /pets, GET /pets/{id} and DELETE /pets/{id}, in that
+	// order before sorting. Direction-dependent changes use the usual side.
+	reqBody := func(d *openapi.Document) *openapi.Schema {
+		return d.Operations[0].RequestBody.Content["application/json"].Schema
+	}
+	pet := func(d *openapi.Document) *openapi.Schema {
+		return d.Operations[1].Responses[0].Content["application/json"].Schema
+	}
+	query := func(required bool) func(*openapi.Document) {
+		return func(d *openapi.Document) {
+			d.Operations[1].Parameters = append(d.Operations[1].Parameters, openapi.Parameter{Name: "q", In: openapi.PARAM_IN_QUERY, Required: required, Schema: str()})
+		}
+	}
+	cases := map[string]struct{ base, head func(*openapi.Document) }{
+		service.CHANGE_OPERATION_REMOVED: {head: func(d *openapi.Document) { d.Operations = d.Operations[:2] }},
+		service.CHANGE_OPERATION_ADDED: {head: func(d *openapi.Document) {
+			d.Operations = append(d.Operations, openapi.Operation{Path: "/pets", Method: "GET", Responses: []openapi.Response{{Status: "200"}}})
+		}},
+		service.CHANGE_OPERATION_DEPRECATED:     {head: func(d *openapi.Document) { d.Operations[2].Deprecated = true }},
+		service.CHANGE_PARAMETER_ADDED_REQUIRED: {head: query(true)},
+		service.CHANGE_PARAMETER_ADDED:          {head: query(false)},
+		service.CHANGE_PARAMETER_REMOVED:        {base: query(false)},
+		service.CHANGE_PARAMETER_REQUIRED:       {base: query(false), head: query(true)},
+		service.CHANGE_PARAMETER_OPTIONAL:       {base: query(true), head: query(false)},
+		service.CHANGE_PARAMETER_DEPRECATED: {base: query(false), head: func(d *openapi.Document) {
+			query(false)(d)
+			d.Operations[1].Parameters[1].Deprecated = true
+		}},
+		service.CHANGE_REQUEST_BODY_ADDED: {head: func(d *openapi.Document) {
+			d.Operations[2].RequestBody = &openapi.RequestBody{Content: map[string]openapi.MediaType{"application/json": {Schema: str()}}}
+		}},
+		service.CHANGE_REQUEST_BODY_REMOVED:  {head: func(d *openapi.Document) { d.Operations[0].RequestBody = nil }},
+		service.CHANGE_REQUEST_BODY_REQUIRED: {base: func(d *openapi.Document) { d.Operations[0].RequestBody.Required = false }},
+		service.CHANGE_MEDIA_TYPE_REMOVED: {head: func(d *openapi.Document) {
+			delete(d.Operations[0].RequestBody.Content, "application/json")
+		}},
+		service.CHANGE_MEDIA_TYPE_ADDED: {head: func(d *openapi.Document) {
+			d.Operations[0].RequestBody.Content["application/xml"] = openapi.MediaType{Schema: str()}
+		}},
+		service.CHANGE_RESPONSE_REMOVED: {head: func(d *openapi.Document) { d.Operations[0].Responses = nil }},
+		service.CHANGE_RESPONSE_ADDED: {head: func(d *openapi.Document) {
+			d.Operations[2].Responses = append(d.Operations[2].Responses, openapi.Response{Status: "404"})
+		}},
+		service.CHANGE_RESPONSE_HEADER_REMOVED: {base: func(d *openapi.Document) {
+			d.Operations[2].Responses[0].Headers = map[string]openapi.Header{"X-Request-Id": {}}
+		}},
+		service.CHANGE_SECURITY_ADDED: {head: func(d *openapi.Document) {
+			d.Operations[2].Security = []openapi.SecurityRequirement{{}, {"apiKey": nil}}
+		}},
+		service.CHANGE_SECURITY_REMOVED: {head: func(d *openapi.Document) {
+			d.Operations[2].Security = []openapi.SecurityRequirement{{"apiKey": nil}}
+		}},
+		service.CHANGE_TYPE_CHANGED:       {head: func(d *openapi.Document) { pet(d).Properties["id"].Type = []string{"string"} }},
+		service.CHANGE_FORMAT_CHANGED:     {base: func(d *openapi.Document) { pet(d).Properties["id"].Format = "int32" }, head: func(d *openapi.Document) { pet(d).Properties["id"].Format = "int64" }},
+		service.CHANGE_NULLABLE_CHANGED:   {head: func(d *openapi.Document) { pet(d).Properties["name"].Nullable = true }},
+		service.CHANGE_ENUM_VALUE_REMOVED: {head: func(d *openapi.Document) { reqBody(d).Properties["status"].Enum = []any{"available"} }},
+		service.CHANGE_ENUM_VALUE_ADDED: {head: func(d *openapi.Document) {
+			reqBody(d).Properties["status"].Enum = append(reqBody(d).Properties["status"].Enum, "pending")
+		}},
+		service.CHANGE_CONSTRAINT_TIGHTENED: {head: func(d *openapi.Document) { reqBody(d).Properties["name"].MinLength = 1 }},
+		service.CHANGE_PROPERTY_REMOVED:     {head: func(d *openapi.Document) { delete(pet(d).Properties, "name") }},
+		service.CHANGE_PROPERTY_ADDED:       {head: func(d *openapi.Document) { reqBody(d).Properties["tag"] = str() }},
+		service.CHANGE_PROPERTY_ADDED_REQUIRED: {head: func(d *openapi.Document) {
+			reqBody(d).Properties["tag"] = str()
+			reqBody(d).Required = append(reqBody(d).Required, "tag")
+		}},
+		service.CHANGE_PROPERTY_REQUIRED: {head: func(d *openapi.Document) {
+			reqBody(d).Required = append(reqBody(d).Required, "status")
+		}},
+		service.CHANGE_PROPERTY_OPTIONAL:   {head: func(d *openapi.Document) { reqBody(d).Required = nil }},
+		service.CHANGE_PROPERTY_DEPRECATED: {head: func(d *openapi.Document) { reqBody(d).Properties["name"].Deprecated = true }},
+	}
+
+	for _, rule := range service.ChangeRules() {
+		t.Run(rule.ID, func(t *testing.T) {
+			tc, ok := cases[rule.ID]
+			if !ok {
+				t.Fatalf("no case triggers %s", rule.ID)
+			}
+			svc := newCompareService(t, map[string]*openapi.Document{
+				"base.yaml": petAPI(tc.base),
+				"head.yaml": petAPI(tc.head),
+			}, nil, nil)
+			cmp, err := svc.Compare(context.Background(), "base.yaml", "head.yaml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			i := slices.IndexFunc(cmp.Changes, func(c report.Change) bool { return c.ID == rule.ID })
+			if i < 0 {
+				t.Fatalf("%s not emitted, got %q", rule.ID, changeIDs(cmp.Changes))
+			}
+			if got := cmp.Changes[i].Finding("head.yaml").Severity; got != rule.Severity {
+				t.Fatalf("%s is emitted as %s, but ChangeRules says %s", rule.ID, got, rule.Severity)
+			}
+		})
+	}
+}
+
+func TestOpenAPICompareService_IdenticalSpecsHaveNoChanges(t *testing.T) {
+	svc := newCompareService(t, map[string]*openapi.Document{
+		"a.yaml": petAPI(nil),
+		"b.yaml": petAPI(nil),
+	}, nil, nil)
+	cmp, err := svc.Compare(context.Background(), "a.yaml", "b.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cmp.Changes) != 0 {
+		t.Fatalf("unexpected changes: %q", changeIDs(cmp.Changes))
+	}
+}
+
+func TestOpenAPICompareService_CompareGitUsesMergeBase(t *testing.T) {
+	const (
+		headCommit = "1111111111111111111111111111111111111111"
+		baseCommit = "2222222222222222222222222222222222222222"
+	)
+	revisions := &fakeRevisions{
+		commits:   map[string]string{"HEAD": headCommit},
+		mergeBase: baseCommit,
+	}
+	var sandboxes []string
+	svc := newCompareService(t, map[string]*openapi.Document{
+		"/checkout/" + baseCommit + "/api/openapi.yaml": petAPI(nil),
+		"/checkout/" + headCommit + "/api/openapi.yaml": petAPI(func(d *openapi.Document) {
+			d.Operations = d.Operations[1:]
+		}),
+	}, revisions, &sandboxes)
+
+	cmp, err := svc.CompareGit(context.Background(), input.GitCompareRequest{Path: "api/openapi.yaml", Target: "main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(revisions.mergeArgs, []string{headCommit, "main"}) {
+		t.Fatalf("merge-base args = %q", revisions.mergeArgs)
+	}
+	if cmp.Base != "222222222222:api/openapi.yaml" || cmp.Head != "111111111111:api/openapi.yaml" {
+		t.Fatalf("labels = %q -> %q", cmp.Base, cmp.Head)
+	}
+	if !slices.Equal(sandboxes, []string{"/checkout/" + baseCommit, "/checkout/" + headCommit}) {
+		t.Fatalf("sandboxes = %q", sandboxes)
+	}
+	if got := changeIDs(cmp.Changes); !slices.Equal(got, []string{"POST /pets operation-removed breaking"}) {
+		t.Fatalf("changes = %q", got)
+	}
+}
+
+func TestOpenAPICompareService_CompareGitErrors(t *testing.T) {
+	revisions := &fakeRevisions{commits: map[string]string{}}
+	svc := newCompareService(t, nil, revisions, nil)
+
+	_, err := svc.CompareGit(context.Background(), input.GitCompareRequest{Path: "openapi.yaml"})
+	var ae *customerrors.AppError
+	if !errors.As(err, &ae) || ae.Type != customerrors.VALIDATION_ERROR {
+		t.Fatalf("missing target: err = %v", err)
+	}
+
+	_, err = svc.CompareGit(context.Background(), input.GitCompareRequest{Path: "openapi.yaml", Target: "main", Head: "nope"})
+	if !errors.As(err, &ae) || ae.Details[customerrors.DetailKind] != string(openapi.UNKNOWN_REVISION) {
+		t.Fatalf("unknown revision: err = %v", err)
+	}
+
+	noGit := newCompareService(t, nil, nil, nil)
+	if _, err := noGit.CompareGit(context.Background(), input.GitCompareRequest{Path: "openapi.yaml", Target: "main"}); err == nil {
+		t.Fatal("expected dependency error without a revision source")
+	}
+}
+
+func TestOpenAPICompareService_BaselineCheckDiffsAgainstLoadedBase(t *testing.T) {
+	const commit = "3333333333333333333333333333333333333333"
+	revisions := &fakeRevisions{commits: map[string]string{"main": commit}}
+	svc := newCompareService(t, map[string]*openapi.Document{
+		"base.yaml": petAPI(nil),
+		"/checkout/" + commit + "//repo/api/openapi.yaml": petAPI(nil),
+	}, revisions, nil)
+	head := openapi.OpenAPIDoc{Model: petAPI(func(d *openapi.Document) { d.Operations = d.Operations[1:] })}
+
+	for _, base := range []input.Baseline{{File: "base.yaml"}, {Rev: "main"}} {
+		check, err := svc.BaselineCheck(context.Background(), "/repo/api/openapi.yaml", base)
+		if err != nil {
+			t.Fatalf("%+v: %v", base, err)
+		}
+		findings, err := check.Check(context.Background(), head)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if check.Name() != report.CHECK_COMPARE || len(findings) != 1 || findings[0].RuleID != "operation-removed" || findings[0].Severity != report.SEVERITY_ERROR {
+			t.Fatalf("%+v: findings = %+v", base, findings)
+		}
+	}
+
+	if _, err := svc.BaselineCheck(context.Background(), "/repo/api/openapi.yaml", input.Baseline{Rev: "nope"}); err == nil {
+		t.Fatal("expected an unknown revision to fail")
+	}
+}
@@ -0,0 +1,546 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+	"github.com/betoth/contractcheck/internal/application/ports/output/traffic"
+)
+
+// maxCoverageDepth bounds how deep body schemas are searched for enums, so
+// recursive schemas stay finite.
+const maxCoverageDepth = 5
+
+// maxOperationIssues caps the distinct problems listed per operation.
+const maxOperationIssues = 10
+
+// CoverageParams declares the dependencies required to build the service.
+type CoverageParams struct {
+	Importer input.ImportOpenAPISpec
+	Traffic  traffic.Reader
+	Logger   output.Logger
+}
+
+// validate performs defensive checks on constructor params.
+func (p CoverageParams) validate() error {
+	if p.Importer == nil {
+		return customerrors.NewDependencyError("importer")
+	}
+	if p.Traffic == nil {
+		return customerrors.NewDependencyError("traffic")
+	}
+	if p.Logger == nil {
+		return customerrors.NewDependencyError("logger")
+	}
+	return nil
+}
+
+// CoverageService measures how much of a spec recorded traffic exercises
+// (input port implementation).
+type CoverageService struct {
+	importer input.ImportOpenAPISpec
+	traffic  traffic.Reader
+	logger   output.Logger
+}
+
+// NewCoverageService constructs the service after validating dependencies.
+func NewCoverageService(params CoverageParams) (*CoverageService, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	return &CoverageService{importer: params.Importer, traffic: params.Traffic, logger: params.Logger}, nil
+}
+
+// Coverage imports the spec at specPath and matches every exchange of the
+// traffic files to its operations. Unlike a Pact file, a traffic file that
+// cannot be read fails the call: coverage of part of the traffic would
+// understate what was exercised.
+func (s *CoverageService) Coverage(ctx context.Context, specPath string, trafficPaths []string) (report.Coverage, error) {
+	log := s.logger.With("local", "service.CoverageService.Coverage")
+	if len(trafficPaths) == 0 {
+		return report.Coverage{}, customerrors.NewValidationError(
+			"No traffic files", errors.New("at least one traffic file is required"), nil)
+	}
+
+	doc, err := s.importer.Import(ctx, specPath)
+	if err != nil {
+		log.Error("failed to import spec", "file", specPath)
+		return report.Coverage{}, err
+	}
+	if doc.Model == nil {
+		return report.Coverage{}, customerrors.NewDependencyError("loader model")
+	}
+
+	t := newCoverageTracker(doc.Model)
+	out := report.Coverage{Spec: specPath, Sources: trafficPaths}
+	for _, path := range trafficPaths {
+		exchanges, err := s.traffic.Read(ctx, path)
+		if err != nil {
+			log.Error("failed to read traffic", "file", path)
+			return report.Coverage{}, err
+		}
+		for _, ex := range exchanges {
+			t.record(ex)
+		}
+		out.Exchanges += len(exchanges)
+	}
+	out = t.report(out)
+	log.Debug("coverage measured",
+		"file", specPath,
+		"exchanges", out.Exchanges,
+		"operations", out.Totals.Operations.Percent,
+		"unmatched", len(out.Unmatched),
+	)
+	return out, nil
+}
+
+// coverageTracker tallies exchanges per operation of one document.
+type coverageTracker struct {
+	routes    routes
+	verify    runner
+	tallies   []*operationTally // in document order
+	byOp      map[*openapi.Operation]*operationTally
+	unmatched map[string]*report.UnmatchedRequest
+}
+
+// operationTally is the coverage of one operation being built, with
+// indexes into its slices.
+type operationTally struct {
+	cov        report.OperationCoverage
+	statuses   map[string]int   // declared status -> index in cov.Statuses
+	parameters map[int]int      // index in op.Parameters -> index in cov.Parameters
+	enums      map[string]int   // location -> index in cov.Enums
+	values     []map[string]int // per enum: value key -> index in Values
+	paramEnums map[int]string   // index in op.Parameters -> enum location
+	issues     map[string]bool
+}
+
+func newCoverageTracker(doc *openapi.Document) *coverageTracker {
+	t := &coverageTracker{
+		routes:    newRoutes(doc),
+		verify:    runner{doc: doc},
+		byOp:      map[*openapi.Operation]*operationTally{},
+		unmatched: map[string]*report.UnmatchedRequest{},
+	}
+	for i := range doc.Operations {
+		op := &doc.Operations[i]
+		tally := newOperationTally(op)
+		t.tallies = append(t.tallies, tally)
+		t.byOp[op] = tally
+	}
+	return t
+}
+
+// newOperationTally lists what can be covered of op: its declared
+// statuses, its parameters and every enum of its parameters and JSON
+// bodies.
+func newOperationTally(op *openapi.Operation) *operationTally {
+	t := &operationTally{
+		cov: report.OperationCoverage{
+			Operation:  op.Key(),
+			Path:       op.Path,
+			Method:     op.Method,
+			Statuses:   []report.StatusCoverage{},
+			Parameters: []report.ParameterCoverage{},
+			Enums:      []report.EnumCoverage{},
+		},
+		statuses:   map[string]int{},
+		parameters: map[int]int{},
+		enums:      map[string]int{},
+		paramEnums: map[int]string{},
+		issues:     map[string]bool{},
+	}
+	for _, r := range op.Responses {
+		t.statuses[r.Status] = len(t.cov.Statuses)
+		t.cov.Statuses = append(t.cov.Statuses, report.StatusCoverage{Status: r.Status})
+	}
+	for i, p := range op.Parameters {
+		if p.In == openapi.PARAM_IN_HEADER && ignoredHeaders[strings.ToLower(p.Name)] {
+			continue
+		}
+		t.parameters[i] = len(t.cov.Parameters)
+		t.cov.Parameters = append(t.cov.Parameters, report.ParameterCoverage{Name: p.Name, In: p.In})
+		if s := enumSchema(p.Schema); s != nil {
+			loc := parameterLocation(p.In, p.Name)
+			t.paramEnums[i] = loc
+			t.addEnum(loc, s)
+		}
+	}
+	if op.RequestBody != nil {
+		for _, mediaType := range sortedKeys(op.RequestBody.Content) {
+			if isJSONMediaType(mediaType) {
+				walkEnums(op.RequestBody.Content[mediaType].Schema, "", 0, func(pointer string, s *openapi.Schema) {
+					t.addEnum(bodyLocation("request body", pointer), s)
+				})
+			}
+		}
+	}
+	for _, r := range op.Responses {
+		for _, mediaType := range sortedKeys(r.Content) {
+			if isJSONMediaType(mediaType) {
+				walkEnums(r.Content[mediaType].Schema, "", 0, func(pointer string, s *openapi.Schema) {
+					t.addEnum(responseLocation(r.Status, pointer), s)
+				})
+			}
+		}
+	}
+	return t
+}
+
+// addEnum registers the values of an enum at loc; the first schema found
+// for a location wins.
+func (t *operationTally) addEnum(loc string, s *openapi.Schema) {
+	if _, ok := t.enums[loc]; ok {
+		return
+	}
+	e := report.EnumCoverage{Location: loc, Values: []report.EnumValueCoverage{}}
+	index := map[string]int{}
+	for _, v := range s.Enum {
+		key := enumKey(v)
+		if _, dup := index[key]; dup {
+			continue
+		}
+		index[key] = len(e.Values)
+		e.Values = append(e.Values, report.EnumValueCoverage{Value: enumLabel(v)})
+	}
+	t.enums[loc] = len(t.cov.Enums)
+	t.cov.Enums = append(t.cov.Enums, e)
+	t.values = append(t.values, index)
+}
+
+// record matches ex to an operation and counts what it exercised.
+// Exchanges whose response breaks the contract cover nothing.
+func (t *coverageTracker) record(ex traffic.Exchange) {
+	u, err := url.Parse(ex.Request.URL)
+	if err != nil {
+		u = &url.URL{Path: ex.Request.URL}
+	}
+	op, vars, _ := t.routes.match(ex.Request.Method, u.EscapedPath())
+	if op == nil {
+		key := ex.Request.Method + " " + u.Path
+		if t.unmatched[key] == nil {
+			t.unmatched[key] = &report.UnmatchedRequest{Method: ex.Request.Method, Path: u.Path}
+		}
+		t.unmatched[key].Calls++
+		return
+	}
+	tally := t.byOp[op]
+
+	invalid := false
+	for _, f := range t.verify.response(*op, "", ex.Response) {
+		if f.Severity == report.SEVERITY_ERROR {
+			invalid = true
+			if len(tally.issues) < maxOperationIssues {
+				tally.issues[f.Message] = true
+			}
+		}
+	}
+	if invalid {
+		tally.cov.Invalid++
+		return
+	}
+	tally.cov.Calls++
+	declared := declaredResponse(op, ex.Response.Status)
+	tally.cov.Statuses[tally.statuses[declared.Status]].Calls++
+
+	used := map[[2]string]bool{}
+	use := func(loc string, v any) {
+		if _, ok := tally.enums[loc]; ok {
+			used[[2]string{loc, enumKey(v)}] = true
+		}
+	}
+	query := u.Query()
+	cookies := parseCookies(ex.Request.Header.Get("Cookie"))
+	for i, p := range op.Parameters {
+		idx, ok := tally.parameters[i]
+		if !ok {
+			continue
+		}
+		var values []string
+		switch p.In {
+		case openapi.PARAM_IN_PATH:
+			if v, ok := vars[p.Name]; ok {
+				values = []string{v}
+			}
+		case openapi.PARAM_IN_QUERY:
+			values = query[p.Name]
+		case openapi.PARAM_IN_HEADER:
+			values = ex.Request.Header.Values(p.Name)
+		case openapi.PARAM_IN_COOKIE:
+			if v, ok := cookies[p.Name]; ok {
+				values = []string{v}
+			}
+		}
+		if len(values) == 0 {
+			continue
+		}
+		tally.cov.Parameters[idx].Calls++
+		if loc, ok := tally.paramEnums[i]; ok {
+			switch v := coerceParameter(p.Schema, values).(type) {
+			case []any:
+				for _, item := range v {
+					use(loc, item)
+				}
+			default:
+				use(loc, v)
+			}
+		}
+	}
+	if op.RequestBody != nil {
+		if s, v, ok := jsonBody(op.RequestBody.Content, ex.Request.Header.Get("Content-Type"), ex.Request.Body); ok {
+			walkEnumValues(s, v, "", 0, func(pointer string, v any) { use(bodyLocation("request body", pointer), v) })
+		}
+	}
+	if s, v, ok := jsonBody(declared.Content, ex.Response.Header.Get("Content-Type"), ex.Response.Body); ok {
+		walkEnumValues(s, v, "", 0, func(pointer string, v any) { use(responseLocation(declared.Status, pointer), v) })
+	}
+
+	for key := range used {
+		e := tally.enums[key[0]]
+		if i, ok := tally.values[e][key[1]]; ok {
+			tally.cov.Enums[e].Values[i].Calls++
+		}
+	}
+}
+
+// report completes out with the tallies: totals per path, per method and
+// overall, and the list of what was never exercised.
+func (t *coverageTracker) report(out report.Coverage) report.Coverage {
+	var all, paths coverageSum
+	byPath := map[string]*coverageSum{}
+	byMethod := map[string]*coverageSum{}
+	var pathOrder []string
+	out.Operations = []report.OperationCoverage{}
+	out.Uncovered = []report.UncoveredItem{}
+	for _, tally := range t.tallies {
+		cov := tally.cov
+		cov.Issues = sortedKeys(tally.issues)
+		out.Operations = append(out.Operations, cov)
+		out.Uncovered = append(out.Uncovered, uncovered(cov)...)
+
+		all.add(cov)
+		if byPath[cov.Path] == nil {
+			byPath[cov.Path] = &coverageSum{}
+			pathOrder = append(pathOrder, cov.Path)
+		}
+		byPath[cov.Path].add(cov)
+		if byMethod[cov.Method] == nil {
+			byMethod[cov.Method] = &coverageSum{}
+		}
+		byMethod[cov.Method].add(cov)
+	}
+
+	out.Totals = all.totals()
+	out.ByPath = []report.PathCoverage{}
+	for _, path := range pathOrder {
+		sum := byPath[path]
+		paths.ops[1]++
+		if sum.ops[0] > 0 {
+			paths.ops[0]++
+		}
+		out.ByPath = append(out.ByPath, report.PathCoverage{Path: path, CoverageTotals: sum.totals()})
+	}
+	out.Paths = report.NewCoverageCount(paths.ops[0], paths.ops[1])
+	out.ByMethod = []report.MethodCoverage{}
+	for _, method := range sortedKeys(byMethod) {
+		out.ByMethod = append(out.ByMethod, report.MethodCoverage{Method: method, CoverageTotals: byMethod[method].totals()})
+	}
+
+	out.Unmatched = []report.UnmatchedRequest{}
+	for _, key := range sortedKeys(t.unmatched) {
+		out.Unmatched = append(out.Unmatched, *t.unmatched[key])
+	}
+	sort.SliceStable(out.Unmatched, func(i, j int) bool { return out.Unmatched[i].Path < out.Unmatched[j].Path })
+	return out
+}
+
+// uncovered lists what cov never exercised; an operation never called is
+// listed alone.
+func uncovered(cov report.OperationCoverage) []report.UncoveredItem {
+	if cov.Calls == 0 {
+		return []report.UncoveredItem{{Operation: cov.Operation, Kind: report.COVERAGE_OPERATION}}
+	}
+	var out []report.UncoveredItem
+	for _, s := range cov.Statuses {
+		if s.Calls == 0 {
+			out = append(out, report.UncoveredItem{Operation: cov.Operation, Kind: report.COVERAGE_STATUS, Item: s.Status})
+		}
+	}
+	for _, p := range cov.Parameters {
+		if p.Calls == 0 {
+			out = append(out, report.UncoveredItem{Operation: cov.Operation, Kind: report.COVERAGE_PARAMETER, Item: parameterLocation(p.In, p.Name)})
+		}
+	}
+	for _, e := range cov.Enums {
+		for _, v := range e.Values {
+			if v.Calls == 0 {
+				out = append(out, report.UncoveredItem{Operation: cov.Operation, Kind: report.COVERAGE_ENUM_VALUE, Item: e.Location + ": " + v.Value})
+			}
+		}
+	}
+	return out
+}
+
+// coverageSum adds up covered and total items, as {covered, total} pairs.
+type coverageSum struct {
+	ops, statuses, params, values [2]int
+}
+
+func (s *coverageSum) add(cov report.OperationCoverage) {
+	s.ops[1]++
+	if cov.Calls > 0 {
+		s.ops[0]++
+	}
+	for _, st := range cov.Statuses {
+		s.statuses[1]++
+		if st.Calls > 0 {
+			s.statuses[0]++
+		}
+	}
+	for _, p := range cov.Parameters {
+		s.params[1]++
+		if p.Calls > 0 {
+			s.params[0]++
+		}
+	}
+	for _, e := range cov.Enums {
+		for _, v := range e.Values {
+			s.values[1]++
+			if v.Calls > 0 {
+				s.values[0]++
+			}
+		}
+	}
+}
+
+func (s coverageSum) totals() report.CoverageTotals {
+	return report.CoverageTotals{
+		Operations: report.NewCoverageCount(s.ops[0], s.ops[1]),
+		Statuses:   report.NewCoverageCount(s.statuses[0], s.statuses[1]),
+		Parameters: report.NewCoverageCount(s.params[0], s.params[1]),
+		EnumValues: report.NewCoverageCount(s.values[0], s.values[1]),
+	}
+}
+
+// jsonBody decodes a JSON body and returns the schema declared for its
+// media type.
+func jsonBody(content map[string]openapi.MediaType, contentType string, body []byte) (*openapi.Schema, any, bool) {
+	if len(body) == 0 {
+		return nil, nil, false
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || !isJSONMediaType(mediaType) {
+		return nil, nil, false
+	}
+	mt, ok := matchMediaType(content, mediaType)
+	if !ok || mt.Schema == nil {
+		return nil, nil, false
+	}
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return nil, nil, false
+	}
+	return mt.Schema, v, true
+}
+
+// walkEnums visits every enum of s, by JSON pointer; "*" stands for the
+// items of an array.
+func walkEnums(s *openapi.Schema, pointer string, depth int, visit func(pointer string, s *openapi.Schema)) {
+	if s == nil || depth > maxCoverageDepth {
+		return
+	}
+	if len(s.Enum) > 0 {
+		visit(pointer, s)
+		return
+	}
+	walkEnums(s.Items, pointer+"/*", depth+1, visit)
+	props, _ := objectProperties(s)
+	for _, name := range sortedKeys(props) {
+		walkEnums(props[name], pointer+"/"+pointerToken(name), depth+1, visit)
+	}
+}
+
+// walkEnumValues visits the values v holds at the enums of s, with the
+// pointers walkEnums gives them.
+func walkEnumValues(s *openapi.Schema, v any, pointer string, depth int, visit func(pointer string, v any)) {
+	if s == nil || depth > maxCoverageDepth {
+		return
+	}
+	if len(s.Enum) > 0 {
+		visit(pointer, v)
+		return
+	}
+	switch v := v.(type) {
+	case []any:
+		for _, item := range v {
+			walkEnumValues(s.Items, item, pointer+"/*", depth+1, visit)
+		}
+	case map[string]any:
+		props, _ := objectProperties(s)
+		for name, item := range v {
+			walkEnumValues(props[name], item, pointer+"/"+pointerToken(name), depth+1, visit)
+		}
+	}
+}
+
+// enumSchema is the schema holding a parameter's enum: its own, or that of
+// its items for arrays.
+func enumSchema(s *openapi.Schema) *openapi.Schema {
+	switch {
+	case s == nil:
+		return nil
+	case len(s.Enum) > 0:
+		return s
+	case s.HasType("array") && s.Items != nil && len(s.Items.Enum) > 0:
+		return s.Items
+	}
+	return nil
+}
+
+func parameterLocation(in, name string) string {
+	return fmt.Sprintf("%s parameter %q", in, name)
+}
+
+func responseLocation(status, pointer string) string {
+	return bodyLocation("response "+status+" body", pointer)
+}
+
+// bodyLocation names an enum of a body: "request body /status", or just
+// "request body" for an enum at the root.
+func bodyLocation(body, pointer string) string {
+	if pointer == "" {
+		return body
+	}
+	return body + " " + pointer
+}
+
+// enumKey compares enum values by their JSON form, so 1 and 1.0 match.
+func enumKey(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// enumLabel shows strings bare and other values as JSON.
+func enumLabel(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return enumKey(v)
+}
+
+// compile-time check
+var _ input.MeasureCoverage = (*CoverageService)(nil)
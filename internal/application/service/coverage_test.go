@@ -0,0 +1,152 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+	"github.com/betoth/contractcheck/internal/application/ports/output/traffic"
+	"github.com/betoth/contractcheck/internal/application/service"
+)
+
+// memTraffic serves recorded exchanges by path.
+type memTraffic map[string][]traffic.Exchange
+
+func (m memTraffic) Read(_ context.Context, path string) ([]traffic.Exchange, error) {
+	ex, ok := m[path]
+	if !ok {
+		return nil, openapi.NewValidationError(openapi.INVALID_HAR, "Invalid HAR file", path, errors.New("bad har"))
+	}
+	return ex, nil
+}
+
+func exchange(method, url string, reqBody string, status int, respBody string) traffic.Exchange {
+	ex := traffic.Exchange{
+		Request:  output.HTTPRequest{Method: method, URL: url, Header: http.Header{}},
+		Response: output.HTTPResponse{Status: status, Header: http.Header{}},
+	}
+	if reqBody != "" {
+		ex.Request.Header.Set("Content-Type", "application/json")
+		ex.Request.Body = []byte(reqBody)
+	}
+	if respBody != "" {
+		ex.Response.Header.Set("Content-Type", "application/json")
+		ex.Response.Body = []byte(respBody)
+	}
+	return ex
+}
+
+func coveragePetAPI() *openapi.Document {
+	return petAPI(func(d *openapi.Document) {
+		d.Servers = []openapi.Server{{URL: "https://api.example.com/v1"}}
+		d.Operations = append(d.Operations, openapi.Operation{
+			Path: "/pets", Method: "GET",
+			Parameters: []openapi.Parameter{
+				{Name: "status", In: openapi.PARAM_IN_QUERY, Schema: &openapi.Schema{Type: []string{"array"}, Items: &openapi.Schema{Type: []string{"string"}, Enum: []any{"available", "sold"}}}},
+				{Name: "limit", In: openapi.PARAM_IN_QUERY, Schema: &openapi.Schema{Type: []string{"integer"}}},
+			},
+			Responses: []openapi.Response{{Status: "200"}, {Status: "4XX"}},
+		})
+	})
+}
+
+func TestCoverageService_Coverage(t *testing.T) {
+	svc, err := service.NewCoverageService(service.CoverageParams{
+		Importer: modelImporter{doc: coveragePetAPI()},
+		Traffic: memTraffic{
+			"browser.har": {
+				exchange("GET", "https://api.example.com/v1/pets?status=available,sold", "", 200, ""),
+				exchange("GET", "https://api.example.com/v1/pets?limit=x", "", 400, ""),
+				exchange("GET", "https://api.example.com/v1/pets/7", "", 200, `{"id":7,"name":"rex","status":"available"}`),
+				exchange("GET", "https://api.example.com/v1/pets/8", "", 500, ""),
+				exchange("GET", "https://api.example.com/v1/health", "", 200, ""),
+			},
+			"tests.har": {
+				exchange("POST", "https://api.example.com/v1/pets", `{"name":"rex","status":"sold"}`, 201, ""),
+				exchange("GET", "https://api.example.com/v1/pets/9", "", 200, `{"id":9}`),
+				exchange("GET", "https://api.example.com/v1/health", "", 200, ""),
+			},
+		},
+		Logger: nopLogger{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cov, err := svc.Coverage(context.Background(), "pets.yaml", []string{"browser.har", "tests.har"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cov.Exchanges != 8 {
+		t.Errorf("exchanges = %d, want 8", cov.Exchanges)
+	}
+	wantUncovered := []report.UncoveredItem{
+		{Operation: "POST /pets", Kind: report.COVERAGE_ENUM_VALUE, Item: "request body /status: available"},
+		{Operation: "DELETE /pets/{id}", Kind: report.COVERAGE_OPERATION},
+		{Operation: "GET /pets/{id}", Kind: report.COVERAGE_ENUM_VALUE, Item: "response 200 body /status: sold"},
+	}
+	if !reflect.DeepEqual(cov.Uncovered, wantUncovered) {
+		t.Errorf("uncovered =\n%+v\nwant\n%+v", cov.Uncovered, wantUncovered)
+	}
+	wantTotals := report.CoverageTotals{
+		Operations: report.NewCoverageCount(3, 4),
+		Statuses:   report.NewCoverageCount(4, 5),
+		Parameters: report.NewCoverageCount(3, 3),
+		EnumValues: report.NewCoverageCount(4, 6),
+	}
+	if cov.Totals != wantTotals || cov.Paths != report.NewCoverageCount(2, 2) {
+		t.Errorf("totals = %+v, paths %+v", cov.Totals, cov.Paths)
+	}
+	if want := (report.CoverageCount{Covered: 4, Total: 6, Percent: 66.7}); cov.Totals.EnumValues != want {
+		t.Errorf("enum values = %+v, want %+v", cov.Totals.EnumValues, want)
+	}
+	if len(cov.ByPath) != 2 || cov.ByPath[1].Path != "/pets/{id}" || cov.ByPath[1].Operations != report.NewCoverageCount(1, 2) {
+		t.Errorf("by path = %+v", cov.ByPath)
+	}
+	if len(cov.ByMethod) != 3 || cov.ByMethod[0].Method != "DELETE" || cov.ByMethod[1].Statuses != report.NewCoverageCount(3, 3) {
+		t.Errorf("by method = %+v", cov.ByMethod)
+	}
+
+	// The 500 and the body without a name break the contract: they are
+	// counted as invalid and cover nothing.
+	for _, op := range cov.Operations {
+		if op.Operation != "GET /pets/{id}" {
+			continue
+		}
+		if op.Calls != 1 || op.Invalid != 2 || len(op.Issues) != 2 {
+			t.Errorf("GET /pets/{id} = %d call(s), %d invalid, issues %q", op.Calls, op.Invalid, op.Issues)
+		}
+	}
+	if want := []report.UnmatchedRequest{{Method: "GET", Path: "/v1/health", Calls: 2}}; !reflect.DeepEqual(cov.Unmatched, want) {
+		t.Errorf("unmatched = %+v", cov.Unmatched)
+	}
+}
+
+func TestCoverageService_Errors(t *testing.T) {
+	svc, err := service.NewCoverageService(service.CoverageParams{
+		Importer: modelImporter{doc: coveragePetAPI()},
+		Traffic:  memTraffic{},
+		Logger:   nopLogger{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = svc.Coverage(context.Background(), "pets.yaml", nil)
+	var ae *customerrors.AppError
+	if !errors.As(err, &ae) || ae.Type != customerrors.VALIDATION_ERROR {
+		t.Errorf("no traffic files: err = %v", err)
+	}
+	_, err = svc.Coverage(context.Background(), "pets.yaml", []string{"missing.har"})
+	if !errors.As(err, &ae) || ae.Details[customerrors.DetailKind] != string(openapi.INVALID_HAR) {
+		t.Errorf("unreadable traffic: err = %v", err)
+	}
+	if _, err := service.NewCoverageService(service.CoverageParams{Importer: modelImporter{}, Logger: nopLogger{}}); err == nil {
+		t.Error("missing traffic reader: want an error")
+	}
+}
@@ -0,0 +1,458 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+// Deprecation lifecycle rule IDs, as set in Finding.RuleID.
+const (
+	DEPRECATION_REMOVED_UNANNOUNCED   = "removed-without-deprecation"
+	DEPRECATION_REMOVED_EARLY         = "removed-before-grace-period"
+	DEPRECATION_REMOVED_BEFORE_SUNSET = "removed-before-sunset"
+	DEPRECATION_DATE_UNKNOWN          = "deprecation-date-unknown"
+	DEPRECATION_SUNSET_PASSED         = "sunset-passed"
+	DEPRECATION_INVALID_SUNSET        = "invalid-sunset"
+)
+
+// SUNSET_EXTENSION announces when a deprecated operation, parameter or
+// property goes away. Operations may use a documented `Sunset` response
+// header (RFC 8594) instead.
+const (
+	SUNSET_EXTENSION = "x-sunset"
+	SUNSET_HEADER    = "Sunset"
+)
+
+// DeprecationRules describes the rule IDs of the deprecation lifecycle check.
+func DeprecationRules() []report.Rule {
+	return []report.Rule{
+		{ID: DEPRECATION_REMOVED_UNANNOUNCED, Name: "RemovedWithoutDeprecation", Summary: "An element was removed without being deprecated first.", Severity: report.SEVERITY_ERROR},
+		{ID: DEPRECATION_REMOVED_EARLY, Name: "RemovedBeforeGracePeriod", Summary: "A deprecated element was removed before its grace period ended.", Severity: report.SEVERITY_ERROR},
+		{ID: DEPRECATION_REMOVED_BEFORE_SUNSET, Name: "RemovedBeforeSunset", Summary: "A deprecated element was removed before its announced sunset date.", Severity: report.SEVERITY_ERROR},
+		{ID: DEPRECATION_DATE_UNKNOWN, Name: "DeprecationDateUnknown", Summary: "A removed element has no recorded deprecation date to check the grace period against.", Severity: report.SEVERITY_WARNING},
+		{ID: DEPRECATION_SUNSET_PASSED, Name: "SunsetPassed", Summary: "A deprecated element is still present after its sunset date.", Severity: report.SEVERITY_WARNING},
+		{ID: DEPRECATION_INVALID_SUNSET, Name: "InvalidSunset", Summary: "A sunset date cannot be read.", Severity: report.SEVERITY_WARNING},
+	}
+}
+
+// DeprecationParams declares the dependencies required to build the service.
+// LoaderFor, Revisions and VersionPolicy are as in OpenAPICompareParams.
+type DeprecationParams struct {
+	LoaderFor     func(sandboxDir string) openapi.Loader
+	Revisions     output.RevisionSource
+	Ledger        output.DeprecationLedger
+	Logger        output.Logger
+	VersionPolicy input.VersionPolicy
+}
+
+// validate performs defensive checks on constructor params.
+func (p DeprecationParams) validate() error {
+	if p.LoaderFor == nil {
+		return customerrors.NewDependencyError("loaderFor")
+	}
+	if p.Ledger == nil {
+		return customerrors.NewDependencyError("ledger")
+	}
+	if p.Logger == nil {
+		return customerrors.NewDependencyError("logger")
+	}
+	if p.VersionPolicy == nil {
+		return customerrors.NewDependencyError("versionPolicy")
+	}
+	return nil
+}
+
+// DeprecationService checks that elements are deprecated, and stay
+// deprecated long enough, before they are removed (input port implementation).
+type DeprecationService struct {
+	specs  specPairs
+	ledger output.DeprecationLedger
+	logger output.Logger
+	now    func() time.Time
+}
+
+// NewDeprecationService constructs the service after validating dependencies.
+func NewDeprecationService(params DeprecationParams) (*DeprecationService, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	return &DeprecationService{
+		specs: specPairs{
+			loaderFor:     params.LoaderFor,
+			revisions:     params.Revisions,
+			logger:        params.Logger,
+			versionPolicy: params.VersionPolicy,
+		},
+		ledger: params.Ledger,
+		logger: params.Logger,
+		now:    time.Now,
+	}, nil
+}
+
+// CheckFiles checks the removals from one spec file to another.
+func (s *DeprecationService) CheckFiles(ctx context.Context, basePath, headPath string, opts input.DeprecationOptions) (report.DeprecationReport, error) {
+	p, err := s.specs.files(ctx, basePath, headPath)
+	if err != nil {
+		return report.DeprecationReport{}, err
+	}
+	return s.check(ctx, p, opts)
+}
+
+// CheckGit checks the removals between the merge-base of req.Head and
+// req.Target, and req.Head.
+func (s *DeprecationService) CheckGit(ctx context.Context, req input.GitCompareRequest, opts input.DeprecationOptions) (report.DeprecationReport, error) {
+	p, err := s.specs.git(ctx, req)
+	if err != nil {
+		return report.DeprecationReport{}, err
+	}
+	return s.check(ctx, p, opts)
+}
+
+func (s *DeprecationService) check(ctx context.Context, p specPair, opts input.DeprecationOptions) (report.DeprecationReport, error) {
+	log := s.logger.With("local", "service.DeprecationService.check")
+
+	today := s.now().UTC().Truncate(24 * time.Hour)
+	if opts.Date != "" {
+		d, err := time.Parse(time.DateOnly, opts.Date)
+		if err != nil {
+			return report.DeprecationReport{}, customerrors.NewValidationError("Invalid check date", err, nil)
+		}
+		today = d
+	}
+	if opts.GraceDays < 0 {
+		return report.DeprecationReport{}, customerrors.NewValidationError(
+			"Invalid grace period", fmt.Errorf("grace period must not be negative, got %d days", opts.GraceDays), nil)
+	}
+
+	var records []report.DeprecationRecord
+	if opts.Ledger != "" {
+		var err error
+		if records, err = s.ledger.Load(ctx, opts.Ledger); err != nil {
+			log.Error("failed to read deprecation ledger", "file", opts.Ledger)
+			return report.DeprecationReport{}, err
+		}
+	}
+	recorded := make(map[string]report.DeprecationRecord, len(records))
+	for _, r := range records {
+		recorded[r.Element] = r
+	}
+
+	base, head := newLifecycleInventory(p.base), newLifecycleInventory(p.head)
+	lc := lifecycle{
+		today:    today,
+		grace:    opts.GraceDays,
+		tracked:  opts.Ledger != "",
+		recorded: recorded,
+		findings: []report.Finding{},
+	}
+	for _, key := range base.order {
+		if _, kept := head.elements[key]; kept {
+			continue
+		}
+		// Only the outermost removed element is reported.
+		e := base.elements[key]
+		if _, parentKept := head.elements[e.parent]; e.parent != "" && !parentKept {
+			continue
+		}
+		lc.removed(base, key)
+	}
+	for _, key := range head.order {
+		lc.present(head.elements[key])
+	}
+
+	rep := report.DeprecationReport{
+		Base:       p.baseLabel,
+		Head:       p.headLabel,
+		Date:       today.Format(time.DateOnly),
+		GraceDays:  opts.GraceDays,
+		Operations: comparedOperations(p.base, p.head),
+		Findings:   lc.findings,
+		Ledger:     nextLedger(head, recorded, p.headLabel, today),
+	}
+	if opts.Ledger != "" && !opts.Frozen {
+		if err := s.ledger.Save(ctx, opts.Ledger, rep.Ledger); err != nil {
+			log.Error("failed to write deprecation ledger", "file", opts.Ledger)
+			return report.DeprecationReport{}, err
+		}
+	}
+
+	log.Debug("deprecation lifecycle checked",
+		"head", p.headLabel,
+		"findings", len(rep.Findings),
+		"tracked", len(rep.Ledger),
+	)
+	return rep, nil
+}
+
+// lifecycle applies the rules of one run.
+type lifecycle struct {
+	today    time.Time
+	grace    int
+	tracked  bool
+	recorded map[string]report.DeprecationRecord
+	findings []report.Finding
+}
+
+func (lc *lifecycle) add(id string, sev report.Severity, e lifecycleElement, detail, format string, args ...any) {
+	lc.findings = append(lc.findings, report.Finding{
+		RuleID:    id,
+		Check:     report.CHECK_DEPRECATION,
+		Severity:  sev,
+		Message:   fmt.Sprintf(format, args...),
+		Detail:    detail,
+		Operation: e.operation,
+	})
+}
+
+// removed checks the removal of base element key against its deprecation:
+// its own, or the one it inherits from its operation or enclosing property.
+func (lc *lifecycle) removed(base lifecycleInventory, key string) {
+	e := base.elements[key]
+	dep, ok := base.deprecation(key)
+	if !ok {
+		lc.add(DEPRECATION_REMOVED_UNANNOUNCED, report.SEVERITY_ERROR, e, "", "%s was removed without being deprecated first", e.what)
+		return
+	}
+	rec, hasRecord := lc.recorded[dep.name]
+
+	sunset := dep.sunset
+	if sunset == "" {
+		sunset = rec.Sunset
+	}
+	if d, err := time.Parse(time.DateOnly, sunset); err == nil && lc.today.Before(d) {
+		lc.add(DEPRECATION_REMOVED_BEFORE_SUNSET, report.SEVERITY_ERROR, e, "",
+			"%s was removed before its sunset date %s", e.what, sunset)
+	}
+	if lc.grace == 0 {
+		return
+	}
+	since, err := time.Parse(time.DateOnly, rec.Since)
+	if !hasRecord || err != nil {
+		detail := "no deprecation ledger is configured"
+		if lc.tracked {
+			detail = "the deprecation ledger has no record of " + dep.name
+		}
+		lc.add(DEPRECATION_DATE_UNKNOWN, report.SEVERITY_WARNING, e, detail,
+			"%s was removed, but when it was deprecated is unknown, so the %d-day grace period cannot be verified", e.what, lc.grace)
+		return
+	}
+	if days := int(lc.today.Sub(since).Hours() / 24); days < lc.grace {
+		detail := "deprecated since " + rec.Since
+		if rec.Revision != "" {
+			detail += " (" + rec.Revision + ")"
+		}
+		lc.add(DEPRECATION_REMOVED_EARLY, report.SEVERITY_ERROR, e, detail,
+			"%s was removed %d day(s) after its deprecation; the grace period is %d days", e.what, days, lc.grace)
+	}
+}
+
+// present checks the sunset dates of an element still in the head spec.
+func (lc *lifecycle) present(e lifecycleElement) {
+	if e.badSunset != "" {
+		lc.add(DEPRECATION_INVALID_SUNSET, report.SEVERITY_WARNING, e, "",
+			"%s has a sunset date that cannot be read: %q (want YYYY-MM-DD or an HTTP date)", e.what, e.badSunset)
+	}
+	if !e.deprecated || e.sunset == "" {
+		return
+	}
+	if d, err := time.Parse(time.DateOnly, e.sunset); err == nil && d.Before(lc.today) {
+		lc.add(DEPRECATION_SUNSET_PASSED, report.SEVERITY_WARNING, e, "",
+			"%s is still present after its sunset date %s", e.what, e.sunset)
+	}
+}
+
+// nextLedger records every element the head spec deprecates, keeping the
+// first-seen date of known ones. Elements no longer deprecated are dropped.
+func nextLedger(head lifecycleInventory, recorded map[string]report.DeprecationRecord, headLabel string, today time.Time) []report.DeprecationRecord {
+	out := []report.DeprecationRecord{}
+	for _, key := range head.order {
+		e := head.elements[key]
+		if !e.deprecated {
+			continue
+		}
+		rec, ok := recorded[e.name]
+		if !ok {
+			rec = report.DeprecationRecord{
+				Element:   e.name,
+				Operation: e.operation,
+				Since:     today.Format(time.DateOnly),
+				Revision:  headLabel,
+			}
+		}
+		if e.sunset != "" {
+			rec.Sunset = e.sunset
+		}
+		out = append(out, rec)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Element < out[j].Element })
+	return out
+}
+
+// lifecycleElement is an operation, parameter, media type or property that
+// clients may depend on.
+//   - name: recorded in the ledger, e.g. "GET /pets parameter query limit".
+//   - what: the same without the operation, for messages.
+//   - parent: key of the enclosing element; "" for operations.
+//   - deprecated: declared on the element itself.
+//   - sunset: ISO 8601 date; badSunset keeps a declared value that could not be read.
+type lifecycleElement struct {
+	name       string
+	what       string
+	operation  string
+	parent     string
+	deprecated bool
+	sunset     string
+	badSunset  string
+}
+
+// lifecycleInventory indexes the elements of one spec by a key that
+// survives path variable renames (see operationShape).
+type lifecycleInventory struct {
+	elements map[string]lifecycleElement
+	order    []string
+}
+
+func newLifecycleInventory(doc *openapi.Document) lifecycleInventory {
+	inv := lifecycleInventory{elements: map[string]lifecycleElement{}}
+	for _, op := range doc.Operations {
+		opKey := operationShape(op)
+		e := lifecycleElement{name: op.Key(), what: "operation", operation: op.Key(), deprecated: op.Deprecated}
+		e.sunset, e.badSunset = operationSunset(op)
+		inv.add(opKey, e)
+
+		for _, p := range op.Parameters {
+			// Path variables go away with their path, never on their own.
+			if p.In == openapi.PARAM_IN_PATH {
+				continue
+			}
+			what := fmt.Sprintf("parameter %s %s", p.In, p.Name)
+			pe := lifecycleElement{name: op.Key() + " " + what, what: what, operation: op.Key(), parent: opKey, deprecated: p.Deprecated}
+			pe.sunset, pe.badSunset = parseSunset(p.Extensions[SUNSET_EXTENSION])
+			inv.add(opKey+" parameter "+p.In+":"+strings.ToLower(p.Name), pe)
+		}
+		if op.RequestBody != nil {
+			inv.content(op, opKey, "request body", op.RequestBody.Content)
+		}
+		for _, r := range op.Responses {
+			inv.content(op, opKey, "response "+r.Status, r.Content)
+		}
+	}
+	return inv
+}
+
+func (inv *lifecycleInventory) add(key string, e lifecycleElement) {
+	if _, dup := inv.elements[key]; dup {
+		return
+	}
+	inv.elements[key] = e
+	inv.order = append(inv.order, key)
+}
+
+func (inv *lifecycleInventory) content(op openapi.Operation, opKey, loc string, content map[string]openapi.MediaType) {
+	for _, mt := range sortedKeys(content) {
+		where := loc + " " + mt
+		key := opKey + " " + where
+		inv.add(key, lifecycleElement{name: op.Key() + " " + where, what: where, operation: op.Key(), parent: opKey})
+		inv.properties(op, key, where, key, content[mt].Schema, "", map[*openapi.Schema]bool{})
+	}
+}
+
+// properties adds the properties of s below ptr; contentKey anchors their
+// keys, parentKey is the enclosing element. stack guards recursive schemas.
+func (inv *lifecycleInventory) properties(op openapi.Operation, contentKey, where, parentKey string, s *openapi.Schema, ptr string, stack map[*openapi.Schema]bool) {
+	if s == nil || stack[s] {
+		return
+	}
+	stack[s] = true
+	defer delete(stack, s)
+
+	props, _ := flattenObject(s)
+	for _, name := range sortedKeys(props) {
+		p := props[name]
+		if p == nil {
+			continue
+		}
+		field := ptr + "/" + name
+		what := fmt.Sprintf("property %s (%s)", strings.TrimPrefix(field, "/"), where)
+		key := contentKey + " property " + field
+		e := lifecycleElement{name: op.Key() + " " + what, what: what, operation: op.Key(), parent: parentKey, deprecated: p.Deprecated}
+		e.sunset, e.badSunset = parseSunset(p.Extensions[SUNSET_EXTENSION])
+		inv.add(key, e)
+		inv.properties(op, contentKey, where, key, p, field, stack)
+	}
+	inv.properties(op, contentKey, where, parentKey, s.Items, ptr+"/items", stack)
+}
+
+// deprecation returns the element key is deprecated through: itself or its
+// nearest deprecated ancestor.
+func (inv lifecycleInventory) deprecation(key string) (lifecycleElement, bool) {
+	for key != "" {
+		e, ok := inv.elements[key]
+		if !ok {
+			break
+		}
+		if e.deprecated {
+			return e, true
+		}
+		key = e.parent
+	}
+	return lifecycleElement{}, false
+}
+
+// operationSunset reads x-sunset, or else the example or default of a
+// documented Sunset response header.
+func operationSunset(op openapi.Operation) (sunset, bad string) {
+	if v, ok := op.Extensions[SUNSET_EXTENSION]; ok {
+		return parseSunset(v)
+	}
+	for _, r := range op.Responses {
+		for name, h := range r.Headers {
+			if !strings.EqualFold(name, SUNSET_HEADER) || h.Schema == nil {
+				continue
+			}
+			for _, v := range []any{h.Schema.Example, h.Schema.Default} {
+				if v != nil {
+					return parseSunset(v)
+				}
+			}
+		}
+	}
+	return "", ""
+}
+
+// sunsetLayouts are the accepted sunset date forms: ISO 8601 dates and
+// timestamps, and HTTP dates as the Sunset header uses (RFC 8594).
+var sunsetLayouts = []string{time.DateOnly, time.RFC3339, time.RFC1123, time.RFC1123Z}
+
+// parseSunset normalizes a sunset value to an ISO 8601 date. bad is the
+// value as written when it cannot be read.
+func parseSunset(v any) (sunset, bad string) {
+	switch v := v.(type) {
+	case nil:
+		return "", ""
+	case time.Time:
+		return v.UTC().Format(time.DateOnly), ""
+	case string:
+		s := strings.TrimSpace(v)
+		for _, layout := range sunsetLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t.UTC().Format(time.DateOnly), ""
+			}
+		}
+		return "", v
+	default:
+		return "", fmt.Sprint(v)
+	}
+}
+
+// compile-time check
+var _ input.CheckDeprecations = (*DeprecationService)(nil)
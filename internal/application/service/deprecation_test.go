@@ -0,0 +1,232 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+	"github.com/betoth/contractcheck/internal/application/service"
+)
+
+// memLedger keeps ledgers in memory, by path.
+type memLedger struct {
+	files map[string][]report.DeprecationRecord
+	saves int
+}
+
+func (l *memLedger) Load(_ context.Context, path string) ([]report.DeprecationRecord, error) {
+	return l.files[path], nil
+}
+
+func (l *memLedger) Save(_ context.Context, path string, records []report.DeprecationRecord) error {
+	l.files[path] = records
+	l.saves++
+	return nil
+}
+
+func newDeprecationService(t *testing.T, docs map[string]*openapi.Document, ledger *memLedger) *service.DeprecationService {
+	t.Helper()
+	svc, err := service.NewDeprecationService(service.DeprecationParams{
+		LoaderFor:     func(string) openapi.Loader { return modelLoader{docs: docs} },
+		Ledger:        ledger,
+		Logger:        nopLogger{},
+		VersionPolicy: service.NewOpenAPIVersionPolicy([]int{3}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svc
+}
+
+func findingIDs(findings []report.Finding) []string {
+	ids := make([]string, 0, len(findings))
+	for _, f := range findings {
+		ids = append(ids, f.Operation+" "+f.RuleID)
+	}
+	return ids
+}
+
+// deprecatedPetAPI deprecates DELETE (sunset via header), the "status"
+// property of the GET response and a query parameter with x-sunset.
+func deprecatedPetAPI() *openapi.Document {
+	return petAPI(func(d *openapi.Document) {
+		get := &d.Operations[1]
+		get.Parameters = append(get.Parameters, openapi.Parameter{
+			Name: "fields", In: openapi.PARAM_IN_QUERY, Deprecated: true, Schema: str(),
+			Extensions: map[string]any{service.SUNSET_EXTENSION: "2026-03-01"},
+		})
+		get.Responses[0].Content["application/json"].Schema.Properties["status"].Deprecated = true
+
+		del := &d.Operations[2]
+		del.Deprecated = true
+		del.Responses[0].Headers = map[string]openapi.Header{
+			"sunset": {Schema: &openapi.Schema{Type: []string{"string"}, Example: "Sun, 01 Feb 2026 00:00:00 GMT"}},
+		}
+	})
+}
+
+// cleanedPetAPI removes what deprecatedPetAPI deprecated.
+func cleanedPetAPI() *openapi.Document {
+	return petAPI(func(d *openapi.Document) {
+		d.Operations = d.Operations[:2]
+		delete(d.Operations[1].Responses[0].Content["application/json"].Schema.Properties, "status")
+	})
+}
+
+func TestDeprecationService_Lifecycle(t *testing.T) {
+	ledger := &memLedger{files: map[string][]report.DeprecationRecord{}}
+	svc := newDeprecationService(t, map[string]*openapi.Document{
+		"v1.yaml": petAPI(func(d *openapi.Document) {
+			d.Operations[1].Parameters = append(d.Operations[1].Parameters, openapi.Parameter{Name: "fields", In: openapi.PARAM_IN_QUERY, Schema: str()})
+		}),
+		"v2.yaml": deprecatedPetAPI(),
+		"v3.yaml": cleanedPetAPI(),
+	}, ledger)
+	ctx := context.Background()
+
+	// Deprecating is fine and gets recorded with its sunset.
+	rep, err := svc.CheckFiles(ctx, "v1.yaml", "v2.yaml", input.DeprecationOptions{Ledger: "ledger.json", GraceDays: 60, Date: "2026-01-01"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rep.Findings) != 0 {
+		t.Fatalf("findings = %q", findingIDs(rep.Findings))
+	}
+	want := []report.DeprecationRecord{
+		{Element: "DELETE /pets/{id}", Operation: "DELETE /pets/{id}", Since: "2026-01-01", Revision: "v2.yaml", Sunset: "2026-02-01"},
+		{Element: "GET /pets/{id} parameter query fields", Operation: "GET /pets/{id}", Since: "2026-01-01", Revision: "v2.yaml", Sunset: "2026-03-01"},
+		{Element: "GET /pets/{id} property status (response 200 application/json)", Operation: "GET /pets/{id}", Since: "2026-01-01", Revision: "v2.yaml"},
+	}
+	if !slices.Equal(ledger.files["ledger.json"], want) {
+		t.Fatalf("ledger:\n got %+v\nwant %+v", ledger.files["ledger.json"], want)
+	}
+
+	// A later run keeps the first-seen dates.
+	if _, err := svc.CheckFiles(ctx, "v2.yaml", "v2.yaml", input.DeprecationOptions{Ledger: "ledger.json", Date: "2026-01-20"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := ledger.files["ledger.json"][0].Since; got != "2026-01-01" {
+		t.Fatalf("since = %s, want the first-seen date", got)
+	}
+
+	cases := []struct {
+		date string
+		want []string
+	}{
+		// Before every sunset and within the grace period.
+		{"2026-01-15", []string{
+			"DELETE /pets/{id} " + service.DEPRECATION_REMOVED_BEFORE_SUNSET,
+			"DELETE /pets/{id} " + service.DEPRECATION_REMOVED_EARLY,
+			"GET /pets/{id} " + service.DEPRECATION_REMOVED_BEFORE_SUNSET,
+			"GET /pets/{id} " + service.DEPRECATION_REMOVED_EARLY,
+			"GET /pets/{id} " + service.DEPRECATION_REMOVED_EARLY,
+		}},
+		// Past the grace period, but the parameter's sunset is still ahead.
+		{"2026-02-15", []string{"GET /pets/{id} " + service.DEPRECATION_REMOVED_BEFORE_SUNSET}},
+		{"2026-03-01", []string{}},
+	}
+	for _, tc := range cases {
+		rep, err := svc.CheckFiles(ctx, "v2.yaml", "v3.yaml", input.DeprecationOptions{Ledger: "ledger.json", Frozen: true, GraceDays: 30, Date: tc.date})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := findingIDs(rep.Findings); !slices.Equal(got, tc.want) {
+			t.Errorf("%s: findings\n got %q\nwant %q", tc.date, got, tc.want)
+		}
+		for _, f := range rep.Findings {
+			if f.Check != report.CHECK_DEPRECATION || f.Severity != report.SEVERITY_ERROR {
+				t.Errorf("%s: finding %+v", tc.date, f)
+			}
+		}
+	}
+	if ledger.saves != 2 {
+		t.Fatalf("saves = %d: frozen runs must not write the ledger", ledger.saves)
+	}
+}
+
+func TestDeprecationService_RemovalsWithoutDeprecation(t *testing.T) {
+	head := petAPI(func(d *openapi.Document) {
+		// Renaming a path variable removes nothing.
+		get := &d.Operations[1]
+		get.Path = "/pets/{petId}"
+		get.Parameters[0].Name = "petId"
+		// Removing a whole response reports the media type, not each property.
+		get.Responses = []openapi.Response{{Status: "200"}}
+		delete(d.Operations[0].RequestBody.Content["application/json"].Schema.Properties, "status")
+		d.Operations = d.Operations[:2]
+	})
+	svc := newDeprecationService(t, map[string]*openapi.Document{"v1.yaml": petAPI(nil), "v2.yaml": head}, &memLedger{})
+
+	rep, err := svc.CheckFiles(context.Background(), "v1.yaml", "v2.yaml", input.DeprecationOptions{Date: "2026-01-01"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// In spec order: by path, then method.
+	want := []string{
+		"POST /pets " + service.DEPRECATION_REMOVED_UNANNOUNCED,
+		"DELETE /pets/{id} " + service.DEPRECATION_REMOVED_UNANNOUNCED,
+		"GET /pets/{id} " + service.DEPRECATION_REMOVED_UNANNOUNCED,
+	}
+	if got := findingIDs(rep.Findings); !slices.Equal(got, want) {
+		t.Fatalf("findings\n got %q\nwant %q", got, want)
+	}
+	if msg := rep.Findings[2].Message; msg != "response 200 application/json was removed without being deprecated first" {
+		t.Fatalf("message = %q", msg)
+	}
+	if !slices.Equal(rep.Operations, []string{"DELETE /pets/{id}", "GET /pets/{petId}", "POST /pets"}) {
+		t.Fatalf("operations = %q", rep.Operations)
+	}
+}
+
+func TestDeprecationService_InheritedDeprecationAndWarnings(t *testing.T) {
+	base := petAPI(func(d *openapi.Document) {
+		d.Operations[1].Deprecated = true
+		d.Operations[1].Extensions = map[string]any{service.SUNSET_EXTENSION: "soon"}
+		d.Operations[0].Deprecated = true
+		d.Operations[0].Extensions = map[string]any{service.SUNSET_EXTENSION: "2025-12-01T00:00:00Z"}
+	})
+	head := petAPI(func(d *openapi.Document) {
+		// The GET operation is deprecated, so its response properties are too.
+		delete(d.Operations[1].Responses[0].Content["application/json"].Schema.Properties, "name")
+		d.Operations[1].Deprecated = true
+		d.Operations[1].Extensions = map[string]any{service.SUNSET_EXTENSION: "soon"}
+		d.Operations[0].Deprecated = true
+		d.Operations[0].Extensions = map[string]any{service.SUNSET_EXTENSION: "2025-12-01T00:00:00Z"}
+	})
+	svc := newDeprecationService(t, map[string]*openapi.Document{"v1.yaml": base, "v2.yaml": head}, &memLedger{})
+
+	// Without a ledger the grace period cannot be verified.
+	rep, err := svc.CheckFiles(context.Background(), "v1.yaml", "v2.yaml", input.DeprecationOptions{GraceDays: 30, Date: "2026-01-01"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"GET /pets/{id} " + service.DEPRECATION_DATE_UNKNOWN,
+		"POST /pets " + service.DEPRECATION_SUNSET_PASSED,
+		"GET /pets/{id} " + service.DEPRECATION_INVALID_SUNSET,
+	}
+	if got := findingIDs(rep.Findings); !slices.Equal(got, want) {
+		t.Fatalf("findings\n got %q\nwant %q", got, want)
+	}
+	for _, f := range rep.Findings {
+		if f.Severity != report.SEVERITY_WARNING {
+			t.Errorf("finding %+v: want a warning", f)
+		}
+	}
+}
+
+func TestDeprecationService_InvalidOptions(t *testing.T) {
+	svc := newDeprecationService(t, map[string]*openapi.Document{"v1.yaml": petAPI(nil)}, &memLedger{})
+	for _, opts := range []input.DeprecationOptions{{GraceDays: -1}, {Date: "01/02/2026"}} {
+		_, err := svc.CheckFiles(context.Background(), "v1.yaml", "v1.yaml", opts)
+		var ae *customerrors.AppError
+		if !errors.As(err, &ae) || ae.Type != customerrors.VALIDATION_ERROR {
+			t.Errorf("%+v: err = %v, want a validation error", opts, err)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+)
+
+// derefGrowthWarning is the output/input node ratio above which a warning is logged.
+const derefGrowthWarning = 10.0
+
+// OpenAPIDerefParams declares the hard dependencies required to build the service.
+type OpenAPIDerefParams struct {
+	Dereferencer openapi.Dereferencer
+	Logger       output.Logger
+}
+
+// validate performs defensive checks on constructor params.
+func (p OpenAPIDerefParams) validate() error {
+	if p.Dereferencer == nil {
+		return customerrors.NewDependencyError("dereferencer")
+	}
+	if p.Logger == nil {
+		return customerrors.NewDependencyError("logger")
+	}
+	return nil
+}
+
+// OpenAPIDerefService flattens documents for ref-unaware consumers (input port implementation).
+type OpenAPIDerefService struct {
+	dereferencer openapi.Dereferencer
+	logger       output.Logger
+}
+
+// NewOpenAPIDerefService constructs the service after validating dependencies.
+func NewOpenAPIDerefService(params OpenAPIDerefParams) (*OpenAPIDerefService, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	return &OpenAPIDerefService{
+		dereferencer: params.Dereferencer,
+		logger:       params.Logger,
+	}, nil
+}
+
+// Dereference inlines local refs of doc and logs structural stats; a large
+// growth ratio is reported as a warning so explosion is visible before export.
+func (s *OpenAPIDerefService) Dereference(ctx context.Context, doc openapi.OpenAPIDoc, opts openapi.DerefOptions) (openapi.OpenAPIDoc, openapi.DerefStats, error) {
+	log := s.logger.With("local", "service.OpenAPIDerefService.Dereference")
+
+	log.Info("starting to dereference OpenAPI spec", "cycles", string(opts.Cycles), "maxNodes", opts.MaxNodes)
+	out, stats, err := s.dereferencer.Dereference(ctx, doc, opts)
+	if err != nil {
+		log.Error("failed to dereference OpenAPI spec", "error", err)
+		return openapi.OpenAPIDoc{}, openapi.DerefStats{}, err
+	}
+
+	kv := []any{
+		"inputNodes", stats.InputNodes,
+		"outputNodes", stats.OutputNodes,
+		"outputDepth", stats.OutputDepth,
+		"inlinedRefs", stats.InlinedRefs,
+		"cycles", stats.Cycles,
+	}
+	if stats.Growth() > derefGrowthWarning {
+		log.Warn("dereferenced spec grew significantly", append(kv, "growth", stats.Growth())...)
+	} else {
+		log.Debug("successfully dereferenced OpenAPI spec", kv...)
+	}
+	return out, stats, nil
+}
+
+// compile-time check
+var _ input.DereferenceOpenAPISpec = (*OpenAPIDerefService)(nil)
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"time"
+
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+)
+
+const (
+	// diagnosticsLogLimit bounds the number of log entries in a bundle.
+	diagnosticsLogLimit = 5000
+	// diagnosticsJobLimit bounds the number of job records in a bundle.
+	diagnosticsJobLimit = 50
+	// redactedValue replaces values of sensitive keys in a bundle.
+	redactedValue = "[REDACTED]"
+)
+
+// DiagnosticsParams declares the dependencies of the diagnostics service.
+// - Logs: source of recent entries (in-memory ring buffer or persisted file).
+// - Jobs: optional recent job history.
+// - Config: effective configuration snapshot; must be JSON-serializable.
+// - Version: build/version information; must be JSON-serializable.
+// - RedactKeys: values under keys containing one of these are masked anywhere in the bundle.
+type DiagnosticsParams struct {
+	Logs       output.LogReader
+	Jobs       output.JobHistory
+	Logger     output.Logger
+	Config     any
+	Version    any
+	RedactKeys []string
+}
+
+// validate performs defensive checks on constructor params.
+func (p DiagnosticsParams) validate() error {
+	if p.Logs == nil {
+		return customerrors.NewDependencyError("logs")
+	}
+	if p.Logger == nil {
+		return customerrors.NewDependencyError("logger")
+	}
+	return nil
+}
+
+// DiagnosticsService builds diagnostics bundles (input port implementation).
+type DiagnosticsService struct {
+	logs    output.LogReader
+	jobs    output.JobHistory
+	logger  output.Logger
+	config  any
+	version any
+	redact  []string
+	now     func() time.Time
+}
+
+// NewDiagnosticsService constructs the service after validating dependencies.
+func NewDiagnosticsService(params DiagnosticsParams) (*DiagnosticsService, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	return &DiagnosticsService{
+		logs:    params.Logs,
+		jobs:    params.Jobs,
+		logger:  params.Logger,
+		config:  params.Config,
+		version: params.Version,
+		redact:  output.RedactionKeys(params.RedactKeys),
+		now:     time.Now,
+	}, nil
+}
+
+// diagnosticsManifest is the top-level summary stored as manifest.json.
+type diagnosticsManifest struct {
+	CreatedAt time.Time `json:"createdAt"`
+	Version   any       `json:"version"`
+	OS        osInfo    `json:"os"`
+	Files     []string  `json:"files"`
+}
+
+type osInfo struct {
+	GOOS      string `json:"goos"`
+	GOARCH    string `json:"goarch"`
+	NumCPU    int    `json:"numCPU"`
+	GoVersion string `json:"goVersion"`
+}
+
+// Export writes the zip bundle to w:
+//   - manifest.json: creation time, version, OS info and file list
+//   - config.json:   effective configuration (redacted)
+//   - logs.jsonl:    recent log entries, one JSON object per line (redacted)
+//   - jobs.json:     recent job history, newest first (when available)
+func (s *DiagnosticsService) Export(ctx context.Context, w io.Writer) error {
+	log := s.logger.With("local", "service.DiagnosticsService.Export")
+
+	entries, err := s.logs.Recent(ctx, output.LogQuery{Limit: diagnosticsLogLimit})
+	if err != nil {
+		// A bundle without logs is still useful; record why they are missing.
+		log.Warn("failed to read recent logs", "error", err)
+		entries = nil
+	}
+
+	files := []string{"manifest.json", "config.json", "logs.jsonl"}
+	var jobs []output.JobRecord
+	if s.jobs != nil {
+		jobs = s.jobs.Recent(diagnosticsJobLimit)
+		files = append(files, "jobs.json")
+	}
+
+	manifest := diagnosticsManifest{
+		CreatedAt: s.now().UTC(),
+		Version:   s.version,
+		OS: osInfo{
+			GOOS:      runtime.GOOS,
+			GOARCH:    runtime.GOARCH,
+			NumCPU:    runtime.NumCPU(),
+			GoVersion: runtime.Version(),
+		},
+		Files: files,
+	}
+
+	zw := zip.NewWriter(w)
+	if err := s.writeJSON(zw, "manifest.json", manifest); err != nil {
+		return err
+	}
+	if err := s.writeJSON(zw, "config.json", s.config); err != nil {
+		return err
+	}
+	if err := s.writeLogs(ctx, zw, entries); err != nil {
+		return err
+	}
+	if s.jobs != nil {
+		if err := s.writeJSON(zw, "jobs.json", jobs); err != nil {
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("finalize diagnostics bundle: %w", err)
+	}
+
+	log.Info("diagnostics bundle exported", "logEntries", len(entries), "jobs", len(jobs))
+	return nil
+}
+
+// writeJSON stores v as an indented, redacted JSON file in the archive.
+func (s *DiagnosticsService) writeJSON(zw *zip.Writer, name string, v any) error {
+	clean, err := s.redacted(v)
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", name, err)
+	}
+	f, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", name, err)
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(clean); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+// writeLogs stores entries as JSON lines in logs.jsonl.
+func (s *DiagnosticsService) writeLogs(ctx context.Context, zw *zip.Writer, entries []output.LogEntry) error {
+	f, err := zw.Create("logs.jsonl")
+	if err != nil {
+		return fmt.Errorf("create logs.jsonl: %w", err)
+	}
+	enc := json.NewEncoder(f)
+	for _, e := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		clean, err := s.redacted(e)
+		if err != nil {
+			return fmt.Errorf("encode log entry: %w", err)
+		}
+		if err := enc.Encode(clean); err != nil {
+			return fmt.Errorf("write logs.jsonl: %w", err)
+		}
+	}
+	return nil
+}
+
+// redacted round-trips v through JSON and masks values of sensitive keys at any depth.
+func (s *DiagnosticsService) redacted(v any) (any, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic any
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return nil, err
+	}
+	return s.redactValue(generic), nil
+}
+
+func (s *DiagnosticsService) redactValue(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, child := range t {
+			if output.IsSensitiveKey(k, s.redact) && child != nil {
+				t[k] = redactedValue
+				continue
+			}
+			t[k] = s.redactValue(child)
+		}
+		return t
+	case []any:
+		for i, child := range t {
+			t[i] = s.redactValue(child)
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+// compile-time check
+var _ input.ExportDiagnostics = (*DiagnosticsService)(nil)
@@ -0,0 +1,116 @@
+package service_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/application/service"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) With(...any) output.Logger  { return nopLogger{} }
+func (nopLogger) Named(string) output.Logger { return nopLogger{} }
+func (nopLogger) Info(string, ...any)        {}
+func (nopLogger) Warn(string, ...any)        {}
+func (nopLogger) Error(string, ...any)       {}
+func (nopLogger) Debug(string, ...any)       {}
+func (nopLogger) Sync() error                { return nil }
+
+type staticLogs []output.LogEntry
+
+func (s staticLogs) Recent(context.Context, output.LogQuery) ([]output.LogEntry, error) {
+	return s, nil
+}
+func (s staticLogs) Path() string { return "" }
+
+type staticJobs []output.JobRecord
+
+func (s staticJobs) Record(output.JobRecord)       {}
+func (s staticJobs) Recent(int) []output.JobRecord { return s }
+
+func TestDiagnosticsService_ExportRedactsSecrets(t *testing.T) {
+	svc, err := service.NewDiagnosticsService(service.DiagnosticsParams{
+		Logs: staticLogs{{
+			Level:   "info",
+			Message: "login",
+			Fields:  map[string]any{"user": "ana", "Access-Token": "abc123"},
+		}},
+		Jobs:       staticJobs{{Kind: "import", Target: "petstore.yaml", Status: output.JOB_FAILED}},
+		Logger:     nopLogger{},
+		Config:     map[string]any{"proxy": map[string]any{"password": "hunter2", "host": "localhost"}},
+		Version:    "1.2.3",
+		RedactKeys: []string{"password", "access_token"},
+	})
+	if err != nil {
+		t.Fatalf("NewDiagnosticsService: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := svc.Export(context.Background(), &buf); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	files := readZip(t, buf.Bytes())
+	for _, name := range []string{"manifest.json", "config.json", "logs.jsonl", "jobs.json"} {
+		if _, ok := files[name]; !ok {
+			t.Fatalf("missing %s in bundle; have %v", name, keys(files))
+		}
+	}
+
+	for name, body := range files {
+		if strings.Contains(body, "hunter2") || strings.Contains(body, "abc123") {
+			t.Fatalf("secret leaked in %s: %s", name, body)
+		}
+	}
+	if !strings.Contains(files["config.json"], `"host": "localhost"`) {
+		t.Fatalf("expected non-sensitive config to be kept: %s", files["config.json"])
+	}
+
+	var manifest map[string]any
+	if err := json.Unmarshal([]byte(files["manifest.json"]), &manifest); err != nil {
+		t.Fatalf("manifest.json: %v", err)
+	}
+	if manifest["version"] != "1.2.3" {
+		t.Fatalf("expected version 1.2.3 in manifest, got %#v", manifest["version"])
+	}
+}
+
+func TestNewDiagnosticsService_MissingDependencies(t *testing.T) {
+	if _, err := service.NewDiagnosticsService(service.DiagnosticsParams{Logger: nopLogger{}}); err == nil {
+		t.Fatal("expected dependency error without logs")
+	}
+}
+
+func readZip(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("invalid zip: %v", err)
+	}
+	out := make(map[string]string, len(zr.File))
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		b, _ := io.ReadAll(rc)
+		rc.Close()
+		out[f.Name] = string(b)
+	}
+	return out
+}
+
+func keys(m map[string]string) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
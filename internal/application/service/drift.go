@@ -0,0 +1,435 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+	"github.com/betoth/contractcheck/internal/application/ports/output/traffic"
+)
+
+// DriftParams declares the dependencies required to build the service.
+type DriftParams struct {
+	Importer input.ImportOpenAPISpec
+	Traffic  traffic.Reader
+	Logger   output.Logger
+}
+
+// validate performs defensive checks on constructor params.
+func (p DriftParams) validate() error {
+	if p.Importer == nil {
+		return customerrors.NewDependencyError("importer")
+	}
+	if p.Traffic == nil {
+		return customerrors.NewDependencyError("traffic")
+	}
+	if p.Logger == nil {
+		return customerrors.NewDependencyError("logger")
+	}
+	return nil
+}
+
+// DriftService reports where recorded traffic disagrees with a spec
+// (input port implementation).
+type DriftService struct {
+	importer input.ImportOpenAPISpec
+	traffic  traffic.Reader
+	logger   output.Logger
+}
+
+// NewDriftService constructs the service after validating dependencies.
+func NewDriftService(params DriftParams) (*DriftService, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	return &DriftService{importer: params.Importer, traffic: params.Traffic, logger: params.Logger}, nil
+}
+
+// Drift imports the spec at specPath and compares every exchange of the
+// traffic files with the operation it matches. As with coverage, a traffic
+// file that cannot be read fails the call.
+func (s *DriftService) Drift(ctx context.Context, specPath string, trafficPaths []string) (report.DriftReport, error) {
+	log := s.logger.With("local", "service.DriftService.Drift")
+	if len(trafficPaths) == 0 {
+		return report.DriftReport{}, customerrors.NewValidationError(
+			"No traffic files", errors.New("at least one traffic file is required"), nil)
+	}
+
+	doc, err := s.importer.Import(ctx, specPath)
+	if err != nil {
+		log.Error("failed to import spec", "file", specPath)
+		return report.DriftReport{}, err
+	}
+	if doc.Model == nil {
+		return report.DriftReport{}, customerrors.NewDependencyError("loader model")
+	}
+
+	t := newDriftTracker(doc.Model)
+	out := report.DriftReport{Spec: specPath, Sources: trafficPaths}
+	for _, path := range trafficPaths {
+		exchanges, err := s.traffic.Read(ctx, path)
+		if err != nil {
+			log.Error("failed to read traffic", "file", path)
+			return report.DriftReport{}, err
+		}
+		for _, ex := range exchanges {
+			if isPreflight(ex.Request) {
+				continue
+			}
+			t.record(ex)
+			out.Exchanges++
+		}
+	}
+	out.Drifts = t.drifts(out.Exchanges)
+	out.Findings = make([]report.Finding, len(out.Drifts))
+	for i, d := range out.Drifts {
+		out.Findings[i] = driftFinding(specPath, d)
+	}
+	log.Debug("drift detected", "file", specPath, "exchanges", out.Exchanges, "drifts", len(out.Drifts))
+	return out, nil
+}
+
+// driftTracker tallies the disagreements of exchanges per operation of one
+// document.
+type driftTracker struct {
+	routes    routes
+	apiKeys   map[string]bool // query parameters declared as API keys
+	tallies   []*driftTally   // in document order
+	byOp      map[*openapi.Operation]*driftTally
+	unmatched map[string]int // "METHOD /template" -> exchanges
+}
+
+// driftTally counts exchanges per drifting item of one operation.
+//   - statuses: undeclared status codes.
+//   - params: undeclared query parameters, by location.
+//   - bodies: JSON bodies checked against their schema, by body
+//     ("request body", "response 200 body").
+//   - extra: undocumented fields, by body, then location.
+//   - fields: documented response fields, by location.
+type driftTally struct {
+	op       *openapi.Operation
+	calls    int
+	statuses map[int]int
+	params   map[string]int
+	bodies   map[string]int
+	extra    map[string]map[string]int
+	fields   map[string]*fieldSightings
+}
+
+// fieldSightings counts the responses holding a field's object, and those
+// among them that had the field.
+type fieldSightings struct {
+	objects int
+	present int
+}
+
+func newDriftTracker(doc *openapi.Document) *driftTracker {
+	t := &driftTracker{
+		routes:    newRoutes(doc),
+		apiKeys:   map[string]bool{},
+		byOp:      map[*openapi.Operation]*driftTally{},
+		unmatched: map[string]int{},
+	}
+	for _, scheme := range doc.SecuritySchemes {
+		if scheme.Type == "apiKey" && scheme.In == openapi.PARAM_IN_QUERY {
+			t.apiKeys[scheme.Name] = true
+		}
+	}
+	for i := range doc.Operations {
+		op := &doc.Operations[i]
+		tally := &driftTally{
+			op:       op,
+			statuses: map[int]int{},
+			params:   map[string]int{},
+			bodies:   map[string]int{},
+			extra:    map[string]map[string]int{},
+			fields:   map[string]*fieldSightings{},
+		}
+		t.tallies = append(t.tallies, tally)
+		t.byOp[op] = tally
+	}
+	return t
+}
+
+// record matches ex to an operation and counts how it disagrees with it.
+// Each drifting item counts once per exchange, however often it appears.
+func (t *driftTracker) record(ex traffic.Exchange) {
+	u, err := url.Parse(ex.Request.URL)
+	if err != nil {
+		u = &url.URL{Path: ex.Request.URL}
+	}
+	op, _, _ := t.routes.match(ex.Request.Method, u.EscapedPath())
+	if op == nil {
+		template, _ := templatePath(u.Path)
+		t.unmatched[ex.Request.Method+" "+template]++
+		return
+	}
+	tally := t.byOp[op]
+	tally.calls++
+
+	declared := map[string]bool{}
+	for _, p := range op.Parameters {
+		if p.In == openapi.PARAM_IN_QUERY {
+			declared[p.Name] = true
+		}
+	}
+	for name := range u.Query() {
+		if !declared[name] && !t.apiKeys[name] {
+			tally.params[parameterLocation(openapi.PARAM_IN_QUERY, name)]++
+		}
+	}
+
+	if op.RequestBody != nil {
+		if s, v, ok := jsonBody(op.RequestBody.Content, ex.Request.Header.Get("Content-Type"), ex.Request.Body); ok {
+			tally.checkBody("request body", s, v)
+		}
+	}
+
+	resp := declaredResponse(op, ex.Response.Status)
+	if resp == nil {
+		tally.statuses[ex.Response.Status]++
+		return
+	}
+	s, v, ok := jsonBody(resp.Content, ex.Response.Header.Get("Content-Type"), ex.Response.Body)
+	if !ok {
+		return
+	}
+	b := tally.checkBody("response "+resp.Status+" body", s, v)
+	for pointer, props := range b.objects {
+		for name, p := range props {
+			if p != nil && p.WriteOnly {
+				continue
+			}
+			field := pointer + "/" + pointerToken(name)
+			loc := responseLocation(resp.Status, field)
+			if tally.fields[loc] == nil {
+				tally.fields[loc] = &fieldSightings{}
+			}
+			tally.fields[loc].objects++
+			if b.present[field] {
+				tally.fields[loc].present++
+			}
+		}
+	}
+}
+
+// checkBody walks the JSON body v against s and counts its undocumented
+// fields against the bodies checked for the same body of the operation.
+func (t *driftTally) checkBody(body string, s *openapi.Schema, v any) *bodyFields {
+	b := newBodyFields()
+	b.walk(s, v, "", 0)
+	t.bodies[body]++
+	if t.extra[body] == nil {
+		t.extra[body] = map[string]int{}
+	}
+	for pointer := range b.extra {
+		t.extra[body][bodyLocation(body, pointer)]++
+	}
+	return b
+}
+
+// drifts lists the tallies, most frequent first; ties keep the order of
+// the DRIFT_* kinds, then operations and items sort by name.
+func (t *driftTracker) drifts(exchanges int) []report.Drift {
+	out := []report.Drift{}
+	for key, n := range t.unmatched {
+		out = append(out, report.Drift{Kind: report.DRIFT_UNDOCUMENTED_OPERATION, Operation: key, Count: n, Of: exchanges})
+	}
+	for _, tally := range t.tallies {
+		op := tally.op.Key()
+		for status, n := range tally.statuses {
+			out = append(out, report.Drift{Kind: report.DRIFT_UNDOCUMENTED_STATUS, Operation: op, Item: strconv.Itoa(status), Count: n, Of: tally.calls})
+		}
+		for loc, n := range tally.params {
+			out = append(out, report.Drift{Kind: report.DRIFT_UNDOCUMENTED_PARAMETER, Operation: op, Item: loc, Count: n, Of: tally.calls})
+		}
+		for body, extra := range tally.extra {
+			for loc, n := range extra {
+				out = append(out, report.Drift{Kind: report.DRIFT_UNDOCUMENTED_FIELD, Operation: op, Item: loc, Count: n, Of: tally.bodies[body]})
+			}
+		}
+		for loc, f := range tally.fields {
+			if f.present == 0 {
+				out = append(out, report.Drift{Kind: report.DRIFT_UNSEEN_FIELD, Operation: op, Item: loc, Count: f.objects, Of: f.objects})
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		switch {
+		case a.Count != b.Count:
+			return a.Count > b.Count
+		case a.Kind != b.Kind:
+			return driftRank[a.Kind] < driftRank[b.Kind]
+		case a.Operation != b.Operation:
+			return a.Operation < b.Operation
+		}
+		return a.Item < b.Item
+	})
+	return out
+}
+
+var driftRank = map[string]int{
+	report.DRIFT_UNDOCUMENTED_OPERATION: 0,
+	report.DRIFT_UNDOCUMENTED_STATUS:    1,
+	report.DRIFT_UNDOCUMENTED_PARAMETER: 2,
+	report.DRIFT_UNDOCUMENTED_FIELD:     3,
+	report.DRIFT_UNSEEN_FIELD:           4,
+}
+
+// DriftRules describes the rule IDs of the drift check.
+func DriftRules() []report.Rule {
+	return []report.Rule{
+		{ID: report.DRIFT_UNDOCUMENTED_OPERATION, Name: "UndocumentedOperation", Summary: "Recorded requests match no operation in the spec.", Severity: report.SEVERITY_WARNING},
+		{ID: report.DRIFT_UNDOCUMENTED_STATUS, Name: "UndocumentedStatus", Summary: "Recorded traffic shows a status the operation does not declare.", Severity: report.SEVERITY_WARNING},
+		{ID: report.DRIFT_UNDOCUMENTED_PARAMETER, Name: "UndocumentedParameter", Summary: "Recorded requests send a parameter the operation does not declare.", Severity: report.SEVERITY_WARNING},
+		{ID: report.DRIFT_UNDOCUMENTED_FIELD, Name: "UndocumentedField", Summary: "Recorded bodies carry a field the schema does not document.", Severity: report.SEVERITY_WARNING},
+		{ID: report.DRIFT_UNSEEN_FIELD, Name: "UnseenField", Summary: "A documented response field never appears in recorded traffic.", Severity: report.SEVERITY_INFO},
+	}
+}
+
+// driftFinding turns a drift into a finding: undocumented traffic is a
+// warning, fields that never appear are informational.
+func driftFinding(specPath string, d report.Drift) report.Finding {
+	f := report.Finding{
+		RuleID:    d.Kind,
+		Check:     report.CHECK_DRIFT,
+		Severity:  report.SEVERITY_WARNING,
+		File:      specPath,
+		Operation: d.Operation,
+	}
+	switch d.Kind {
+	case report.DRIFT_UNDOCUMENTED_OPERATION:
+		f.Message = fmt.Sprintf("requests match no operation in %d of %d exchange(s)", d.Count, d.Of)
+	case report.DRIFT_UNDOCUMENTED_STATUS:
+		f.Message = fmt.Sprintf("undeclared status %s in %d of %d call(s)", d.Item, d.Count, d.Of)
+	case report.DRIFT_UNDOCUMENTED_PARAMETER:
+		f.Message = fmt.Sprintf("undeclared %s in %d of %d call(s)", d.Item, d.Count, d.Of)
+	case report.DRIFT_UNDOCUMENTED_FIELD:
+		f.Message = fmt.Sprintf("undocumented field at %s in %d of %d body(ies)", d.Item, d.Count, d.Of)
+	case report.DRIFT_UNSEEN_FIELD:
+		f.Severity = report.SEVERITY_INFO
+		f.Message = fmt.Sprintf("documented field at %s missing from all %d response(s)", d.Item, d.Count)
+	}
+	return f
+}
+
+// bodyFields is what one JSON body shows of its schema, by JSON pointer
+// ("*" for array items):
+//   - objects: positions of objects with documented properties, with those
+//     every such object may have.
+//   - present: documented fields the body has.
+//   - extra: fields the schema does not document.
+type bodyFields struct {
+	objects map[string]map[string]*openapi.Schema
+	present map[string]bool
+	extra   map[string]bool
+}
+
+func newBodyFields() *bodyFields {
+	return &bodyFields{
+		objects: map[string]map[string]*openapi.Schema{},
+		present: map[string]bool{},
+		extra:   map[string]bool{},
+	}
+}
+
+// walk compares v with s. Objects whose schema documents no properties
+// (free-form objects and maps) are not compared, and open objects
+// (additionalProperties: true or a schema) have no undocumented fields.
+func (b *bodyFields) walk(s *openapi.Schema, v any, pointer string, depth int) {
+	if s == nil || depth > maxCoverageDepth {
+		return
+	}
+	switch v := v.(type) {
+	case []any:
+		items := itemsSchema(s, 0)
+		for _, item := range v {
+			b.walk(items, item, pointer+"/*", depth+1)
+		}
+	case map[string]any:
+		props, always, open := documentedProperties(s, 0)
+		if len(props) == 0 {
+			return
+		}
+		if b.objects[pointer] == nil {
+			b.objects[pointer] = always
+		}
+		for name, item := range v {
+			field := pointer + "/" + pointerToken(name)
+			p, ok := props[name]
+			switch {
+			case ok:
+				b.present[field] = true
+				b.walk(p, item, field, depth+1)
+			case !open:
+				b.extra[field] = true
+			}
+		}
+	}
+}
+
+// documentedProperties collects the properties s documents, including
+// those of its allOf, oneOf and anyOf parts, and tells whether any part
+// allows additional properties explicitly. always holds the properties
+// every matching object may have: those of oneOf and anyOf alternatives
+// are left out, since a body only follows some of them.
+func documentedProperties(s *openapi.Schema, depth int) (props, always map[string]*openapi.Schema, open bool) {
+	props, always = map[string]*openapi.Schema{}, map[string]*openapi.Schema{}
+	if s == nil || depth > maxCoverageDepth {
+		return props, always, false
+	}
+	for name, p := range s.Properties {
+		props[name], always[name] = p, p
+	}
+	open = s.AdditionalProperties != nil || (s.AdditionalPropertiesAllowed != nil && *s.AdditionalPropertiesAllowed)
+	for i, parts := range [][]*openapi.Schema{s.AllOf, s.OneOf, s.AnyOf} {
+		for _, part := range parts {
+			partProps, partAlways, partOpen := documentedProperties(part, depth+1)
+			for name, p := range partProps {
+				if _, ok := props[name]; !ok {
+					props[name] = p
+				}
+			}
+			if i == 0 {
+				for name, p := range partAlways {
+					if _, ok := always[name]; !ok {
+						always[name] = p
+					}
+				}
+			}
+			open = open || partOpen
+		}
+	}
+	return props, always, open
+}
+
+// itemsSchema is the items schema of s or of its first composed part that
+// declares one.
+func itemsSchema(s *openapi.Schema, depth int) *openapi.Schema {
+	if s == nil || depth > maxCoverageDepth {
+		return nil
+	}
+	if s.Items != nil {
+		return s.Items
+	}
+	for _, parts := range [][]*openapi.Schema{s.AllOf, s.OneOf, s.AnyOf} {
+		for _, part := range parts {
+			if items := itemsSchema(part, depth+1); items != nil {
+				return items
+			}
+		}
+	}
+	return nil
+}
+
+// compile-time check
+var _ input.DetectDrift = (*DriftService)(nil)
@@ -0,0 +1,159 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+	"github.com/betoth/contractcheck/internal/application/ports/output/traffic"
+	"github.com/betoth/contractcheck/internal/application/service"
+)
+
+func driftPetAPI() *openapi.Document {
+	return petAPI(func(d *openapi.Document) {
+		d.SecuritySchemes = map[string]openapi.SecurityScheme{"key": {Type: "apiKey", In: openapi.PARAM_IN_QUERY, Name: "api_key"}}
+		get := &d.Operations[1]
+		get.Parameters = append(get.Parameters, openapi.Parameter{Name: "fields", In: openapi.PARAM_IN_QUERY, Schema: str()})
+		pet := get.Responses[0].Content["application/json"].Schema
+		pet.Properties["tag"] = str()
+		pet.Properties["secret"] = &openapi.Schema{Type: []string{"string"}, WriteOnly: true}
+	})
+}
+
+func newDriftService(t *testing.T, exchanges []traffic.Exchange) *service.DriftService {
+	t.Helper()
+	svc, err := service.NewDriftService(service.DriftParams{
+		Importer: modelImporter{doc: driftPetAPI()},
+		Traffic:  memTraffic{"staging.har": exchanges},
+		Logger:   nopLogger{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svc
+}
+
+func TestDriftService_RanksDriftByFrequency(t *testing.T) {
+	preflight := exchange("OPTIONS", "https://api.example.com/orders/42", "", 204, "")
+	preflight.Request.Header.Set("Access-Control-Request-Method", "GET")
+	svc := newDriftService(t, []traffic.Exchange{
+		exchange("GET", "https://api.example.com/pets/1?fields=name&debug=1", "", 200, `{"id": 1, "name": "rex", "nickname": "r"}`),
+		exchange("GET", "https://api.example.com/pets/2?debug=1&api_key=k", "", 200, `{"id": 2, "name": "tom", "nickname": "t", "owner": "ana"}`),
+		exchange("GET", "https://api.example.com/pets/3", "", 500, ""),
+		exchange("GET", "https://api.example.com/pets/4", "", 200, `{"id": 4, "name": "kit"}`),
+		exchange("POST", "https://api.example.com/pets", `{"name": "max", "color": "brown"}`, 201, ""),
+		exchange("DELETE", "https://api.example.com/pets/1", "", 204, ""),
+		exchange("GET", "https://api.example.com/orders/42", "", 200, `{}`),
+		exchange("GET", "https://api.example.com/orders/43", "", 200, `{}`),
+		preflight,
+	})
+
+	rep, err := svc.Drift(context.Background(), "api.yaml", []string{"staging.har"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Exchanges != 8 {
+		t.Errorf("exchanges = %d, want 8 (the preflight is skipped)", rep.Exchanges)
+	}
+	var got []string
+	for _, d := range rep.Drifts {
+		got = append(got, fmt.Sprintf("%s %s %s %d/%d", d.Kind, d.Operation, d.Item, d.Count, d.Of))
+	}
+	want := []string{
+		"unseen-field GET /pets/{id} response 200 body /status 3/3",
+		"unseen-field GET /pets/{id} response 200 body /tag 3/3",
+		"undocumented-operation GET /orders/{orderId}  2/8",
+		`undocumented-parameter GET /pets/{id} query parameter "debug" 2/4`,
+		"undocumented-field GET /pets/{id} response 200 body /nickname 2/3",
+		"undocumented-status GET /pets/{id} 500 1/4",
+		"undocumented-field GET /pets/{id} response 200 body /owner 1/3",
+		"undocumented-field POST /pets request body /color 1/1",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("drifts =\n%q\nwant\n%q", got, want)
+	}
+
+	if len(rep.Findings) != len(rep.Drifts) {
+		t.Fatalf("findings = %d, want one per drift", len(rep.Findings))
+	}
+	unseen, field, status := rep.Findings[0], rep.Findings[4], rep.Findings[5]
+	if unseen.Severity != report.SEVERITY_INFO || unseen.Check != report.CHECK_DRIFT || unseen.File != "api.yaml" {
+		t.Errorf("unseen field finding = %+v", unseen)
+	}
+	if status.Severity != report.SEVERITY_WARNING || status.RuleID != report.DRIFT_UNDOCUMENTED_STATUS ||
+		status.Message != "undeclared status 500 in 1 of 4 call(s)" || status.Operation != "GET /pets/{id}" {
+		t.Errorf("status finding = %+v", status)
+	}
+	// Fields count against the bodies checked, not every call: the 500 had none.
+	if field.Message != "undocumented field at response 200 body /nickname in 2 of 3 body(ies)" {
+		t.Errorf("field finding = %+v", field)
+	}
+	// Every kind shows up above; the rules report writers get must agree.
+	rules := map[string]report.Severity{}
+	for _, r := range service.DriftRules() {
+		rules[r.ID] = r.Severity
+	}
+	for _, f := range rep.Findings {
+		if sev, ok := rules[f.RuleID]; !ok || sev != f.Severity {
+			t.Errorf("finding %s has severity %s, DriftRules says %q", f.RuleID, f.Severity, sev)
+		}
+	}
+	if subjects := rep.Report().Subjects; len(subjects) != 1 || subjects[0].File != "api.yaml" || len(subjects[0].Operations) != 3 {
+		t.Errorf("subjects = %+v", subjects)
+	}
+}
+
+func TestDriftService_IgnoresOpenAndAlternativeSchemas(t *testing.T) {
+	allowed := true
+	doc := petAPI(func(d *openapi.Document) {
+		d.Operations[1].Responses[0].Content["application/json"] = openapi.MediaType{Schema: &openapi.Schema{
+			Type:  []string{"object"},
+			AllOf: []*openapi.Schema{object([]string{"id"}, map[string]*openapi.Schema{"id": {Type: []string{"integer"}}})},
+			OneOf: []*openapi.Schema{
+				object(nil, map[string]*openapi.Schema{"bark": str()}),
+				object(nil, map[string]*openapi.Schema{"meow": str()}),
+			},
+			Properties: map[string]*openapi.Schema{
+				"labels": {Type: []string{"object"}, Properties: map[string]*openapi.Schema{"color": str()}, AdditionalPropertiesAllowed: &allowed},
+			},
+		}}
+	})
+	svc, err := service.NewDriftService(service.DriftParams{
+		Importer: modelImporter{doc: doc},
+		Traffic: memTraffic{"staging.har": {
+			exchange("GET", "https://api.example.com/pets/1", "", 200, `{"id": 1, "bark": "woof", "labels": {"color": "red", "size": "xl"}}`),
+		}},
+		Logger: nopLogger{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep, err := svc.Drift(context.Background(), "api.yaml", []string{"staging.har"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rep.Drifts) != 0 {
+		t.Errorf("drifts = %+v, want none", rep.Drifts)
+	}
+}
+
+func TestDriftService_Errors(t *testing.T) {
+	svc := newDriftService(t, nil)
+	_, err := svc.Drift(context.Background(), "api.yaml", nil)
+	var ae *customerrors.AppError
+	if !errors.As(err, &ae) || ae.Type != customerrors.VALIDATION_ERROR {
+		t.Errorf("no traffic files: err = %v, want a validation error", err)
+	}
+	_, err = svc.Drift(context.Background(), "api.yaml", []string{"missing.har"})
+	if !errors.As(err, &ae) || ae.Details[customerrors.DetailKind] != string(openapi.INVALID_HAR) {
+		t.Errorf("unreadable traffic: err = %v", err)
+	}
+	if _, err := service.NewDriftService(service.DriftParams{Importer: modelImporter{}, Logger: nopLogger{}}); err == nil {
+		t.Error("missing traffic reader: want an error")
+	}
+}
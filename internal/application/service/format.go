@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+)
+
+// OpenAPIFormatParams declares the hard dependencies required to build the service.
+//   - LoaderFor returns a loader whose external refs are confined to sandboxDir
+//     (the spec's directory).
+type OpenAPIFormatParams struct {
+	LoaderFor     func(sandboxDir string) openapi.Loader
+	Formatter     openapi.Formatter
+	Logger        output.Logger
+	VersionPolicy input.VersionPolicy
+}
+
+// validate performs defensive checks on constructor params.
+func (p OpenAPIFormatParams) validate() error {
+	if p.LoaderFor == nil {
+		return customerrors.NewDependencyError("loaderFor")
+	}
+	if p.Formatter == nil {
+		return customerrors.NewDependencyError("formatter")
+	}
+	if p.Logger == nil {
+		return customerrors.NewDependencyError("logger")
+	}
+	if p.VersionPolicy == nil {
+		return customerrors.NewDependencyError("versionPolicy")
+	}
+	return nil
+}
+
+// OpenAPIFormatService renders specs canonically (input port implementation).
+type OpenAPIFormatService struct {
+	loaderFor     func(string) openapi.Loader
+	formatter     openapi.Formatter
+	logger        output.Logger
+	versionPolicy input.VersionPolicy
+}
+
+// NewOpenAPIFormatService constructs the service after validating dependencies.
+func NewOpenAPIFormatService(params OpenAPIFormatParams) (*OpenAPIFormatService, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	return &OpenAPIFormatService{
+		loaderFor:     params.LoaderFor,
+		formatter:     params.Formatter,
+		logger:        params.Logger,
+		versionPolicy: params.VersionPolicy,
+	}, nil
+}
+
+// Format validates filePath as an importable spec (so broken files are never
+// rewritten), then renders it canonically through the formatter.
+func (s *OpenAPIFormatService) Format(ctx context.Context, filePath string, opts openapi.FormatOptions) (openapi.FormatResult, error) {
+	log := s.logger.With("local", "service.OpenAPIFormatService.Format")
+
+	log.Info("starting to format OpenAPI spec", "file", filePath, "format", string(opts.Format))
+	doc, err := s.loaderFor(filepath.Dir(filePath)).Load(ctx, filePath)
+	if err != nil {
+		log.Error("failed to load OpenAPI spec", "file", filePath)
+		return openapi.FormatResult{}, err
+	}
+
+	if !s.versionPolicy.IsSupported(doc.Version.Major()) {
+		log.Error(
+			"invalid OpenAPI version",
+			"file", filePath,
+			"version", doc.Version.String(),
+			"accepted", s.versionPolicy.SupportedVersions(),
+		)
+		return openapi.FormatResult{}, customerrors.NewUnsupportedVersionError(
+			filePath,
+			doc.Version.String(),
+			s.versionPolicy.SupportedVersions(),
+		)
+	}
+
+	res, err := s.formatter.Format(ctx, filePath, opts)
+	if err != nil {
+		log.Error("failed to format OpenAPI spec", "file", filePath, "error", err)
+		return openapi.FormatResult{}, err
+	}
+
+	log.Debug("successfully formatted OpenAPI spec", "canonical", res.Canonical)
+	return res, nil
+}
+
+// compile-time check
+var _ input.FormatOpenAPISpec = (*OpenAPIFormatService)(nil)
@@ -0,0 +1,897 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+	"github.com/betoth/contractcheck/internal/application/ports/output/testgen"
+)
+
+// DEFAULT_FUZZ_CASES bounds the mutated requests sent per operation.
+const DEFAULT_FUZZ_CASES = 50
+
+// OVERSIZED_STRING_LENGTH is the length of the strings sent to string
+// fields without maxLength.
+const OVERSIZED_STRING_LENGTH = 1 << 16
+
+const (
+	maxShrinkSteps  = 32   // requests spent minimizing one failing case
+	maxFuzzDepth    = 3    // body nesting mutated
+	maxReportedBody = 2048 // bytes of a failing body kept in reports
+)
+
+// Fuzz rule IDs, as set in Finding.RuleID.
+const (
+	FUZZ_SERVER_ERROR           = "fuzz-server-error"
+	FUZZ_INVALID_ACCEPTED       = "fuzz-invalid-accepted"
+	FUZZ_REJECTION_UNDOCUMENTED = "fuzz-rejection-undocumented"
+	FUZZ_REQUEST_FAILED         = "fuzz-request-failed"
+	FUZZ_OPERATION_SKIPPED      = "fuzz-operation-skipped"
+)
+
+// FuzzRules describes the rule IDs of the fuzz check.
+func FuzzRules() []report.Rule {
+	return []report.Rule{
+		{ID: FUZZ_SERVER_ERROR, Name: "FuzzServerError", Summary: "A mutated request was answered with a 5xx status the operation does not declare.", Severity: report.SEVERITY_ERROR},
+		{ID: FUZZ_INVALID_ACCEPTED, Name: "FuzzInvalidAccepted", Summary: "A request the spec forbids was answered with a status other than 4xx.", Severity: report.SEVERITY_ERROR},
+		{ID: FUZZ_REJECTION_UNDOCUMENTED, Name: "FuzzRejectionUndocumented", Summary: "A request the spec forbids was rejected with a 4xx status the operation does not declare.", Severity: report.SEVERITY_WARNING},
+		{ID: FUZZ_REQUEST_FAILED, Name: "FuzzRequestFailed", Summary: "The service could not be reached or did not answer a mutated request in time.", Severity: report.SEVERITY_ERROR},
+		{ID: FUZZ_OPERATION_SKIPPED, Name: "FuzzOperationSkipped", Summary: "An operation was not fuzzed: no valid request could be synthesized or no credentials were configured.", Severity: report.SEVERITY_INFO},
+	}
+}
+
+// FuzzParams declares the dependencies required to build the service.
+type FuzzParams struct {
+	Importer input.ImportOpenAPISpec
+	Client   output.HTTPClient
+	Logger   output.Logger
+}
+
+// validate performs defensive checks on constructor params.
+func (p FuzzParams) validate() error {
+	if p.Importer == nil {
+		return customerrors.NewDependencyError("importer")
+	}
+	if p.Client == nil {
+		return customerrors.NewDependencyError("http client")
+	}
+	if p.Logger == nil {
+		return customerrors.NewDependencyError("logger")
+	}
+	return nil
+}
+
+// FuzzService sends mutated requests to a live service (input port
+// implementation).
+type FuzzService struct {
+	importer input.ImportOpenAPISpec
+	client   output.HTTPClient
+	logger   output.Logger
+}
+
+// NewFuzzService constructs the service after validating dependencies.
+func NewFuzzService(params FuzzParams) (*FuzzService, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	return &FuzzService{importer: params.Importer, client: params.Client, logger: params.Logger}, nil
+}
+
+// Fuzz imports the spec at specPath and, for each operation, mutates the
+// request a contract run would send (see validRequest) one parameter or
+// body field at a time. Mutations the spec forbids must be rejected with a
+// declared 4xx status; no mutation may produce an undeclared 5xx. Failing
+// cases are minimized before they are reported.
+//
+// Random choices come from opts.Seed and the operation, so a run is
+// reproducible whatever the concurrency.
+func (s *FuzzService) Fuzz(ctx context.Context, specPath string, opts input.FuzzOptions) (report.FuzzRun, error) {
+	log := s.logger.With("local", "service.FuzzService.Fuzz")
+	if opts.Concurrency < 0 || opts.Cases < 0 {
+		return report.FuzzRun{}, customerrors.NewValidationError(
+			"Invalid fuzz options", fmt.Errorf("concurrency and cases must not be negative, got %d and %d", opts.Concurrency, opts.Cases), nil)
+	}
+	if opts.Concurrency == 0 {
+		opts.Concurrency = DEFAULT_RUN_CONCURRENCY
+	}
+	if opts.Cases == 0 {
+		opts.Cases = DEFAULT_FUZZ_CASES
+	}
+	if opts.Seed == 0 {
+		opts.Seed = time.Now().UnixNano()
+	}
+
+	doc, err := s.importer.Import(ctx, specPath)
+	if err != nil {
+		log.Error("failed to import spec", "file", specPath)
+		return report.FuzzRun{}, err
+	}
+	if doc.Model == nil {
+		return report.FuzzRun{}, customerrors.NewDependencyError("loader model")
+	}
+	base, err := runBaseURL(doc.Model, opts.BaseURL)
+	if err != nil {
+		return report.FuzzRun{}, customerrors.NewValidationError("Invalid base URL", err, nil)
+	}
+
+	f := fuzzer{
+		runner: runner{check: report.CHECK_FUZZ, doc: doc.Model, file: specPath, base: base, credentials: opts.Credentials, client: s.client},
+		seed:   opts.Seed,
+		cases:  opts.Cases,
+	}
+	ops := doc.Model.Operations
+	results := make([]fuzzResult, len(ops))
+	slots := make(chan struct{}, opts.Concurrency)
+	var wg sync.WaitGroup
+	for i := range ops {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-slots }()
+			f.operation(ctx, ops[i], &results[i])
+		}(i)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return report.FuzzRun{}, err
+	}
+
+	out := report.FuzzRun{
+		Spec: specPath, BaseURL: base, Seed: opts.Seed, Operations: len(ops),
+		Failures: []report.FuzzFailure{}, Findings: []report.Finding{},
+	}
+	for _, res := range results {
+		out.Cases += res.cases
+		out.Failures = append(out.Failures, res.failures...)
+		out.Findings = append(out.Findings, res.findings...)
+	}
+	log.Info("fuzz run finished",
+		"file", specPath,
+		"base_url", base,
+		"seed", opts.Seed,
+		"cases", out.Cases,
+		"failures", len(out.Failures),
+	)
+	return out, nil
+}
+
+// fuzzResult collects the outcome of fuzzing one operation.
+type fuzzResult struct {
+	cases    int
+	failures []report.FuzzFailure
+	findings []report.Finding
+}
+
+// fuzzer mutates and sends the requests of one run.
+type fuzzer struct {
+	runner
+	seed  int64
+	cases int
+}
+
+// fuzzOutcome classifies the answer to one mutated request; rule is empty
+// when the service handled it well.
+type fuzzOutcome struct {
+	rule    string
+	sev     report.Severity
+	message string
+	req     output.HTTPRequest
+	status  int
+}
+
+func (f fuzzer) operation(ctx context.Context, op openapi.Operation, res *fuzzResult) {
+	skip := func(format string, args ...any) {
+		res.findings = append(res.findings, f.finding(op, "", FUZZ_OPERATION_SKIPPED, report.SEVERITY_INFO, format, args...))
+	}
+	valid := validRequest(op)
+	if valid.Skip != "" {
+		skip("%s", valid.Skip)
+		return
+	}
+	if _, err := f.request(op, valid); err != nil {
+		skip("%v", err)
+		return
+	}
+
+	in := newFuzzRequest(valid)
+	rng := rand.New(rand.NewPCG(uint64(f.seed), operationSeed(op)))
+	muts := fuzzMutations(op, in, rng)
+	if len(muts) > f.cases {
+		picks := rng.Perm(len(muts))[:f.cases]
+		sort.Ints(picks)
+		chosen := make([]mutation, len(picks))
+		for i, p := range picks {
+			chosen[i] = muts[p]
+		}
+		muts = chosen
+	}
+
+	for _, m := range muts {
+		if ctx.Err() != nil {
+			return
+		}
+		req := in.clone()
+		m.apply(&req)
+		res.cases++
+		out := f.try(ctx, op, m, req)
+		if out.rule == "" {
+			continue
+		}
+		if out.rule != FUZZ_REQUEST_FAILED {
+			req, out = f.shrink(ctx, op, m, req, out)
+		}
+		fd := f.finding(op, out.req.Method+" "+out.req.URL, out.rule, out.sev, "%s", out.message)
+		fd.Pointer = m.pointer
+		res.findings = append(res.findings, fd)
+		res.failures = append(res.failures, report.FuzzFailure{
+			RuleID:      out.rule,
+			Operation:   op.Key(),
+			Target:      m.label,
+			Mutation:    m.kind,
+			Description: m.what,
+			Method:      out.req.Method,
+			URL:         out.req.URL,
+			Body:        shorten(string(out.req.Body), maxReportedBody),
+			Status:      out.status,
+		})
+		if out.rule == FUZZ_REQUEST_FAILED {
+			return // the service is unreachable; more requests only repeat this
+		}
+	}
+}
+
+// try sends one mutated request and classifies the answer.
+func (f fuzzer) try(ctx context.Context, op openapi.Operation, m mutation, r fuzzRequest) fuzzOutcome {
+	req, err := f.request(op, r.testCase())
+	if err != nil {
+		return fuzzOutcome{rule: FUZZ_REQUEST_FAILED, sev: report.SEVERITY_ERROR, message: err.Error()}
+	}
+	out := fuzzOutcome{req: req}
+	resp, err := f.client.Do(ctx, req)
+	if err != nil {
+		out.rule, out.sev = FUZZ_REQUEST_FAILED, report.SEVERITY_ERROR
+		out.message = fmt.Sprintf("%s: %s (%s): the request failed: %v", m.label, m.what, m.kind, err)
+		return out
+	}
+	out.status = resp.Status
+	declared := declaredResponse(&op, resp.Status)
+	switch {
+	case resp.Status >= 500 && declared == nil:
+		out.rule, out.sev = FUZZ_SERVER_ERROR, report.SEVERITY_ERROR
+		out.message = fmt.Sprintf("%s: %s (%s) was answered with undocumented %d", m.label, m.what, m.kind, resp.Status)
+	case !m.invalid:
+	case resp.Status < 400 || resp.Status >= 500:
+		// Only a 4xx tells the client its input was at fault; a documented
+		// 5xx still means the server did not validate the request.
+		out.rule, out.sev = FUZZ_INVALID_ACCEPTED, report.SEVERITY_ERROR
+		out.message = fmt.Sprintf("invalid %s: %s (%s) was answered with %d instead of a 4xx", m.label, m.what, m.kind, resp.Status)
+	case declared == nil:
+		out.rule, out.sev = FUZZ_REJECTION_UNDOCUMENTED, report.SEVERITY_WARNING
+		out.message = fmt.Sprintf("invalid %s: %s (%s) was rejected with undocumented %d", m.label, m.what, m.kind, resp.Status)
+	}
+	return out
+}
+
+// shrink minimizes a failing request: it drops optional parameters and
+// body fields and halves oversized strings while the request keeps
+// failing with the same rule.
+func (f fuzzer) shrink(ctx context.Context, op openapi.Operation, m mutation, r fuzzRequest, out fuzzOutcome) (fuzzRequest, fuzzOutcome) {
+	for steps := 0; steps < maxShrinkSteps; {
+		progressed := false
+		for _, cand := range simplifications(op, m, r) {
+			if steps >= maxShrinkSteps || ctx.Err() != nil {
+				return r, out
+			}
+			steps++
+			if o := f.try(ctx, op, m, cand); o.rule == out.rule {
+				r, out, progressed = cand, o, true
+				break
+			}
+		}
+		if !progressed {
+			break
+		}
+	}
+	return r, out
+}
+
+// simplifications lists the requests one step simpler than r that still
+// carry mutation m.
+func simplifications(op openapi.Operation, m mutation, r fuzzRequest) []fuzzRequest {
+	var out []fuzzRequest
+	for _, p := range op.Parameters {
+		if p.Required || (p.In == m.target.in && p.Name == m.target.name) {
+			continue
+		}
+		switch p.In {
+		case openapi.PARAM_IN_QUERY:
+			if _, ok := r.c.Query[p.Name]; ok {
+				c := r.clone()
+				delete(c.c.Query, p.Name)
+				out = append(out, c)
+			}
+		case openapi.PARAM_IN_HEADER:
+			if _, ok := r.c.Headers[p.Name]; ok {
+				c := r.clone()
+				delete(c.c.Headers, p.Name)
+				out = append(out, c)
+			}
+		}
+	}
+	if r.json && !r.noBody {
+		for _, path := range optionalBodyPaths(requestSchema(op, r.c.ContentType), r.body, nil) {
+			if m.target.in == fuzzBody && (hasPrefix(path, m.target.path) || hasPrefix(m.target.path, path)) {
+				continue
+			}
+			c := r.clone()
+			c.body = deletePointer(c.body, path)
+			out = append(out, c)
+		}
+	}
+	if m.kind == report.MUTATION_OVERSIZED {
+		if v, ok := r.get(m.target); ok {
+			if s, ok := v.(string); ok && len(s) > 1 {
+				c := r.clone()
+				c.set(m.target, s[:len(s)/2])
+				out = append(out, c)
+			}
+		}
+	}
+	return out
+}
+
+// optionalBodyPaths lists the body fields that can be dropped: optional
+// properties, and properties the schema does not declare.
+func optionalBodyPaths(s *openapi.Schema, v any, path []string) [][]string {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil
+	}
+	props, required := objectProperties(s)
+	var out [][]string
+	for _, name := range sortedKeys(obj) {
+		child := append(append([]string{}, path...), name)
+		if !required[name] {
+			out = append(out, child)
+			continue
+		}
+		out = append(out, optionalBodyPaths(props[name], obj[name], child)...)
+	}
+	return out
+}
+
+func hasPrefix(path, prefix []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if path[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// fuzzBody is the location of body targets.
+const fuzzBody = "body"
+
+// fuzzTarget locates a mutated value: a parameter, or a body field by
+// pointer tokens (an empty path is the whole body).
+type fuzzTarget struct {
+	in   string // a PARAM_IN_* location or fuzzBody
+	name string
+	path []string
+}
+
+// mutation changes one value of a valid request.
+//   - label names the target for people, e.g. `query parameter "limit"`.
+//   - what describes the new value, e.g. "-1, below the minimum 0".
+//   - invalid: the spec forbids the result, so the service must reject it.
+type mutation struct {
+	kind    string
+	target  fuzzTarget
+	label   string
+	what    string
+	value   any
+	remove  bool
+	invalid bool
+	pointer string
+}
+
+func (m mutation) apply(r *fuzzRequest) {
+	if m.remove {
+		r.remove(m.target)
+		return
+	}
+	r.set(m.target, m.value)
+}
+
+// fuzzMutations lists the mutations of op's request in spec order:
+// parameters, then the body from the root down.
+func fuzzMutations(op openapi.Operation, in fuzzRequest, rng *rand.Rand) []mutation {
+	opPointer := "/paths/" + pointerToken(op.Path) + "/" + strings.ToLower(op.Method)
+	var out []mutation
+	for i, p := range op.Parameters {
+		if p.In == openapi.PARAM_IN_COOKIE || (p.In == openapi.PARAM_IN_HEADER && ignoredHeaders[strings.ToLower(p.Name)]) {
+			continue
+		}
+		// Loaded models know where the parameter was declared (possibly on
+		// the path item); hand-built ones fall back to the operation's list.
+		pointer := p.Pointer
+		if pointer == "" {
+			pointer = opPointer + "/parameters/" + strconv.Itoa(i)
+		}
+		m := mutation{target: fuzzTarget{in: p.In, name: p.Name}, label: fmt.Sprintf("%s parameter %q", p.In, p.Name), pointer: pointer}
+		if p.Required && p.In != openapi.PARAM_IN_PATH {
+			missing := m
+			missing.kind, missing.what, missing.remove, missing.invalid = report.MUTATION_MISSING_REQUIRED, "left out", true, true
+			out = append(out, missing)
+		}
+		for _, vm := range valueMutations(p.Schema, true, rng) {
+			out = append(out, vm.on(m))
+		}
+	}
+
+	rb := op.RequestBody
+	if rb == nil || !in.json {
+		return out
+	}
+	pointer := opPointer + "/requestBody"
+	if rb.Required {
+		out = append(out, mutation{
+			kind: report.MUTATION_MISSING_REQUIRED, target: fuzzTarget{in: fuzzBody}, label: "request body",
+			what: "left out", remove: true, invalid: true, pointer: pointer,
+		})
+	}
+	return append(out, bodyMutations(requestSchema(op, in.c.ContentType), in.body, nil, pointer, rng, 0)...)
+}
+
+// bodyMutations mutates the body value v at path, then its fields.
+func bodyMutations(s *openapi.Schema, v any, path []string, pointer string, rng *rand.Rand, depth int) []mutation {
+	label := "request body"
+	if len(path) > 0 {
+		label += " /" + strings.Join(path, "/")
+	}
+	m := mutation{target: fuzzTarget{in: fuzzBody, path: path}, label: label, pointer: pointer}
+	var out []mutation
+	for _, vm := range valueMutations(s, false, rng) {
+		out = append(out, vm.on(m))
+	}
+	if depth >= maxFuzzDepth {
+		return out
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		props, required := objectProperties(s)
+		for _, name := range sortedKeys(props) {
+			p := props[name]
+			if p == nil || p.ReadOnly {
+				continue
+			}
+			child := append(append([]string{}, path...), name)
+			if required[name] {
+				out = append(out, mutation{
+					kind: report.MUTATION_MISSING_REQUIRED, target: fuzzTarget{in: fuzzBody, path: child},
+					label: label + " field " + strconv.Quote(name), what: "left out", remove: true, invalid: true, pointer: pointer,
+				})
+			}
+			if value, ok := v[name]; ok {
+				out = append(out, bodyMutations(p, value, child, pointer, rng, depth+1)...)
+			} else {
+				// Absent optional fields are set, not descended into.
+				out = append(out, bodyMutations(p, nil, child, pointer, rng, maxFuzzDepth)...)
+			}
+		}
+	case []any:
+		if s != nil && len(v) > 0 {
+			out = append(out, bodyMutations(s.Items, v[0], append(append([]string{}, path...), "0"), pointer, rng, depth+1)...)
+		}
+	}
+	return out
+}
+
+// objectProperties merges the properties and required names of s and its
+// allOf members.
+func objectProperties(s *openapi.Schema) (map[string]*openapi.Schema, map[string]bool) {
+	props, required := map[string]*openapi.Schema{}, map[string]bool{}
+	if s == nil {
+		return props, required
+	}
+	for _, part := range append([]*openapi.Schema{s}, s.AllOf...) {
+		if part == nil {
+			continue
+		}
+		for name, p := range part.Properties {
+			props[name] = p
+		}
+		for _, name := range part.Required {
+			required[name] = true
+		}
+	}
+	return props, required
+}
+
+// valueMutation is a replacement value for a schema.
+type valueMutation struct {
+	kind    string
+	what    string
+	value   any
+	invalid bool
+}
+
+func (vm valueMutation) on(m mutation) mutation {
+	m.kind, m.what, m.value, m.invalid = vm.kind, vm.what, vm.value, vm.invalid
+	return m
+}
+
+// valueMutations lists replacements for a value of s: an invalid enum
+// value, a value of the wrong type, values just past each bound, and an
+// oversized string when the length is unbounded. text is set for
+// parameters, whose values are sent as text.
+func valueMutations(s *openapi.Schema, text bool, rng *rand.Rand) []valueMutation {
+	if s == nil {
+		return nil
+	}
+	var out []valueMutation
+	add := func(kind string, value any, invalid bool, format string, args ...any) {
+		out = append(out, valueMutation{kind: kind, value: value, invalid: invalid, what: fmt.Sprintf(format, args...)})
+	}
+	if len(s.Enum) > 0 {
+		v := invalidEnumValue(s.Enum)
+		add(report.MUTATION_INVALID_ENUM, v, true, "%s, not an enum value", showValue(v))
+	}
+	if v, ok := wrongTypeValue(s, text, rng); ok {
+		add(report.MUTATION_WRONG_TYPE, v, true, "%s instead of %s", showValue(v), strings.Join(s.Type, " or "))
+	}
+
+	switch {
+	case s.HasType("integer") || s.HasType("number"):
+		if s.Minimum != nil {
+			v := *s.Minimum - 1
+			if s.ExclusiveMinimum {
+				v = *s.Minimum
+			}
+			add(report.MUTATION_BOUNDARY, v, true, "%s, below the minimum %s", showValue(v), showValue(*s.Minimum))
+		}
+		if s.Maximum != nil {
+			v := *s.Maximum + 1
+			if s.ExclusiveMaximum {
+				v = *s.Maximum
+			}
+			add(report.MUTATION_BOUNDARY, v, true, "%s, above the maximum %s", showValue(v), showValue(*s.Maximum))
+		}
+		if !s.HasType("number") {
+			add(report.MUTATION_BOUNDARY, 1.5, true, "1.5, not an integer")
+		}
+	case s.HasType("string") && len(s.Enum) == 0:
+		if s.MinLength > 0 {
+			n := int(s.MinLength - 1)
+			add(report.MUTATION_BOUNDARY, randomString(rng, n), true, "%d character(s), below minLength %d", n, s.MinLength)
+		}
+		if s.MaxLength != nil {
+			n := int(*s.MaxLength + 1)
+			add(report.MUTATION_BOUNDARY, randomString(rng, n), true, "%d characters, above maxLength %d", n, *s.MaxLength)
+		} else {
+			add(report.MUTATION_OVERSIZED, randomString(rng, OVERSIZED_STRING_LENGTH), false, "%d characters", OVERSIZED_STRING_LENGTH)
+		}
+	case s.HasType("array"):
+		item := sampleValue(s.Items, openapi.SCHEMA_REQUEST)
+		if s.MinItems > 0 {
+			n := int(s.MinItems - 1)
+			add(report.MUTATION_BOUNDARY, repeatValue(item, n), true, "%d item(s), below minItems %d", n, s.MinItems)
+		}
+		if s.MaxItems != nil {
+			n := int(*s.MaxItems + 1)
+			add(report.MUTATION_BOUNDARY, repeatValue(item, n), true, "%d items, above maxItems %d", n, *s.MaxItems)
+		}
+	}
+	return out
+}
+
+// wrongTypeValue picks a value none of s's types accept. Parameters only
+// get one for numbers and booleans: any text is a valid string.
+func wrongTypeValue(s *openapi.Schema, text bool, rng *rand.Rand) (any, bool) {
+	if len(s.Type) == 0 {
+		return nil, false
+	}
+	if text {
+		switch {
+		case s.HasType("string"):
+			return nil, false
+		case s.HasType("integer") || s.HasType("number"):
+			return "not-a-number", true
+		case s.HasType("boolean"):
+			return "not-a-boolean", true
+		}
+		return nil, false
+	}
+	candidates := []struct {
+		types []string
+		value any
+	}{
+		{[]string{"string"}, "not-a-" + s.Type[0]},
+		{[]string{"integer", "number"}, 12345.0},
+		{[]string{"boolean"}, true},
+		{[]string{"object"}, map[string]any{}},
+		{[]string{"array"}, []any{}},
+		{[]string{"null"}, nil},
+	}
+	var values []any
+	for _, c := range candidates {
+		accepted := c.value == nil && s.Nullable
+		for _, t := range c.types {
+			accepted = accepted || s.HasType(t)
+		}
+		if !accepted {
+			values = append(values, c.value)
+		}
+	}
+	if len(values) == 0 {
+		return nil, false
+	}
+	return values[rng.IntN(len(values))], true
+}
+
+// invalidEnumValue returns a value of the enum's type outside it.
+func invalidEnumValue(enum []any) any {
+	in := func(v any) bool {
+		for _, e := range enum {
+			if e == v {
+				return true
+			}
+		}
+		return false
+	}
+	if _, ok := enum[0].(float64); ok {
+		max := 0.0
+		for _, e := range enum {
+			if n, ok := e.(float64); ok && n > max {
+				max = n
+			}
+		}
+		return max + 1
+	}
+	v := "invalid"
+	for in(v) {
+		v += "_"
+	}
+	return v
+}
+
+func repeatValue(v any, n int) []any {
+	out := make([]any, n)
+	for i := range out {
+		out[i] = v
+	}
+	return out
+}
+
+func randomString(rng *rand.Rand, n int) string {
+	const letters = "abcdefghijklmnopqrstuvwxyz"
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = letters[rng.IntN(len(letters))]
+	}
+	return string(b)
+}
+
+// showValue renders a mutated value for messages, shortening long strings.
+func showValue(v any) string {
+	if s, ok := v.(string); ok && len(s) > 32 {
+		return fmt.Sprintf("%q... (%d characters)", s[:16], len(s))
+	}
+	raw, _ := json.Marshal(v)
+	return string(raw)
+}
+
+func shorten(s string, max int) string {
+	if len(s) > max {
+		return s[:max] + "..."
+	}
+	return s
+}
+
+// operationSeed derives an operation's random stream from its key.
+func operationSeed(op openapi.Operation) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(op.Key()))
+	return h.Sum64()
+}
+
+// requestSchema is the schema of the request body of the given media type.
+func requestSchema(op openapi.Operation, mediaType string) *openapi.Schema {
+	if op.RequestBody == nil {
+		return nil
+	}
+	return op.RequestBody.Content[mediaType].Schema
+}
+
+// fuzzRequest is a request being mutated: a case with its JSON body
+// decoded, so body fields can be changed.
+type fuzzRequest struct {
+	c      testgen.Case
+	body   any
+	json   bool
+	noBody bool
+}
+
+func newFuzzRequest(c testgen.Case) fuzzRequest {
+	r := fuzzRequest{c: c}
+	if c.Body != "" && isJSONMediaType(c.ContentType) && json.Unmarshal([]byte(c.Body), &r.body) == nil {
+		r.json = true
+	}
+	return r.clone()
+}
+
+// clone copies r deeply enough to mutate the copy.
+func (r fuzzRequest) clone() fuzzRequest {
+	out := r
+	out.c.PathParams = map[string]string{}
+	for k, v := range r.c.PathParams {
+		out.c.PathParams[k] = v
+	}
+	out.c.Query = map[string][]string{}
+	for k, v := range r.c.Query {
+		out.c.Query[k] = append([]string{}, v...)
+	}
+	out.c.Headers = map[string]string{}
+	for k, v := range r.c.Headers {
+		out.c.Headers[k] = v
+	}
+	out.body = copyJSON(r.body)
+	return out
+}
+
+// testCase renders r back into a case.
+func (r fuzzRequest) testCase() testgen.Case {
+	c := r.c
+	if !r.json {
+		return c
+	}
+	if r.noBody {
+		c.ContentType, c.Body = "", ""
+		return c
+	}
+	raw, _ := json.Marshal(r.body)
+	c.Body = string(raw)
+	return c
+}
+
+func (r *fuzzRequest) get(t fuzzTarget) (any, bool) {
+	switch t.in {
+	case openapi.PARAM_IN_PATH:
+		v, ok := r.c.PathParams[t.name]
+		return v, ok
+	case openapi.PARAM_IN_QUERY:
+		if v, ok := r.c.Query[t.name]; ok && len(v) > 0 {
+			return v[0], true
+		}
+	case openapi.PARAM_IN_HEADER:
+		v, ok := r.c.Headers[t.name]
+		return v, ok
+	case fuzzBody:
+		return getPointer(r.body, t.path)
+	}
+	return nil, false
+}
+
+func (r *fuzzRequest) set(t fuzzTarget, v any) {
+	switch t.in {
+	case openapi.PARAM_IN_PATH:
+		r.c.PathParams[t.name] = sampleText(v)
+	case openapi.PARAM_IN_QUERY:
+		r.c.Query[t.name] = []string{sampleText(v)}
+	case openapi.PARAM_IN_HEADER:
+		r.c.Headers[t.name] = sampleText(v)
+	case fuzzBody:
+		r.body = setPointer(r.body, t.path, v)
+	}
+}
+
+func (r *fuzzRequest) remove(t fuzzTarget) {
+	switch t.in {
+	case openapi.PARAM_IN_QUERY:
+		delete(r.c.Query, t.name)
+	case openapi.PARAM_IN_HEADER:
+		delete(r.c.Headers, t.name)
+	case fuzzBody:
+		if len(t.path) == 0 {
+			r.noBody = true
+			return
+		}
+		r.body = deletePointer(r.body, t.path)
+	}
+}
+
+func copyJSON(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, e := range v {
+			out[k] = copyJSON(e)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = copyJSON(e)
+		}
+		return out
+	}
+	return v
+}
+
+func getPointer(v any, path []string) (any, bool) {
+	for _, tok := range path {
+		switch c := v.(type) {
+		case map[string]any:
+			var ok bool
+			if v, ok = c[tok]; !ok {
+				return nil, false
+			}
+		case []any:
+			i, err := strconv.Atoi(tok)
+			if err != nil || i < 0 || i >= len(c) {
+				return nil, false
+			}
+			v = c[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// setPointer sets the value at path in v, creating objects on the way,
+// and returns the updated v.
+func setPointer(v any, path []string, x any) any {
+	if len(path) == 0 {
+		return x
+	}
+	switch c := v.(type) {
+	case map[string]any:
+		c[path[0]] = setPointer(c[path[0]], path[1:], x)
+		return c
+	case []any:
+		if i, err := strconv.Atoi(path[0]); err == nil && i >= 0 && i < len(c) {
+			c[i] = setPointer(c[i], path[1:], x)
+		}
+		return c
+	}
+	return map[string]any{path[0]: setPointer(nil, path[1:], x)}
+}
+
+// deletePointer removes the object field at path from v and returns the
+// updated v.
+func deletePointer(v any, path []string) any {
+	if len(path) == 0 {
+		return v
+	}
+	parent, ok := getPointer(v, path[:len(path)-1])
+	if obj, isObj := parent.(map[string]any); ok && isObj {
+		delete(obj, path[len(path)-1])
+	}
+	return v
+}
+
+// compile-time check
+var _ input.FuzzAPI = (*FuzzService)(nil)
@@ -0,0 +1,178 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+	"github.com/betoth/contractcheck/internal/application/service"
+)
+
+// sloppyPets validates some inputs, crashes on others and accepts the rest.
+var sloppyPets = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	switch r.Method + " " + r.URL.Path {
+	case "GET /pets":
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		switch {
+		case r.URL.Query().Has("limit") && err != nil:
+			w.WriteHeader(http.StatusBadRequest)
+		case limit > 50:
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[]`))
+		}
+	case "POST /pets":
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["name"] == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if name, ok := body["name"].(string); !ok || len(name) > 1000 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+})
+
+func fuzzPetAPI() *openapi.Document {
+	return petAPI(func(d *openapi.Document) {
+		d.Servers = []openapi.Server{{URL: "http://pets.test"}}
+		post := &d.Operations[0]
+		post.Responses = append(post.Responses, openapi.Response{Status: "400"})
+		mt := post.RequestBody.Content["application/json"]
+		mt.Example = map[string]any{"name": "rex", "tag": "extra"}
+		post.RequestBody.Content["application/json"] = mt
+		d.Operations = append(d.Operations, openapi.Operation{
+			Path: "/pets", Method: "GET",
+			Parameters: []openapi.Parameter{{Name: "limit", In: openapi.PARAM_IN_QUERY, Schema: &openapi.Schema{Type: []string{"integer"}, Maximum: float(50)}}},
+			Responses:  []openapi.Response{{Status: "200"}, {Status: "400"}},
+		})
+	})
+}
+
+func newFuzzService(t *testing.T, doc *openapi.Document) *service.FuzzService {
+	t.Helper()
+	svc, err := service.NewFuzzService(service.FuzzParams{
+		Importer: modelImporter{doc: doc},
+		Client:   &handlerClient{handler: sloppyPets},
+		Logger:   nopLogger{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svc
+}
+
+func TestFuzzService_FindsMishandledInputs(t *testing.T) {
+	svc := newFuzzService(t, fuzzPetAPI())
+	run, err := svc.Fuzz(context.Background(), "pets.yaml", input.FuzzOptions{Seed: 42})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Seed != 42 || run.Operations != 4 || run.Cases == 0 {
+		t.Errorf("run = seed %d, %d operation(s), %d case(s)", run.Seed, run.Operations, run.Cases)
+	}
+
+	var got []string
+	for _, f := range run.Failures {
+		got = append(got, f.Operation+" "+f.RuleID+" "+f.Target+" "+f.Mutation)
+	}
+	want := []string{
+		`GET /pets fuzz-rejection-undocumented query parameter "limit" boundary`,
+		`POST /pets fuzz-server-error request body /name wrong-type`,
+		`POST /pets fuzz-server-error request body /name oversized`,
+		`POST /pets fuzz-invalid-accepted request body /status invalid-enum`,
+		`POST /pets fuzz-invalid-accepted request body /status wrong-type`,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("failures =\n%q\nwant\n%q", got, want)
+	}
+	if ids := findingIDs(run.Findings); len(ids) != len(want) {
+		t.Errorf("findings = %q", ids)
+	}
+	for _, f := range run.Findings {
+		if f.Check != report.CHECK_FUZZ || f.Pointer == "" {
+			t.Errorf("finding %s: check %q, pointer %q", f.RuleID, f.Check, f.Pointer)
+		}
+	}
+	if p := run.Findings[0].Pointer; p != "/paths/~1pets/get/parameters/0" {
+		t.Errorf("parameter finding points at %q", p)
+	}
+
+	// Failing cases are minimized: the optional field is dropped and the
+	// oversized name halved down to the shortest length that still fails.
+	for _, f := range run.Failures {
+		var body map[string]any
+		if f.Body != "" {
+			if err := json.Unmarshal([]byte(f.Body), &body); err != nil {
+				continue // shortened
+			}
+		}
+		if _, ok := body["tag"]; ok {
+			t.Errorf("%s %s: optional field not dropped: %s", f.Target, f.Mutation, f.Body)
+		}
+		if f.Mutation == report.MUTATION_OVERSIZED {
+			if name, _ := body["name"].(string); len(name) != 1024 {
+				t.Errorf("oversized name has %d characters, want 1024", len(name))
+			}
+		}
+	}
+	if f := run.Failures[0]; f.URL != "http://pets.test/pets?limit=51" || f.Status != http.StatusUnprocessableEntity {
+		t.Errorf("boundary failure = %s %d", f.URL, f.Status)
+	}
+
+	again, err := svc.Fuzz(context.Background(), "pets.yaml", input.FuzzOptions{Seed: 42, Concurrency: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again.Failures, run.Failures) || again.Cases != run.Cases {
+		t.Error("a run with the same seed sent different requests")
+	}
+}
+
+func TestFuzzService_DocumentedServerErrorIsNotARejection(t *testing.T) {
+	doc := fuzzPetAPI()
+	post := &doc.Operations[slices.IndexFunc(doc.Operations, func(op openapi.Operation) bool { return op.Key() == "POST /pets" })]
+	post.Responses = append(post.Responses, openapi.Response{Status: "500"})
+
+	run, err := newFuzzService(t, doc).Fuzz(context.Background(), "pets.yaml", input.FuzzOptions{Seed: 42})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range run.Failures {
+		if f.Target == "request body /name" {
+			got = append(got, f.RuleID+" "+f.Mutation+" "+strconv.Itoa(f.Status))
+		}
+	}
+	// The oversized name is valid (no maxLength), so its documented 500 passes.
+	want := []string{"fuzz-invalid-accepted wrong-type 500"}
+	if !slices.Equal(got, want) {
+		t.Errorf("failures =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestFuzzService_CasesLimit(t *testing.T) {
+	run, err := newFuzzService(t, fuzzPetAPI()).Fuzz(context.Background(), "pets.yaml", input.FuzzOptions{Seed: 7, Cases: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// One case per operation with mutations; DELETE has none.
+	if run.Cases != 3 {
+		t.Errorf("cases = %d, want 3", run.Cases)
+	}
+	if _, err := newFuzzService(t, fuzzPetAPI()).Fuzz(context.Background(), "pets.yaml", input.FuzzOptions{Cases: -1}); err == nil {
+		t.Error("negative cases: want an error")
+	}
+}
@@ -0,0 +1,543 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/traffic"
+)
+
+// INFERRED_OPENAPI_VERSION is the OpenAPI version of inferred drafts.
+const INFERRED_OPENAPI_VERSION = "3.0.3"
+
+// DEFAULT_INFERRED_VERSION is the info.version of a draft unless set.
+const DEFAULT_INFERRED_VERSION = "0.1.0"
+
+// InferParams declares the dependencies required to build the service.
+type InferParams struct {
+	Traffic traffic.Reader
+	Parser  openapi.Parser
+	Logger  output.Logger
+}
+
+// validate performs defensive checks on constructor params.
+func (p InferParams) validate() error {
+	if p.Traffic == nil {
+		return customerrors.NewDependencyError("traffic")
+	}
+	if p.Parser == nil {
+		return customerrors.NewDependencyError("parser")
+	}
+	if p.Logger == nil {
+		return customerrors.NewDependencyError("logger")
+	}
+	return nil
+}
+
+// InferService drafts specs from recorded traffic (input port implementation).
+type InferService struct {
+	traffic traffic.Reader
+	parser  openapi.Parser
+	logger  output.Logger
+}
+
+// NewInferService constructs the service after validating dependencies.
+func NewInferService(params InferParams) (*InferService, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	return &InferService{traffic: params.Traffic, parser: params.Parser, logger: params.Logger}, nil
+}
+
+// Infer reads every traffic file, keeps the exchanges sent to the base URL
+// and drafts a document from them. CORS preflight requests are ignored. The
+// draft is validated by the parser; a draft that does not validate is an
+// error, not a result.
+func (s *InferService) Infer(ctx context.Context, trafficPaths []string, opts input.InferOptions) (openapi.OpenAPIDoc, error) {
+	log := s.logger.With("local", "service.InferService.Infer")
+	if len(trafficPaths) == 0 {
+		return openapi.OpenAPIDoc{}, customerrors.NewValidationError(
+			"No traffic files", errors.New("at least one traffic file is required"), nil)
+	}
+
+	var exchanges []traffic.Exchange
+	for _, path := range trafficPaths {
+		ex, err := s.traffic.Read(ctx, path)
+		if err != nil {
+			log.Error("failed to read traffic", "file", path)
+			return openapi.OpenAPIDoc{}, err
+		}
+		exchanges = append(exchanges, ex...)
+	}
+
+	base, err := inferBase(exchanges, opts.BaseURL)
+	if err != nil {
+		return openapi.OpenAPIDoc{}, err
+	}
+	inf := newInference()
+	for _, ex := range exchanges {
+		if isPreflight(ex.Request) {
+			continue
+		}
+		if rel, ok := underBase(base, ex.Request.URL); ok {
+			inf.add(ex, rel)
+		}
+	}
+	if inf.exchanges == 0 {
+		return openapi.OpenAPIDoc{}, customerrors.NewValidationError(
+			"No exchanges to infer from", fmt.Errorf("no recorded request went to %s", base), nil)
+	}
+
+	data, err := json.Marshal(inf.document(base, opts))
+	if err != nil {
+		return openapi.OpenAPIDoc{}, err
+	}
+	doc, err := s.parser.Parse(ctx, data, "inferred spec")
+	if err != nil {
+		log.Error("inferred spec is invalid", "base", base.String())
+		return openapi.OpenAPIDoc{}, err
+	}
+	// Keep the draft's own key order (openapi, info, servers, paths), which
+	// reads better than the parser's.
+	doc.JSON = data
+	log.Debug("spec inferred",
+		"base", base.String(),
+		"exchanges", len(exchanges),
+		"used", inf.exchanges,
+		"operations", len(inf.operations),
+	)
+	return doc, nil
+}
+
+// inferBase parses the explicit base URL, or picks the origin most
+// exchanges went to (the smallest on ties, for stable output).
+func inferBase(exchanges []traffic.Exchange, explicit string) (*url.URL, error) {
+	if explicit != "" {
+		u, err := url.Parse(explicit)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			if err == nil {
+				err = errors.New("want an absolute URL such as https://api.example.com/v1")
+			}
+			return nil, customerrors.NewValidationError("Invalid base URL", err, map[string]any{"baseUrl": explicit})
+		}
+		return &url.URL{Scheme: u.Scheme, Host: u.Host, Path: strings.TrimSuffix(u.Path, "/")}, nil
+	}
+	counts := map[string]int{}
+	for _, ex := range exchanges {
+		if u, err := url.Parse(ex.Request.URL); err == nil && u.Host != "" {
+			counts[u.Scheme+"://"+strings.ToLower(u.Host)]++
+		}
+	}
+	best := ""
+	for _, origin := range sortedKeys(counts) {
+		if best == "" || counts[origin] > counts[best] {
+			best = origin
+		}
+	}
+	if best == "" {
+		return nil, customerrors.NewValidationError(
+			"No exchanges to infer from", errors.New("the traffic holds no absolute request URLs"), nil)
+	}
+	return url.Parse(best)
+}
+
+// underBase returns the path of raw relative to base, when raw was sent to it.
+func underBase(base *url.URL, raw string) (*url.URL, bool) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != base.Scheme || !strings.EqualFold(u.Host, base.Host) {
+		return nil, false
+	}
+	rest, ok := strings.CutPrefix(u.Path, base.Path)
+	if !ok || (rest != "" && rest[0] != '/') {
+		return nil, false
+	}
+	if rest == "" {
+		rest = "/"
+	}
+	rel := *u
+	rel.Path, rel.RawPath = rest, ""
+	return &rel, true
+}
+
+// isPreflight tells CORS preflight requests, which browsers send on their
+// own, from OPTIONS requests an application makes.
+func isPreflight(req output.HTTPRequest) bool {
+	return req.Method == http.MethodOptions && req.Header.Get("Access-Control-Request-Method") != ""
+}
+
+// Path segments that identify a resource rather than name a collection.
+var (
+	numericIDPattern = regexp.MustCompile(`^[0-9]+$`)
+	uuidPattern      = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hexIDPattern     = regexp.MustCompile(`^[0-9a-fA-F]{16,}$`) // e.g. MongoDB ObjectIds
+	tokenIDPattern   = regexp.MustCompile(`^[A-Za-z0-9_-]{20,}$`)
+)
+
+// isIdentifier tells whether a path segment is an ID: a number, a UUID, a
+// long hex string, or a long token mixing letters and digits.
+func isIdentifier(segment string) bool {
+	switch {
+	case numericIDPattern.MatchString(segment), uuidPattern.MatchString(segment), hexIDPattern.MatchString(segment):
+		return true
+	case tokenIDPattern.MatchString(segment):
+		return strings.ContainsFunc(segment, unicode.IsDigit) && strings.ContainsFunc(segment, unicode.IsLetter)
+	}
+	return false
+}
+
+// templatePath turns a decoded path into a template: identifier segments
+// become parameters named after the segment before them ("/pets/42" is
+// "/pets/{petId}"). It returns the template and the parameter values.
+func templatePath(path string) (string, []pathValue) {
+	if path == "/" || path == "" {
+		return "/", nil
+	}
+	var (
+		out    []string
+		values []pathValue
+		used   = map[string]bool{}
+	)
+	prev := ""
+	for _, seg := range strings.Split(strings.Trim(path, "/"), "/") {
+		if !isIdentifier(seg) {
+			out = append(out, seg)
+			prev = seg
+			continue
+		}
+		name := "id"
+		if word := camelCase(singular(prev)); word != "" {
+			name = lowerFirst(word) + "Id"
+		}
+		for i := 2; used[name]; i++ {
+			name = fmt.Sprintf("%s%d", strings.TrimRight(name, "0123456789"), i)
+		}
+		used[name] = true
+		out = append(out, "{"+name+"}")
+		values = append(values, pathValue{name: name, value: seg})
+		prev = ""
+	}
+	return "/" + strings.Join(out, "/"), values
+}
+
+type pathValue struct {
+	name  string
+	value string
+}
+
+// singular strips the plural of an English collection name, well enough
+// for parameter names: "categories" is "category", "pets" is "pet".
+func singular(word string) string {
+	lower := strings.ToLower(word)
+	switch {
+	case strings.HasSuffix(lower, "ies") && len(word) > 3:
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(lower, "sses"), strings.HasSuffix(lower, "xes"), strings.HasSuffix(lower, "ches"), strings.HasSuffix(lower, "shes"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(lower, "s") && !strings.HasSuffix(lower, "ss") && len(word) > 1:
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+// camelCase joins the words of "pet-owners" or "pet_owners" as "PetOwners".
+func camelCase(s string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// inference accumulates exchanges per operation.
+type inference struct {
+	exchanges  int
+	operations map[string]*inferredOperation // by "METHOD /template"
+}
+
+type inferredOperation struct {
+	method     string
+	path       string
+	calls      int
+	pathParams []string
+	pathValues map[string]*shape
+	query      map[string]*inferredQuery
+	bodies     int
+	request    map[string]*shape // by media type; nil shape for opaque bodies
+	responses  map[int]map[string]*shape
+}
+
+type inferredQuery struct {
+	calls    int
+	repeated bool
+	values   *shape
+}
+
+func newInference() *inference {
+	return &inference{operations: map[string]*inferredOperation{}}
+}
+
+// add records one exchange; rel is its URL relative to the base.
+func (inf *inference) add(ex traffic.Exchange, rel *url.URL) {
+	template, values := templatePath(rel.Path)
+	key := ex.Request.Method + " " + template
+	op := inf.operations[key]
+	if op == nil {
+		op = &inferredOperation{
+			method:     ex.Request.Method,
+			path:       template,
+			pathValues: map[string]*shape{},
+			query:      map[string]*inferredQuery{},
+			request:    map[string]*shape{},
+			responses:  map[int]map[string]*shape{},
+		}
+		for _, v := range values {
+			op.pathParams = append(op.pathParams, v.name)
+			op.pathValues[v.name] = &shape{noEnum: true}
+		}
+		inf.operations[key] = op
+	}
+	inf.exchanges++
+	op.calls++
+
+	for _, v := range values {
+		op.pathValues[v.name].observeText(v.value)
+	}
+	for name, vals := range rel.Query() {
+		q := op.query[name]
+		if q == nil {
+			q = &inferredQuery{values: &shape{}}
+			op.query[name] = q
+		}
+		q.calls++
+		q.repeated = q.repeated || len(vals) > 1
+		for _, v := range vals {
+			q.values.observeText(v)
+		}
+	}
+	if len(ex.Request.Body) > 0 {
+		op.bodies++
+		observeBody(op.request, ex.Request.Header.Get("Content-Type"), ex.Request.Body)
+	}
+	content := op.responses[ex.Response.Status]
+	if content == nil {
+		content = map[string]*shape{}
+		op.responses[ex.Response.Status] = content
+	}
+	if len(ex.Response.Body) > 0 {
+		observeBody(content, ex.Response.Header.Get("Content-Type"), ex.Response.Body)
+	}
+}
+
+// observeBody adds a body to the shapes of its media type. JSON and form
+// bodies are inferred; other bodies are opaque (a nil shape).
+func observeBody(content map[string]*shape, contentType string, body []byte) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "application/octet-stream"
+	}
+	s, seen := content[mediaType]
+	switch {
+	case isJSONMediaType(mediaType):
+		var v any
+		if err := json.Unmarshal(body, &v); err != nil {
+			if !seen {
+				content[mediaType] = nil
+			}
+			return
+		}
+		if s == nil {
+			s = &shape{}
+			content[mediaType] = s
+		}
+		s.observe(v, 0)
+	case mediaType == "application/x-www-form-urlencoded":
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			if !seen {
+				content[mediaType] = nil
+			}
+			return
+		}
+		if s == nil {
+			s = &shape{}
+			content[mediaType] = s
+		}
+		s.observeForm(form)
+	default:
+		content[mediaType] = nil
+	}
+}
+
+// Document layout of drafts. Maps are written with sorted keys, so the
+// output is deterministic.
+type inferredDoc struct {
+	OpenAPI string                                      `json:"openapi"`
+	Info    inferredInfo                                `json:"info"`
+	Servers []inferredServer                            `json:"servers"`
+	Paths   map[string]map[string]*inferredOperationDoc `json:"paths"`
+}
+
+type inferredInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description"`
+}
+
+type inferredServer struct {
+	URL string `json:"url"`
+}
+
+type inferredOperationDoc struct {
+	OperationID string                         `json:"operationId"`
+	Parameters  []inferredParameter            `json:"parameters,omitempty"`
+	RequestBody *inferredRequestBody           `json:"requestBody,omitempty"`
+	Responses   map[string]inferredResponseDoc `json:"responses"`
+}
+
+type inferredParameter struct {
+	Name     string          `json:"name"`
+	In       string          `json:"in"`
+	Required bool            `json:"required,omitempty"`
+	Schema   *inferredSchema `json:"schema"`
+}
+
+type inferredRequestBody struct {
+	Required bool                         `json:"required,omitempty"`
+	Content  map[string]inferredMediaType `json:"content"`
+}
+
+type inferredResponseDoc struct {
+	Description string                       `json:"description"`
+	Content     map[string]inferredMediaType `json:"content,omitempty"`
+}
+
+type inferredMediaType struct {
+	Schema *inferredSchema `json:"schema,omitempty"`
+}
+
+// document renders the accumulated operations as an OpenAPI document.
+func (inf *inference) document(base *url.URL, opts input.InferOptions) inferredDoc {
+	doc := inferredDoc{
+		OpenAPI: INFERRED_OPENAPI_VERSION,
+		Info: inferredInfo{
+			Title:       opts.Title,
+			Version:     opts.Version,
+			Description: fmt.Sprintf("Drafted from %d recorded exchange(s); review before publishing.", inf.exchanges),
+		},
+		Servers: []inferredServer{{URL: base.String()}},
+		Paths:   map[string]map[string]*inferredOperationDoc{},
+	}
+	if doc.Info.Title == "" {
+		doc.Info.Title = base.Host + " API"
+	}
+	if doc.Info.Version == "" {
+		doc.Info.Version = DEFAULT_INFERRED_VERSION
+	}
+
+	ids := map[string]bool{}
+	for _, key := range sortedKeys(inf.operations) {
+		op := inf.operations[key]
+		if doc.Paths[op.path] == nil {
+			doc.Paths[op.path] = map[string]*inferredOperationDoc{}
+		}
+		out := &inferredOperationDoc{
+			OperationID: uniqueOperationID(ids, op.method, op.path),
+			Responses:   map[string]inferredResponseDoc{},
+		}
+		for _, name := range op.pathParams {
+			out.Parameters = append(out.Parameters, inferredParameter{
+				Name: name, In: openapi.PARAM_IN_PATH, Required: true, Schema: op.pathValues[name].schema(),
+			})
+		}
+		for _, name := range sortedKeys(op.query) {
+			q := op.query[name]
+			s := q.values.schema()
+			if q.repeated {
+				s = &inferredSchema{Type: "array", Items: s}
+			}
+			out.Parameters = append(out.Parameters, inferredParameter{
+				Name: name, In: openapi.PARAM_IN_QUERY, Required: q.calls == op.calls, Schema: s,
+			})
+		}
+		if op.bodies > 0 {
+			out.RequestBody = &inferredRequestBody{Required: op.bodies == op.calls, Content: mediaTypes(op.request)}
+		}
+		for status, content := range op.responses {
+			out.Responses[fmt.Sprint(status)] = inferredResponseDoc{
+				Description: responseDescription(status),
+				Content:     mediaTypes(content),
+			}
+		}
+		doc.Paths[op.path][strings.ToLower(op.method)] = out
+	}
+	return doc
+}
+
+func mediaTypes(content map[string]*shape) map[string]inferredMediaType {
+	if len(content) == 0 {
+		return nil
+	}
+	out := make(map[string]inferredMediaType, len(content))
+	for mediaType, s := range content {
+		mt := inferredMediaType{}
+		if s != nil {
+			mt.Schema = s.schema()
+		} else if !isJSONMediaType(mediaType) {
+			mt.Schema = &inferredSchema{Type: "string"}
+		}
+		out[mediaType] = mt
+	}
+	return out
+}
+
+func responseDescription(status int) string {
+	if text := http.StatusText(status); text != "" {
+		return text
+	}
+	return fmt.Sprintf("Status %d", status)
+}
+
+// uniqueOperationID names an operation after its method and path:
+// "GET /pets/{petId}" is "getPetsByPetId".
+func uniqueOperationID(used map[string]bool, method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, seg := range strings.Split(strings.Trim(path, "/"), "/") {
+		if name, ok := strings.CutPrefix(seg, "{"); ok {
+			b.WriteString("By" + camelCase(strings.TrimSuffix(name, "}")))
+			continue
+		}
+		b.WriteString(camelCase(seg))
+	}
+	if path == "/" {
+		b.WriteString("Root")
+	}
+	id := b.String()
+	for i := 2; used[id]; i++ {
+		id = fmt.Sprintf("%s%d", b.String(), i)
+	}
+	used[id] = true
+	return id
+}
+
+// compile-time check
+var _ input.InferOpenAPISpec = (*InferService)(nil)
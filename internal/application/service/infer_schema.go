@@ -0,0 +1,258 @@
+package service
+
+import (
+	"math"
+	"net/mail"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema inference limits.
+const (
+	// maxInferDepth bounds how deep bodies are inferred; deeper values are
+	// left untyped.
+	maxInferDepth = 10
+	// maxEnumValues is the most distinct strings inferred as an enum, and
+	// maxEnumLength the longest; each value must also be seen at least twice
+	// on average, so free text seen once is not mistaken for an enum.
+	maxEnumValues = 10
+	maxEnumLength = 32
+)
+
+// shape merges the observations of one value position (a body field, a
+// parameter) into a schema.
+//   - seen counts non-null observations; nulls the nulls.
+//   - kinds counts observations per JSON type.
+//   - strings counts distinct string values while they may form an enum;
+//     noEnum is set once they cannot.
+//   - formats counts string observations per detected format ("" for none).
+//   - objects counts object observations; props a shape per property.
+type shape struct {
+	seen    int
+	nulls   int
+	kinds   map[string]int
+	strings map[string]int
+	noEnum  bool
+	formats map[string]int
+	objects int
+	props   map[string]*shape
+	items   *shape
+}
+
+func (s *shape) kind(k string) {
+	if s.kinds == nil {
+		s.kinds = map[string]int{}
+	}
+	s.kinds[k]++
+	s.seen++
+}
+
+// observe adds a decoded JSON value.
+func (s *shape) observe(v any, depth int) {
+	switch v := v.(type) {
+	case nil:
+		s.nulls++
+	case bool:
+		s.kind("boolean")
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			s.kind("integer")
+		} else {
+			s.kind("number")
+		}
+	case string:
+		s.kind("string")
+		s.observeString(v)
+	case []any:
+		s.kind("array")
+		if depth >= maxInferDepth {
+			return
+		}
+		if s.items == nil {
+			s.items = &shape{}
+		}
+		for _, item := range v {
+			s.items.observe(item, depth+1)
+		}
+	case map[string]any:
+		s.kind("object")
+		s.objects++
+		if depth >= maxInferDepth {
+			return
+		}
+		if s.props == nil {
+			s.props = map[string]*shape{}
+		}
+		for name, item := range v {
+			p := s.props[name]
+			if p == nil {
+				p = &shape{}
+				s.props[name] = p
+			}
+			p.observe(item, depth+1)
+		}
+	}
+}
+
+// observeText adds a value that arrived as text (a parameter or a form
+// field): numbers and booleans are recognized, except numbers with leading
+// zeros, which are codes rather than quantities.
+func (s *shape) observeText(raw string) {
+	switch {
+	case raw == "true" || raw == "false":
+		s.kind("boolean")
+	case len(raw) > 1 && raw[0] == '0' && raw[1] != '.':
+		s.kind("string")
+		s.observeString(raw)
+	default:
+		if _, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			s.kind("integer")
+			return
+		}
+		if f, err := strconv.ParseFloat(raw, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+			s.kind("number")
+			return
+		}
+		s.kind("string")
+		s.observeString(raw)
+	}
+}
+
+// observeForm adds a form body as an object of text fields.
+func (s *shape) observeForm(form url.Values) {
+	s.kind("object")
+	s.objects++
+	if s.props == nil {
+		s.props = map[string]*shape{}
+	}
+	for name, values := range form {
+		p := s.props[name]
+		if p == nil {
+			p = &shape{}
+			s.props[name] = p
+		}
+		for _, v := range values {
+			p.observeText(v)
+		}
+	}
+}
+
+func (s *shape) observeString(v string) {
+	if s.formats == nil {
+		s.formats = map[string]int{}
+	}
+	format := stringFormat(v)
+	s.formats[format]++
+	if s.noEnum {
+		return
+	}
+	if format != "" || len(v) > maxEnumLength || strings.ContainsAny(v, " \t\n") {
+		s.noEnum, s.strings = true, nil
+		return
+	}
+	if s.strings == nil {
+		s.strings = map[string]int{}
+	}
+	s.strings[v]++
+	if len(s.strings) > maxEnumValues {
+		s.noEnum, s.strings = true, nil
+	}
+}
+
+// stringFormat detects the OpenAPI format of a string value.
+func stringFormat(v string) string {
+	switch {
+	case uuidPattern.MatchString(v):
+		return "uuid"
+	case isDateTime(v):
+		return "date-time"
+	case isDate(v):
+		return "date"
+	case strings.Contains(v, "@") && !strings.ContainsAny(v, " <>"):
+		if addr, err := mail.ParseAddress(v); err == nil && addr.Address == v {
+			return "email"
+		}
+	case strings.HasPrefix(v, "http://") || strings.HasPrefix(v, "https://"):
+		if u, err := url.Parse(v); err == nil && u.Host != "" {
+			return "uri"
+		}
+	}
+	return ""
+}
+
+func isDateTime(v string) bool {
+	_, err := time.Parse(time.RFC3339Nano, v)
+	return err == nil
+}
+
+func isDate(v string) bool {
+	_, err := time.Parse(time.DateOnly, v)
+	return err == nil
+}
+
+// inferredSchema is the OpenAPI 3.0 layout of an inferred schema.
+type inferredSchema struct {
+	Type       string                     `json:"type,omitempty"`
+	Format     string                     `json:"format,omitempty"`
+	Nullable   bool                       `json:"nullable,omitempty"`
+	Enum       []any                      `json:"enum,omitempty"`
+	Properties map[string]*inferredSchema `json:"properties,omitempty"`
+	Required   []string                   `json:"required,omitempty"`
+	Items      *inferredSchema            `json:"items,omitempty"`
+}
+
+// schema renders the merged observations. Integers mixed with decimals are
+// numbers; other mixes of types stay untyped, since OpenAPI 3.0 has no type
+// unions. A property is required when every object observed has it.
+func (s *shape) schema() *inferredSchema {
+	out := &inferredSchema{Nullable: s.nulls > 0}
+	kinds := make([]string, 0, len(s.kinds))
+	for k := range s.kinds {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	switch {
+	case len(kinds) == 1:
+		out.Type = kinds[0]
+	case len(kinds) == 2 && kinds[0] == "integer" && kinds[1] == "number":
+		out.Type = "number"
+	}
+	if out.Type == "" {
+		// nullable needs a type in OpenAPI 3.0.
+		out.Nullable = false
+	}
+
+	switch out.Type {
+	case "string":
+		if len(s.formats) == 1 {
+			for f := range s.formats {
+				out.Format = f
+			}
+		}
+		if !s.noEnum && !out.Nullable && len(s.strings) >= 2 && s.seen >= 2*len(s.strings) {
+			for _, v := range sortedKeys(s.strings) {
+				out.Enum = append(out.Enum, v)
+			}
+		}
+	case "array":
+		out.Items = &inferredSchema{}
+		if s.items != nil {
+			out.Items = s.items.schema()
+		}
+	case "object":
+		if len(s.props) > 0 {
+			out.Properties = make(map[string]*inferredSchema, len(s.props))
+		}
+		for _, name := range sortedKeys(s.props) {
+			p := s.props[name]
+			out.Properties[name] = p.schema()
+			if p.seen+p.nulls == s.objects {
+				out.Required = append(out.Required, name)
+			}
+		}
+	}
+	return out
+}
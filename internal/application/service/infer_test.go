@@ -0,0 +1,200 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/traffic"
+	"github.com/betoth/contractcheck/internal/application/service"
+)
+
+// jsonParser accepts any JSON document, recording what it was given.
+type jsonParser struct{ got []byte }
+
+func (p *jsonParser) Parse(_ context.Context, data []byte, name string) (openapi.OpenAPIDoc, error) {
+	p.got = data
+	if !json.Valid(data) {
+		return openapi.OpenAPIDoc{}, openapi.NewValidationError(openapi.INVALID_SYNTAX, "Invalid JSON syntax", name, errors.New("bad json"))
+	}
+	return openapi.OpenAPIDoc{JSON: data, Version: "3.0.3"}, nil
+}
+
+func petTraffic() []traffic.Exchange {
+	const owner = "3f2b8c1e-9d4a-4b7e-8f60-2a1c5d9e7b13"
+	preflight := exchange("OPTIONS", "https://api.example.com/v1/pets", "", 204, "")
+	preflight.Request.Header.Set("Access-Control-Request-Method", "POST")
+	return []traffic.Exchange{
+		exchange("GET", "https://api.example.com/v1/pets?status=available", "", 200, `[
+			{"id": 1, "name": "rex", "status": "available", "born": "2020-01-02"},
+			{"id": 2, "name": "tom", "status": "sold", "born": "2021-03-04", "tag": null},
+			{"id": 3, "name": "max", "status": "available", "born": "2022-05-06"},
+			{"id": 4, "name": "kit", "status": "sold", "born": "2023-07-08"}
+		]`),
+		exchange("GET", "https://api.example.com/v1/pets?status=sold&status=available", "", 200, `[]`),
+		exchange("GET", "https://api.example.com/v1/pets/1", "", 200, `{"id": 1, "name": "rex", "owner": {"email": "ana@example.com"}}`),
+		exchange("GET", "https://api.example.com/v1/pets/2", "", 404, ""),
+		exchange("GET", "https://api.example.com/v1/owners/"+owner+"/pets/1", "", 200, `{"id": 1, "weight": 4}`),
+		exchange("GET", "https://api.example.com/v1/owners/"+owner+"/pets/2", "", 200, `{"id": 2, "weight": 4.5}`),
+		exchange("POST", "https://api.example.com/v1/pets", `{"name": "max"}`, 201, `{"id": 3, "name": "max"}`),
+		preflight,
+		exchange("GET", "https://cdn.example.com/app.js", "", 200, ""),
+		exchange("GET", "https://api.example.com/health", "", 200, ""),
+	}
+}
+
+func newInferService(t *testing.T, parser openapi.Parser) *service.InferService {
+	t.Helper()
+	svc, err := service.NewInferService(service.InferParams{
+		Traffic: memTraffic{"browser.har": petTraffic()},
+		Parser:  parser,
+		Logger:  nopLogger{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svc
+}
+
+// at walks a decoded JSON document along keys.
+func at(t *testing.T, v any, keys ...string) any {
+	t.Helper()
+	for _, k := range keys {
+		m, ok := v.(map[string]any)
+		if !ok {
+			t.Fatalf("%q: not an object at %q", keys, k)
+		}
+		if v, ok = m[k]; !ok {
+			t.Fatalf("%q: no %q", keys, k)
+		}
+	}
+	return v
+}
+
+func TestInferService_DraftsSpec(t *testing.T) {
+	parser := &jsonParser{}
+	doc, err := newInferService(t, parser).Infer(context.Background(), []string{"browser.har"}, input.InferOptions{BaseURL: "https://api.example.com/v1/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(doc.JSON, parser.got) {
+		t.Error("the draft should be returned as validated")
+	}
+	var spec map[string]any
+	if err := json.Unmarshal(doc.JSON, &spec); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := at(t, spec, "servers").([]any)[0]; !reflect.DeepEqual(got, map[string]any{"url": "https://api.example.com/v1"}) {
+		t.Errorf("server = %v", got)
+	}
+	if got := at(t, spec, "info", "title"); got != "api.example.com API" {
+		t.Errorf("title = %v", got)
+	}
+	var paths []string
+	for p := range at(t, spec, "paths").(map[string]any) {
+		paths = append(paths, p)
+	}
+	slices.Sort(paths)
+	// The preflight, the CDN and /health (outside the base path) are left out.
+	if want := []string{"/owners/{ownerId}/pets/{petId}", "/pets", "/pets/{petId}"}; !slices.Equal(paths, want) {
+		t.Errorf("paths = %q, want %q", paths, want)
+	}
+
+	nested := at(t, spec, "paths", "/owners/{ownerId}/pets/{petId}", "get").(map[string]any)
+	wantParams := []any{
+		map[string]any{"name": "ownerId", "in": "path", "required": true, "schema": map[string]any{"type": "string", "format": "uuid"}},
+		map[string]any{"name": "petId", "in": "path", "required": true, "schema": map[string]any{"type": "integer"}},
+	}
+	if !reflect.DeepEqual(nested["parameters"], wantParams) || nested["operationId"] != "getOwnersByOwnerIdPetsByPetId" {
+		t.Errorf("nested operation = %v", nested)
+	}
+	if got := at(t, nested, "responses", "200", "content", "application/json", "schema", "properties", "weight"); !reflect.DeepEqual(got, map[string]any{"type": "number"}) {
+		t.Errorf("weight = %v, want a number", got)
+	}
+
+	list := at(t, spec, "paths", "/pets", "get").(map[string]any)
+	status := list["parameters"].([]any)[0]
+	if want := map[string]any{"name": "status", "in": "query", "required": true, "schema": map[string]any{"type": "array", "items": map[string]any{"type": "string"}}}; !reflect.DeepEqual(status, want) {
+		t.Errorf("status parameter = %v", status)
+	}
+	pet := at(t, list, "responses", "200", "content", "application/json", "schema", "items").(map[string]any)
+	if got := pet["required"]; !reflect.DeepEqual(got, []any{"born", "id", "name", "status"}) {
+		t.Errorf("required = %v", got)
+	}
+	props := pet["properties"].(map[string]any)
+	if want := map[string]any{"type": "string", "enum": []any{"available", "sold"}}; !reflect.DeepEqual(props["status"], want) {
+		t.Errorf("status = %v, want an enum", props["status"])
+	}
+	if want := map[string]any{"type": "string", "format": "date"}; !reflect.DeepEqual(props["born"], want) {
+		t.Errorf("born = %v", props["born"])
+	}
+	if want := map[string]any{"type": "string"}; !reflect.DeepEqual(props["name"], want) {
+		t.Errorf("name = %v, want no enum", props["name"])
+	}
+	if want := map[string]any{}; !reflect.DeepEqual(props["tag"], want) {
+		t.Errorf("tag = %v, want untyped", props["tag"])
+	}
+
+	one := at(t, spec, "paths", "/pets/{petId}", "get").(map[string]any)
+	if got := at(t, one, "responses", "404"); !reflect.DeepEqual(got, map[string]any{"description": "Not Found"}) {
+		t.Errorf("404 = %v", got)
+	}
+	if got := at(t, one, "responses", "200", "content", "application/json", "schema", "properties", "owner", "properties", "email", "format"); got != "email" {
+		t.Errorf("email format = %v", got)
+	}
+	if got := at(t, spec, "paths", "/pets", "post", "requestBody", "required"); got != true {
+		t.Errorf("request body required = %v", got)
+	}
+}
+
+func TestInferService_PicksBusiestOrigin(t *testing.T) {
+	parser := &jsonParser{}
+	if _, err := newInferService(t, parser).Infer(context.Background(), []string{"browser.har"}, input.InferOptions{Title: "Pets", Version: "1.0.0"}); err != nil {
+		t.Fatal(err)
+	}
+	var spec map[string]any
+	if err := json.Unmarshal(parser.got, &spec); err != nil {
+		t.Fatal(err)
+	}
+	if got := at(t, spec, "servers").([]any)[0]; !reflect.DeepEqual(got, map[string]any{"url": "https://api.example.com"}) {
+		t.Errorf("server = %v", got)
+	}
+	if _, ok := at(t, spec, "paths").(map[string]any)["/v1/pets/{petId}"]; !ok {
+		t.Error("without a base path, paths keep their prefix")
+	}
+	if got := at(t, spec, "info").(map[string]any); got["title"] != "Pets" || got["version"] != "1.0.0" {
+		t.Errorf("info = %v", got)
+	}
+}
+
+func TestInferService_Errors(t *testing.T) {
+	svc := newInferService(t, &jsonParser{})
+	cases := map[string]struct {
+		files []string
+		opts  input.InferOptions
+	}{
+		"no traffic files":    {nil, input.InferOptions{}},
+		"relative base URL":   {[]string{"browser.har"}, input.InferOptions{BaseURL: "/v1"}},
+		"no matching traffic": {[]string{"browser.har"}, input.InferOptions{BaseURL: "https://api.example.com/v2"}},
+	}
+	for name, tc := range cases {
+		_, err := svc.Infer(context.Background(), tc.files, tc.opts)
+		var ae *customerrors.AppError
+		if !errors.As(err, &ae) || ae.Type != customerrors.VALIDATION_ERROR {
+			t.Errorf("%s: err = %v, want a validation error", name, err)
+		}
+	}
+	if _, err := svc.Infer(context.Background(), []string{"missing.har"}, input.InferOptions{}); err == nil {
+		t.Error("unreadable traffic: want an error")
+	}
+	if _, err := service.NewInferService(service.InferParams{Traffic: memTraffic{}, Logger: nopLogger{}}); err == nil {
+		t.Error("missing parser: want an error")
+	}
+}
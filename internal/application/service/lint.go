@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+// LintCheck flags operations that validate but hamper clients and tooling:
+// no operationId (generated clients invent method names), no success
+// response, or neither summary nor description. It plugs into pipelines
+// (e.g. watch mode) as a report.Check.
+type LintCheck struct{}
+
+// NewLintCheck builds the check.
+func NewLintCheck() *LintCheck {
+	return &LintCheck{}
+}
+
+// LintRules describes the lint finding IDs for report writers.
+func LintRules() []report.Rule {
+	return []report.Rule{
+		{ID: string(openapi.OPERATION_ID_MISSING), Name: "OperationIdMissing", Summary: "An operation has no operationId.", Severity: report.SEVERITY_WARNING},
+		{ID: string(openapi.SUCCESS_RESPONSE_MISSING), Name: "SuccessResponseMissing", Summary: "An operation declares no 2XX or default response.", Severity: report.SEVERITY_WARNING},
+		{ID: string(openapi.OPERATION_SUMMARY_MISSING), Name: "OperationSummaryMissing", Summary: "An operation has neither a summary nor a description.", Severity: report.SEVERITY_INFO},
+	}
+}
+
+// Name implements report.Check.
+func (c *LintCheck) Name() string { return report.CHECK_LINT }
+
+// Check implements report.Check. Findings carry no file; callers that know
+// it set it.
+func (c *LintCheck) Check(_ context.Context, doc openapi.OpenAPIDoc) ([]report.Finding, error) {
+	if doc.Model == nil {
+		return nil, customerrors.NewDependencyError("loader model")
+	}
+	var findings []report.Finding
+	add := func(kind openapi.ErrorKind, severity report.Severity, op openapi.Operation, message string) {
+		findings = append(findings, report.Finding{
+			RuleID:    string(kind),
+			Check:     report.CHECK_LINT,
+			Severity:  severity,
+			Message:   message,
+			Pointer:   "/paths/" + pointerToken(op.Path) + "/" + strings.ToLower(op.Method),
+			Operation: op.Key(),
+		})
+	}
+	for _, op := range doc.Model.Operations {
+		if op.ID == "" {
+			add(openapi.OPERATION_ID_MISSING, report.SEVERITY_WARNING, op, "the operation has no operationId")
+		}
+		if !hasSuccessResponse(op) {
+			add(openapi.SUCCESS_RESPONSE_MISSING, report.SEVERITY_WARNING, op, "the operation declares no 2XX or default response")
+		}
+		if op.Summary == "" && op.Description == "" {
+			add(openapi.OPERATION_SUMMARY_MISSING, report.SEVERITY_INFO, op, "the operation has neither a summary nor a description")
+		}
+	}
+	report.Sort(findings)
+	return findings, nil
+}
+
+// hasSuccessResponse reports whether op declares a 2XX or default response.
+func hasSuccessResponse(op openapi.Operation) bool {
+	for _, resp := range op.Responses {
+		if resp.Status == "default" || strings.HasPrefix(resp.Status, "2") {
+			return true
+		}
+	}
+	return false
+}
+
+// pointerToken escapes a JSON pointer reference token (RFC 6901).
+func pointerToken(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+// compile-time check
+var _ report.Check = (*LintCheck)(nil)
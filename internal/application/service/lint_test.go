@@ -0,0 +1,35 @@
+package service_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/service"
+)
+
+func TestLintCheck_FlagsOperationsThatHamperClients(t *testing.T) {
+	doc := openapi.OpenAPIDoc{Model: &openapi.Document{Operations: []openapi.Operation{
+		{Path: "/pets", Method: "GET", ID: "listPets", Summary: "List pets", Responses: []openapi.Response{{Status: "200"}}},
+		{Path: "/pets/{id}", Method: "DELETE", Responses: []openapi.Response{{Status: "404"}}},
+		{Path: "/pets/{id}", Method: "PUT", ID: "putPet", Description: "Replace a pet", Responses: []openapi.Response{{Status: "default"}}},
+	}}}
+
+	findings, err := service.NewLintCheck().Check(context.Background(), doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.Check+" "+string(f.Severity)+" "+f.RuleID+" "+f.Pointer)
+	}
+	want := []string{
+		"lint warning operation_id_missing /paths/~1pets~1{id}/delete",
+		"lint warning success_response_missing /paths/~1pets~1{id}/delete",
+		"lint info operation_summary_missing /paths/~1pets~1{id}/delete",
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("findings =\n%q\nwant\n%q", got, want)
+	}
+}
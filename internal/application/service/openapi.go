@@ -2,6 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/betoth/contractcheck/internal/application/customerrors"
 	"github.com/betoth/contractcheck/internal/application/ports/input"
@@ -10,10 +13,12 @@ import (
 )
 
 // OpenAPILoaderParams declares the hard dependencies required to build the service.
+// Jobs is optional: when set, every Import is recorded for diagnostics.
 type OpenAPILoaderParams struct {
 	Loader        openapi.Loader
 	Logger        output.Logger
 	VersionPolicy input.VersionPolicy
+	Jobs          output.JobHistory
 }
 
 // validate performs defensive checks on constructor params.
@@ -35,6 +40,7 @@ type OpenAPILoaderService struct {
 	loader        openapi.Loader
 	logger        output.Logger
 	versionPolicy input.VersionPolicy
+	jobs          output.JobHistory
 }
 
 // NewOpenAPILoaderService constructs the service after validating dependencies.
@@ -46,16 +52,18 @@ func NewOpenAPILoaderService(params OpenAPILoaderParams) (*OpenAPILoaderService,
 		loader:        params.Loader,
 		logger:        params.Logger,
 		versionPolicy: params.VersionPolicy,
+		jobs:          params.Jobs,
 	}, nil
 }
 
 // Import loads an OpenAPI spec from disk through the output adapter, then enforces
 // the configured VersionPolicy. It returns a canonical OpenAPIDoc or a typed error.
-func (s *OpenAPILoaderService) Import(ctx context.Context, filePath string) (openapi.OpenAPIDoc, error) {
+func (s *OpenAPILoaderService) Import(ctx context.Context, filePath string) (doc openapi.OpenAPIDoc, err error) {
 	log := s.logger.With("local", "service.OpenAPILoaderService.Import")
+	defer s.recordJob(filePath, time.Now(), &err)
 
 	log.Info("starting to load OpenAPI spec", "file", filePath)
-	doc, err := s.loader.Load(ctx, filePath)
+	doc, err = s.loader.Load(ctx, filePath)
 	if err != nil {
 		log.Error("failed to load OpenAPI spec", "file", filePath)
 		return openapi.OpenAPIDoc{}, err
@@ -75,9 +83,41 @@ func (s *OpenAPILoaderService) Import(ctx context.Context, filePath string) (ope
 		)
 	}
 
-	log.Debug("successfully loaded OpenAPI spec")
+	if doc.Model != nil {
+		log.Debug("successfully loaded OpenAPI spec",
+			"operations", len(doc.Model.Operations),
+			"schemas", len(doc.Model.Schemas),
+		)
+	} else {
+		log.Debug("successfully loaded OpenAPI spec")
+	}
 	return doc, nil
 }
 
+// recordJob stores the outcome of an Import in the optional job history.
+func (s *OpenAPILoaderService) recordJob(filePath string, started time.Time, errp *error) {
+	if s.jobs == nil {
+		return
+	}
+	job := output.JobRecord{
+		Kind:      "import",
+		Target:    filePath,
+		Status:    output.JOB_SUCCEEDED,
+		StartedAt: started,
+		Duration:  time.Since(started),
+	}
+	if err := *errp; err != nil {
+		job.Status = output.JOB_FAILED
+		job.Error = err.Error()
+		var ae *customerrors.AppError
+		if errors.As(err, &ae) {
+			if kind, ok := ae.Details[customerrors.DetailKind]; ok {
+				job.ErrorKind = fmt.Sprint(kind)
+			}
+		}
+	}
+	s.jobs.Record(job)
+}
+
 // compile-time check
 var _ input.ImportOpenAPISpec = (*OpenAPILoaderService)(nil)
@@ -0,0 +1,427 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/pact"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+// Pact verification rule IDs, as set in Finding.RuleID.
+const (
+	PACT_PATH_MISSING             = "pact-path-missing"
+	PACT_METHOD_MISSING           = "pact-method-missing"
+	PACT_PARAMETER_MISSING        = "pact-parameter-missing"
+	PACT_PARAMETER_INVALID        = "pact-parameter-invalid"
+	PACT_QUERY_UNDECLARED         = "pact-query-undeclared"
+	PACT_REQUEST_BODY_MISSING     = "pact-request-body-missing"
+	PACT_REQUEST_BODY_UNDECLARED  = "pact-request-body-undeclared"
+	PACT_MEDIA_TYPE_UNSUPPORTED   = "pact-media-type-unsupported"
+	PACT_REQUEST_BODY_INVALID     = "pact-request-body-invalid"
+	PACT_STATUS_UNDECLARED        = "pact-status-undeclared"
+	PACT_RESPONSE_BODY_UNDECLARED = "pact-response-body-undeclared"
+	PACT_RESPONSE_BODY_INVALID    = "pact-response-body-invalid"
+	PACT_INTERACTION_SKIPPED      = "pact-interaction-skipped"
+)
+
+// PactRules describes the rule IDs of the Pact verification check.
+func PactRules() []report.Rule {
+	return []report.Rule{
+		{ID: PACT_PATH_MISSING, Name: "PactPathMissing", Summary: "A consumer calls a path the provider spec does not declare.", Severity: report.SEVERITY_ERROR},
+		{ID: PACT_METHOD_MISSING, Name: "PactMethodMissing", Summary: "A consumer calls a declared path with a method the provider spec does not declare.", Severity: report.SEVERITY_ERROR},
+		{ID: PACT_PARAMETER_MISSING, Name: "PactParameterMissing", Summary: "A consumer omits a required parameter.", Severity: report.SEVERITY_ERROR},
+		{ID: PACT_PARAMETER_INVALID, Name: "PactParameterInvalid", Summary: "A consumer sends a parameter value its schema rejects.", Severity: report.SEVERITY_ERROR},
+		{ID: PACT_QUERY_UNDECLARED, Name: "PactQueryUndeclared", Summary: "A consumer sends a query parameter the provider spec does not declare.", Severity: report.SEVERITY_WARNING},
+		{ID: PACT_REQUEST_BODY_MISSING, Name: "PactRequestBodyMissing", Summary: "A consumer omits a required request body.", Severity: report.SEVERITY_ERROR},
+		{ID: PACT_REQUEST_BODY_UNDECLARED, Name: "PactRequestBodyUndeclared", Summary: "A consumer sends a body to an operation that declares none.", Severity: report.SEVERITY_WARNING},
+		{ID: PACT_MEDIA_TYPE_UNSUPPORTED, Name: "PactMediaTypeUnsupported", Summary: "A body uses a media type the provider spec does not declare.", Severity: report.SEVERITY_ERROR},
+		{ID: PACT_REQUEST_BODY_INVALID, Name: "PactRequestBodyInvalid", Summary: "A consumer sends a request body its schema rejects.", Severity: report.SEVERITY_ERROR},
+		{ID: PACT_STATUS_UNDECLARED, Name: "PactStatusUndeclared", Summary: "A consumer expects a status code the operation does not declare.", Severity: report.SEVERITY_ERROR},
+		{ID: PACT_RESPONSE_BODY_UNDECLARED, Name: "PactResponseBodyUndeclared", Summary: "A consumer expects a body from a response that declares none.", Severity: report.SEVERITY_ERROR},
+		{ID: PACT_RESPONSE_BODY_INVALID, Name: "PactResponseBodyInvalid", Summary: "A consumer expects a response body the response schema rejects.", Severity: report.SEVERITY_ERROR},
+		{ID: PACT_INTERACTION_SKIPPED, Name: "PactInteractionSkipped", Summary: "A Pact file holds interactions that are not synchronous HTTP and were not verified.", Severity: report.SEVERITY_INFO},
+	}
+}
+
+// PactParams declares the dependencies required to build the service.
+type PactParams struct {
+	Importer input.ImportOpenAPISpec
+	Pacts    pact.Reader
+	Logger   output.Logger
+}
+
+// validate performs defensive checks on constructor params.
+func (p PactParams) validate() error {
+	if p.Importer == nil {
+		return customerrors.NewDependencyError("importer")
+	}
+	if p.Pacts == nil {
+		return customerrors.NewDependencyError("pacts")
+	}
+	if p.Logger == nil {
+		return customerrors.NewDependencyError("logger")
+	}
+	return nil
+}
+
+// PactService verifies consumer contracts against a provider spec
+// (input port implementation).
+type PactService struct {
+	importer input.ImportOpenAPISpec
+	pacts    pact.Reader
+	logger   output.Logger
+}
+
+// NewPactService constructs the service after validating dependencies.
+func NewPactService(params PactParams) (*PactService, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	return &PactService{importer: params.Importer, pacts: params.Pacts, logger: params.Logger}, nil
+}
+
+// Verify imports the spec at specPath and checks every Pact file against it.
+// A spec that cannot be imported fails the call; a Pact file that cannot be
+// read becomes an error finding of its result, so the other consumers are
+// still verified.
+func (s *PactService) Verify(ctx context.Context, specPath string, pactPaths []string) (report.PactVerification, error) {
+	log := s.logger.With("local", "service.PactService.Verify")
+	if len(pactPaths) == 0 {
+		return report.PactVerification{}, customerrors.NewValidationError(
+			"No Pact files", errors.New("at least one Pact file is required"), nil)
+	}
+
+	doc, err := s.importer.Import(ctx, specPath)
+	if err != nil {
+		log.Error("failed to import provider spec", "file", specPath)
+		return report.PactVerification{}, err
+	}
+	if doc.Model == nil {
+		return report.PactVerification{}, customerrors.NewDependencyError("loader model")
+	}
+	v := pactVerifier{doc: doc.Model, routes: newRoutes(doc.Model)}
+
+	out := report.PactVerification{Spec: specPath, Pacts: []report.PactResult{}}
+	for _, path := range pactPaths {
+		p, err := s.pacts.Read(ctx, path)
+		if err != nil {
+			log.Error("failed to read pact", "file", path)
+			f := report.FromError(err)
+			f.File = path
+			out.Pacts = append(out.Pacts, report.PactResult{File: path, Operations: []string{}, Findings: []report.Finding{f}})
+			continue
+		}
+		res := v.verify(path, p)
+		log.Debug("pact verified",
+			"file", path,
+			"consumer", res.Consumer,
+			"interactions", res.Interactions,
+			"failing", res.Failing,
+		)
+		out.Pacts = append(out.Pacts, res)
+	}
+	return out, nil
+}
+
+// pactVerifier checks interactions against one provider document.
+type pactVerifier struct {
+	doc    *openapi.Document
+	routes routes
+}
+
+// interactionCheck collects the findings of one interaction.
+type interactionCheck struct {
+	file     string
+	detail   string
+	op       string
+	findings []report.Finding
+	failed   bool
+}
+
+func (c *interactionCheck) add(id string, sev report.Severity, format string, args ...any) {
+	c.findings = append(c.findings, report.Finding{
+		RuleID:    id,
+		Check:     report.CHECK_PACT,
+		Severity:  sev,
+		Message:   fmt.Sprintf(format, args...),
+		Detail:    c.detail,
+		File:      c.file,
+		Operation: c.op,
+	})
+	if sev == report.SEVERITY_ERROR {
+		c.failed = true
+	}
+}
+
+func (v pactVerifier) verify(file string, p pact.Pact) report.PactResult {
+	res := report.PactResult{
+		File:         file,
+		Consumer:     p.Consumer,
+		Provider:     p.Provider,
+		SpecVersion:  p.SpecVersion,
+		Interactions: len(p.Interactions),
+		Skipped:      p.Skipped,
+		Operations:   []string{},
+		Findings:     []report.Finding{},
+	}
+	ops := map[string]bool{}
+	for _, it := range p.Interactions {
+		c := interactionCheck{
+			file:   file,
+			detail: fmt.Sprintf("interaction %q (consumer %s)", it.Description, p.Consumer),
+			op:     it.Request.Method + " " + it.Request.Path,
+		}
+		if op := v.interaction(&c, it); op != nil {
+			ops[op.Key()] = true
+		}
+		if c.failed {
+			res.Failing++
+		}
+		res.Findings = append(res.Findings, c.findings...)
+	}
+	if p.Skipped > 0 {
+		res.Findings = append(res.Findings, report.Finding{
+			RuleID:   PACT_INTERACTION_SKIPPED,
+			Check:    report.CHECK_PACT,
+			Severity: report.SEVERITY_INFO,
+			Message:  fmt.Sprintf("%d interaction(s) are not synchronous HTTP and were not verified", p.Skipped),
+			File:     file,
+		})
+	}
+	res.Operations = append(res.Operations, sortedKeys(ops)...)
+	return res
+}
+
+// interaction checks one interaction and returns the operation it hit.
+func (v pactVerifier) interaction(c *interactionCheck, it pact.Interaction) *openapi.Operation {
+	req := it.Request
+	op, vars, pathFound := v.routes.match(req.Method, req.Path)
+	if op == nil {
+		if pathFound {
+			c.add(PACT_METHOD_MISSING, report.SEVERITY_ERROR, "%s is not declared for path %s", req.Method, req.Path)
+		} else {
+			c.add(PACT_PATH_MISSING, report.SEVERITY_ERROR, "path %s is not declared", req.Path)
+		}
+		return nil
+	}
+	c.op = op.Key()
+	v.parameters(c, op, req, vars)
+	v.requestBody(c, op, req.Body)
+	v.response(c, op, it.Response)
+	return op
+}
+
+// ignoredHeaders are described by the operation itself (content, accepted
+// responses, security), not by header parameters; OpenAPI ignores them.
+var ignoredHeaders = map[string]bool{"accept": true, "content-type": true, "authorization": true}
+
+func (v pactVerifier) parameters(c *interactionCheck, op *openapi.Operation, req pact.Request, vars map[string]string) {
+	declared := map[string]bool{}
+	for _, sec := range op.EffectiveSecurity(v.doc) {
+		for name := range sec {
+			if scheme, ok := v.doc.SecuritySchemes[name]; ok && scheme.In == openapi.PARAM_IN_QUERY {
+				declared[scheme.Name] = true
+			}
+		}
+	}
+	cookies := parseCookies(req.Headers["cookie"])
+
+	for _, p := range op.Parameters {
+		var values []string
+		switch p.In {
+		case openapi.PARAM_IN_PATH:
+			if value, ok := vars[p.Name]; ok {
+				values = []string{value}
+			}
+		case openapi.PARAM_IN_QUERY:
+			declared[p.Name] = true
+			values = req.Query[p.Name]
+		case openapi.PARAM_IN_HEADER:
+			if ignoredHeaders[strings.ToLower(p.Name)] {
+				continue
+			}
+			if value, ok := req.Headers[strings.ToLower(p.Name)]; ok {
+				values = []string{value}
+			}
+		case openapi.PARAM_IN_COOKIE:
+			if value, ok := cookies[p.Name]; ok {
+				values = []string{value}
+			}
+		}
+		if len(values) == 0 {
+			if p.Required {
+				c.add(PACT_PARAMETER_MISSING, report.SEVERITY_ERROR, "required %s parameter %q is missing", p.In, p.Name)
+			}
+			continue
+		}
+		for _, viol := range p.Schema.Validate(coerceParameter(p.Schema, values), openapi.SCHEMA_REQUEST) {
+			c.add(PACT_PARAMETER_INVALID, report.SEVERITY_ERROR, "%s parameter %q %s", p.In, p.Name, viol)
+		}
+	}
+
+	for _, name := range sortedKeys(req.Query) {
+		if !declared[name] {
+			c.add(PACT_QUERY_UNDECLARED, report.SEVERITY_WARNING, "query parameter %q is not declared", name)
+		}
+	}
+}
+
+func (v pactVerifier) requestBody(c *interactionCheck, op *openapi.Operation, body *pact.Body) {
+	rb := op.RequestBody
+	switch {
+	case body == nil:
+		if rb != nil && rb.Required {
+			c.add(PACT_REQUEST_BODY_MISSING, report.SEVERITY_ERROR, "the required request body is missing")
+		}
+		return
+	case rb == nil:
+		c.add(PACT_REQUEST_BODY_UNDECLARED, report.SEVERITY_WARNING, "the operation declares no request body")
+		return
+	}
+	mediaType := bodyMediaType(body)
+	mt, ok := matchMediaType(rb.Content, mediaType)
+	if !ok {
+		c.add(PACT_MEDIA_TYPE_UNSUPPORTED, report.SEVERITY_ERROR, "request body media type %s is not declared", mediaType)
+		return
+	}
+	if !body.JSON {
+		return
+	}
+	for _, viol := range mt.Schema.Validate(body.Content, openapi.SCHEMA_REQUEST) {
+		c.add(PACT_REQUEST_BODY_INVALID, report.SEVERITY_ERROR, "request body %s", bodyViolation(viol))
+	}
+}
+
+func (v pactVerifier) response(c *interactionCheck, op *openapi.Operation, resp pact.Response) {
+	declared := declaredResponse(op, resp.Status)
+	if declared == nil {
+		c.add(PACT_STATUS_UNDECLARED, report.SEVERITY_ERROR, "status %d is not declared", resp.Status)
+		return
+	}
+	body := resp.Body
+	if body == nil {
+		return
+	}
+	if len(declared.Content) == 0 {
+		c.add(PACT_RESPONSE_BODY_UNDECLARED, report.SEVERITY_ERROR, "response %s declares no body", declared.Status)
+		return
+	}
+	mediaType := bodyMediaType(body)
+	mt, ok := matchMediaType(declared.Content, mediaType)
+	if !ok {
+		c.add(PACT_MEDIA_TYPE_UNSUPPORTED, report.SEVERITY_ERROR, "response %s media type %s is not declared", declared.Status, mediaType)
+		return
+	}
+	if !body.JSON {
+		return
+	}
+	for _, viol := range mt.Schema.Validate(body.Content, openapi.SCHEMA_RESPONSE) {
+		c.add(PACT_RESPONSE_BODY_INVALID, report.SEVERITY_ERROR, "response %s body %s", declared.Status, bodyViolation(viol))
+	}
+}
+
+// declaredResponse finds the response for status: the exact code, then its
+// range ("4XX"), then "default".
+func declaredResponse(op *openapi.Operation, status int) *openapi.Response {
+	for _, key := range []string{strconv.Itoa(status), fmt.Sprintf("%dXX", status/100), "default"} {
+		if r := op.Response(key); r != nil {
+			return r
+		}
+	}
+	return nil
+}
+
+// bodyMediaType is the body's media type, assuming JSON for JSON bodies
+// without one and plain text otherwise.
+func bodyMediaType(b *pact.Body) string {
+	switch {
+	case b.ContentType != "":
+		return b.ContentType
+	case b.JSON:
+		return "application/json"
+	}
+	return "text/plain"
+}
+
+// matchMediaType picks the content entry for mediaType: the exact type,
+// then "type/*", then "*/*". Declared keys may carry parameters.
+func matchMediaType(content map[string]openapi.MediaType, mediaType string) (openapi.MediaType, bool) {
+	byType := make(map[string]openapi.MediaType, len(content))
+	for key, mt := range content {
+		name, _, _ := strings.Cut(key, ";")
+		byType[strings.ToLower(strings.TrimSpace(name))] = mt
+	}
+	major, _, _ := strings.Cut(mediaType, "/")
+	for _, key := range []string{mediaType, major + "/*", "*/*"} {
+		if mt, ok := byType[key]; ok {
+			return mt, true
+		}
+	}
+	return openapi.MediaType{}, false
+}
+
+// bodyViolation renders a schema violation of a body: "at /name: ..." or
+// the bare message for the root.
+func bodyViolation(v openapi.SchemaViolation) string {
+	if v.Pointer == "" {
+		return v.Message
+	}
+	return "at " + v.Pointer + ": " + v.Message
+}
+
+// coerceParameter turns the raw strings of a parameter into the JSON value
+// its schema describes, so Validate can check it. Values that do not parse
+// stay strings and fail the type check. Arrays take repeated values or one
+// comma-separated value (style form/simple).
+func coerceParameter(s *openapi.Schema, values []string) any {
+	if s != nil && s.HasType("array") {
+		if len(values) == 1 {
+			values = strings.Split(values[0], ",")
+		}
+		items := make([]any, len(values))
+		for i, raw := range values {
+			items[i] = coerceScalar(s.Items, raw)
+		}
+		return items
+	}
+	return coerceScalar(s, values[0])
+}
+
+func coerceScalar(s *openapi.Schema, raw string) any {
+	switch {
+	case s == nil:
+		return raw
+	case s.HasType("integer") || s.HasType("number"):
+		if n, err := strconv.ParseFloat(raw, 64); err == nil {
+			return n
+		}
+	case s.HasType("boolean"):
+		if b, err := strconv.ParseBool(raw); err == nil {
+			return b
+		}
+	}
+	return raw
+}
+
+// parseCookies reads a Cookie header ("a=1; b=2").
+func parseCookies(header string) map[string]string {
+	cookies := map[string]string{}
+	for _, part := range strings.Split(header, ";") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if ok && name != "" {
+			cookies[name] = value
+		}
+	}
+	return cookies
+}
+
+// compile-time check
+var _ input.VerifyPacts = (*PactService)(nil)
@@ -0,0 +1,239 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/pact"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+	"github.com/betoth/contractcheck/internal/application/service"
+)
+
+// modelImporter imports a prebuilt model for any path.
+type modelImporter struct {
+	doc *openapi.Document
+}
+
+func (i modelImporter) Import(_ context.Context, path string) (openapi.OpenAPIDoc, error) {
+	if i.doc == nil {
+		return openapi.OpenAPIDoc{}, openapi.NewValidationError(openapi.FILE_NOT_FOUND, "File not found", path, errors.New("missing"))
+	}
+	return openapi.OpenAPIDoc{Version: "3.0.3", Model: i.doc}, nil
+}
+
+// memPacts serves Pact contracts by path.
+type memPacts map[string]pact.Pact
+
+func (m memPacts) Read(_ context.Context, path string) (pact.Pact, error) {
+	p, ok := m[path]
+	if !ok {
+		return pact.Pact{}, openapi.NewValidationError(openapi.INVALID_PACT, "Invalid Pact file", path, errors.New("bad pact"))
+	}
+	return p, nil
+}
+
+func newPactService(t *testing.T, doc *openapi.Document, pacts memPacts) *service.PactService {
+	t.Helper()
+	svc, err := service.NewPactService(service.PactParams{
+		Importer: modelImporter{doc: doc},
+		Pacts:    pacts,
+		Logger:   nopLogger{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svc
+}
+
+func jsonBody(v any) *pact.Body {
+	return &pact.Body{ContentType: "application/json", Content: v, JSON: true}
+}
+
+// pactPetAPI serves petAPI under https://api.example.com/v1, with a
+// paginated list operation.
+func pactPetAPI() *openapi.Document {
+	return petAPI(func(d *openapi.Document) {
+		d.Servers = []openapi.Server{{URL: "https://api.example.com/v1"}}
+		d.Operations = append(d.Operations, openapi.Operation{
+			Path: "/pets", Method: "GET",
+			Parameters: []openapi.Parameter{
+				{Name: "limit", In: openapi.PARAM_IN_QUERY, Schema: &openapi.Schema{Type: []string{"integer"}, Maximum: float(50)}},
+				{Name: "X-Tenant", In: openapi.PARAM_IN_HEADER, Required: true, Schema: str()},
+			},
+			Responses: []openapi.Response{
+				{Status: "200", Content: map[string]openapi.MediaType{"application/json": {Schema: &openapi.Schema{Type: []string{"array"}, Items: str()}}}},
+				{Status: "4XX", Content: map[string]openapi.MediaType{"application/problem+json": {Schema: object([]string{"title"}, map[string]*openapi.Schema{"title": str()})}}},
+			},
+		})
+	})
+}
+
+func float(v float64) *float64 { return &v }
+
+func TestPactService_VerifiesInteractions(t *testing.T) {
+	web := pact.Pact{
+		Consumer: "web", Provider: "pets", SpecVersion: "3.0.0", Skipped: 1,
+		Interactions: []pact.Interaction{
+			{
+				Description: "get a pet",
+				Request:     pact.Request{Method: "GET", Path: "/v1/pets/42"},
+				Response:    pact.Response{Status: 200, Body: jsonBody(map[string]any{"id": 42.0, "name": "rex"})},
+			},
+			{
+				Description: "list pets",
+				Request: pact.Request{Method: "GET", Path: "/pets", Query: map[string][]string{"limit": {"10"}},
+					Headers: map[string]string{"x-tenant": "acme"}},
+				Response: pact.Response{Status: 200, Body: jsonBody([]any{"rex"})},
+			},
+			{
+				Description: "list pets without tenant",
+				Request:     pact.Request{Method: "GET", Path: "/pets"},
+				Response: pact.Response{Status: 400, Body: &pact.Body{
+					ContentType: "application/problem+json", Content: map[string]any{"title": "missing tenant"}, JSON: true}},
+			},
+			{
+				Description: "create a pet",
+				Request:     pact.Request{Method: "POST", Path: "/v1/pets", Body: jsonBody(map[string]any{"name": "rex"})},
+				Response:    pact.Response{Status: 201},
+			},
+		},
+	}
+	svc := newPactService(t, pactPetAPI(), memPacts{"web.json": web})
+
+	got, err := svc.Verify(context.Background(), "openapi.yaml", []string{"web.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Pacts) != 1 {
+		t.Fatalf("pacts = %d, want 1", len(got.Pacts))
+	}
+	res := got.Pacts[0]
+	if want := []string{"GET /pets pact-parameter-missing", " pact-interaction-skipped"}; !slices.Equal(findingIDs(res.Findings), want) {
+		t.Errorf("findings = %v, want %v", findingIDs(res.Findings), want)
+	}
+	if res.Consumer != "web" || res.Interactions != 4 || res.Failing != 1 || res.Skipped != 1 {
+		t.Errorf("result = %+v", res)
+	}
+	if want := []string{"GET /pets", "GET /pets/{id}", "POST /pets"}; !slices.Equal(res.Operations, want) {
+		t.Errorf("operations = %v, want %v", res.Operations, want)
+	}
+	for _, f := range res.Findings {
+		if f.File != "web.json" || f.Check != report.CHECK_PACT {
+			t.Errorf("finding %s: file %q, check %q", f.RuleID, f.File, f.Check)
+		}
+	}
+}
+
+func TestPactService_ReportsContractViolations(t *testing.T) {
+	interactions := []pact.Interaction{
+		{Description: "unknown path", Request: pact.Request{Method: "GET", Path: "/owners"}},
+		{Description: "unknown method", Request: pact.Request{Method: "PUT", Path: "/pets/1"}},
+		{
+			Description: "bad limit",
+			Request: pact.Request{Method: "GET", Path: "/pets", Query: map[string][]string{"limit": {"500"}, "sort": {"name"}},
+				Headers: map[string]string{"x-tenant": "acme"}},
+			Response: pact.Response{Status: 200},
+		},
+		{
+			Description: "bad body",
+			Request:     pact.Request{Method: "POST", Path: "/pets", Body: jsonBody(map[string]any{"name": 7.0})},
+			Response:    pact.Response{Status: 201},
+		},
+		{Description: "no body", Request: pact.Request{Method: "POST", Path: "/pets"}, Response: pact.Response{Status: 201}},
+		{
+			Description: "xml body",
+			Request:     pact.Request{Method: "POST", Path: "/pets", Body: &pact.Body{ContentType: "application/xml", Content: "<pet/>"}},
+			Response:    pact.Response{Status: 201},
+		},
+		{
+			Description: "body on delete",
+			Request:     pact.Request{Method: "DELETE", Path: "/pets/1", Body: jsonBody(map[string]any{})},
+			Response:    pact.Response{Status: 204},
+		},
+		{Description: "undeclared status", Request: pact.Request{Method: "DELETE", Path: "/pets/1"}, Response: pact.Response{Status: 500}},
+		{
+			Description: "undeclared response body",
+			Request:     pact.Request{Method: "DELETE", Path: "/pets/1"},
+			Response:    pact.Response{Status: 204, Body: jsonBody(map[string]any{})},
+		},
+		{
+			Description: "bad response",
+			Request:     pact.Request{Method: "GET", Path: "/pets/1"},
+			Response:    pact.Response{Status: 200, Body: jsonBody(map[string]any{"id": "1", "name": "rex", "status": "lost"})},
+		},
+	}
+	svc := newPactService(t, pactPetAPI(), memPacts{"mobile.json": {Consumer: "mobile", Provider: "pets", Interactions: interactions}})
+
+	got, err := svc.Verify(context.Background(), "openapi.yaml", []string{"mobile.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := got.Pacts[0]
+	want := []string{
+		"GET /owners pact-path-missing",
+		"PUT /pets/1 pact-method-missing",
+		"GET /pets pact-parameter-invalid",
+		"GET /pets pact-query-undeclared",
+		"POST /pets pact-request-body-invalid",
+		"POST /pets pact-request-body-missing",
+		"POST /pets pact-media-type-unsupported",
+		"DELETE /pets/{id} pact-request-body-undeclared",
+		"DELETE /pets/{id} pact-status-undeclared",
+		"DELETE /pets/{id} pact-response-body-undeclared",
+		"GET /pets/{id} pact-response-body-invalid",
+		"GET /pets/{id} pact-response-body-invalid",
+	}
+	if !slices.Equal(findingIDs(res.Findings), want) {
+		t.Errorf("findings =\n%v\nwant\n%v", findingIDs(res.Findings), want)
+	}
+	// Every interaction but the warning-only one fails.
+	if res.Failing != len(interactions)-1 {
+		t.Errorf("failing = %d, want %d", res.Failing, len(interactions)-1)
+	}
+	if got, want := res.Findings[len(res.Findings)-1].Message, `response 200 body at /status: "lost" is not one of the allowed values`; got != want {
+		t.Errorf("message = %q, want %q", got, want)
+	}
+	if got, want := res.Findings[0].Detail, `interaction "unknown path" (consumer mobile)`; got != want {
+		t.Errorf("detail = %q, want %q", got, want)
+	}
+}
+
+func TestPactService_UnreadablePactIsAFinding(t *testing.T) {
+	ok := pact.Pact{Consumer: "web", Provider: "pets", Interactions: []pact.Interaction{
+		{Description: "delete", Request: pact.Request{Method: "DELETE", Path: "/pets/1"}, Response: pact.Response{Status: 204}},
+	}}
+	svc := newPactService(t, pactPetAPI(), memPacts{"web.json": ok})
+
+	got, err := svc.Verify(context.Background(), "openapi.yaml", []string{"broken.json", "web.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Pacts) != 2 {
+		t.Fatalf("pacts = %d, want 2", len(got.Pacts))
+	}
+	broken := got.Pacts[0].Findings
+	if len(broken) != 1 || broken[0].RuleID != string(openapi.INVALID_PACT) || broken[0].File != "broken.json" {
+		t.Errorf("broken pact findings = %+v", broken)
+	}
+	if f := got.Pacts[1].Findings; len(f) != 0 {
+		t.Errorf("web findings = %v, want none", findingIDs(f))
+	}
+
+	rep := got.Report()
+	if len(rep.Subjects) != 2 || rep.Subjects[1].File != "web.json" || len(rep.Findings) != 1 {
+		t.Errorf("report = %+v", rep)
+	}
+}
+
+func TestPactService_SpecImportFails(t *testing.T) {
+	svc := newPactService(t, nil, memPacts{})
+	if _, err := svc.Verify(context.Background(), "missing.yaml", []string{"web.json"}); err == nil {
+		t.Fatal("expected an error for a spec that cannot be imported")
+	}
+	if _, err := svc.Verify(context.Background(), "openapi.yaml", nil); err == nil {
+		t.Fatal("expected an error without Pact files")
+	}
+}
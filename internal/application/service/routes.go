@@ -0,0 +1,136 @@
+package service
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+)
+
+// routes matches concrete request paths ("/v1/pets/42") to the operations
+// of a document, with or without the base path of one of its servers.
+type routes struct {
+	entries []route
+	bases   []string // server base paths, longest first; "" is always last
+}
+
+type route struct {
+	op        *openapi.Operation
+	pattern   *regexp.Regexp
+	vars      []string // path variable names, in pattern group order
+	templated int      // number of path variables; fewer is more specific
+}
+
+func newRoutes(doc *openapi.Document) routes {
+	r := routes{}
+	bases := map[string]bool{}
+	addBases := func(servers []openapi.Server) {
+		for _, srv := range servers {
+			if b := serverBasePath(srv); b != "" {
+				bases[b] = true
+			}
+		}
+	}
+	addBases(doc.Servers)
+	for i := range doc.Operations {
+		op := &doc.Operations[i]
+		addBases(op.Servers)
+		pattern, vars := pathPattern(op.Path)
+		r.entries = append(r.entries, route{op: op, pattern: pattern, vars: vars, templated: len(vars)})
+	}
+	for b := range bases {
+		r.bases = append(r.bases, b)
+	}
+	sort.Slice(r.bases, func(i, j int) bool { return len(r.bases[i]) > len(r.bases[j]) })
+	r.bases = append(r.bases, "")
+	return r
+}
+
+// match returns the operation for method and path, and its path variables.
+// When no operation matches, pathFound reports whether the path exists with
+// other methods. Literal segments win over variables ("/pets/mine" over
+// "/pets/{id}").
+func (r routes) match(method, path string) (op *openapi.Operation, vars map[string]string, pathFound bool) {
+	method = strings.ToUpper(method)
+	path, _, _ = strings.Cut(path, "?")
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	for _, base := range r.bases {
+		rel, ok := strings.CutPrefix(path, base)
+		if !ok || (base != "" && rel != "" && rel[0] != '/') {
+			continue
+		}
+		if rel == "" {
+			rel = "/"
+		}
+		var best *route
+		var groups []string
+		for i := range r.entries {
+			e := &r.entries[i]
+			m := e.pattern.FindStringSubmatch(rel)
+			if m == nil {
+				continue
+			}
+			pathFound = true
+			if e.op.Method != method || (best != nil && best.templated <= e.templated) {
+				continue
+			}
+			best, groups = e, m[1:]
+		}
+		if best != nil {
+			vars = make(map[string]string, len(best.vars))
+			for i, name := range best.vars {
+				value, err := url.PathUnescape(groups[i])
+				if err != nil {
+					value = groups[i]
+				}
+				vars[name] = value
+			}
+			return best.op, vars, true
+		}
+		if pathFound {
+			return nil, nil, true
+		}
+	}
+	return nil, nil, false
+}
+
+// pathPattern compiles a path template; each "{name}" matches one non-empty
+// segment or part of one.
+func pathPattern(template string) (*regexp.Regexp, []string) {
+	var b strings.Builder
+	var vars []string
+	b.WriteString("^")
+	rest := template
+	for {
+		start := strings.IndexByte(rest, '{')
+		end := strings.IndexByte(rest, '}')
+		if start < 0 || end < start {
+			b.WriteString(regexp.QuoteMeta(rest))
+			break
+		}
+		b.WriteString(regexp.QuoteMeta(rest[:start]))
+		b.WriteString("([^/]+)")
+		vars = append(vars, rest[start+1:end])
+		rest = rest[end+1:]
+	}
+	b.WriteString("/?$")
+	return regexp.MustCompile(b.String()), vars
+}
+
+// serverBasePath extracts the path of a server URL, with variables set to
+// their defaults: "https://{region}.example.com/v1/" is "/v1".
+func serverBasePath(srv openapi.Server) string {
+	raw := srv.URL
+	for name, v := range srv.Variables {
+		raw = strings.ReplaceAll(raw, "{"+name+"}", v.Default)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(u.Path, "/")
+}
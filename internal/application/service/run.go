@@ -0,0 +1,540 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+	"github.com/betoth/contractcheck/internal/application/ports/output/testgen"
+)
+
+// DEPENDS_ON_EXTENSION orders a contract run: an operation is called after
+// the operations it lists, by operationId or as "METHOD /path", in one
+// string or a list.
+const DEPENDS_ON_EXTENSION = "x-contractcheck-depends-on"
+
+// DEFAULT_RUN_CONCURRENCY is how many requests a run keeps in flight.
+const DEFAULT_RUN_CONCURRENCY = 4
+
+// Contract run rule IDs, as set in Finding.RuleID.
+const (
+	RUN_REQUEST_FAILED           = "run-request-failed"
+	RUN_STATUS_UNDECLARED        = "run-status-undeclared"
+	RUN_STATUS_UNEXPECTED        = "run-status-unexpected"
+	RUN_HEADER_MISSING           = "run-header-missing"
+	RUN_HEADER_INVALID           = "run-header-invalid"
+	RUN_RESPONSE_BODY_MISSING    = "run-response-body-missing"
+	RUN_RESPONSE_BODY_UNDECLARED = "run-response-body-undeclared"
+	RUN_MEDIA_TYPE_UNSUPPORTED   = "run-media-type-unsupported"
+	RUN_RESPONSE_BODY_INVALID    = "run-response-body-invalid"
+	RUN_CREDENTIALS_MISSING      = "run-credentials-missing"
+	RUN_DEPENDENCY_INVALID       = "run-dependency-invalid"
+	RUN_OPERATION_SKIPPED        = "run-operation-skipped"
+)
+
+// RunRules describes the rule IDs of the contract run check.
+func RunRules() []report.Rule {
+	return []report.Rule{
+		{ID: RUN_REQUEST_FAILED, Name: "RunRequestFailed", Summary: "The service could not be reached or did not answer in time.", Severity: report.SEVERITY_ERROR},
+		{ID: RUN_STATUS_UNDECLARED, Name: "RunStatusUndeclared", Summary: "The service answered with a status code the operation does not declare.", Severity: report.SEVERITY_ERROR},
+		{ID: RUN_STATUS_UNEXPECTED, Name: "RunStatusUnexpected", Summary: "The service rejected a request synthesized from the spec with a declared error status.", Severity: report.SEVERITY_WARNING},
+		{ID: RUN_HEADER_MISSING, Name: "RunHeaderMissing", Summary: "A response lacks a required header.", Severity: report.SEVERITY_ERROR},
+		{ID: RUN_HEADER_INVALID, Name: "RunHeaderInvalid", Summary: "A response header value does not match its schema.", Severity: report.SEVERITY_ERROR},
+		{ID: RUN_RESPONSE_BODY_MISSING, Name: "RunResponseBodyMissing", Summary: "A response that declares content has no body.", Severity: report.SEVERITY_ERROR},
+		{ID: RUN_RESPONSE_BODY_UNDECLARED, Name: "RunResponseBodyUndeclared", Summary: "A response that declares no content has a body.", Severity: report.SEVERITY_WARNING},
+		{ID: RUN_MEDIA_TYPE_UNSUPPORTED, Name: "RunMediaTypeUnsupported", Summary: "A response body uses a media type the response does not declare.", Severity: report.SEVERITY_ERROR},
+		{ID: RUN_RESPONSE_BODY_INVALID, Name: "RunResponseBodyInvalid", Summary: "A response body does not match its schema.", Severity: report.SEVERITY_ERROR},
+		{ID: RUN_CREDENTIALS_MISSING, Name: "RunCredentialsMissing", Summary: "No credentials were configured for any security requirement of an operation, so it was not called.", Severity: report.SEVERITY_WARNING},
+		{ID: RUN_DEPENDENCY_INVALID, Name: "RunDependencyInvalid", Summary: "An x-contractcheck-depends-on entry names an unknown operation or forms a cycle.", Severity: report.SEVERITY_ERROR},
+		{ID: RUN_OPERATION_SKIPPED, Name: "RunOperationSkipped", Summary: "An operation was not called: no request could be synthesized or a dependency did not pass.", Severity: report.SEVERITY_INFO},
+	}
+}
+
+// RunParams declares the dependencies required to build the service.
+type RunParams struct {
+	Importer input.ImportOpenAPISpec
+	Client   output.HTTPClient
+	Logger   output.Logger
+}
+
+// validate performs defensive checks on constructor params.
+func (p RunParams) validate() error {
+	if p.Importer == nil {
+		return customerrors.NewDependencyError("importer")
+	}
+	if p.Client == nil {
+		return customerrors.NewDependencyError("http client")
+	}
+	if p.Logger == nil {
+		return customerrors.NewDependencyError("logger")
+	}
+	return nil
+}
+
+// RunService calls the operations of a spec on a live service and checks
+// the responses (input port implementation).
+type RunService struct {
+	importer input.ImportOpenAPISpec
+	client   output.HTTPClient
+	logger   output.Logger
+}
+
+// NewRunService constructs the service after validating dependencies.
+func NewRunService(params RunParams) (*RunService, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	return &RunService{importer: params.Importer, client: params.Client, logger: params.Logger}, nil
+}
+
+// Run imports the spec at specPath and calls each operation once with a
+// request synthesized from examples or schemas (see validRequest), then
+// checks the status, headers and body of the response. Operations start
+// after the ones their DEPENDS_ON_EXTENSION names, and are skipped when one
+// of those did not pass.
+func (s *RunService) Run(ctx context.Context, specPath string, opts input.RunOptions) (report.ContractRun, error) {
+	log := s.logger.With("local", "service.RunService.Run")
+	if opts.Concurrency < 0 {
+		return report.ContractRun{}, customerrors.NewValidationError(
+			"Invalid concurrency", fmt.Errorf("concurrency must not be negative, got %d", opts.Concurrency), nil)
+	}
+	if opts.Concurrency == 0 {
+		opts.Concurrency = DEFAULT_RUN_CONCURRENCY
+	}
+
+	doc, err := s.importer.Import(ctx, specPath)
+	if err != nil {
+		log.Error("failed to import spec", "file", specPath)
+		return report.ContractRun{}, err
+	}
+	if doc.Model == nil {
+		return report.ContractRun{}, customerrors.NewDependencyError("loader model")
+	}
+	base, err := runBaseURL(doc.Model, opts.BaseURL)
+	if err != nil {
+		return report.ContractRun{}, customerrors.NewValidationError("Invalid base URL", err, nil)
+	}
+
+	r := runner{check: report.CHECK_RUN, doc: doc.Model, file: specPath, base: base, credentials: opts.Credentials, client: s.client}
+	ops := doc.Model.Operations
+	deps, depFindings := runDependencies(specPath, ops)
+	results := make([]operationResult, len(ops))
+	done := make([]chan struct{}, len(ops))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+	slots := make(chan struct{}, opts.Concurrency)
+	var wg sync.WaitGroup
+	for i := range ops {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer close(done[i])
+			defer results[i].finish()
+			results[i].findings = depFindings[i]
+			if deps[i] == nil { // on a cycle, already reported
+				results[i].run.Outcome = report.OUTCOME_SKIPPED
+				return
+			}
+			for _, d := range deps[i] {
+				<-done[d]
+				if results[d].run.Outcome != report.OUTCOME_PASSED {
+					results[i].skip(r.finding(ops[i], "", RUN_OPERATION_SKIPPED, report.SEVERITY_INFO, "dependency %s did not pass", ops[d].Key()))
+					return
+				}
+			}
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				results[i].skip(r.finding(ops[i], "", RUN_OPERATION_SKIPPED, report.SEVERITY_INFO, "the run was cancelled"))
+				return
+			}
+			defer func() { <-slots }()
+			r.call(ctx, ops[i], &results[i])
+		}(i)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return report.ContractRun{}, err
+	}
+
+	out := report.ContractRun{Spec: specPath, BaseURL: base, Operations: []report.OperationRun{}, Findings: []report.Finding{}}
+	for i, res := range results {
+		res.run.Operation = ops[i].Key()
+		out.Operations = append(out.Operations, res.run)
+		out.Findings = append(out.Findings, res.findings...)
+	}
+	log.Info("contract run finished",
+		"file", specPath,
+		"base_url", base,
+		"passed", out.Count(report.OUTCOME_PASSED),
+		"failed", out.Count(report.OUTCOME_FAILED),
+		"skipped", out.Count(report.OUTCOME_SKIPPED),
+	)
+	return out, nil
+}
+
+// operationResult collects the outcome of one operation.
+type operationResult struct {
+	run      report.OperationRun
+	findings []report.Finding
+}
+
+func (r *operationResult) skip(f report.Finding) {
+	r.run.Outcome = report.OUTCOME_SKIPPED
+	r.findings = append(r.findings, f)
+}
+
+// finish sets the outcome of an operation that was called.
+func (r *operationResult) finish() {
+	if r.run.Outcome != "" {
+		return
+	}
+	r.run.Outcome = report.OUTCOME_PASSED
+	for _, f := range r.findings {
+		if f.Severity == report.SEVERITY_ERROR {
+			r.run.Outcome = report.OUTCOME_FAILED
+		}
+	}
+}
+
+// runBaseURL returns explicit, or else the spec's first server URL that is
+// absolute once its variables take their defaults.
+func runBaseURL(doc *openapi.Document, explicit string) (string, error) {
+	if explicit != "" {
+		u, err := url.Parse(explicit)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "", fmt.Errorf("%q is not an absolute http(s) URL", explicit)
+		}
+		return strings.TrimSuffix(explicit, "/"), nil
+	}
+	for _, srv := range doc.Servers {
+		raw := srv.URL
+		for name, v := range srv.Variables {
+			raw = strings.ReplaceAll(raw, "{"+name+"}", v.Default)
+		}
+		if u, err := url.Parse(raw); err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" {
+			return strings.TrimSuffix(raw, "/"), nil
+		}
+	}
+	return "", errors.New("the spec declares no absolute server URL; set a base URL")
+}
+
+// runDependencies resolves DEPENDS_ON_EXTENSION into indexes of ops. The
+// dependencies of an operation on a cycle are nil; unknown references are
+// reported and ignored.
+func runDependencies(file string, ops []openapi.Operation) ([][]int, [][]report.Finding) {
+	index := map[string]int{}
+	for i, op := range ops {
+		index[op.Key()] = i
+		if op.ID != "" {
+			index[op.ID] = i
+		}
+	}
+	deps := make([][]int, len(ops))
+	findings := make([][]report.Finding, len(ops))
+	invalid := func(i int, format string, args ...any) {
+		findings[i] = append(findings[i], report.Finding{
+			RuleID:    RUN_DEPENDENCY_INVALID,
+			Check:     report.CHECK_RUN,
+			Severity:  report.SEVERITY_ERROR,
+			Message:   fmt.Sprintf(format, args...),
+			File:      file,
+			Operation: ops[i].Key(),
+		})
+	}
+	for i, op := range ops {
+		deps[i] = []int{}
+		for _, ref := range dependsOn(op.Extensions[DEPENDS_ON_EXTENSION]) {
+			d, ok := index[ref]
+			if !ok {
+				if method, path, cut := strings.Cut(ref, " "); cut {
+					d, ok = index[strings.ToUpper(method)+" "+strings.TrimSpace(path)]
+				}
+			}
+			if !ok {
+				invalid(i, "%s names unknown operation %q", DEPENDS_ON_EXTENSION, ref)
+				continue
+			}
+			deps[i] = append(deps[i], d)
+		}
+	}
+
+	// An operation is on a cycle when it can reach itself.
+	var onCycle []int
+	for i := range ops {
+		seen := map[int]bool{}
+		stack := append([]int{}, deps[i]...)
+		for len(stack) > 0 {
+			d := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if d == i {
+				onCycle = append(onCycle, i)
+				break
+			}
+			if !seen[d] {
+				seen[d] = true
+				stack = append(stack, deps[d]...)
+			}
+		}
+	}
+	for _, i := range onCycle {
+		invalid(i, "%s forms a cycle", DEPENDS_ON_EXTENSION)
+	}
+	for _, i := range onCycle {
+		deps[i] = nil
+	}
+	return deps, findings
+}
+
+// dependsOn reads the extension value: one string or a list of strings.
+func dependsOn(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		var refs []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				refs = append(refs, s)
+			}
+		}
+		return refs
+	case []string:
+		return v
+	}
+	return nil
+}
+
+// runner calls operations and checks their responses; check names the
+// check its findings belong to.
+type runner struct {
+	check       string
+	doc         *openapi.Document
+	file        string
+	base        string
+	credentials map[string]string
+	client      output.HTTPClient
+}
+
+func (r runner) finding(op openapi.Operation, detail, id string, sev report.Severity, format string, args ...any) report.Finding {
+	return report.Finding{
+		RuleID:    id,
+		Check:     r.check,
+		Severity:  sev,
+		Message:   fmt.Sprintf(format, args...),
+		Detail:    detail,
+		File:      r.file,
+		Operation: op.Key(),
+	}
+}
+
+// call sends the operation's synthesized request and checks the response.
+func (r runner) call(ctx context.Context, op openapi.Operation, res *operationResult) {
+	c := validRequest(op)
+	if c.Skip != "" {
+		res.skip(r.finding(op, "", RUN_OPERATION_SKIPPED, report.SEVERITY_INFO, "%s", c.Skip))
+		return
+	}
+	req, err := r.request(op, c)
+	if err != nil {
+		res.skip(r.finding(op, "", RUN_CREDENTIALS_MISSING, report.SEVERITY_WARNING, "%v", err))
+		return
+	}
+	res.run.URL = req.URL
+	detail := req.Method + " " + req.URL
+
+	start := time.Now()
+	resp, err := r.client.Do(ctx, req)
+	res.run.DurationMS = time.Since(start).Milliseconds()
+	if err != nil {
+		res.findings = append(res.findings, r.finding(op, detail, RUN_REQUEST_FAILED, report.SEVERITY_ERROR, "the request failed: %v", err))
+		return
+	}
+	res.run.Status = resp.Status
+	res.findings = append(res.findings, r.response(op, detail, resp)...)
+}
+
+// request builds the HTTP request of case c, with credentials for the
+// first security requirement they satisfy. It fails when an operation is
+// secured and no requirement can be satisfied.
+func (r runner) request(op openapi.Operation, c testgen.Case) (output.HTTPRequest, error) {
+	path := op.Path
+	for name, value := range c.PathParams {
+		path = strings.ReplaceAll(path, "{"+name+"}", url.PathEscape(value))
+	}
+	query := url.Values(c.Query)
+	if query == nil {
+		query = url.Values{}
+	}
+	header := http.Header{}
+	for name, value := range c.Headers {
+		header.Set(name, value)
+	}
+	var body []byte
+	if c.ContentType != "" {
+		header.Set("Content-Type", c.ContentType)
+		body = []byte(c.Body)
+	}
+
+	if reqs := op.EffectiveSecurity(r.doc); len(reqs) > 0 {
+		req, ok := r.satisfiable(reqs)
+		if !ok {
+			return output.HTTPRequest{}, fmt.Errorf("no credentials are configured for %s", requirementNames(reqs))
+		}
+		for _, name := range sortedKeys(req) {
+			applyCredential(r.doc.SecuritySchemes[name], r.credentials[name], header, query)
+		}
+	}
+
+	target := r.base + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	return output.HTTPRequest{Method: op.Method, URL: target, Header: header, Body: body}, nil
+}
+
+// satisfiable returns the first requirement whose schemes all have
+// credentials; an empty requirement (optional security) needs none.
+func (r runner) satisfiable(reqs []openapi.SecurityRequirement) (openapi.SecurityRequirement, bool) {
+	for _, req := range reqs {
+		ok := true
+		for name := range req {
+			scheme, declared := r.doc.SecuritySchemes[name]
+			if !declared || scheme.Type == "mutualTLS" || r.credentials[name] == "" {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return req, true
+		}
+	}
+	return nil, false
+}
+
+// requirementNames lists alternatives as "a or b+c".
+func requirementNames(reqs []openapi.SecurityRequirement) string {
+	alts := make([]string, len(reqs))
+	for i, req := range reqs {
+		alts[i] = strings.Join(sortedKeys(req), "+")
+	}
+	return strings.Join(alts, " or ")
+}
+
+// applyCredential sends value the way scheme expects it.
+func applyCredential(scheme openapi.SecurityScheme, value string, header http.Header, query url.Values) {
+	switch scheme.Type {
+	case "http":
+		switch strings.ToLower(scheme.Scheme) {
+		case "basic":
+			header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(value)))
+		case "bearer", "":
+			header.Set("Authorization", "Bearer "+value)
+		default:
+			header.Set("Authorization", scheme.Scheme+" "+value)
+		}
+	case "apiKey":
+		switch scheme.In {
+		case openapi.PARAM_IN_HEADER:
+			header.Set(scheme.Name, value)
+		case openapi.PARAM_IN_QUERY:
+			query.Set(scheme.Name, value)
+		case openapi.PARAM_IN_COOKIE:
+			cookie := scheme.Name + "=" + value
+			if existing := header.Get("Cookie"); existing != "" {
+				cookie = existing + "; " + cookie
+			}
+			header.Set("Cookie", cookie)
+		}
+	default: // oauth2, openIdConnect
+		header.Set("Authorization", "Bearer "+value)
+	}
+}
+
+// response checks a response against the operation's declared responses.
+func (r runner) response(op openapi.Operation, detail string, resp output.HTTPResponse) []report.Finding {
+	var out []report.Finding
+	add := func(id string, sev report.Severity, format string, args ...any) {
+		out = append(out, r.finding(op, detail, id, sev, format, args...))
+	}
+	declared := declaredResponse(&op, resp.Status)
+	if declared == nil {
+		add(RUN_STATUS_UNDECLARED, report.SEVERITY_ERROR, "status %d is not declared", resp.Status)
+		return out
+	}
+	if resp.Status >= 400 {
+		add(RUN_STATUS_UNEXPECTED, report.SEVERITY_WARNING, "the synthesized request was answered with %d; add examples the service accepts", resp.Status)
+	}
+
+	names := make([]string, 0, len(declared.Headers))
+	for name := range declared.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		h := declared.Headers[name]
+		if strings.EqualFold(name, "Content-Type") {
+			continue
+		}
+		values := resp.Header.Values(name)
+		if len(values) == 0 {
+			if h.Required {
+				add(RUN_HEADER_MISSING, report.SEVERITY_ERROR, "required response header %q is missing", name)
+			}
+			continue
+		}
+		for _, viol := range h.Schema.Validate(coerceParameter(h.Schema, values), openapi.SCHEMA_RESPONSE) {
+			add(RUN_HEADER_INVALID, report.SEVERITY_ERROR, "response header %q %s", name, viol)
+		}
+	}
+
+	if len(resp.Body) == 0 {
+		if len(declared.Content) > 0 && resp.Status != http.StatusNoContent && op.Method != http.MethodHead {
+			add(RUN_RESPONSE_BODY_MISSING, report.SEVERITY_ERROR, "response %s declares content but has no body", declared.Status)
+		}
+		return out
+	}
+	if len(declared.Content) == 0 {
+		add(RUN_RESPONSE_BODY_UNDECLARED, report.SEVERITY_WARNING, "response %s declares no content but has a body", declared.Status)
+		return out
+	}
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		mediaType = "application/octet-stream"
+	}
+	mt, ok := matchMediaType(declared.Content, mediaType)
+	if !ok {
+		add(RUN_MEDIA_TYPE_UNSUPPORTED, report.SEVERITY_ERROR, "response %s media type %s is not declared", declared.Status, mediaType)
+		return out
+	}
+	if mt.Schema == nil || !isJSONMediaType(mediaType) {
+		return out
+	}
+	var v any
+	if err := json.Unmarshal(resp.Body, &v); err != nil {
+		add(RUN_RESPONSE_BODY_INVALID, report.SEVERITY_ERROR, "response %s body is not valid JSON: %v", declared.Status, err)
+		return out
+	}
+	for _, viol := range mt.Schema.Validate(v, openapi.SCHEMA_RESPONSE) {
+		add(RUN_RESPONSE_BODY_INVALID, report.SEVERITY_ERROR, "response %s body %s", declared.Status, bodyViolation(viol))
+	}
+	return out
+}
+
+// compile-time check
+var _ input.RunContractTests = (*RunService)(nil)
@@ -0,0 +1,244 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+	"github.com/betoth/contractcheck/internal/application/service"
+)
+
+// handlerClient serves requests with an http.Handler, in process.
+type handlerClient struct {
+	handler http.Handler
+	mu      sync.Mutex
+	calls   []*http.Request
+}
+
+func (c *handlerClient) Do(_ context.Context, req output.HTTPRequest) (output.HTTPResponse, error) {
+	r := httptest.NewRequest(req.Method, req.URL, bytes.NewReader(req.Body))
+	r.Header = req.Header.Clone()
+	c.mu.Lock()
+	c.calls = append(c.calls, r)
+	c.mu.Unlock()
+	if c.handler == nil {
+		return output.HTTPResponse{}, errors.New("connection refused")
+	}
+	w := httptest.NewRecorder()
+	c.handler.ServeHTTP(w, r)
+	return output.HTTPResponse{Status: w.Code, Header: w.Header(), Body: w.Body.Bytes()}, nil
+}
+
+func newRunService(t *testing.T, doc *openapi.Document, client output.HTTPClient) *service.RunService {
+	t.Helper()
+	svc, err := service.NewRunService(service.RunParams{
+		Importer: modelImporter{doc: doc},
+		Client:   client,
+		Logger:   nopLogger{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svc
+}
+
+func outcomes(run report.ContractRun) []string {
+	out := make([]string, len(run.Operations))
+	for i, op := range run.Operations {
+		out[i] = op.Operation + " " + op.Outcome
+	}
+	return out
+}
+
+// runPetAPI serves petAPI at https://api.example.com/v1 behind an API key,
+// with dependencies between the pet operations.
+func runPetAPI() *openapi.Document {
+	return petAPI(func(d *openapi.Document) {
+		d.Servers = []openapi.Server{{URL: "https://{host}/v1", Variables: map[string]openapi.ServerVariable{"host": {Default: "api.example.com"}}}}
+		d.SecuritySchemes = map[string]openapi.SecurityScheme{
+			"key":   {Type: "apiKey", In: openapi.PARAM_IN_HEADER, Name: "X-Api-Key"},
+			"basic": {Type: "http", Scheme: "basic"},
+		}
+		d.Security = []openapi.SecurityRequirement{{"key": nil}}
+		for i := range d.Operations {
+			op := &d.Operations[i]
+			switch op.Method {
+			case "POST":
+				op.ID = "createPet"
+			case "GET":
+				op.Extensions = map[string]any{service.DEPENDS_ON_EXTENSION: "createPet"}
+				op.Responses[0].Headers = map[string]openapi.Header{"X-Rate": {Required: true, Schema: &openapi.Schema{Type: []string{"integer"}}}}
+			case "DELETE":
+				op.Extensions = map[string]any{service.DEPENDS_ON_EXTENSION: []any{"get /pets/{id}"}}
+			}
+		}
+		d.Operations = append(d.Operations,
+			openapi.Operation{Path: "/health", Method: "GET", Security: []openapi.SecurityRequirement{},
+				Extensions: map[string]any{service.DEPENDS_ON_EXTENSION: "nope"},
+				Responses:  []openapi.Response{{Status: "200"}}},
+			openapi.Operation{Path: "/admin", Method: "GET", Security: []openapi.SecurityRequirement{{"basic": nil}},
+				Responses: []openapi.Response{{Status: "200"}}},
+			openapi.Operation{Path: "/loop", Method: "GET", Extensions: map[string]any{service.DEPENDS_ON_EXTENSION: "GET /loop"},
+				Responses: []openapi.Response{{Status: "200"}}},
+		)
+	})
+}
+
+func TestRunService_CallsOperations(t *testing.T) {
+	client := &handlerClient{handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "POST /v1/pets":
+			w.WriteHeader(http.StatusCreated)
+		case "GET /v1/pets/string":
+			fmt.Fprint(w, `{"id":1}`)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})}
+	run, err := newRunService(t, runPetAPI(), client).Run(context.Background(), "pets.yaml", input.RunOptions{
+		Credentials: map[string]string{"key": "k1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if run.BaseURL != "https://api.example.com/v1" {
+		t.Errorf("base URL = %s", run.BaseURL)
+	}
+	wantOutcomes := []string{
+		"GET /admin skipped",
+		"GET /health failed",
+		"GET /loop skipped",
+		"POST /pets passed",
+		"DELETE /pets/{id} skipped",
+		"GET /pets/{id} failed",
+	}
+	if got := outcomes(run); !slices.Equal(got, wantOutcomes) {
+		t.Errorf("outcomes =\n%q\nwant\n%q", got, wantOutcomes)
+	}
+	wantFindings := []string{
+		"GET /admin " + service.RUN_CREDENTIALS_MISSING,
+		"GET /health " + service.RUN_DEPENDENCY_INVALID,
+		"GET /health " + service.RUN_STATUS_UNDECLARED,
+		"GET /loop " + service.RUN_DEPENDENCY_INVALID,
+		"DELETE /pets/{id} " + service.RUN_OPERATION_SKIPPED,
+		"GET /pets/{id} " + service.RUN_HEADER_MISSING,
+		"GET /pets/{id} " + service.RUN_RESPONSE_BODY_INVALID,
+	}
+	if got := findingIDs(run.Findings); !slices.Equal(got, wantFindings) {
+		t.Errorf("findings =\n%q\nwant\n%q", got, wantFindings)
+	}
+
+	// createPet runs before the operation that depends on it.
+	var order []string
+	for _, r := range client.calls {
+		order = append(order, r.Method+" "+r.URL.Path)
+	}
+	if i, j := slices.Index(order, "POST /v1/pets"), slices.Index(order, "GET /v1/pets/string"); i < 0 || j < i {
+		t.Errorf("call order = %v", order)
+	}
+	for _, r := range client.calls {
+		if r.URL.Path == "/v1/pets" {
+			body, _ := io.ReadAll(r.Body)
+			if r.Header.Get("X-Api-Key") != "k1" || string(body) != `{"name":"string"}` {
+				t.Errorf("POST /pets: headers %v, body %s", r.Header, body)
+			}
+		}
+		if r.URL.Path == "/v1/health" && r.Header.Get("X-Api-Key") != "" {
+			t.Error("GET /health opts out of security but was sent the API key")
+		}
+	}
+}
+
+func TestRunService_CredentialsAndTransportErrors(t *testing.T) {
+	doc := runPetAPI()
+	doc.Operations = []openapi.Operation{*doc.Operation("GET", "/admin")}
+	client := &handlerClient{}
+	run, err := newRunService(t, doc, client).Run(context.Background(), "pets.yaml", input.RunOptions{
+		BaseURL:     "http://localhost:8080/",
+		Credentials: map[string]string{"basic": "ann:secret"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(client.calls) != 1 || client.calls[0].URL.String() != "http://localhost:8080/admin" {
+		t.Fatalf("calls = %v", client.calls)
+	}
+	if user, pass, ok := client.calls[0].BasicAuth(); !ok || user != "ann" || pass != "secret" {
+		t.Errorf("basic auth = %q %q", user, pass)
+	}
+	if got := findingIDs(run.Findings); !slices.Equal(got, []string{"GET /admin " + service.RUN_REQUEST_FAILED}) {
+		t.Errorf("findings = %q", got)
+	}
+}
+
+// slowClient answers 200 after a pause and records the peak concurrency.
+type slowClient struct {
+	inFlight, peak atomic.Int32
+}
+
+func (c *slowClient) Do(context.Context, output.HTTPRequest) (output.HTTPResponse, error) {
+	n := c.inFlight.Add(1)
+	defer c.inFlight.Add(-1)
+	for {
+		p := c.peak.Load()
+		if n <= p || c.peak.CompareAndSwap(p, n) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	return output.HTTPResponse{Status: http.StatusOK}, nil
+}
+
+func TestRunService_Concurrency(t *testing.T) {
+	doc := &openapi.Document{Servers: []openapi.Server{{URL: "http://localhost"}}}
+	for i := 0; i < 8; i++ {
+		doc.Operations = append(doc.Operations, openapi.Operation{
+			Path: fmt.Sprintf("/r%d", i), Method: "GET", Responses: []openapi.Response{{Status: "200"}},
+		})
+	}
+	client := &slowClient{}
+	run, err := newRunService(t, doc, client).Run(context.Background(), "api.yaml", input.RunOptions{Concurrency: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Count(report.OUTCOME_PASSED) != 8 {
+		t.Errorf("outcomes = %q", outcomes(run))
+	}
+	if peak := client.peak.Load(); peak > 2 {
+		t.Errorf("peak concurrency = %d, want at most 2", peak)
+	}
+}
+
+func TestRunService_InvalidOptions(t *testing.T) {
+	cases := map[string]struct {
+		doc  *openapi.Document
+		opts input.RunOptions
+	}{
+		"negative concurrency": {petAPI(nil), input.RunOptions{BaseURL: "http://localhost", Concurrency: -1}},
+		"no server URL":        {petAPI(nil), input.RunOptions{}},
+		"relative base URL":    {petAPI(nil), input.RunOptions{BaseURL: "/v1"}},
+	}
+	for name, tc := range cases {
+		_, err := newRunService(t, tc.doc, &handlerClient{}).Run(context.Background(), "pets.yaml", tc.opts)
+		var ae *customerrors.AppError
+		if !errors.As(err, &ae) || ae.Type != customerrors.VALIDATION_ERROR {
+			t.Errorf("%s: err = %v", name, err)
+		}
+	}
+}
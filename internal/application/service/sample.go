@@ -0,0 +1,201 @@
+package service
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+)
+
+// maxSampleDepth bounds nesting of synthesized values, so required
+// recursive properties do not recurse forever.
+const maxSampleDepth = 8
+
+// sampleStrings are the values synthesized for string formats.
+var sampleStrings = map[string]string{
+	"date":      "2024-01-01",
+	"date-time": "2024-01-01T00:00:00Z",
+	"time":      "00:00:00",
+	"email":     "user@example.com",
+	"uuid":      "00000000-0000-4000-8000-000000000000",
+	"uri":       "https://example.com",
+	"url":       "https://example.com",
+	"hostname":  "example.com",
+	"ipv4":      "192.0.2.1",
+	"ipv6":      "2001:db8::1",
+	"byte":      "c3RyaW5n",
+	"password":  "secret",
+}
+
+// sampleValue synthesizes a value of s, deterministically: the schema's
+// example, default or first enum value when it has one, else the simplest
+// value that meets its constraints. Objects get their required properties,
+// leaving out readOnly ones in requests and writeOnly ones in responses.
+// A nil schema yields nil.
+func sampleValue(s *openapi.Schema, dir openapi.SchemaDirection) any {
+	return sampleAt(s, dir, 0)
+}
+
+func sampleAt(s *openapi.Schema, dir openapi.SchemaDirection, depth int) any {
+	if s == nil || depth > maxSampleDepth {
+		return nil
+	}
+	switch {
+	case s.Example != nil:
+		return s.Example
+	case s.Default != nil:
+		return s.Default
+	case len(s.Enum) > 0:
+		return s.Enum[0]
+	}
+
+	if len(s.AllOf) > 0 {
+		merged := map[string]any{}
+		for _, sub := range s.AllOf {
+			v := sampleAt(sub, dir, depth+1)
+			m, ok := v.(map[string]any)
+			if !ok {
+				return v
+			}
+			for k, pv := range m {
+				merged[k] = pv
+			}
+		}
+		if own, ok := sampleType(s, dir, depth).(map[string]any); ok {
+			for k, pv := range own {
+				merged[k] = pv
+			}
+		}
+		return merged
+	}
+	for _, alts := range [][]*openapi.Schema{s.OneOf, s.AnyOf} {
+		if len(alts) > 0 {
+			return sampleAt(alts[0], dir, depth+1)
+		}
+	}
+	return sampleType(s, dir, depth)
+}
+
+// sampleType synthesizes a value from s's own type and constraints.
+func sampleType(s *openapi.Schema, dir openapi.SchemaDirection, depth int) any {
+	switch {
+	case s.HasType("object") || (len(s.Type) == 0 && len(s.Properties) > 0):
+		return sampleObject(s, dir, depth)
+	case s.HasType("array") || (len(s.Type) == 0 && s.Items != nil):
+		n := uint64(1)
+		if s.MinItems > n {
+			n = s.MinItems
+		}
+		if s.MaxItems != nil && *s.MaxItems < n {
+			n = *s.MaxItems
+		}
+		items := make([]any, n)
+		for i := range items {
+			items[i] = sampleAt(s.Items, dir, depth+1)
+		}
+		return items
+	case s.HasType("integer"):
+		return sampleNumber(s, true)
+	case s.HasType("number"):
+		return sampleNumber(s, false)
+	case s.HasType("boolean"):
+		return true
+	case s.HasType("string") || len(s.Type) == 0:
+		return sampleString(s)
+	}
+	return nil // "null"
+}
+
+func sampleObject(s *openapi.Schema, dir openapi.SchemaDirection, depth int) map[string]any {
+	out := map[string]any{}
+	skip := func(p *openapi.Schema) bool {
+		return p != nil && ((dir == openapi.SCHEMA_REQUEST && p.ReadOnly) || (dir == openapi.SCHEMA_RESPONSE && p.WriteOnly))
+	}
+	for _, name := range s.Required {
+		if p := s.Properties[name]; !skip(p) {
+			out[name] = sampleAt(p, dir, depth+1)
+		}
+	}
+	// Fill up to minProperties with optional properties, in name order.
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if uint64(len(out)) >= s.MinProperties {
+			break
+		}
+		if _, ok := out[name]; !ok && !skip(s.Properties[name]) {
+			out[name] = sampleAt(s.Properties[name], dir, depth+1)
+		}
+	}
+	return out
+}
+
+func sampleNumber(s *openapi.Schema, integer bool) float64 {
+	n := 1.0
+	step := 1.0
+	if !integer {
+		step = 0.5
+	}
+	switch {
+	case s.Minimum != nil:
+		n = *s.Minimum
+		if s.ExclusiveMinimum {
+			n += step
+		}
+	case s.Maximum != nil && *s.Maximum < n:
+		n = *s.Maximum
+		if s.ExclusiveMaximum {
+			n -= step
+		}
+	}
+	if s.MultipleOf != nil && *s.MultipleOf > 0 {
+		n = math.Ceil(n / *s.MultipleOf) * *s.MultipleOf
+	}
+	if integer {
+		n = math.Ceil(n)
+	}
+	return n
+}
+
+func sampleString(s *openapi.Schema) string {
+	v, ok := sampleStrings[s.Format]
+	if !ok {
+		v = "string"
+	}
+	if n := uint64(len(v)); n < s.MinLength {
+		v += strings.Repeat("x", int(s.MinLength-n))
+	}
+	if s.MaxLength != nil && uint64(len(v)) > *s.MaxLength && !ok {
+		v = v[:*s.MaxLength]
+	}
+	return v
+}
+
+// sampleText renders a synthesized value as it goes in a path, query or
+// header: strings as is, numbers without exponent, arrays comma-joined.
+func sampleText(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = sampleText(item)
+		}
+		return strings.Join(parts, ",")
+	}
+	raw, _ := json.Marshal(v)
+	return string(raw)
+}
@@ -0,0 +1,389 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+// SecurityRules describes the rule IDs of the security audit: the
+// security ErrorKinds.
+func SecurityRules() []report.Rule {
+	return []report.Rule{
+		{ID: string(openapi.SECURITY_MISSING), Name: "SecurityMissing", Summary: "An operation can be called without credentials.", Severity: report.SEVERITY_WARNING},
+		{ID: string(openapi.SECURITY_INCONSISTENT_SCOPES), Name: "SecurityInconsistentScopes", Summary: "An operation requires other OAuth scopes than similar operations on the same resource, or scopes its scheme does not define.", Severity: report.SEVERITY_WARNING},
+		{ID: string(openapi.SECURITY_API_KEY_IN_QUERY), Name: "SecurityAPIKeyInQuery", Summary: "An API key is sent in the query string, where logs and browser history keep it.", Severity: report.SEVERITY_WARNING},
+		{ID: string(openapi.SECURITY_BASIC_WITHOUT_TLS), Name: "SecurityBasicWithoutTLS", Summary: "HTTP basic credentials are sent to a server over plain HTTP.", Severity: report.SEVERITY_ERROR},
+		{ID: string(openapi.SECURITY_OAUTH_URL_MISSING), Name: "SecurityOAuthURLMissing", Summary: "An OAuth flow or OpenID Connect scheme lacks the URL clients need to obtain tokens.", Severity: report.SEVERITY_ERROR},
+	}
+}
+
+// flowURLs lists the URLs each OAuth flow requires.
+var flowURLs = map[string][]string{
+	"implicit":          {"authorizationUrl"},
+	"password":          {"tokenUrl"},
+	"clientCredentials": {"tokenUrl"},
+	"authorizationCode": {"authorizationUrl", "tokenUrl"},
+}
+
+// SecurityCheck audits the security schemes and requirements of loaded
+// documents. It plugs into pipelines (e.g. watch mode) as a report.Check.
+type SecurityCheck struct{}
+
+// NewSecurityCheck builds the check.
+func NewSecurityCheck() *SecurityCheck {
+	return &SecurityCheck{}
+}
+
+// Name implements report.Check.
+func (c *SecurityCheck) Name() string { return report.CHECK_SECURITY }
+
+// Check implements report.Check. Findings carry no file; callers that know
+// it set it.
+func (c *SecurityCheck) Check(_ context.Context, doc openapi.OpenAPIDoc) ([]report.Finding, error) {
+	if doc.Model == nil {
+		return nil, customerrors.NewDependencyError("loader model")
+	}
+	findings := auditSecurity(doc.Model)
+	report.Sort(findings)
+	return findings, nil
+}
+
+// SecurityAuditParams declares the dependencies required to build the service.
+type SecurityAuditParams struct {
+	Importer input.ImportOpenAPISpec
+	Logger   output.Logger
+}
+
+// validate performs defensive checks on constructor params.
+func (p SecurityAuditParams) validate() error {
+	if p.Importer == nil {
+		return customerrors.NewDependencyError("importer")
+	}
+	if p.Logger == nil {
+		return customerrors.NewDependencyError("logger")
+	}
+	return nil
+}
+
+// SecurityAuditService audits the security of a spec file (input port
+// implementation).
+type SecurityAuditService struct {
+	importer input.ImportOpenAPISpec
+	logger   output.Logger
+	check    *SecurityCheck
+}
+
+// NewSecurityAuditService constructs the service after validating dependencies.
+func NewSecurityAuditService(params SecurityAuditParams) (*SecurityAuditService, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	return &SecurityAuditService{importer: params.Importer, logger: params.Logger, check: NewSecurityCheck()}, nil
+}
+
+// Audit imports the spec at specPath and runs the security check on it.
+func (s *SecurityAuditService) Audit(ctx context.Context, specPath string) (report.Report, error) {
+	log := s.logger.With("local", "service.SecurityAuditService.Audit")
+
+	doc, err := s.importer.Import(ctx, specPath)
+	if err != nil {
+		log.Error("failed to import spec", "file", specPath)
+		return report.Report{}, err
+	}
+	findings, err := s.check.Check(ctx, doc)
+	if err != nil {
+		return report.Report{}, err
+	}
+	subject := report.Subject{File: specPath, Checks: []string{report.CHECK_SECURITY}}
+	for _, op := range doc.Model.Operations {
+		subject.Operations = append(subject.Operations, op.Key())
+	}
+	for i := range findings {
+		findings[i].File = specPath
+	}
+	log.Debug("security audited", "file", specPath, "findings", len(findings))
+	return report.Report{Subjects: []report.Subject{subject}, Findings: findings}, nil
+}
+
+// auditSecurity checks the schemes, then the requirements of every
+// operation.
+func auditSecurity(doc *openapi.Document) []report.Finding {
+	var findings []report.Finding
+	add := func(kind openapi.ErrorKind, severity report.Severity, pointer, operation, message string) *report.Finding {
+		findings = append(findings, report.Finding{
+			RuleID:    string(kind),
+			Check:     report.CHECK_SECURITY,
+			Severity:  severity,
+			Message:   message,
+			Pointer:   pointer,
+			Operation: operation,
+		})
+		return &findings[len(findings)-1]
+	}
+
+	for _, name := range sortedKeys(doc.SecuritySchemes) {
+		scheme := doc.SecuritySchemes[name]
+		pointer := "/components/securitySchemes/" + pointerToken(name)
+		switch scheme.Type {
+		case "apiKey":
+			if scheme.In == openapi.PARAM_IN_QUERY {
+				add(openapi.SECURITY_API_KEY_IN_QUERY, report.SEVERITY_WARNING, pointer, "",
+					fmt.Sprintf("scheme %q sends its API key in the query parameter %q, where logs and browser history keep it", name, scheme.Name))
+			}
+		case "oauth2":
+			if len(scheme.Flows) == 0 {
+				add(openapi.SECURITY_OAUTH_URL_MISSING, report.SEVERITY_ERROR, pointer, "",
+					fmt.Sprintf("OAuth scheme %q declares no flows", name))
+			}
+			for _, flowName := range sortedKeys(scheme.Flows) {
+				flow := scheme.Flows[flowName]
+				for _, field := range flowURLs[flowName] {
+					if (field == "authorizationUrl" && flow.AuthorizationURL == "") || (field == "tokenUrl" && flow.TokenURL == "") {
+						add(openapi.SECURITY_OAUTH_URL_MISSING, report.SEVERITY_ERROR, pointer+"/flows/"+flowName, "",
+							fmt.Sprintf("OAuth flow %q of scheme %q has no %s", flowName, name, field))
+					}
+				}
+			}
+		case "openIdConnect":
+			if scheme.OpenIDConnectURL == "" {
+				add(openapi.SECURITY_OAUTH_URL_MISSING, report.SEVERITY_ERROR, pointer, "",
+					fmt.Sprintf("OpenID Connect scheme %q has no openIdConnectUrl", name))
+			}
+		}
+	}
+
+	scopes := newScopeGroups()
+	plain := map[string][]string{} // basic scheme -> plain HTTP server URLs, in order found
+	plainOps := map[[2]string]int{}
+	for _, op := range doc.Operations {
+		pointer := "/paths/" + pointerToken(op.Path) + "/" + strings.ToLower(op.Method)
+		reqs := op.EffectiveSecurity(doc)
+		switch {
+		case len(reqs) == 0 && op.Security != nil:
+			add(openapi.SECURITY_MISSING, report.SEVERITY_INFO, pointer, op.Key(), "the operation is explicitly public (security: [])")
+		case len(reqs) == 0:
+			add(openapi.SECURITY_MISSING, report.SEVERITY_WARNING, pointer, op.Key(), "the operation has no security requirement")
+		case hasEmptyRequirement(reqs):
+			add(openapi.SECURITY_MISSING, report.SEVERITY_INFO, pointer, op.Key(), "credentials are optional: one security alternative requires none")
+		}
+
+		required := map[string][]string{}
+		for _, req := range reqs {
+			for name, s := range req {
+				required[name] = append(required[name], s...)
+			}
+		}
+		for _, name := range sortedKeys(required) {
+			scheme, ok := doc.SecuritySchemes[name]
+			if !ok {
+				continue
+			}
+			switch {
+			case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "basic"):
+				for _, server := range plainHTTPServers(op, doc) {
+					key := [2]string{name, server}
+					if plainOps[key] == 0 {
+						plain[name] = append(plain[name], server)
+					}
+					plainOps[key]++
+				}
+			case scheme.Type == "oauth2" || scheme.Type == "openIdConnect":
+				set := uniqueSorted(required[name])
+				if scheme.Type == "oauth2" {
+					for _, scope := range set {
+						if !definesScope(scheme, scope) {
+							add(openapi.SECURITY_INCONSISTENT_SCOPES, report.SEVERITY_WARNING, pointer, op.Key(),
+								fmt.Sprintf("the operation requires scope %q, which scheme %q does not define", scope, name))
+						}
+					}
+				}
+				scopes.add(op, name, set, pointer)
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(plain) {
+		for _, server := range plain[name] {
+			f := add(openapi.SECURITY_BASIC_WITHOUT_TLS, report.SEVERITY_ERROR, "/components/securitySchemes/"+pointerToken(name), "",
+				fmt.Sprintf("scheme %q sends HTTP basic credentials over plain HTTP to %s", name, server))
+			f.Detail = fmt.Sprintf("used by %d operation(s)", plainOps[[2]string{name, server}])
+		}
+	}
+	for _, d := range scopes.deviations() {
+		add(openapi.SECURITY_INCONSISTENT_SCOPES, report.SEVERITY_WARNING, d.pointer, d.operation,
+			fmt.Sprintf("the operation requires scopes %s of scheme %q where the other %s operations on %s require %s",
+				scopeList(d.scopes), d.scheme, d.access, d.resource, scopeList(d.usual)))
+	}
+	return findings
+}
+
+func hasEmptyRequirement(reqs []openapi.SecurityRequirement) bool {
+	for _, req := range reqs {
+		if len(req) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// plainHTTPServers lists the servers of op reached over plain HTTP, with
+// variables set to their defaults. Loopback hosts are left out: credentials
+// sent to them never cross a network.
+func plainHTTPServers(op openapi.Operation, doc *openapi.Document) []string {
+	servers := op.Servers
+	if len(servers) == 0 {
+		servers = doc.Servers
+	}
+	var out []string
+	for _, srv := range servers {
+		raw := srv.URL
+		for name, v := range srv.Variables {
+			raw = strings.ReplaceAll(raw, "{"+name+"}", v.Default)
+		}
+		u, err := url.Parse(raw)
+		if err != nil || !strings.EqualFold(u.Scheme, "http") || isLoopback(u.Hostname()) {
+			continue
+		}
+		out = append(out, raw)
+	}
+	return out
+}
+
+func isLoopback(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func definesScope(scheme openapi.SecurityScheme, scope string) bool {
+	for _, flow := range scheme.Flows {
+		if _, ok := flow.Scopes[scope]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func uniqueSorted(values []string) []string {
+	set := map[string]bool{}
+	for _, v := range values {
+		set[v] = true
+	}
+	return sortedKeys(set)
+}
+
+func scopeList(scopes []string) string {
+	if len(scopes) == 0 {
+		return "none"
+	}
+	return "[" + strings.Join(scopes, " ") + "]"
+}
+
+// scopeGroups collects the scopes operations require of each OAuth scheme,
+// grouped by resource and access: operations that read the same resource
+// should require the same scopes, and so should those that write it.
+type scopeGroups struct {
+	order  []string
+	groups map[string]*scopeGroup
+}
+
+type scopeGroup struct {
+	resource, scheme, access string
+	uses                     []scopeUse // in document order
+}
+
+type scopeUse struct {
+	operation, pointer string
+	scopes             []string
+}
+
+// scopeDeviation is an operation whose scopes differ from the usual ones
+// of its group.
+type scopeDeviation struct {
+	operation, pointer, scheme, access, resource string
+	scopes, usual                                []string
+}
+
+func newScopeGroups() *scopeGroups {
+	return &scopeGroups{groups: map[string]*scopeGroup{}}
+}
+
+func (g *scopeGroups) add(op openapi.Operation, scheme string, scopes []string, pointer string) {
+	resource, access := scopeResource(op.Path), "write"
+	switch op.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		access = "read"
+	}
+	key := resource + "\x00" + scheme + "\x00" + access
+	if g.groups[key] == nil {
+		g.groups[key] = &scopeGroup{resource: resource, scheme: scheme, access: access}
+		g.order = append(g.order, key)
+	}
+	g.groups[key].uses = append(g.groups[key].uses, scopeUse{operation: op.Key(), pointer: pointer, scopes: scopes})
+}
+
+// deviations lists, per group, the operations that do not require the
+// scopes most of the group requires; on a tie, the first operation's
+// scopes are the usual ones.
+func (g *scopeGroups) deviations() []scopeDeviation {
+	var out []scopeDeviation
+	for _, key := range g.order {
+		group := g.groups[key]
+		counts := map[string]int{}
+		usual := ""
+		for _, use := range group.uses {
+			sig := strings.Join(use.scopes, " ")
+			counts[sig]++
+			if counts[sig] > counts[usual] || len(counts) == 1 {
+				usual = sig
+			}
+		}
+		if len(counts) < 2 {
+			continue
+		}
+		for _, use := range group.uses {
+			if strings.Join(use.scopes, " ") == usual {
+				continue
+			}
+			out = append(out, scopeDeviation{
+				operation: use.operation, pointer: use.pointer,
+				scheme: group.scheme, access: group.access, resource: group.resource,
+				scopes: use.scopes, usual: strings.Fields(usual),
+			})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].operation < out[j].operation })
+	return out
+}
+
+// scopeResource names the resource of a path: the path without its
+// trailing templated segments, so /pets and /pets/{petId} are one resource.
+func scopeResource(path string) string {
+	segments := strings.Split(strings.TrimSuffix(path, "/"), "/")
+	for len(segments) > 1 && strings.HasPrefix(segments[len(segments)-1], "{") {
+		segments = segments[:len(segments)-1]
+	}
+	if resource := strings.Join(segments, "/"); resource != "" {
+		return resource
+	}
+	return "/"
+}
+
+// compile-time check
+var (
+	_ report.Check        = (*SecurityCheck)(nil)
+	_ input.AuditSecurity = (*SecurityAuditService)(nil)
+)
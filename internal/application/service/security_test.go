@@ -0,0 +1,135 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+	"github.com/betoth/contractcheck/internal/application/service"
+)
+
+func securedPetAPI() *openapi.Document {
+	return petAPI(func(d *openapi.Document) {
+		d.Servers = []openapi.Server{
+			{URL: "{scheme}://api.example.com", Variables: map[string]openapi.ServerVariable{"scheme": {Default: "http"}}},
+			{URL: "http://localhost:8080"},
+			{URL: "https://secure.example.com"},
+		}
+		d.SecuritySchemes = map[string]openapi.SecurityScheme{
+			"key":   {Type: "apiKey", In: openapi.PARAM_IN_QUERY, Name: "api_key"},
+			"basic": {Type: "http", Scheme: "Basic"},
+			"oauth": {Type: "oauth2", Flows: map[string]openapi.OAuthFlow{
+				"authorizationCode": {AuthorizationURL: "https://auth.example.com/authorize", Scopes: map[string]string{"pets:read": "", "pets:write": ""}},
+				"clientCredentials": {TokenURL: "https://auth.example.com/token", Scopes: map[string]string{"pets:read": ""}},
+			}},
+			"oidc": {Type: "openIdConnect"},
+		}
+		d.Security = []openapi.SecurityRequirement{{"oauth": {"pets:read"}}}
+		d.Operations = append(d.Operations, openapi.Operation{Path: "/pets", Method: "GET", Responses: []openapi.Response{{Status: "200"}}})
+		for i := range d.Operations {
+			op := &d.Operations[i]
+			switch op.Key() {
+			case "POST /pets":
+				op.Security = []openapi.SecurityRequirement{{"basic": nil}, {}}
+			case "GET /pets/{id}":
+				op.Security = []openapi.SecurityRequirement{{"oauth": {"pets:admin"}}}
+			case "DELETE /pets/{id}":
+				op.Security = []openapi.SecurityRequirement{}
+			}
+		}
+	})
+}
+
+func findingLines(findings []report.Finding) []string {
+	lines := make([]string, 0, len(findings))
+	for _, f := range findings {
+		lines = append(lines, fmt.Sprintf("%s %s %s %s: %s", f.Severity, f.RuleID, f.Pointer, f.Operation, f.Message))
+	}
+	return lines
+}
+
+func TestSecurityAuditService_Audit(t *testing.T) {
+	svc, err := service.NewSecurityAuditService(service.SecurityAuditParams{
+		Importer: modelImporter{doc: securedPetAPI()},
+		Logger:   nopLogger{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep, err := svc.Audit(context.Background(), "api.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := findingLines(rep.Findings)
+	slices.Sort(got)
+	want := []string{
+		`error security_basic_without_tls /components/securitySchemes/basic : scheme "basic" sends HTTP basic credentials over plain HTTP to http://api.example.com`,
+		`error security_oauth_url_missing /components/securitySchemes/oauth/flows/authorizationCode : OAuth flow "authorizationCode" of scheme "oauth" has no tokenUrl`,
+		`error security_oauth_url_missing /components/securitySchemes/oidc : OpenID Connect scheme "oidc" has no openIdConnectUrl`,
+		`info security_missing /paths/~1pets/post POST /pets: credentials are optional: one security alternative requires none`,
+		`info security_missing /paths/~1pets~1{id}/delete DELETE /pets/{id}: the operation is explicitly public (security: [])`,
+		`warning security_api_key_in_query /components/securitySchemes/key : scheme "key" sends its API key in the query parameter "api_key", where logs and browser history keep it`,
+		`warning security_inconsistent_scopes /paths/~1pets~1{id}/get GET /pets/{id}: the operation requires scope "pets:admin", which scheme "oauth" does not define`,
+		`warning security_inconsistent_scopes /paths/~1pets~1{id}/get GET /pets/{id}: the operation requires scopes [pets:admin] of scheme "oauth" where the other read operations on /pets require [pets:read]`,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("findings =\n%s\nwant\n%s", got, want)
+	}
+
+	for _, f := range rep.Findings {
+		if f.File != "api.yaml" || f.Check != report.CHECK_SECURITY {
+			t.Errorf("finding %q: file %q, check %q", f.Message, f.File, f.Check)
+		}
+		if f.RuleID == string(openapi.SECURITY_BASIC_WITHOUT_TLS) && f.Detail != "used by 1 operation(s)" {
+			t.Errorf("basic detail = %q", f.Detail)
+		}
+	}
+	if rep.Findings[0].Severity != report.SEVERITY_ERROR {
+		t.Errorf("first finding = %v, want errors first", rep.Findings[0])
+	}
+	if s := rep.Subjects; len(s) != 1 || s[0].File != "api.yaml" || len(s[0].Operations) != 4 {
+		t.Errorf("subjects = %+v", s)
+	}
+}
+
+func TestSecurityCheck_FlagsUnsecuredOperations(t *testing.T) {
+	check := service.NewSecurityCheck()
+	if check.Name() != report.CHECK_SECURITY {
+		t.Errorf("name = %q", check.Name())
+	}
+	findings, err := check.Check(context.Background(), openapi.OpenAPIDoc{Model: petAPI(nil)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"warning security_missing /paths/~1pets/post POST /pets: the operation has no security requirement",
+		"warning security_missing /paths/~1pets~1{id}/delete DELETE /pets/{id}: the operation has no security requirement",
+		"warning security_missing /paths/~1pets~1{id}/get GET /pets/{id}: the operation has no security requirement",
+	}
+	if got := findingLines(findings); !slices.Equal(got, want) {
+		t.Errorf("findings =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := check.Check(context.Background(), openapi.OpenAPIDoc{}); err == nil {
+		t.Error("no model: want an error")
+	}
+}
+
+func TestSecurityRules_DescribeEverySecurityKind(t *testing.T) {
+	ids := map[string]bool{}
+	for _, r := range service.SecurityRules() {
+		ids[r.ID] = true
+	}
+	for _, kind := range []openapi.ErrorKind{
+		openapi.SECURITY_MISSING, openapi.SECURITY_INCONSISTENT_SCOPES, openapi.SECURITY_API_KEY_IN_QUERY,
+		openapi.SECURITY_BASIC_WITHOUT_TLS, openapi.SECURITY_OAUTH_URL_MISSING,
+	} {
+		if !ids[string(kind)] {
+			t.Errorf("no rule for %s", kind)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+// SemverParams declares the dependencies required to build the service.
+type SemverParams struct {
+	Logger output.Logger
+}
+
+// validate performs defensive checks on constructor params.
+func (p SemverParams) validate() error {
+	if p.Logger == nil {
+		return customerrors.NewDependencyError("logger")
+	}
+	return nil
+}
+
+// SemverService recommends info.version bumps (input port implementation).
+type SemverService struct {
+	logger output.Logger
+}
+
+// NewSemverService constructs the service after validating dependencies.
+func NewSemverService(params SemverParams) (*SemverService, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	return &SemverService{logger: params.Logger}, nil
+}
+
+// changeBump is the bump one change calls for on a 1.0.0+ API: breaking
+// changes are major, new features and deprecations minor (as SemVer asks
+// for deprecations), and any other compatible change a patch.
+func changeBump(ch report.Change) openapi.VersionBump {
+	if ch.Breaking() {
+		return openapi.BUMP_MAJOR
+	}
+	switch changelogCategory[ch.ID] {
+	case report.CHANGELOG_ADDED, report.CHANGELOG_DEPRECATED:
+		return openapi.BUMP_MINOR
+	}
+	return openapi.BUMP_PATCH
+}
+
+// initialDevelopment shifts bumps down one component for 0.y.z APIs, the
+// usual reading of SemVer's "anything may change": breaking changes bump
+// the minor version, everything else the patch.
+var initialDevelopment = map[openapi.VersionBump]openapi.VersionBump{
+	openapi.BUMP_MAJOR: openapi.BUMP_MINOR,
+	openapi.BUMP_MINOR: openapi.BUMP_PATCH,
+	openapi.BUMP_PATCH: openapi.BUMP_PATCH,
+}
+
+// RecommendVersion derives the required bump from cmp's changes, the next
+// version from the base spec's info.version, and whether the head spec's
+// declared version understates the changes.
+func (s *SemverService) RecommendVersion(_ context.Context, cmp report.Comparison) (report.VersionAdvice, error) {
+	advice := report.VersionAdvice{
+		Base:        cmp.Base,
+		Head:        cmp.Head,
+		BaseVersion: cmp.BaseSpec.Version,
+		HeadVersion: cmp.HeadSpec.Version,
+		Required:    openapi.BUMP_NONE,
+		Reasons:     []string{},
+	}
+	base, baseOK := openapi.APIVersion(cmp.BaseSpec.Version).Semver()
+	head, headOK := openapi.APIVersion(cmp.HeadSpec.Version).Semver()
+	preStable := baseOK && base.Major == 0
+
+	reasons := map[string]bool{}
+	for _, ch := range cmp.Changes {
+		bump := changeBump(ch)
+		if preStable {
+			bump = initialDevelopment[bump]
+		}
+		switch {
+		case advice.Required.Less(bump):
+			advice.Required = bump
+			reasons = map[string]bool{ch.ID: true}
+		case bump == advice.Required:
+			reasons[ch.ID] = true
+		}
+	}
+	for id := range reasons {
+		advice.Reasons = append(advice.Reasons, id)
+	}
+	sort.Strings(advice.Reasons)
+
+	if preStable && len(cmp.Changes) > 0 {
+		advice.Notes = append(advice.Notes, fmt.Sprintf("%s is an initial-development (0.y.z) version: breaking changes bump the minor version", base))
+	}
+	switch {
+	case !baseOK && cmp.BaseSpec.Version == "":
+		advice.Notes = append(advice.Notes, "the base spec declares no info.version")
+	case !baseOK:
+		advice.Notes = append(advice.Notes, fmt.Sprintf("base info.version %q is not a semantic version", cmp.BaseSpec.Version))
+	default:
+		advice.Recommended = base.Bump(advice.Required).String()
+	}
+	if !headOK && cmp.HeadSpec.Version != "" {
+		advice.Notes = append(advice.Notes, fmt.Sprintf("head info.version %q is not a semantic version", cmp.HeadSpec.Version))
+	}
+	if baseOK && headOK {
+		advice.Declared = openapi.BumpBetween(base, head)
+		advice.Understated = advice.Declared.Less(advice.Required)
+		if head.Compare(base) < 0 {
+			advice.Notes = append(advice.Notes, fmt.Sprintf("head version %s is lower than base version %s", head, base))
+		}
+	}
+
+	s.logger.With("local", "service.SemverService.RecommendVersion").Debug("version bump derived",
+		"head", cmp.Head,
+		"required", advice.Required,
+		"declared", advice.Declared,
+		"understated", advice.Understated,
+	)
+	return advice, nil
+}
+
+// compile-time check
+var _ input.RecommendVersion = (*SemverService)(nil)
@@ -0,0 +1,77 @@
+package service_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+	"github.com/betoth/contractcheck/internal/application/service"
+)
+
+func TestSemverService_RecommendVersion(t *testing.T) {
+	svc, err := service.NewSemverService(service.SemverParams{Logger: nopLogger{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	removed := report.Change{ID: service.CHANGE_OPERATION_REMOVED, Level: report.CHANGE_BREAKING}
+	required := report.Change{ID: service.CHANGE_PROPERTY_ADDED_REQUIRED, Level: report.CHANGE_BREAKING}
+	added := report.Change{ID: service.CHANGE_PARAMETER_ADDED, Level: report.CHANGE_NON_BREAKING}
+	deprecated := report.Change{ID: service.CHANGE_PROPERTY_DEPRECATED, Level: report.CHANGE_NON_BREAKING}
+	relaxed := report.Change{ID: service.CHANGE_PARAMETER_OPTIONAL, Level: report.CHANGE_NON_BREAKING}
+
+	cases := []struct {
+		name        string
+		base, head  string
+		changes     []report.Change
+		required    openapi.VersionBump
+		declared    openapi.VersionBump
+		recommended string
+		understated bool
+		reasons     []string
+		notes       int
+	}{
+		{"breaking needs major", "1.4.2", "1.5.0", []report.Change{added, removed, required},
+			openapi.BUMP_MAJOR, openapi.BUMP_MINOR, "2.0.0", true,
+			[]string{service.CHANGE_OPERATION_REMOVED, service.CHANGE_PROPERTY_ADDED_REQUIRED}, 0},
+		{"additions and deprecations need minor", "1.4.2", "2.0.0", []report.Change{relaxed, deprecated, added},
+			openapi.BUMP_MINOR, openapi.BUMP_MAJOR, "1.5.0", false,
+			[]string{service.CHANGE_PARAMETER_ADDED, service.CHANGE_PROPERTY_DEPRECATED}, 0},
+		{"other compatible changes need patch", "v1.4.2", "v1.4.3", []report.Change{relaxed},
+			openapi.BUMP_PATCH, openapi.BUMP_PATCH, "1.4.3", false,
+			[]string{service.CHANGE_PARAMETER_OPTIONAL}, 0},
+		{"no changes", "1.4.2", "1.4.2", nil,
+			openapi.BUMP_NONE, openapi.BUMP_NONE, "1.4.2", false, []string{}, 0},
+		{"initial development shifts down", "0.3.1", "0.4.0", []report.Change{removed, added},
+			openapi.BUMP_MINOR, openapi.BUMP_MINOR, "0.4.0", false,
+			[]string{service.CHANGE_OPERATION_REMOVED}, 1},
+		{"version went down", "1.4.2", "1.4.0", []report.Change{added},
+			openapi.BUMP_MINOR, openapi.BUMP_NONE, "1.5.0", true,
+			[]string{service.CHANGE_PARAMETER_ADDED}, 1},
+		{"date versions cannot be bumped", "2024-06-01", "2024-09-01", []report.Change{removed},
+			openapi.BUMP_MAJOR, "", "", false,
+			[]string{service.CHANGE_OPERATION_REMOVED}, 2},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := svc.RecommendVersion(context.Background(), report.Comparison{
+				BaseSpec: report.SpecInfo{Version: tc.base},
+				HeadSpec: report.SpecInfo{Version: tc.head},
+				Changes:  tc.changes,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Required != tc.required || got.Declared != tc.declared || got.Recommended != tc.recommended || got.Understated != tc.understated {
+				t.Errorf("advice = %+v", got)
+			}
+			if !slices.Equal(got.Reasons, tc.reasons) {
+				t.Errorf("reasons = %q, want %q", got.Reasons, tc.reasons)
+			}
+			if len(got.Notes) != tc.notes {
+				t.Errorf("notes = %q, want %d", got.Notes, tc.notes)
+			}
+		})
+	}
+}
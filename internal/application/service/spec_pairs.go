@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+)
+
+// specPairs loads the two sides of a comparison, from files on disk or from
+// git revisions, for the services that look at how a spec evolved.
+//   - loaderFor returns a loader whose external refs are confined to its
+//     sandbox directory (the spec's directory for files, the checkout for
+//     git revisions).
+//   - revisions is optional; without it git fails with a dependency error.
+type specPairs struct {
+	loaderFor     func(sandboxDir string) openapi.Loader
+	revisions     output.RevisionSource
+	logger        output.Logger
+	versionPolicy input.VersionPolicy
+}
+
+// specPair is a loaded base and head, with the labels reports show for them
+// (paths, or "rev:path" for git sources).
+type specPair struct {
+	baseLabel, headLabel string
+	base, head           *openapi.Document
+}
+
+// files loads two spec files on disk.
+func (s specPairs) files(ctx context.Context, basePath, headPath string) (specPair, error) {
+	log := s.logger.With("local", "service.specPairs.files")
+
+	base, err := s.load(ctx, basePath, filepath.Dir(basePath), basePath)
+	if err != nil {
+		log.Error("failed to load base spec", "file", basePath)
+		return specPair{}, err
+	}
+	head, err := s.load(ctx, headPath, filepath.Dir(headPath), headPath)
+	if err != nil {
+		log.Error("failed to load head spec", "file", headPath)
+		return specPair{}, err
+	}
+	return specPair{baseLabel: basePath, headLabel: headPath, base: base, head: head}, nil
+}
+
+// git loads req.Path at the merge-base of req.Head and req.Target, and at
+// req.Head.
+func (s specPairs) git(ctx context.Context, req input.GitCompareRequest) (specPair, error) {
+	log := s.logger.With("local", "service.specPairs.git")
+	if s.revisions == nil {
+		return specPair{}, customerrors.NewDependencyError("revisions")
+	}
+	if req.Repo == "" {
+		req.Repo = "."
+	}
+	if req.Head == "" {
+		req.Head = "HEAD"
+	}
+	if req.Target == "" {
+		return specPair{}, customerrors.NewValidationError(
+			"Missing target revision",
+			errors.New("a target branch is required to compute the merge-base"),
+			map[string]any{customerrors.DetailFile: req.Path},
+		)
+	}
+
+	headCommit, err := s.revisions.Resolve(ctx, req.Repo, req.Head)
+	if err != nil {
+		return specPair{}, err
+	}
+	baseCommit, err := s.revisions.MergeBase(ctx, req.Repo, headCommit, req.Target)
+	if err != nil {
+		return specPair{}, err
+	}
+	log.Info("loading spec revisions",
+		"file", req.Path,
+		"head", headCommit,
+		"target", req.Target,
+		"mergeBase", baseCommit,
+	)
+
+	p := specPair{
+		baseLabel: fmt.Sprintf("%s:%s", shortCommit(baseCommit), filepath.ToSlash(req.Path)),
+		headLabel: fmt.Sprintf("%s:%s", shortCommit(headCommit), filepath.ToSlash(req.Path)),
+	}
+	if p.base, err = s.loadRevision(ctx, req.Repo, baseCommit, req.Path, p.baseLabel); err != nil {
+		return specPair{}, err
+	}
+	if p.head, err = s.loadRevision(ctx, req.Repo, headCommit, req.Path, p.headLabel); err != nil {
+		return specPair{}, err
+	}
+	return p, nil
+}
+
+// revision loads path as of rev, in the repository that contains it.
+func (s specPairs) revision(ctx context.Context, path, rev string) (*openapi.Document, string, error) {
+	if s.revisions == nil {
+		return nil, "", customerrors.NewDependencyError("revisions")
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, "", err
+	}
+	repo := filepath.Dir(abs)
+	commit, err := s.revisions.Resolve(ctx, repo, rev)
+	if err != nil {
+		return nil, "", err
+	}
+	label := fmt.Sprintf("%s:%s", shortCommit(commit), filepath.ToSlash(path))
+	doc, err := s.loadRevision(ctx, repo, commit, abs, label)
+	if err != nil {
+		return nil, "", err
+	}
+	return doc, label, nil
+}
+
+// loadRevision materializes path at commit and loads it confined to the checkout.
+func (s specPairs) loadRevision(ctx context.Context, repo, commit, path, label string) (*openapi.Document, error) {
+	co, cleanup, err := s.revisions.Materialize(ctx, repo, commit, path)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	return s.load(ctx, co.Root, co.Dir, label)
+}
+
+// load reads one side, enforces the version policy and returns its model.
+// label replaces the on-disk path in errors (e.g. a temp checkout).
+func (s specPairs) load(ctx context.Context, path, sandbox, label string) (*openapi.Document, error) {
+	doc, err := s.loaderFor(sandbox).Load(ctx, path)
+	if err != nil {
+		return nil, relabel(err, label)
+	}
+	if !s.versionPolicy.IsSupported(doc.Version.Major()) {
+		return nil, customerrors.NewUnsupportedVersionError(label, doc.Version.String(), s.versionPolicy.SupportedVersions())
+	}
+	if doc.Model == nil {
+		return nil, customerrors.NewDependencyError("loader model")
+	}
+	return doc.Model, nil
+}
+
+// relabel points an AppError's file detail at label.
+func relabel(err error, label string) error {
+	var ae *customerrors.AppError
+	if errors.As(err, &ae) && ae.Details != nil {
+		ae.Details[customerrors.DetailFile] = label
+	}
+	return err
+}
+
+// shortCommit abbreviates a commit ID for labels.
+func shortCommit(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
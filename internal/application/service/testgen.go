@@ -0,0 +1,366 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/testgen"
+)
+
+// DEFAULT_TEST_PACKAGE is the package of generated tests when none is given.
+const DEFAULT_TEST_PACKAGE = "contract"
+
+// TestGenParams declares the dependencies required to build the service.
+type TestGenParams struct {
+	Importer input.ImportOpenAPISpec
+	Renderer testgen.Renderer
+	Logger   output.Logger
+}
+
+// validate performs defensive checks on constructor params.
+func (p TestGenParams) validate() error {
+	if p.Importer == nil {
+		return customerrors.NewDependencyError("importer")
+	}
+	if p.Renderer == nil {
+		return customerrors.NewDependencyError("renderer")
+	}
+	if p.Logger == nil {
+		return customerrors.NewDependencyError("logger")
+	}
+	return nil
+}
+
+// TestGenService generates contract tests from a spec (input port implementation).
+type TestGenService struct {
+	importer input.ImportOpenAPISpec
+	renderer testgen.Renderer
+	logger   output.Logger
+}
+
+// NewTestGenService constructs the service after validating dependencies.
+func NewTestGenService(params TestGenParams) (*TestGenService, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	return &TestGenService{importer: params.Importer, renderer: params.Renderer, logger: params.Logger}, nil
+}
+
+// Generate imports the spec at specPath, plans one test per operation and
+// declared response, and renders the plan.
+func (s *TestGenService) Generate(ctx context.Context, specPath string, opts input.TestGenOptions) (testgen.Generated, error) {
+	log := s.logger.With("local", "service.TestGenService.Generate")
+	if opts.Package == "" {
+		opts.Package = DEFAULT_TEST_PACKAGE
+	}
+	if !token.IsIdentifier(opts.Package) {
+		return testgen.Generated{}, customerrors.NewValidationError(
+			"Invalid package name", fmt.Errorf("%q is not a Go identifier", opts.Package), nil)
+	}
+
+	doc, err := s.importer.Import(ctx, specPath)
+	if err != nil {
+		log.Error("failed to import spec", "file", specPath)
+		return testgen.Generated{}, err
+	}
+	if doc.Model == nil {
+		return testgen.Generated{}, customerrors.NewDependencyError("loader model")
+	}
+
+	suite := planSuite(doc.Model, opts.Package, filepath.ToSlash(specPath))
+	files, err := s.renderer.Render(suite)
+	if err != nil {
+		return testgen.Generated{}, err
+	}
+	out := testgen.Generated{Files: files}
+	for _, f := range suite.Files {
+		for _, c := range f.Cases {
+			out.Tests++
+			if c.Skip != "" {
+				out.Skipped++
+			}
+		}
+	}
+	log.Info("contract tests generated", "file", specPath, "tests", out.Tests, "skipped", out.Skipped)
+	return out, nil
+}
+
+// planSuite builds the test plan: operations grouped in files by their
+// first tag, cases in spec order (path, method, status).
+func planSuite(doc *openapi.Document, pkg, spec string) testgen.Suite {
+	suite := testgen.Suite{Package: pkg, Spec: spec, Schemas: map[string]*openapi.Schema{}}
+	files := map[string]*testgen.File{}
+	names := map[string]bool{}
+	for _, op := range doc.Operations {
+		fileName := "untagged"
+		if len(op.Tags) > 0 {
+			fileName = snakeName(op.Tags[0])
+		}
+		f, ok := files[fileName]
+		if !ok {
+			f = &testgen.File{Name: fileName}
+			files[fileName] = f
+		}
+		// Dedupe the operation part so the status stays last: ListPets2_200.
+		name := uniqueName(names, testBaseName(op))
+		f.Cases = append(f.Cases, planOperation(doc, op, name, suite.Schemas)...)
+	}
+	for _, name := range sortedKeys(files) {
+		suite.Files = append(suite.Files, *files[name])
+	}
+	return suite
+}
+
+// planOperation plans one case per declared response, named
+// name_<status>. The first success
+// status gets a valid request; 401 an anonymous one when the operation is
+// secured; the first of 400/422 a request missing a required query
+// parameter or carrying a body of the wrong type. Statuses no synthesized
+// request is known to produce are generated as skipped tests.
+func planOperation(doc *openapi.Document, op openapi.Operation, name string, schemas map[string]*openapi.Schema) []testgen.Case {
+	valid := validRequest(op)
+	invalid, canInvalidate := invalidRequest(op, valid)
+	secured := len(op.EffectiveSecurity(doc)) > 0
+
+	var cases []testgen.Case
+	var success, rejected string
+	for _, resp := range op.Responses {
+		if resp.Status == "" {
+			continue
+		}
+		c := valid
+		c.Name = name + "_" + strings.ToUpper(resp.Status[:1]) + resp.Status[1:]
+		c.Status = resp.Status
+		c.Content = responseContent(op, resp, schemas)
+
+		class := resp.Status[0]
+		switch {
+		case class == '2' && success == "":
+			success = resp.Status
+		case class == '2':
+			c.Skip = fmt.Sprintf("the valid request is expected to produce %s", success)
+		case resp.Status == "401" && secured:
+			c.Anonymous = true
+		case (resp.Status == "400" || resp.Status == "422") && rejected == "" && canInvalidate:
+			rejected = resp.Status
+			c = withCaseFields(invalid, c)
+		default:
+			c.Skip = fmt.Sprintf("no synthesized request is known to produce %s", resp.Status)
+		}
+		if valid.Skip != "" && c.Skip == "" {
+			c.Skip = valid.Skip
+		}
+		cases = append(cases, c)
+	}
+	return cases
+}
+
+// withCaseFields copies the response-side fields of c onto req.
+func withCaseFields(req, c testgen.Case) testgen.Case {
+	req.Name, req.Status, req.Content = c.Name, c.Status, c.Content
+	return req
+}
+
+// validRequest synthesizes the required parameters and, when declared, a
+// body of the preferred media type. Skip is set when the body cannot be
+// synthesized.
+func validRequest(op openapi.Operation) testgen.Case {
+	c := testgen.Case{Operation: op.Key(), Method: op.Method, Path: op.Path}
+	var cookies []string
+	for _, p := range op.Parameters {
+		if !p.Required {
+			continue
+		}
+		value := sampleText(sampleValue(p.Schema, openapi.SCHEMA_REQUEST))
+		switch p.In {
+		case openapi.PARAM_IN_PATH:
+			if c.PathParams == nil {
+				c.PathParams = map[string]string{}
+			}
+			c.PathParams[p.Name] = value
+		case openapi.PARAM_IN_QUERY:
+			if c.Query == nil {
+				c.Query = map[string][]string{}
+			}
+			c.Query[p.Name] = []string{value}
+		case openapi.PARAM_IN_HEADER:
+			if ignoredHeaders[strings.ToLower(p.Name)] {
+				continue
+			}
+			if c.Headers == nil {
+				c.Headers = map[string]string{}
+			}
+			c.Headers[p.Name] = value
+		case openapi.PARAM_IN_COOKIE:
+			cookies = append(cookies, p.Name+"="+value)
+		}
+	}
+	if len(cookies) > 0 {
+		if c.Headers == nil {
+			c.Headers = map[string]string{}
+		}
+		sort.Strings(cookies)
+		c.Headers["Cookie"] = strings.Join(cookies, "; ")
+	}
+
+	rb := op.RequestBody
+	if rb == nil || len(rb.Content) == 0 {
+		return c
+	}
+	mediaType := preferredMediaType(rb.Content)
+	mt := rb.Content[mediaType]
+	body := mt.Example
+	if body == nil {
+		body = sampleValue(mt.Schema, openapi.SCHEMA_REQUEST)
+	}
+	switch {
+	case isJSONMediaType(mediaType):
+		if body == nil {
+			body = map[string]any{}
+		}
+		raw, err := json.Marshal(body)
+		if err != nil {
+			c.Skip = fmt.Sprintf("the %s example cannot be encoded: %v", mediaType, err)
+			return c
+		}
+		c.ContentType, c.Body = mediaType, string(raw)
+	case strings.HasPrefix(mediaType, "text/"):
+		c.ContentType, c.Body = mediaType, sampleText(body)
+	case rb.Required:
+		c.Skip = fmt.Sprintf("request bodies of type %s are not synthesized", mediaType)
+	}
+	return c
+}
+
+// invalidRequest derives a request the operation must reject from valid:
+// without its first required query parameter, or else with a JSON body of
+// the wrong type.
+func invalidRequest(op openapi.Operation, valid testgen.Case) (testgen.Case, bool) {
+	if len(valid.Query) > 0 {
+		c := valid
+		c.Query = map[string][]string{}
+		names := sortedKeys(valid.Query)
+		for _, name := range names[1:] {
+			c.Query[name] = valid.Query[name]
+		}
+		return c, true
+	}
+	if valid.Body != "" && isJSONMediaType(valid.ContentType) {
+		c := valid
+		c.Body = "[]"
+		if s := op.RequestBody.Content[valid.ContentType].Schema; s.HasType("array") {
+			c.Body = "{}"
+		}
+		return c, true
+	}
+	return testgen.Case{}, false
+}
+
+// responseContent maps the response's media types to schema keys,
+// registering the schemas in schemas.
+func responseContent(op openapi.Operation, resp openapi.Response, schemas map[string]*openapi.Schema) map[string]string {
+	if len(resp.Content) == 0 {
+		return nil
+	}
+	content := make(map[string]string, len(resp.Content))
+	for mediaType, mt := range resp.Content {
+		if mt.Schema == nil {
+			content[mediaType] = ""
+			continue
+		}
+		key := op.Key() + " " + resp.Status + " " + mediaType
+		schemas[key] = mt.Schema
+		content[mediaType] = key
+	}
+	return content
+}
+
+// preferredMediaType picks application/json, then another JSON type, then
+// the first type in name order.
+func preferredMediaType(content map[string]openapi.MediaType) string {
+	keys := sortedKeys(content)
+	if _, ok := content["application/json"]; ok {
+		return "application/json"
+	}
+	for _, k := range keys {
+		if isJSONMediaType(k) {
+			return k
+		}
+	}
+	return keys[0]
+}
+
+// isJSONMediaType accepts application/json (with parameters) and the +json
+// suffix family.
+func isJSONMediaType(mt string) bool {
+	mt, _, _ = strings.Cut(mt, ";")
+	mt = strings.TrimSpace(mt)
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
+
+// testBaseName names an operation's tests: its operationId, or the method
+// and path ("GET /pets/{id}" is "GetPetsId").
+func testBaseName(op openapi.Operation) string {
+	if name := exportedName(op.ID); name != "" {
+		return name
+	}
+	return exportedName(strings.ToLower(op.Method) + " " + op.Path)
+}
+
+// exportedName joins the alphanumeric words of s in PascalCase; a leading
+// digit gets an "N" prefix so the result stays an identifier.
+func exportedName(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	name := b.String()
+	if name != "" && unicode.IsDigit(rune(name[0])) {
+		name = "N" + name
+	}
+	return name
+}
+
+// snakeName turns a tag into a file name stem: "Pet Store" is "pet_store".
+func snakeName(s string) string {
+	var words []string
+	for _, w := range strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		words = append(words, strings.ToLower(w))
+	}
+	if len(words) == 0 {
+		return "untagged"
+	}
+	return strings.Join(words, "_")
+}
+
+// uniqueName returns name, or name2, name3... if it is already taken.
+func uniqueName(taken map[string]bool, name string) string {
+	candidate := name
+	for i := 2; taken[candidate]; i++ {
+		candidate = fmt.Sprintf("%s%d", name, i)
+	}
+	taken[candidate] = true
+	return candidate
+}
+
+// compile-time check
+var _ input.GenerateContractTests = (*TestGenService)(nil)
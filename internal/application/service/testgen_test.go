@@ -0,0 +1,144 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/testgen"
+	"github.com/betoth/contractcheck/internal/application/service"
+)
+
+// suiteRecorder captures the suite it is asked to render.
+type suiteRecorder struct {
+	suite testgen.Suite
+}
+
+func (r *suiteRecorder) Render(s testgen.Suite) ([]testgen.Source, error) {
+	r.suite = s
+	return []testgen.Source{{Name: "out_test.go"}}, nil
+}
+
+func newTestGenService(t *testing.T, doc *openapi.Document, renderer testgen.Renderer) *service.TestGenService {
+	t.Helper()
+	svc, err := service.NewTestGenService(service.TestGenParams{
+		Importer: modelImporter{doc: doc},
+		Renderer: renderer,
+		Logger:   nopLogger{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svc
+}
+
+// caseLines summarizes cases as "file: Name status [anonymous] [skip]".
+func caseLines(s testgen.Suite) []string {
+	var out []string
+	for _, f := range s.Files {
+		for _, c := range f.Cases {
+			line := fmt.Sprintf("%s: %s %s", f.Name, c.Name, c.Status)
+			if c.Anonymous {
+				line += " anonymous"
+			}
+			if c.Skip != "" {
+				line += " skip"
+			}
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+func TestTestGenService_PlansCases(t *testing.T) {
+	doc := petAPI(func(d *openapi.Document) {
+		d.Security = []openapi.SecurityRequirement{{"key": nil}}
+		for i := range d.Operations {
+			op := &d.Operations[i]
+			switch op.Method {
+			case "POST":
+				op.ID = "createPet"
+				op.Tags = []string{"Pet Store"}
+				op.Responses = append(op.Responses, openapi.Response{Status: "400"}, openapi.Response{Status: "422"})
+			case "GET":
+				op.Tags = []string{"Pet Store"}
+				op.Parameters = append(op.Parameters, openapi.Parameter{Name: "session", In: openapi.PARAM_IN_COOKIE, Required: true, Schema: str()})
+				op.Responses = append(op.Responses, openapi.Response{Status: "203"}, openapi.Response{Status: "401"}, openapi.Response{Status: "404"})
+			case "DELETE":
+				op.ID = "createPet" // clashes with POST: the operation part is deduped
+				op.Security = []openapi.SecurityRequirement{}
+				op.Responses = append(op.Responses, openapi.Response{Status: "401"})
+			}
+		}
+	})
+	renderer := &suiteRecorder{}
+	got, err := newTestGenService(t, doc, renderer).Generate(context.Background(), "specs/pets.yaml", input.TestGenOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Tests != 9 || got.Skipped != 4 || len(got.Files) != 1 {
+		t.Errorf("generated %d test(s), %d skipped, %d file(s)", got.Tests, got.Skipped, len(got.Files))
+	}
+
+	s := renderer.suite
+	if s.Package != service.DEFAULT_TEST_PACKAGE || s.Spec != "specs/pets.yaml" {
+		t.Errorf("suite = %q %q", s.Package, s.Spec)
+	}
+	want := []string{
+		"pet_store: CreatePet_201 201",
+		"pet_store: CreatePet_400 400",
+		"pet_store: CreatePet_422 422 skip",
+		"pet_store: GetPetsId_200 200",
+		"pet_store: GetPetsId_203 203 skip",
+		"pet_store: GetPetsId_401 401 anonymous",
+		"pet_store: GetPetsId_404 404 skip",
+		"untagged: CreatePet2_204 204",
+		"untagged: CreatePet2_401 401 skip",
+	}
+	if lines := caseLines(s); !slices.Equal(lines, want) {
+		t.Errorf("cases =\n%q\nwant\n%q", lines, want)
+	}
+
+	byName := map[string]testgen.Case{}
+	for _, f := range s.Files {
+		for _, c := range f.Cases {
+			byName[c.Name] = c
+		}
+	}
+	if c := byName["CreatePet_201"]; c.ContentType != "application/json" || c.Body != `{"name":"string"}` {
+		t.Errorf("valid body = %s %s", c.ContentType, c.Body)
+	}
+	if c := byName["CreatePet_400"]; c.Body != "[]" {
+		t.Errorf("invalid body = %s", c.Body)
+	}
+	get := byName["GetPetsId_200"]
+	if get.PathParams["id"] != "string" || get.Headers["Cookie"] != "session=string" {
+		t.Errorf("request = %v %v", get.PathParams, get.Headers)
+	}
+	key := get.Content["application/json"]
+	if s.Schemas[key] == nil {
+		t.Errorf("schema %q is not registered", key)
+	}
+}
+
+func TestTestGenService_Errors(t *testing.T) {
+	_, err := newTestGenService(t, petAPI(nil), &suiteRecorder{}).Generate(context.Background(), "pets.yaml", input.TestGenOptions{Package: "not-valid"})
+	var ae *customerrors.AppError
+	if !errors.As(err, &ae) || ae.Type != customerrors.VALIDATION_ERROR {
+		t.Errorf("invalid package: err = %v", err)
+	}
+
+	_, err = newTestGenService(t, nil, &suiteRecorder{}).Generate(context.Background(), "missing.yaml", input.TestGenOptions{})
+	if !errors.As(err, &ae) || ae.Details[customerrors.DetailKind] != string(openapi.FILE_NOT_FOUND) {
+		t.Errorf("missing spec: err = %v", err)
+	}
+
+	if _, err := service.NewTestGenService(service.TestGenParams{Importer: modelImporter{}, Logger: nopLogger{}}); err == nil {
+		t.Error("missing renderer: want a dependency error")
+	}
+}
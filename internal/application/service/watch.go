@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+)
+
+// OpenAPIWatchParams declares the dependencies required to build the service.
+//   - ImporterFor returns an importer whose external refs are confined to
+//     sandboxDir (the watched spec's directory).
+//   - Checks (lint) is optional; without it a run only re-imports (loads and
+//     validates) the spec.
+//   - Baselines is optional; without it sessions with a base fail with a
+//     dependency error.
+type OpenAPIWatchParams struct {
+	ImporterFor func(sandboxDir string) input.ImportOpenAPISpec
+	Watcher     output.FileWatcher
+	Logger      output.Logger
+	Checks      []report.Check
+	Baselines   input.BuildBaselineCheck
+}
+
+// validate performs defensive checks on constructor params.
+func (p OpenAPIWatchParams) validate() error {
+	if p.ImporterFor == nil {
+		return customerrors.NewDependencyError("importerFor")
+	}
+	if p.Watcher == nil {
+		return customerrors.NewDependencyError("watcher")
+	}
+	if p.Logger == nil {
+		return customerrors.NewDependencyError("logger")
+	}
+	return nil
+}
+
+// OpenAPIWatchService re-runs the import, checks and diff pipeline on file
+// changes (input port implementation).
+type OpenAPIWatchService struct {
+	importerFor func(sandboxDir string) input.ImportOpenAPISpec
+	watcher     output.FileWatcher
+	logger      output.Logger
+	checks      []report.Check
+	baselines   input.BuildBaselineCheck
+}
+
+// NewOpenAPIWatchService constructs the service after validating dependencies.
+func NewOpenAPIWatchService(params OpenAPIWatchParams) (*OpenAPIWatchService, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	return &OpenAPIWatchService{
+		importerFor: params.ImporterFor,
+		watcher:     params.Watcher,
+		logger:      params.Logger,
+		checks:      params.Checks,
+		baselines:   params.Baselines,
+	}, nil
+}
+
+// Watch runs the pipeline once, then again after every debounced change to
+// the spec or any file it references. The watched set follows the refs of the
+// last successful import, so adding or removing a `$ref` takes effect on the
+// next run. With opts.Base, the base is loaded once up front and every run
+// also reports the changes against it. emit is called for the first run and
+// for every run whose findings changed. Watch returns nil when ctx is done.
+func (s *OpenAPIWatchService) Watch(ctx context.Context, filePath string, opts input.WatchOptions, emit func(input.WatchResult)) error {
+	log := s.logger.With("local", "service.OpenAPIWatchService.Watch")
+
+	root, err := filepath.Abs(filePath)
+	if err != nil {
+		return err
+	}
+
+	p := pipeline{importer: s.importerFor(filepath.Dir(root)), checks: s.checks}
+	if !opts.Base.IsZero() {
+		if s.baselines == nil {
+			return customerrors.NewDependencyError("baselines")
+		}
+		diff, err := s.baselines.BaselineCheck(ctx, filePath, opts.Base)
+		if err != nil {
+			log.Error("failed to load baseline", "file", filePath, "error", err.Error())
+			return err
+		}
+		p.checks = append(slices.Clone(p.checks), diff)
+	}
+
+	var (
+		previous []report.Finding
+		watched  []string
+		changed  []string
+		events   <-chan []string
+		stop     = func() {}
+	)
+	defer func() { stop() }()
+
+	for run := 1; ; run++ {
+		started := time.Now()
+		findings, sources := p.run(ctx, filePath)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if len(sources) == 0 {
+			sources = []string{root}
+			if watched != nil {
+				sources = watched
+			}
+		}
+
+		// Keep the session alive across runs so edits made while the pipeline
+		// ran are still delivered; restart it only when the refs changed.
+		if !slices.Equal(sources, watched) {
+			stop()
+			watchCtx, cancel := context.WithCancel(ctx)
+			if events, err = s.watcher.Watch(watchCtx, slices.Clone(sources)); err != nil {
+				cancel()
+				log.Error("file watcher failed", "file", filePath, "error", err.Error())
+				return err
+			}
+			watched, stop = sources, cancel
+		}
+
+		delta := report.Diff(previous, findings)
+		previous = findings
+		if run == 1 || !delta.Empty() {
+			emit(input.WatchResult{
+				Run:      run,
+				File:     filePath,
+				Changed:  changed,
+				Watched:  watched,
+				Delta:    delta,
+				Duration: time.Since(started),
+			})
+		}
+		log.Debug("watch run completed",
+			"run", run,
+			"findings", delta.Total,
+			"added", len(delta.Added),
+			"removed", len(delta.Removed),
+		)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case batch, ok := <-events:
+			if !ok {
+				return nil
+			}
+			changed = batch
+		}
+	}
+}
+
+// pipeline is what one watch session runs on every change.
+type pipeline struct {
+	importer input.ImportOpenAPISpec
+	checks   []report.Check
+}
+
+// run imports the spec and runs every check. Failures become findings so the
+// session keeps going while the user fixes the spec.
+func (p pipeline) run(ctx context.Context, filePath string) ([]report.Finding, []string) {
+	doc, err := p.importer.Import(ctx, filePath)
+	if err != nil {
+		return []report.Finding{report.FromError(err)}, nil
+	}
+
+	var findings []report.Finding
+	for _, check := range p.checks {
+		out, err := check.Check(ctx, doc)
+		if err != nil {
+			f := report.FromError(err)
+			f.RuleID = check.Name()
+			findings = append(findings, f)
+			continue
+		}
+		findings = append(findings, out...)
+	}
+	report.Sort(findings)
+
+	var sources []string
+	if doc.Model != nil {
+		sources = doc.Model.Sources
+	}
+	return findings, sources
+}
+
+// compile-time check
+var _ input.WatchOpenAPISpec = (*OpenAPIWatchService)(nil)
@@ -0,0 +1,201 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/betoth/contractcheck/internal/application/customerrors"
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+	"github.com/betoth/contractcheck/internal/application/service"
+)
+
+// scriptedImporter returns one scripted result per Import call.
+type scriptedImporter struct {
+	results []func() (openapi.OpenAPIDoc, error)
+	calls   int
+}
+
+func (s *scriptedImporter) Import(context.Context, string) (openapi.OpenAPIDoc, error) {
+	r := s.results[min(s.calls, len(s.results)-1)]
+	s.calls++
+	return r()
+}
+
+// chanWatcher hands out a channel the test feeds, and records watched sets.
+type chanWatcher struct {
+	mu      sync.Mutex
+	events  chan []string
+	watched [][]string
+}
+
+func (w *chanWatcher) Watch(_ context.Context, files []string) (<-chan []string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.watched = append(w.watched, files)
+	return w.events, nil
+}
+
+func docWithSources(sources ...string) func() (openapi.OpenAPIDoc, error) {
+	return func() (openapi.OpenAPIDoc, error) {
+		return openapi.OpenAPIDoc{Model: &openapi.Document{Sources: sources}}, nil
+	}
+}
+
+func TestOpenAPIWatchService_EmitsOnlyChangedFindings(t *testing.T) {
+	broken := func() (openapi.OpenAPIDoc, error) {
+		return openapi.OpenAPIDoc{}, openapi.NewValidationError(openapi.INVALID_SPEC, "Invalid OpenAPI specification", "/spec/root.yaml", errors.New("boom"))
+	}
+	importer := &scriptedImporter{results: []func() (openapi.OpenAPIDoc, error){
+		docWithSources("/spec/root.yaml", "/spec/pet.yaml"),
+		broken,
+		broken, // unchanged findings: nothing is emitted
+		docWithSources("/spec/root.yaml", "/spec/pet.yaml", "/spec/owner.yaml"),
+	}}
+	watcher := &chanWatcher{events: make(chan []string)}
+	var sandboxes []string
+	svc, err := service.NewOpenAPIWatchService(service.OpenAPIWatchParams{
+		ImporterFor: func(sandbox string) input.ImportOpenAPISpec {
+			sandboxes = append(sandboxes, sandbox)
+			return importer
+		},
+		Watcher: watcher,
+		Logger:  nopLogger{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results := make(chan input.WatchResult, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- svc.Watch(ctx, "/spec/root.yaml", input.WatchOptions{}, func(r input.WatchResult) { results <- r })
+	}()
+
+	next := func() input.WatchResult {
+		t.Helper()
+		select {
+		case r := <-results:
+			return r
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for a watch result")
+			return input.WatchResult{}
+		}
+	}
+
+	first := next()
+	if first.Run != 1 || first.Delta.Total != 0 || len(first.Watched) != 2 {
+		t.Fatalf("unexpected first run: %+v", first)
+	}
+	if !slices.Equal(sandboxes, []string{"/spec"}) {
+		t.Fatalf("expected one importer confined to the spec's directory, got %q", sandboxes)
+	}
+
+	watcher.events <- []string{"/spec/pet.yaml"}
+	second := next()
+	if second.Run != 2 || len(second.Delta.Added) != 1 || second.Delta.Added[0].RuleID != string(openapi.INVALID_SPEC) {
+		t.Fatalf("expected the import failure to be added: %+v", second)
+	}
+	if !slices.Equal(second.Changed, []string{"/spec/pet.yaml"}) {
+		t.Fatalf("Changed = %v", second.Changed)
+	}
+
+	watcher.events <- []string{"/spec/pet.yaml"} // run 3: same failure, no emit
+	watcher.events <- []string{"/spec/root.yaml"}
+	fourth := next()
+	if fourth.Run != 4 || len(fourth.Delta.Removed) != 1 || len(fourth.Delta.Added) != 0 {
+		t.Fatalf("expected the failure to be resolved: %+v", fourth)
+	}
+
+	// A new $ref widens the watched set, which restarts the watcher.
+	watcher.mu.Lock()
+	sets := len(watcher.watched)
+	last := watcher.watched[sets-1]
+	watcher.mu.Unlock()
+	if sets != 2 || len(last) != 3 {
+		t.Fatalf("expected one restart with 3 files, got %v", watcher.watched)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Watch returned %v after cancel", err)
+	}
+}
+
+// fixedCheck reports the same findings for every document.
+type fixedCheck struct{ findings []report.Finding }
+
+func (c fixedCheck) Name() string { return report.CHECK_COMPARE }
+
+func (c fixedCheck) Check(context.Context, openapi.OpenAPIDoc) ([]report.Finding, error) {
+	return c.findings, nil
+}
+
+// fakeBaselines records the baseline requested and returns a fixedCheck.
+type fakeBaselines struct {
+	got input.Baseline
+	err error
+}
+
+func (b *fakeBaselines) BaselineCheck(_ context.Context, _ string, base input.Baseline) (report.Check, error) {
+	b.got = base
+	if b.err != nil {
+		return nil, b.err
+	}
+	return fixedCheck{findings: []report.Finding{{RuleID: "operation-removed", Check: report.CHECK_COMPARE, Severity: report.SEVERITY_ERROR}}}, nil
+}
+
+func TestOpenAPIWatchService_DiffsAgainstBase(t *testing.T) {
+	importer := &scriptedImporter{results: []func() (openapi.OpenAPIDoc, error){docWithSources("/spec/root.yaml")}}
+	baselines := &fakeBaselines{}
+	svc, err := service.NewOpenAPIWatchService(service.OpenAPIWatchParams{
+		ImporterFor: func(string) input.ImportOpenAPISpec { return importer },
+		Watcher:     &chanWatcher{events: make(chan []string)},
+		Logger:      nopLogger{},
+		Baselines:   baselines,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results := make(chan input.WatchResult, 1)
+	go func() {
+		_ = svc.Watch(ctx, "/spec/root.yaml", input.WatchOptions{Base: input.Baseline{Rev: "main"}}, func(r input.WatchResult) { results <- r })
+	}()
+
+	select {
+	case r := <-results:
+		if len(r.Delta.Added) != 1 || r.Delta.Added[0].RuleID != "operation-removed" {
+			t.Fatalf("expected the diff finding: %+v", r.Delta)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a watch result")
+	}
+	if baselines.got.Rev != "main" {
+		t.Fatalf("baseline = %+v", baselines.got)
+	}
+
+	// A base that cannot be loaded ends the session before the first run.
+	baselines.err = errors.New("unknown revision")
+	if err := svc.Watch(context.Background(), "/spec/root.yaml", input.WatchOptions{Base: input.Baseline{Rev: "nope"}}, func(input.WatchResult) {
+		t.Fatal("unexpected run")
+	}); err == nil {
+		t.Fatal("expected the baseline error")
+	}
+}
+
+func TestOpenAPIWatchService_RequiresDependencies(t *testing.T) {
+	_, err := service.NewOpenAPIWatchService(service.OpenAPIWatchParams{Logger: nopLogger{}})
+	var ae *customerrors.AppError
+	if !errors.As(err, &ae) || ae.Type != customerrors.DEPENDENCY_ERROR {
+		t.Fatalf("expected dependency error, got %v", err)
+	}
+}
@@ -2,13 +2,42 @@ package config
 
 // AppConfig holds user/application configuration loaded from YAML.
 type AppConfig struct {
-	OpenAPI OpenAPIConfig `yaml:"openapi"`
+	OpenAPI OpenAPIConfig `yaml:"openapi" json:"openapi"`
+	Log     LogConfig     `yaml:"log" json:"log"`
+	Cache   CacheConfig   `yaml:"cache" json:"cache"`
 }
 
 // OpenAPIConfig configures OpenAPI-related behavior across the app.
 // SupportedMajors lists accepted major versions (e.g., 3 -> 3.x).
 type OpenAPIConfig struct {
-	SupportedMajors []int `yaml:"supported_majors"`
+	SupportedMajors []int `yaml:"supported_majors" json:"supported_majors"`
+}
+
+// LogConfig configures the backend logger and its persistent file sink.
+// Dir defaults to "<user config dir>/ContractCheck/logs"; an empty Dir after
+// normalization disables the file sink (console output is always kept).
+// RedactKeys lists sensitive keys masked in frontend log metadata; MetaMaxBytes
+// and MetaMaxDepth bound that metadata before it reaches the log sink.
+type LogConfig struct {
+	Level        string   `yaml:"level" json:"level"`
+	Dir          string   `yaml:"dir" json:"dir"`
+	MaxSizeMB    int      `yaml:"max_size_mb" json:"max_size_mb"`
+	MaxBackups   int      `yaml:"max_backups" json:"max_backups"`
+	MaxAgeDays   int      `yaml:"max_age_days" json:"max_age_days"`
+	Compress     bool     `yaml:"compress" json:"compress"`
+	RedactKeys   []string `yaml:"redact_keys" json:"redact_keys"`
+	MetaMaxBytes int      `yaml:"meta_max_bytes" json:"meta_max_bytes"`
+	MetaMaxDepth int      `yaml:"meta_max_depth" json:"meta_max_depth"`
+}
+
+// CacheConfig configures the parsed-spec cache. Entries bounds the in-memory
+// LRU; Disk enables the persistent layer under Dir, which defaults to
+// "<user cache dir>/ContractCheck/specs".
+type CacheConfig struct {
+	Enabled bool   `yaml:"enabled" json:"enabled"`
+	Entries int    `yaml:"entries" json:"entries"`
+	Disk    bool   `yaml:"disk" json:"disk"`
+	Dir     string `yaml:"dir" json:"dir"`
 }
 
 // Default returns a safe, opinionated configuration used on first run
@@ -18,5 +47,21 @@ func Default() AppConfig {
 		OpenAPI: OpenAPIConfig{
 			SupportedMajors: []int{3},
 		},
+		Log: LogConfig{
+			Level:      "info",
+			MaxSizeMB:  10,
+			MaxBackups: 5,
+			MaxAgeDays: 14,
+			RedactKeys: []string{
+				"password", "secret", "token", "access_token", "refresh_token",
+				"authorization", "cookie", "api_key",
+			},
+			MetaMaxBytes: 8 * 1024,
+			MetaMaxDepth: 4,
+		},
+		Cache: CacheConfig{
+			Enabled: true,
+			Entries: 32,
+		},
 	}
 }
@@ -4,12 +4,26 @@ package config
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sort"
+	"strings"
 )
 
 // ErrConfigInvalid is a sentinel error indicating the configuration failed validation.
 var ErrConfigInvalid = errors.New("invalid config")
 
+// AppDirName is the per-user directory name used under OS config/cache dirs.
+const AppDirName = "ContractCheck"
+
+// logLevels lists the accepted values for log.level.
+var logLevels = map[string]struct{}{
+	"debug": {},
+	"info":  {},
+	"warn":  {},
+	"error": {},
+}
+
 // LoadAppConfig returns the effective application configuration.
 func LoadAppConfig() (*AppConfig, error) {
 	cfg := Default()
@@ -35,9 +49,87 @@ func normalizeAndValidate(cfg *AppConfig) error {
 		}
 	}
 
+	if err := normalizeLog(&cfg.Log); err != nil {
+		return err
+	}
+	return normalizeCache(&cfg.Cache)
+}
+
+// normalizeLog lowercases the level, resolves the default log directory and
+// validates rotation limits.
+func normalizeLog(cfg *LogConfig) error {
+	cfg.Level = strings.ToLower(strings.TrimSpace(cfg.Level))
+	if cfg.Level == "" {
+		cfg.Level = "info"
+	}
+	if _, ok := logLevels[cfg.Level]; !ok {
+		return fieldErr("log.level", "must be one of: debug, info, warn, error")
+	}
+
+	if cfg.Dir == "" {
+		cfg.Dir = defaultLogDir()
+	}
+
+	if cfg.MaxSizeMB <= 0 {
+		return fieldErr("log.max_size_mb", "must be a positive integer")
+	}
+	if cfg.MaxBackups < 0 {
+		return fieldErr("log.max_backups", "must not be negative")
+	}
+	if cfg.MaxAgeDays < 0 {
+		return fieldErr("log.max_age_days", "must not be negative")
+	}
+	if cfg.MetaMaxBytes <= 0 {
+		return fieldErr("log.meta_max_bytes", "must be a positive integer")
+	}
+	if cfg.MetaMaxDepth <= 0 {
+		return fieldErr("log.meta_max_depth", "must be a positive integer")
+	}
+
+	return nil
+}
+
+// normalizeCache resolves the default cache directory and validates limits.
+func normalizeCache(cfg *CacheConfig) error {
+	if cfg.Entries <= 0 {
+		return fieldErr("cache.entries", "must be a positive integer")
+	}
+	if cfg.Dir == "" {
+		cfg.Dir = defaultCacheDir()
+	}
 	return nil
 }
 
+// UserDataDir resolves "<user config dir>/ContractCheck", the root for
+// persisted app data (logs, diagnostics). Returns "" when the OS does not
+// expose a config dir.
+func UserDataDir() string {
+	base, err := os.UserConfigDir()
+	if err != nil || base == "" {
+		return ""
+	}
+	return filepath.Join(base, AppDirName)
+}
+
+// defaultLogDir resolves "<user data dir>/logs" ("" disables the file sink).
+func defaultLogDir() string {
+	base := UserDataDir()
+	if base == "" {
+		return ""
+	}
+	return filepath.Join(base, "logs")
+}
+
+// defaultCacheDir resolves "<user cache dir>/ContractCheck/specs" ("" when
+// the OS does not expose a cache dir, which disables the disk layer).
+func defaultCacheDir() string {
+	base, err := os.UserCacheDir()
+	if err != nil || base == "" {
+		return ""
+	}
+	return filepath.Join(base, AppDirName, "specs")
+}
+
 // fieldErr wraps ErrConfigInvalid with a field-specific, actionable message.
 func fieldErr(field, msg string) error {
 	return fmt.Errorf("%w: field %q %s", ErrConfigInvalid, field, msg)
@@ -0,0 +1,106 @@
+// Package version exposes build metadata injected at link time.
+//
+// Release builds set the variables below via ldflags (see makefile):
+//
+//	-X github.com/betoth/contractcheck/internal/version.Version=1.2.3
+//	-X github.com/betoth/contractcheck/internal/version.Commit=abc1234
+//	-X github.com/betoth/contractcheck/internal/version.BuildDate=2025-01-01T00:00:00Z
+//
+// When they are not injected (e.g., `go run`, `go install`), Get falls back
+// to the VCS metadata embedded by the Go toolchain (debug.ReadBuildInfo).
+// BuildTime has no such fallback: the VCS time is the commit's, reported as
+// CommitTime.
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// Link-time variables; keep them as plain strings so -X can set them.
+var (
+	Version   = ""
+	Commit    = ""
+	BuildDate = ""
+)
+
+// devVersion is reported when no version could be determined.
+const devVersion = "dev"
+
+// BuildInfo is the structured build metadata shared by UI, CLI and diagnostics.
+// BuildTime is empty unless set at link time; CommitTime is the VCS time of
+// Commit when the toolchain embedded it.
+type BuildInfo struct {
+	Version    string `json:"version"`
+	Commit     string `json:"commit"`
+	Dirty      bool   `json:"dirty"`
+	BuildTime  string `json:"buildTime"`
+	CommitTime string `json:"commitTime"`
+	GoVersion  string `json:"goVersion"`
+	Platform   string `json:"platform"`
+}
+
+// readBuildInfo is swapped in tests.
+var readBuildInfo = debug.ReadBuildInfo
+
+// Get returns the effective build info: ldflags values win, VCS settings
+// from debug.ReadBuildInfo fill the gaps.
+func Get() BuildInfo {
+	info := BuildInfo{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildDate,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	if bi, ok := readBuildInfo(); ok {
+		if info.Version == "" && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+			info.Version = strings.TrimPrefix(bi.Main.Version, "v")
+		}
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = shortRevision(s.Value)
+				}
+			case "vcs.modified":
+				info.Dirty = s.Value == "true"
+			case "vcs.time":
+				info.CommitTime = s.Value
+			}
+		}
+	}
+
+	if info.Version == "" {
+		info.Version = devVersion
+	}
+	return info
+}
+
+// String renders a one-line summary, e.g. "1.2.3 (abc1234-dirty, 2025-01-01T00:00:00Z, go1.24.5 linux/amd64)".
+func (b BuildInfo) String() string {
+	commit := b.Commit
+	if commit == "" {
+		commit = "unknown"
+	}
+	if b.Dirty {
+		commit += "-dirty"
+	}
+	parts := []string{commit}
+	if b.BuildTime != "" {
+		parts = append(parts, b.BuildTime)
+	}
+	parts = append(parts, b.GoVersion+" "+b.Platform)
+	return fmt.Sprintf("%s (%s)", b.Version, strings.Join(parts, ", "))
+}
+
+// shortRevision trims a full VCS hash to the 7-char form used by `git rev-parse --short`.
+func shortRevision(rev string) string {
+	if len(rev) > 7 {
+		return rev[:7]
+	}
+	return rev
+}
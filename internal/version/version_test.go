@@ -0,0 +1,56 @@
+package version
+
+import (
+	"runtime/debug"
+	"testing"
+)
+
+func withBuildInfo(t *testing.T, bi *debug.BuildInfo, ldVersion, ldCommit string) {
+	t.Helper()
+	prevRead, prevVersion, prevCommit, prevDate := readBuildInfo, Version, Commit, BuildDate
+	t.Cleanup(func() {
+		readBuildInfo, Version, Commit, BuildDate = prevRead, prevVersion, prevCommit, prevDate
+	})
+	readBuildInfo = func() (*debug.BuildInfo, bool) { return bi, bi != nil }
+	Version, Commit, BuildDate = ldVersion, ldCommit, ""
+}
+
+func TestGet_FallsBackToVCSSettings(t *testing.T) {
+	withBuildInfo(t, &debug.BuildInfo{
+		Main: debug.Module{Version: "(devel)"},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs.revision", Value: "0123456789abcdef"},
+			{Key: "vcs.modified", Value: "true"},
+			{Key: "vcs.time", Value: "2025-01-02T03:04:05Z"},
+		},
+	}, "", "")
+
+	got := Get()
+	if got.Version != "dev" || got.Commit != "0123456" || !got.Dirty || got.BuildTime != "" || got.CommitTime != "2025-01-02T03:04:05Z" {
+		t.Fatalf("unexpected build info: %+v", got)
+	}
+}
+
+func TestGet_LdflagsWin(t *testing.T) {
+	withBuildInfo(t, &debug.BuildInfo{
+		Main:     debug.Module{Version: "v9.9.9"},
+		Settings: []debug.BuildSetting{{Key: "vcs.revision", Value: "fffffffffff"}},
+	}, "1.2.3", "abc1234")
+
+	got := Get()
+	if got.Version != "1.2.3" || got.Commit != "abc1234" {
+		t.Fatalf("expected ldflags values to win, got %+v", got)
+	}
+}
+
+func TestGet_ModuleVersionWithoutBuildInfo(t *testing.T) {
+	withBuildInfo(t, &debug.BuildInfo{Main: debug.Module{Version: "v0.4.0"}}, "", "")
+	if got := Get().Version; got != "0.4.0" {
+		t.Fatalf("expected module version 0.4.0, got %q", got)
+	}
+
+	withBuildInfo(t, nil, "", "")
+	if got := Get().Version; got != "dev" {
+		t.Fatalf("expected dev without build info, got %q", got)
+	}
+}
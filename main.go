@@ -1,12 +1,38 @@
 package main
 
 import (
+	"context"
 	"embed"
 	"io/fs"
 	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
 
+	"github.com/betoth/contractcheck/internal/adapter/cache"
+	"github.com/betoth/contractcheck/internal/adapter/cli"
+	"github.com/betoth/contractcheck/internal/adapter/compatreport"
+	"github.com/betoth/contractcheck/internal/adapter/deprecation"
+	"github.com/betoth/contractcheck/internal/adapter/git"
+	"github.com/betoth/contractcheck/internal/adapter/gotest"
+	"github.com/betoth/contractcheck/internal/adapter/har"
+	"github.com/betoth/contractcheck/internal/adapter/httpclient"
+	"github.com/betoth/contractcheck/internal/adapter/jobs"
+	"github.com/betoth/contractcheck/internal/adapter/junit"
 	applog "github.com/betoth/contractcheck/internal/adapter/logger"
+	kinopenapi "github.com/betoth/contractcheck/internal/adapter/openapi"
+	"github.com/betoth/contractcheck/internal/adapter/pactfile"
+	"github.com/betoth/contractcheck/internal/adapter/sarif"
 	"github.com/betoth/contractcheck/internal/adapter/ui/wailsapp"
+	"github.com/betoth/contractcheck/internal/adapter/watch"
+	"github.com/betoth/contractcheck/internal/application/ports/input"
+	"github.com/betoth/contractcheck/internal/application/ports/output"
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+	"github.com/betoth/contractcheck/internal/application/ports/output/report"
+	"github.com/betoth/contractcheck/internal/application/service"
+	"github.com/betoth/contractcheck/internal/config"
+	"github.com/betoth/contractcheck/internal/version"
 	"github.com/wailsapp/wails/v2"
 )
 
@@ -14,10 +40,61 @@ import (
 var assets embed.FS
 
 func main() {
-	// Initialize backend logger (zap)
-	l := applog.New()
+	// Load effective configuration (defaults + validation)
+	cfg, err := config.LoadAppConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Initialize backend logger (zap): stderr + rotating JSON file.
+	// The ring buffer keeps recent entries in memory for diagnostics export.
+	// The session ID tags every entry (backend and frontend) of this run.
+	ring := applog.NewRingBuffer(applog.DefaultRingCapacity)
+	l := applog.NewRingLogger(applog.New(cfg.Log), ring).With("session", applog.NewSessionID())
 	defer l.Sync()
 
+	logReader := applog.NewFileReader(applog.LogFilePath(cfg.Log))
+	jobHistory := jobs.NewMemoryHistory(jobs.DefaultCapacity)
+	specLoader := newSpecLoader(cfg)
+	compare := newCompare(cfg, l)
+
+	// CLI mode: a fresh process has an empty ring, so bundle persisted logs instead.
+	// Long-running commands (watch) stop cleanly on Ctrl+C / SIGTERM.
+	if args := os.Args[1:]; cli.IsCommand(args) {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		importer := newImport(cfg, l, specLoader, jobHistory)
+		code := cli.Run(ctx, args, os.Stdout, os.Stderr, cli.Deps{
+			Diagnostics: newDiagnostics(cfg, l, logReader, jobHistory),
+			Bundle:      newBundle(cfg, l),
+			Import:      importer,
+			Dereference: newDereference(l),
+			Writer:      kinopenapi.NewDocWriter(),
+			Format:      newFormat(cfg, l),
+			Watch:       newWatch(cfg, l, jobHistory, compare),
+			Compare:     compare,
+			Reporters:   newReporters(),
+			Renderers: map[string]report.ComparisonWriter{
+				"html":     compatreport.NewHTMLWriter(),
+				"markdown": compatreport.NewMarkdownWriter(),
+			},
+			Changelog:       newChangelog(l),
+			ChangelogWriter: compatreport.NewChangelogWriter(),
+			Semver:          newSemver(l),
+			Deprecations:    newDeprecations(cfg, l),
+			Pacts:           newPact(l, importer),
+			TestGen:         newTestGen(l, importer),
+			Run:             newRun(l, importer),
+			Fuzz:            newFuzz(l, importer),
+			Coverage:        newCoverage(l, importer),
+			CoverageWriter:  compatreport.NewCoverageHTMLWriter(),
+			Infer:           newInfer(l),
+			Drift:           newDrift(l, importer),
+			Audit:           newAudit(l, importer),
+		})
+		stop()
+		os.Exit(code)
+	}
+
 	// Ensure embedded assets point to "frontend/dist"
 	dist, err := fs.Sub(assets, "frontend/dist")
 	if err != nil {
@@ -25,7 +102,313 @@ func main() {
 	}
 
 	// Run Wails with centralized options
-	if err := wails.Run(wailsapp.UIOptions(dist, l)); err != nil {
+	metaPolicy := wailsapp.MetaPolicy{
+		MaxBytes:   cfg.Log.MetaMaxBytes,
+		MaxDepth:   cfg.Log.MetaMaxDepth,
+		RedactKeys: cfg.Log.RedactKeys,
+	}
+	uiOpts := wailsapp.UIOptions(dist, l,
+		wailsapp.WithLogReader(logReader),
+		wailsapp.WithMetaPolicy(metaPolicy),
+		wailsapp.WithDiagnostics(newDiagnostics(cfg, l, ring, jobHistory), diagnosticsDir()),
+		wailsapp.WithWatch(newWatch(cfg, l, jobHistory, compare)),
+	)
+	if err := wails.Run(uiOpts); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// newDiagnostics builds the diagnostics exporter over the given log source.
+func newDiagnostics(cfg *config.AppConfig, l output.Logger, logs output.LogReader, history output.JobHistory) *service.DiagnosticsService {
+	svc, err := service.NewDiagnosticsService(service.DiagnosticsParams{
+		Logs:       logs,
+		Jobs:       history,
+		Logger:     l,
+		Config:     cfg,
+		Version:    version.Get(),
+		RedactKeys: cfg.Log.RedactKeys,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	return svc
+}
+
+// newBundle builds the spec bundling use case (kin-openapi backed).
+func newBundle(cfg *config.AppConfig, l output.Logger) *service.OpenAPIBundleService {
+	svc, err := service.NewOpenAPIBundleService(service.OpenAPIBundleParams{
+		BundlerFor: func(sandboxDir string) openapi.Bundler {
+			return kinopenapi.NewKinBundler(kinopenapi.WithBundleSandbox(sandboxDir))
+		},
+		Writer:        kinopenapi.NewDocWriter(),
+		Logger:        l,
+		VersionPolicy: service.NewOpenAPIVersionPolicy(cfg.OpenAPI.SupportedMajors),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	return svc
+}
+
+// newImport builds the spec import use case (kin-openapi backed).
+func newImport(cfg *config.AppConfig, l output.Logger, loader openapi.Loader, history output.JobHistory) *service.OpenAPILoaderService {
+	svc, err := service.NewOpenAPILoaderService(service.OpenAPILoaderParams{
+		Loader:        loader,
+		Logger:        l,
+		VersionPolicy: service.NewOpenAPIVersionPolicy(cfg.OpenAPI.SupportedMajors),
+		Jobs:          history,
+	})
+	if err != nil {
 		log.Fatal(err)
 	}
+	return svc
+}
+
+// newSpecLoader builds the kin-openapi loader with opts, wrapped in the
+// parsed-spec cache unless it is disabled.
+func newSpecLoader(cfg *config.AppConfig, opts ...kinopenapi.KinLoaderOption) openapi.Loader {
+	kin := kinopenapi.NewKinLoader(opts...)
+	if !cfg.Cache.Enabled {
+		return kin
+	}
+	policy := service.NewOpenAPIVersionPolicy(cfg.OpenAPI.SupportedMajors)
+	cacheOpts := []cache.Option{
+		cache.WithCapacity(cfg.Cache.Entries),
+		cache.WithFingerprint(kin.Fingerprint(), "majors="+policy.FormatVersions()),
+	}
+	if cfg.Cache.Disk {
+		cacheOpts = append(cacheOpts, cache.WithDiskDir(cfg.Cache.Dir, kin.BuildModel))
+	}
+	return cache.NewCachedLoader(kin, cacheOpts...)
+}
+
+// newWatch builds the watch-mode use case. Like newCompare, it follows
+// external refs confined to the spec's directory; diffs go through compare.
+func newWatch(cfg *config.AppConfig, l output.Logger, history output.JobHistory, compare *service.OpenAPICompareService) *service.OpenAPIWatchService {
+	svc, err := service.NewOpenAPIWatchService(service.OpenAPIWatchParams{
+		ImporterFor: func(sandbox string) input.ImportOpenAPISpec {
+			loader := newSpecLoader(cfg, kinopenapi.WithExternalRefsAllowed(), kinopenapi.WithSandbox(sandbox))
+			return newImport(cfg, l, loader, history)
+		},
+		Watcher:   watch.NewFSWatcher(),
+		Logger:    l,
+		Checks:    []report.Check{service.NewLintCheck(), service.NewSecurityCheck()},
+		Baselines: compare,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	return svc
+}
+
+// newReporters builds the finding writers selectable with -format.
+func newReporters() map[string]report.Writer {
+	rules := append(report.ErrorRules(), service.ChangeRules()...)
+	rules = append(rules, service.LintRules()...)
+	rules = append(rules, service.DeprecationRules()...)
+	rules = append(rules, service.PactRules()...)
+	rules = append(rules, service.RunRules()...)
+	rules = append(rules, service.FuzzRules()...)
+	rules = append(rules, service.SecurityRules()...)
+	rules = append(rules, service.DriftRules()...)
+	return map[string]report.Writer{
+		"sarif": sarif.NewWriter(
+			sarif.WithInformationURI("https://github.com/betoth/contractcheck"),
+			sarif.WithRules(rules...),
+		),
+		"junit": junit.NewWriter(),
+	}
+}
+
+// newChangelog builds the changelog use case.
+func newChangelog(l output.Logger) *service.ChangelogService {
+	svc, err := service.NewChangelogService(service.ChangelogParams{Logger: l})
+	if err != nil {
+		log.Fatal(err)
+	}
+	return svc
+}
+
+// newSemver builds the version bump recommendation use case.
+func newSemver(l output.Logger) *service.SemverService {
+	svc, err := service.NewSemverService(service.SemverParams{Logger: l})
+	if err != nil {
+		log.Fatal(err)
+	}
+	return svc
+}
+
+// newCompare builds the contract comparison use case. Each side gets its own
+// loader so external refs stay confined to that side's directory or checkout.
+func newCompare(cfg *config.AppConfig, l output.Logger) *service.OpenAPICompareService {
+	svc, err := service.NewOpenAPICompareService(service.OpenAPICompareParams{
+		LoaderFor: func(sandbox string) openapi.Loader {
+			return kinopenapi.NewKinLoader(kinopenapi.WithExternalRefsAllowed(), kinopenapi.WithSandbox(sandbox))
+		},
+		Revisions:     git.NewSource(),
+		Logger:        l,
+		VersionPolicy: service.NewOpenAPIVersionPolicy(cfg.OpenAPI.SupportedMajors),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	return svc
+}
+
+// newDeprecations builds the deprecation lifecycle check, loading specs like newCompare.
+func newDeprecations(cfg *config.AppConfig, l output.Logger) *service.DeprecationService {
+	svc, err := service.NewDeprecationService(service.DeprecationParams{
+		LoaderFor: func(sandbox string) openapi.Loader {
+			return kinopenapi.NewKinLoader(kinopenapi.WithExternalRefsAllowed(), kinopenapi.WithSandbox(sandbox))
+		},
+		Revisions:     git.NewSource(),
+		Ledger:        deprecation.NewFileLedger(),
+		Logger:        l,
+		VersionPolicy: service.NewOpenAPIVersionPolicy(cfg.OpenAPI.SupportedMajors),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	return svc
+}
+
+// newPact builds the consumer contract verification on top of the import use case.
+func newPact(l output.Logger, importer *service.OpenAPILoaderService) *service.PactService {
+	svc, err := service.NewPactService(service.PactParams{
+		Importer: importer,
+		Pacts:    pactfile.NewReader(),
+		Logger:   l,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	return svc
+}
+
+// newTestGen builds the contract test generator on top of the import use case.
+func newTestGen(l output.Logger, importer *service.OpenAPILoaderService) *service.TestGenService {
+	svc, err := service.NewTestGenService(service.TestGenParams{
+		Importer: importer,
+		Renderer: gotest.NewRenderer(),
+		Logger:   l,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	return svc
+}
+
+// newRun builds the live contract run on top of the import use case.
+func newRun(l output.Logger, importer *service.OpenAPILoaderService) *service.RunService {
+	svc, err := service.NewRunService(service.RunParams{
+		Importer: importer,
+		Client:   httpclient.NewClient(),
+		Logger:   l,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	return svc
+}
+
+// newFuzz builds the fuzzer on top of the import use case.
+func newFuzz(l output.Logger, importer *service.OpenAPILoaderService) *service.FuzzService {
+	svc, err := service.NewFuzzService(service.FuzzParams{
+		Importer: importer,
+		Client:   httpclient.NewClient(),
+		Logger:   l,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	return svc
+}
+
+// newCoverage builds the traffic coverage report on top of the import use case.
+func newCoverage(l output.Logger, importer *service.OpenAPILoaderService) *service.CoverageService {
+	svc, err := service.NewCoverageService(service.CoverageParams{
+		Importer: importer,
+		Traffic:  har.NewReader(),
+		Logger:   l,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	return svc
+}
+
+// newDrift builds the traffic drift report on top of the import use case.
+func newDrift(l output.Logger, importer *service.OpenAPILoaderService) *service.DriftService {
+	svc, err := service.NewDriftService(service.DriftParams{
+		Importer: importer,
+		Traffic:  har.NewReader(),
+		Logger:   l,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	return svc
+}
+
+// newAudit builds the security audit on top of the import use case.
+func newAudit(l output.Logger, importer *service.OpenAPILoaderService) *service.SecurityAuditService {
+	svc, err := service.NewSecurityAuditService(service.SecurityAuditParams{
+		Importer: importer,
+		Logger:   l,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	return svc
+}
+
+// newInfer builds spec inference from HAR files, validated like loaded specs.
+func newInfer(l output.Logger) *service.InferService {
+	svc, err := service.NewInferService(service.InferParams{
+		Traffic: har.NewReader(),
+		Parser:  kinopenapi.NewKinLoader(),
+		Logger:  l,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	return svc
+}
+
+// newDereference builds the spec flattening use case.
+func newDereference(l output.Logger) *service.OpenAPIDerefService {
+	svc, err := service.NewOpenAPIDerefService(service.OpenAPIDerefParams{
+		Dereferencer: kinopenapi.NewKinDereferencer(),
+		Logger:       l,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	return svc
+}
+
+// newFormat builds the canonical formatting use case. Like newCompare, it
+// follows external refs confined to each spec's directory.
+func newFormat(cfg *config.AppConfig, l output.Logger) *service.OpenAPIFormatService {
+	svc, err := service.NewOpenAPIFormatService(service.OpenAPIFormatParams{
+		LoaderFor: func(sandbox string) openapi.Loader {
+			return kinopenapi.NewKinLoader(kinopenapi.WithExternalRefsAllowed(), kinopenapi.WithSandbox(sandbox))
+		},
+		Formatter:     kinopenapi.NewYAMLFormatter(),
+		Logger:        l,
+		VersionPolicy: service.NewOpenAPIVersionPolicy(cfg.OpenAPI.SupportedMajors),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	return svc
+}
+
+// diagnosticsDir is the default destination for bundles exported from the UI.
+func diagnosticsDir() string {
+	base := config.UserDataDir()
+	if base == "" {
+		return ""
+	}
+	return filepath.Join(base, "diagnostics")
 }
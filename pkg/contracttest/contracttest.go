@@ -0,0 +1,287 @@
+// Package contracttest is the runtime of the contract tests generated by
+// `contractcheck testgen`. It sends requests to a running server and checks
+// responses against the schemas of the spec the tests came from.
+//
+// The server and credentials come from the environment:
+//   - CONTRACTCHECK_BASE_URL: the server to test, e.g. "http://localhost:8080/v1".
+//     Tests are skipped when it is not set.
+//   - CONTRACTCHECK_AUTHORIZATION: the Authorization header value, if any.
+//   - CONTRACTCHECK_HEADERS: extra headers, "Name: value" pairs separated by
+//     newlines (e.g. an API key).
+//   - CONTRACTCHECK_QUERY: query credentials as a query string, e.g.
+//     "api_key=k1&tenant=acme", for apiKey schemes "in: query".
+//   - CONTRACTCHECK_COOKIES: cookie credentials as a Cookie header value, e.g.
+//     "session=abc; csrf=xyz", for apiKey schemes "in: cookie".
+package contracttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/betoth/contractcheck/internal/application/ports/output/openapi"
+)
+
+// Environment variables read by NewClient.
+const (
+	ENV_BASE_URL      = "CONTRACTCHECK_BASE_URL"
+	ENV_AUTHORIZATION = "CONTRACTCHECK_AUTHORIZATION"
+	ENV_HEADERS       = "CONTRACTCHECK_HEADERS"
+	ENV_QUERY         = "CONTRACTCHECK_QUERY"
+	ENV_COOKIES       = "CONTRACTCHECK_COOKIES"
+)
+
+// DEFAULT_TIMEOUT bounds each request.
+const DEFAULT_TIMEOUT = 30 * time.Second
+
+// maxBodyBytes caps how much of a response body is read.
+const maxBodyBytes = 10 << 20
+
+// Schemas are the response schemas of a generated suite, by key.
+type Schemas struct {
+	byKey map[string]*openapi.Schema
+}
+
+// MustSchemas parses the schema bundle embedded in generated tests; it
+// panics on malformed input, which only hand edits produce.
+func MustSchemas(bundle string) Schemas {
+	byKey, err := openapi.DecodeSchemas([]byte(bundle))
+	if err != nil {
+		panic(fmt.Sprintf("contracttest: invalid schema bundle: %v", err))
+	}
+	return Schemas{byKey: byKey}
+}
+
+// Request is a request to send. Path is a template ("/pets/{id}") filled
+// from PathParams; Anonymous leaves out the configured credentials.
+type Request struct {
+	Method      string
+	Path        string
+	PathParams  map[string]string
+	Query       map[string][]string
+	Header      map[string]string
+	ContentType string
+	Body        string
+	Anonymous   bool
+}
+
+// Client sends requests to the server under test.
+//   - Header, Query, Cookies: credentials sent with every request unless it
+//     is Anonymous. Query parameters the request sets itself win.
+type Client struct {
+	BaseURL string
+	HTTP    *http.Client
+	Header  http.Header
+	Query   url.Values
+	Cookies []*http.Cookie
+}
+
+// NewClient builds a client from the environment, skipping t when no base
+// URL is configured.
+func NewClient(t testing.TB) *Client {
+	t.Helper()
+	base := os.Getenv(ENV_BASE_URL)
+	if base == "" {
+		t.Skipf("%s is not set", ENV_BASE_URL)
+	}
+	c := &Client{
+		BaseURL: strings.TrimSuffix(base, "/"),
+		HTTP:    &http.Client{Timeout: DEFAULT_TIMEOUT},
+		Header:  http.Header{},
+	}
+	if auth := os.Getenv(ENV_AUTHORIZATION); auth != "" {
+		c.Header.Set("Authorization", auth)
+	}
+	for _, line := range strings.Split(os.Getenv(ENV_HEADERS), "\n") {
+		name, value, ok := strings.Cut(line, ":")
+		if ok && strings.TrimSpace(name) != "" {
+			c.Header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+		}
+	}
+	if raw := os.Getenv(ENV_QUERY); raw != "" {
+		query, err := url.ParseQuery(raw)
+		if err != nil {
+			t.Fatalf("%s: %v", ENV_QUERY, err)
+		}
+		c.Query = query
+	}
+	if raw := os.Getenv(ENV_COOKIES); raw != "" {
+		cookies, err := http.ParseCookie(raw)
+		if err != nil {
+			t.Fatalf("%s: %v", ENV_COOKIES, err)
+		}
+		c.Cookies = cookies
+	}
+	return c
+}
+
+// Do sends req and reads the response; transport errors fail t immediately.
+func (c *Client) Do(t testing.TB, req Request) *Response {
+	t.Helper()
+	path := req.Path
+	for name, value := range req.PathParams {
+		path = strings.ReplaceAll(path, "{"+name+"}", url.PathEscape(value))
+	}
+	query := url.Values{}
+	if !req.Anonymous {
+		for name, values := range c.Query {
+			query[name] = values
+		}
+	}
+	for name, values := range req.Query {
+		query[name] = values
+	}
+	target := c.BaseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	if req.Body != "" {
+		body = strings.NewReader(req.Body)
+	}
+	httpReq, err := http.NewRequest(req.Method, target, body)
+	if err != nil {
+		t.Fatalf("%s %s: %v", req.Method, req.Path, err)
+	}
+	if !req.Anonymous {
+		for name, values := range c.Header {
+			httpReq.Header[name] = values
+		}
+	}
+	for name, value := range req.Header {
+		httpReq.Header.Set(name, value)
+	}
+	if req.ContentType != "" {
+		httpReq.Header.Set("Content-Type", req.ContentType)
+	}
+	if !req.Anonymous {
+		for _, cookie := range c.Cookies {
+			httpReq.AddCookie(cookie)
+		}
+	}
+
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		t.Fatalf("%s %s: %v", req.Method, req.Path, err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+	if err != nil {
+		t.Fatalf("%s %s: reading response: %v", req.Method, req.Path, err)
+	}
+	return &Response{Status: resp.StatusCode, Header: resp.Header, Body: raw}
+}
+
+// Response is a received response.
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// ExpectStatus fails t unless the status matches want: a code ("200"), a
+// range ("4XX") or "default", which accepts any status.
+func (r *Response) ExpectStatus(t testing.TB, want string) {
+	t.Helper()
+	got := fmt.Sprint(r.Status)
+	switch {
+	case want == "default":
+		return
+	case len(want) == 3 && strings.HasSuffix(strings.ToUpper(want), "XX"):
+		if got[0] == want[0] {
+			return
+		}
+	case got == want:
+		return
+	}
+	t.Errorf("status = %s, want %s; body: %s", got, want, snippet(r.Body))
+}
+
+// ExpectContent fails t unless the body has one of the declared media types
+// and, for JSON ones, conforms to its schema. content maps media types
+// (possibly "type/*" or "*/*") to keys of schemas, or to "" when the media
+// type has no schema. With no declared content, any body is accepted.
+func (r *Response) ExpectContent(t testing.TB, schemas Schemas, content map[string]string) {
+	t.Helper()
+	if len(content) == 0 {
+		return
+	}
+	if len(r.Body) == 0 {
+		t.Errorf("response has no body, want one of %s", mediaTypes(content))
+		return
+	}
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		t.Errorf("response Content-Type %q is invalid, want one of %s", r.Header.Get("Content-Type"), mediaTypes(content))
+		return
+	}
+	key, ok := matchContent(content, mediaType)
+	if !ok {
+		t.Errorf("response Content-Type is %s, want one of %s", mediaType, mediaTypes(content))
+		return
+	}
+	schema := schemas.byKey[key]
+	if schema == nil || !isJSON(mediaType) {
+		return
+	}
+	var v any
+	if err := json.Unmarshal(r.Body, &v); err != nil {
+		t.Errorf("response body is not valid JSON: %v; body: %s", err, snippet(r.Body))
+		return
+	}
+	for _, viol := range schema.Validate(v, openapi.SCHEMA_RESPONSE) {
+		t.Errorf("response body %s", viol)
+	}
+}
+
+// matchContent finds the declared entry for mediaType: exact, then
+// "type/*", then "*/*". Declared keys may carry parameters.
+func matchContent(content map[string]string, mediaType string) (string, bool) {
+	byType := make(map[string]string, len(content))
+	for declared, key := range content {
+		name, _, _ := strings.Cut(declared, ";")
+		byType[strings.ToLower(strings.TrimSpace(name))] = key
+	}
+	major, _, _ := strings.Cut(mediaType, "/")
+	for _, candidate := range []string{mediaType, major + "/*", "*/*"} {
+		if key, ok := byType[candidate]; ok {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func mediaTypes(content map[string]string) string {
+	names := make([]string, 0, len(content))
+	for name := range content {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// snippet shortens a body for failure messages.
+func snippet(body []byte) string {
+	const max = 200
+	if len(body) > max {
+		return string(body[:max]) + "..."
+	}
+	return string(body)
+}
@@ -0,0 +1,111 @@
+package contracttest_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/betoth/contractcheck/pkg/contracttest"
+)
+
+// recorder captures failures instead of failing the enclosing test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+	r.FailNow()
+}
+
+const bundle = `{"defs":{"Pet":{"type":["object"],"required":["id","name"],"properties":{"id":{"type":["integer"]},"name":{"type":["string"]},"parent":{"$ref":"#/defs/Pet"}}}},` +
+	`"schemas":{"pet":{"$ref":"#/defs/Pet"}}}`
+
+func TestClient_SendsRequestsAndChecksResponses(t *testing.T) {
+	var got *http.Request
+	var gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		raw, _ := io.ReadAll(r.Body)
+		gotBody = string(raw)
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		if r.URL.Query().Get("broken") != "" {
+			fmt.Fprint(w, `{"id":"7","parent":{"id":1,"name":"x"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":7,"name":"rex"}`)
+	}))
+	defer srv.Close()
+
+	t.Setenv(contracttest.ENV_BASE_URL, srv.URL+"/v1/")
+	t.Setenv(contracttest.ENV_AUTHORIZATION, "Bearer token")
+	t.Setenv(contracttest.ENV_HEADERS, "X-Api-Key: k1\nX-Tenant: acme")
+	t.Setenv(contracttest.ENV_QUERY, "api_key=q1&dry=0")
+	t.Setenv(contracttest.ENV_COOKIES, "session=s1")
+	schemas := contracttest.MustSchemas(bundle)
+	c := contracttest.NewClient(t)
+
+	rec := &recorder{TB: t}
+	resp := c.Do(rec, contracttest.Request{
+		Method:      "PUT",
+		Path:        "/pets/{id}",
+		PathParams:  map[string]string{"id": "a b"},
+		Query:       map[string][]string{"dry": {"1"}},
+		Header:      map[string]string{"X-Tenant": "other", "Cookie": "theme=dark"},
+		ContentType: "application/json",
+		Body:        `{"name":"rex"}`,
+	})
+	resp.ExpectStatus(rec, "200")
+	resp.ExpectStatus(rec, "2XX")
+	resp.ExpectContent(rec, schemas, map[string]string{"application/json": "pet"})
+	if len(rec.errors) > 0 {
+		t.Fatalf("unexpected failures: %v", rec.errors)
+	}
+	if got.URL.Path != "/v1/pets/a b" || got.URL.RawQuery != "api_key=q1&dry=1" || gotBody != `{"name":"rex"}` {
+		t.Errorf("request = %s %s?%s %s", got.Method, got.URL.Path, got.URL.RawQuery, gotBody)
+	}
+	if got.Header.Get("Authorization") != "Bearer token" || got.Header.Get("X-Api-Key") != "k1" || got.Header.Get("X-Tenant") != "other" {
+		t.Errorf("headers = %v", got.Header)
+	}
+	if got.Header.Get("Cookie") != "theme=dark; session=s1" {
+		t.Errorf("cookies = %q", got.Header.Get("Cookie"))
+	}
+
+	c.Do(rec, contracttest.Request{Method: "GET", Path: "/pets", Anonymous: true})
+	if got.Header.Get("Authorization") != "" || got.Header.Get("X-Api-Key") != "" || got.Header.Get("Cookie") != "" || got.URL.RawQuery != "" {
+		t.Errorf("anonymous request sent credentials: %v %q", got.Header, got.URL.RawQuery)
+	}
+
+	rec = &recorder{TB: t}
+	resp = c.Do(rec, contracttest.Request{Method: "GET", Path: "/pets/7", Query: map[string][]string{"broken": {"1"}}})
+	resp.ExpectStatus(rec, "404")
+	resp.ExpectContent(rec, schemas, map[string]string{"application/json": "pet"})
+	resp.ExpectContent(rec, schemas, map[string]string{"application/xml": ""})
+	want := []string{
+		`status = 200, want 404; body: {"id":"7","parent":{"id":1,"name":"x"}}`,
+		`response body is missing required property "name"`,
+		`response body /id: is string, want integer`,
+		`response Content-Type is application/json, want one of application/xml`,
+	}
+	if strings.Join(rec.errors, "\n") != strings.Join(want, "\n") {
+		t.Errorf("failures =\n%s\nwant\n%s", strings.Join(rec.errors, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestNewClient_SkipsWithoutBaseURL(t *testing.T) {
+	t.Setenv(contracttest.ENV_BASE_URL, "")
+	t.Run("skip", func(t *testing.T) {
+		contracttest.NewClient(t)
+		t.Error("NewClient did not skip")
+	})
+}